@@ -2,6 +2,7 @@ package main
 
 import (
 	"context"
+	"errors"
 	"log/slog"
 	"net/http"
 	"os"
@@ -9,13 +10,63 @@ import (
 	"syscall"
 	"time"
 
+	"github.com/dkhrunov/url-shortener/internal/auth"
 	"github.com/dkhrunov/url-shortener/internal/config"
+	"github.com/dkhrunov/url-shortener/internal/lib/analytics"
+	"github.com/dkhrunov/url-shortener/internal/lib/audit"
+	"github.com/dkhrunov/url-shortener/internal/lib/botdetect"
+	"github.com/dkhrunov/url-shortener/internal/lib/clickhub"
+	"github.com/dkhrunov/url-shortener/internal/lib/clientip"
+	"github.com/dkhrunov/url-shortener/internal/lib/geoip"
+	"github.com/dkhrunov/url-shortener/internal/lib/health"
+	"github.com/dkhrunov/url-shortener/internal/lib/jwtauth"
+	"github.com/dkhrunov/url-shortener/internal/lib/lifecycle"
 	"github.com/dkhrunov/url-shortener/internal/lib/logger/slog/handlers/slogpretty"
+	"github.com/dkhrunov/url-shortener/internal/lib/logger/slog/handlers/slogtrace"
 	"github.com/dkhrunov/url-shortener/internal/lib/logger/slog/slogerr"
+	"github.com/dkhrunov/url-shortener/internal/lib/metrics"
+	"github.com/dkhrunov/url-shortener/internal/lib/quota"
+	"github.com/dkhrunov/url-shortener/internal/lib/ratelimit"
+	"github.com/dkhrunov/url-shortener/internal/lib/retention"
+	"github.com/dkhrunov/url-shortener/internal/lib/scanguard"
+	"github.com/dkhrunov/url-shortener/internal/lib/shorturl"
+	"github.com/dkhrunov/url-shortener/internal/lib/tracing"
+	"github.com/dkhrunov/url-shortener/internal/lib/webhook"
 	"github.com/dkhrunov/url-shortener/internal/storage/sqlite"
+	apikeycreate "github.com/dkhrunov/url-shortener/internal/transport/http/handlers/apikey/create"
+	apikeylist "github.com/dkhrunov/url-shortener/internal/transport/http/handlers/apikey/list"
+	apikeyrevoke "github.com/dkhrunov/url-shortener/internal/transport/http/handlers/apikey/revoke"
+	auditlist "github.com/dkhrunov/url-shortener/internal/transport/http/handlers/audit/list"
+	blockeddelete "github.com/dkhrunov/url-shortener/internal/transport/http/handlers/blocked/delete"
+	blockedlist "github.com/dkhrunov/url-shortener/internal/transport/http/handlers/blocked/list"
+	domaindelete "github.com/dkhrunov/url-shortener/internal/transport/http/handlers/domain/delete"
+	domainlist "github.com/dkhrunov/url-shortener/internal/transport/http/handlers/domain/list"
+	domainsave "github.com/dkhrunov/url-shortener/internal/transport/http/handlers/domain/save"
+	allevents "github.com/dkhrunov/url-shortener/internal/transport/http/handlers/events/all"
+	linkevents "github.com/dkhrunov/url-shortener/internal/transport/http/handlers/events/link"
+	"github.com/dkhrunov/url-shortener/internal/transport/http/handlers/health/live"
+	"github.com/dkhrunov/url-shortener/internal/transport/http/handlers/health/ready"
+	"github.com/dkhrunov/url-shortener/internal/transport/http/handlers/qr"
 	"github.com/dkhrunov/url-shortener/internal/transport/http/handlers/redirect"
+	tagdelete "github.com/dkhrunov/url-shortener/internal/transport/http/handlers/tag/delete"
+	tagexpire "github.com/dkhrunov/url-shortener/internal/transport/http/handlers/tag/expire"
+	tagretag "github.com/dkhrunov/url-shortener/internal/transport/http/handlers/tag/retag"
+	tagstats "github.com/dkhrunov/url-shortener/internal/transport/http/handlers/tag/stats"
+	linkanalytics "github.com/dkhrunov/url-shortener/internal/transport/http/handlers/url/analytics"
 	"github.com/dkhrunov/url-shortener/internal/transport/http/handlers/url/delete"
+	"github.com/dkhrunov/url-shortener/internal/transport/http/handlers/url/info"
+	"github.com/dkhrunov/url-shortener/internal/transport/http/handlers/url/list"
+	"github.com/dkhrunov/url-shortener/internal/transport/http/handlers/url/restore"
 	"github.com/dkhrunov/url-shortener/internal/transport/http/handlers/url/save"
+	"github.com/dkhrunov/url-shortener/internal/transport/http/handlers/url/update"
+	"github.com/dkhrunov/url-shortener/internal/transport/http/handlers/url/usage"
+	usercreate "github.com/dkhrunov/url-shortener/internal/transport/http/handlers/user/create"
+	userdelete "github.com/dkhrunov/url-shortener/internal/transport/http/handlers/user/delete"
+	userlist "github.com/dkhrunov/url-shortener/internal/transport/http/handlers/user/list"
+	webhookcreate "github.com/dkhrunov/url-shortener/internal/transport/http/handlers/webhook/create"
+	webhookdelete "github.com/dkhrunov/url-shortener/internal/transport/http/handlers/webhook/delete"
+	webhookdeliveries "github.com/dkhrunov/url-shortener/internal/transport/http/handlers/webhook/deliveries"
+	webhooklist "github.com/dkhrunov/url-shortener/internal/transport/http/handlers/webhook/list"
 	http_middleware "github.com/dkhrunov/url-shortener/internal/transport/http/middleware"
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
@@ -31,6 +82,11 @@ func main() {
 	// Config
 	cfg := config.MustLoad()
 
+	// Schema migrations run as a subcommand and exit
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		os.Exit(runMigrate(cfg, os.Args[2:]))
+	}
+
 	// Setup log
 	log := newLoger(cfg.Env)
 	slog.SetDefault(log)
@@ -38,55 +94,161 @@ func main() {
 	log.Info("starting url-shortener", slog.String("env", cfg.Env))
 	log.Debug("debug messages are enabled")
 
+	// Components register their shutdown, which runs in reverse order
+	app := lifecycle.New()
+
+	// Setup tracing
+	app.OnStop("tracing", newTracing(cfg))
+
 	// Setup storage
 	storage := newStorage(cfg)
+	app.OnStop("storage", func(context.Context) error {
+		return storage.Close()
+	})
+
+	// Purge deleted links and raw clicks after their retention periods
+	if cfg.Retention.Period > 0 || cfg.Retention.Clicks > 0 {
+		purger := retention.New(storage, retention.Config{
+			Period:          cfg.Retention.Period,
+			TombstonePeriod: cfg.Retention.Tombstones.Period,
+			ClickPeriod:     cfg.Retention.Clicks,
+			Interval:        cfg.Retention.Interval,
+		}, log)
+		app.Go("retention", purger.Run)
+		app.OnStop("retention", purger.Stop)
+	}
+
+	// Setup metrics
+	metrics := newMetrics(storage)
+
+	// Deliver link events to webhooks
+	webhooks := newWebhooks(cfg, storage)
+	app.Go("webhooks", webhooks.Run)
+	app.OnStop("webhooks", webhooks.Stop)
+
+	// Setup short links
+	shortURLs := newShortURLs(cfg)
+
+	// Setup JWT verification
+	jwtVerifier := newJWTVerifier(cfg)
+
+	// Setup client address resolution
+	clientIPs := newClientIPs(cfg)
+
+	// Setup live click streams
+	clicks := clickhub.New(cfg.ClickStream.Buffer)
+
+	// Record clicks for analytics
+	geoIP := newGeoIP(cfg)
+	app.OnStop("geoip", func(context.Context) error {
+		return geoIP.Close()
+	})
+
+	clickRecorder := newClickRecorder(cfg, storage, clientIPs, geoIP)
+	app.Go("analytics", clickRecorder.Run)
+	app.OnStop("analytics", clickRecorder.Stop)
+
+	bots := newBotDetector(cfg)
+	if cfg.Analytics.BotsFile != "" {
+		app.Go("bot patterns", bots.Run)
+		app.OnStop("bot patterns", bots.Stop)
+	}
+
+	// Setup health state
+	healthState := &health.State{}
 
 	// The HTTP Server
 	server := &http.Server{
 		Addr:         cfg.Address,
-		Handler:      newRouter(cfg, storage),
+		Handler:      newRouter(cfg, storage, shortURLs, metrics, jwtVerifier, clientIPs, webhooks, clicks, clickRecorder, bots, healthState),
 		ReadTimeout:  cfg.HTTPServer.Timeout,
 		WriteTimeout: cfg.HTTPServer.Timeout,
 		IdleTimeout:  cfg.HTTPServer.IdleTimeout,
 	}
 
-	// Server run context
-	serverCtx, serverStopCtx := context.WithCancel(context.Background())
+	// The metrics server, when metrics are served on a listener of their own
+	var metricsServer *http.Server
+	if cfg.Metrics.Address != "" {
+		metricsServer = &http.Server{
+			Addr:         cfg.Metrics.Address,
+			Handler:      newMetricsRouter(metrics),
+			ReadTimeout:  cfg.HTTPServer.Timeout,
+			WriteTimeout: cfg.HTTPServer.Timeout,
+			IdleTimeout:  cfg.HTTPServer.IdleTimeout,
+		}
+	}
 
-	// Listen for syscall signals for process to interrupt/quit
-	sig := make(chan os.Signal, 1)
-	signal.Notify(sig, syscall.SIGHUP, syscall.SIGINT, syscall.SIGTERM, syscall.SIGQUIT)
-	go func() {
-		<-sig
+	// Run the servers
+	app.Go("server", func() error {
+		log.Info("starting server", slog.String("address", server.Addr))
 
-		// Shutdown signal with grace period of 15 seconds
-		shutdownCtx, _ := context.WithTimeout(serverCtx, 15*time.Second)
+		return listenAndServe(server)
+	})
+	app.OnStop("server", server.Shutdown)
 
-		go func() {
-			<-shutdownCtx.Done()
-			if shutdownCtx.Err() == context.DeadlineExceeded {
-				log.Info("graceful shutdown timed out... forcing exit.")
-			}
-		}()
+	// Click streams never go idle, so they are ended before the server waits for
+	// requests to finish
+	app.OnStop("click streams", func(context.Context) error {
+		clicks.Close()
 
-		// Trigger graceful shutdown
-		err := server.Shutdown(shutdownCtx)
-		if err != nil {
-			log.Error("failed to shutdown server", slogerr.Error(err))
-		}
-		serverStopCtx()
-	}()
+		return nil
+	})
+
+	if metricsServer != nil {
+		app.Go("metrics server", func() error {
+			log.Info("serving metrics", slog.String("address", metricsServer.Addr))
+
+			return listenAndServe(metricsServer)
+		})
+		app.OnStop("metrics server", metricsServer.Shutdown)
+	}
+
+	// Wait for a signal to interrupt/quit, or for a server to fail
+	ctx, stop := signal.NotifyContext(context.Background(),
+		syscall.SIGHUP, syscall.SIGINT, syscall.SIGTERM, syscall.SIGQUIT)
 
-	// Run the server
-	err := server.ListenAndServe()
-	if err != nil && err != http.ErrServerClosed {
-		log.Error("failed to start server")
+	err := app.Wait(ctx)
+
+	// A second signal kills the process without waiting for the shutdown
+	stop()
+
+	failed := false
+	if err != nil {
+		log.Error("failed to run server", slogerr.Error(err))
+
+		failed = true
+	} else {
+		// Fail readiness first and give load balancers time to stop sending traffic
+		healthState.StartShutdown()
+		log.Info("shutting down", slog.String("delay", cfg.HTTPServer.ShutdownDelay.String()))
+		time.Sleep(cfg.HTTPServer.ShutdownDelay)
 	}
 
-	// Wait for server context to be stopped
-	<-serverCtx.Done()
+	// Stop accepting requests, drain in-flight ones, flush background work and close
+	// the storage, within the grace period
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), cfg.HTTPServer.ShutdownTimeout)
+	err = app.Stop(shutdownCtx)
+	cancel()
+	if err != nil {
+		log.Error("failed to shut down gracefully", slogerr.Error(err))
+
+		failed = true
+	}
 
 	log.Info("server stopped")
+
+	if failed {
+		os.Exit(1)
+	}
+}
+
+// listenAndServe serves until the server is shut down.
+func listenAndServe(server *http.Server) error {
+	if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+
+	return nil
 }
 
 func newLoger(env string) *slog.Logger {
@@ -103,7 +265,8 @@ func newLoger(env string) *slog.Logger {
 			slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelInfo}))
 	}
 
-	return log
+	// Add trace IDs to records logged with the context of a traced request
+	return slog.New(slogtrace.NewTraceHandler(log.Handler()))
 }
 
 func newSlogpretty() *slog.Logger {
@@ -118,34 +281,314 @@ func newSlogpretty() *slog.Logger {
 	return slog.New(handler)
 }
 
-func newRouter(cfg *config.Config, storage *sqlite.Sqlite) *chi.Mux {
+func newRouter(
+	cfg *config.Config,
+	storage *sqlite.Sqlite,
+	shortURLs *shorturl.Builder,
+	metrics *metrics.Metrics,
+	jwtVerifier *jwtauth.Verifier,
+	clientIPs *clientip.Resolver,
+	webhooks *webhook.Dispatcher,
+	clicks *clickhub.Hub,
+	clickRecorder *analytics.Recorder,
+	bots *botdetect.Detector,
+	healthState *health.State,
+) *chi.Mux {
 	r := chi.NewRouter()
 
 	r.Use(middleware.RequestID)
+	r.Use(http_middleware.Tracing)
+	r.Use(http_middleware.Metrics(metrics))
 	r.Use(middleware.Recoverer)
 	r.Use(middleware.URLFormat)
 	r.Use(http_middleware.Logger)
 
-	r.Get("/{alias}", redirect.New(storage))
+	r.Get("/healthz", live.New())
+	r.Get("/readyz", ready.New(storage, storage, healthState))
+
+	scanCfg := scanguard.Config{
+		Threshold: cfg.ScanGuard.Threshold,
+		Window:    cfg.ScanGuard.Window,
+		BlockFor:  cfg.ScanGuard.BlockFor,
+	}
+	scanGuard := scanguard.New(scanCfg)
+
+	r.Group(func(r chi.Router) {
+		if scanCfg.Enabled() {
+			r.Use(http_middleware.ScanGuard(scanGuard, clientIPs))
+		}
+		if rate := newRate(cfg.RateLimit.Redirect); rate.Enabled() {
+			r.Use(http_middleware.RateLimit(ratelimit.NewMemory(), rate, http_middleware.ByClientIP(clientIPs)))
+		}
+
+		// HEAD requests of link checkers are redirected too, and counted as bots.
+		redirectHandler := redirect.New(storage, metrics, webhooks, clicks, clickRecorder, bots)
+		r.Get("/{alias}", redirectHandler)
+		r.Head("/{alias}", redirectHandler)
+		r.Get("/{alias}/qr", qr.New(storage, shortURLs))
+	})
+
+	if cfg.Metrics.Address == "" {
+		r.Handle("/metrics", metrics.Handler())
+	}
+
+	limits := quota.Limits{
+		LinksPerDay: cfg.Quota.LinksPerDay,
+		ActiveLinks: cfg.Quota.ActiveLinks,
+	}
+
+	basicUsers := map[string]string{
+		cfg.HTTPServer.User: cfg.HTTPServer.Password,
+	}
+
+	auditor := audit.New(storage, clientIPs)
+
+	// The per-client limit runs before Auth and is shared by all authenticated
+	// groups, so guessing credentials on any of them counts against one budget.
+	clientLimit := func(r chi.Router) {}
+	if rate := newRate(cfg.RateLimit.Client); rate.Enabled() {
+		limiter := ratelimit.NewMemory()
+		clientLimit = func(r chi.Router) {
+			r.Use(http_middleware.RateLimit(limiter, rate, http_middleware.ByClientIP(clientIPs)))
+		}
+	}
 
 	r.Route("/url", func(r chi.Router) {
-		r.Use(middleware.BasicAuth("url-shortener", map[string]string{
-			cfg.HTTPServer.User: cfg.HTTPServer.Password,
-		}))
+		clientLimit(r)
+		if jwtVerifier != nil {
+			r.Use(http_middleware.JWT(jwtVerifier, storage))
+		}
+		r.Use(http_middleware.Auth(storage, basicUsers, cfg.BasicAuthFallback))
+		if rate := newRate(cfg.RateLimit.API); rate.Enabled() {
+			r.Use(http_middleware.RateLimit(ratelimit.NewMemory(), rate, http_middleware.ByAPIKey(clientIPs)))
+		}
+
+		r.With(http_middleware.Require(auth.PermLinkRead)).Get("/", list.New(storage, shortURLs))
+		r.With(http_middleware.Require(auth.PermLinkCreate)).Post("/", save.New(storage, shortURLs, limits, metrics, auditor, webhooks))
+		r.With(http_middleware.Require(auth.PermLinkRead)).Get("/usage", usage.New(storage, limits))
+		r.With(http_middleware.Require(auth.PermLinkRead)).Get("/{alias}", info.New(storage, shortURLs))
+		r.With(http_middleware.Require(auth.PermStatsRead)).Get("/{alias}/events", linkevents.New(storage, clicks, cfg.ClickStream.Heartbeat))
+		r.With(http_middleware.Require(auth.PermStatsRead)).Get("/{alias}/analytics", linkanalytics.New(storage))
+		r.With(http_middleware.Require(auth.PermLinkUpdate)).Patch("/{alias}", update.New(storage, auditor))
+		r.With(http_middleware.Require(auth.PermLinkDelete)).Delete("/{alias}", delete.New(storage, metrics, auditor, webhooks))
+		r.With(http_middleware.Require(auth.PermLinkDelete)).Post("/{alias}/restore", restore.New(storage, limits, auditor))
+
+		r.With(http_middleware.Require(auth.PermStatsRead)).Get("/tags", tagstats.New(storage))
+		r.With(http_middleware.Require(auth.PermLinkDelete)).Post("/tags/{tag}/delete", tagdelete.New(storage, metrics, auditor, webhooks))
+		r.With(http_middleware.Require(auth.PermLinkUpdate)).Post("/tags/{tag}/expire", tagexpire.New(storage, auditor))
+		r.With(http_middleware.Require(auth.PermLinkUpdate)).Post("/tags/{tag}/retag", tagretag.New(storage, auditor))
+	})
+
+	r.Route("/audit", func(r chi.Router) {
+		clientLimit(r)
+		r.Use(http_middleware.Auth(storage, basicUsers, true))
 
-		r.Post("/", save.New(storage))
-		r.Delete("/{alias}", delete.New(storage))
+		r.With(http_middleware.Require(auth.PermAuditRead)).Get("/", auditlist.New(storage))
 	})
 
+	r.Route("/events", func(r chi.Router) {
+		clientLimit(r)
+		r.Use(http_middleware.Auth(storage, basicUsers, true))
+
+		r.With(http_middleware.Require(auth.PermEventsRead)).Get("/", allevents.New(clicks, cfg.ClickStream.Heartbeat))
+	})
+
+	r.Route("/admin", func(r chi.Router) {
+		clientLimit(r)
+		r.Use(http_middleware.Auth(storage, basicUsers, true))
+
+		r.With(http_middleware.Require(auth.PermDomainManage)).Get("/domains", domainlist.New(storage))
+		r.With(http_middleware.Require(auth.PermDomainManage)).Post("/domains", domainsave.New(storage))
+		r.With(http_middleware.Require(auth.PermDomainManage)).Delete("/domains/{id}", domaindelete.New(storage))
+
+		r.With(http_middleware.Require(auth.PermAPIKeyManage)).Get("/api-keys", apikeylist.New(storage))
+		r.With(http_middleware.Require(auth.PermAPIKeyManage)).Post("/api-keys", apikeycreate.New(storage))
+		r.With(http_middleware.Require(auth.PermAPIKeyManage)).Delete("/api-keys/{id}", apikeyrevoke.New(storage))
+
+		r.With(http_middleware.Require(auth.PermUserManage)).Get("/users", userlist.New(storage))
+		r.With(http_middleware.Require(auth.PermUserManage)).Post("/users", usercreate.New(storage))
+		r.With(http_middleware.Require(auth.PermUserManage)).Delete("/users/{id}", userdelete.New(storage))
+
+		r.With(http_middleware.Require(auth.PermWebhookManage)).Get("/webhooks", webhooklist.New(storage))
+		r.With(http_middleware.Require(auth.PermWebhookManage)).Post("/webhooks", webhookcreate.New(storage))
+		r.With(http_middleware.Require(auth.PermWebhookManage)).Delete("/webhooks/{id}", webhookdelete.New(storage))
+		r.With(http_middleware.Require(auth.PermWebhookManage)).Get("/webhooks/{id}/deliveries", webhookdeliveries.New(storage))
+
+		r.With(http_middleware.Require(auth.PermClientManage)).Get("/blocked-clients", blockedlist.New(scanGuard))
+		r.With(http_middleware.Require(auth.PermClientManage)).Delete("/blocked-clients", blockeddelete.New(scanGuard))
+	})
+
+	return r
+}
+
+func newMetricsRouter(metrics *metrics.Metrics) *chi.Mux {
+	r := chi.NewRouter()
+
+	r.Use(middleware.Recoverer)
+
+	r.Handle("/metrics", metrics.Handler())
+
 	return r
 }
 
 func newStorage(cfg *config.Config) *sqlite.Sqlite {
-	storage, err := sqlite.New(cfg.StoragePath)
+	storage, err := sqlite.New(cfg.StoragePath, storageOptions(cfg))
 	if err != nil {
 		slog.Error("failed to init storage", slogerr.Error(err))
 		os.Exit(1)
 	}
 
+	if cfg.AutoMigrate {
+		if err := storage.MigrateUp(context.Background()); err != nil {
+			slog.Error("failed to migrate storage", slogerr.Error(err))
+			os.Exit(1)
+		}
+
+		slog.Info("storage schema is up to date", slog.Int("version", sqlite.LatestSchemaVersion()))
+	}
+
+	if err := storage.Prepare(context.Background()); err != nil {
+		slog.Error("failed to prepare storage queries", slogerr.Error(err))
+		os.Exit(1)
+	}
+
 	return storage
 }
+
+func storageOptions(cfg *config.Config) sqlite.Options {
+	return sqlite.Options{
+		ReadTimeout:     cfg.Storage.ReadTimeout,
+		WriteTimeout:    cfg.Storage.WriteTimeout,
+		JournalMode:     cfg.Storage.JournalMode,
+		Synchronous:     cfg.Storage.Synchronous,
+		BusyTimeout:     cfg.Storage.BusyTimeout,
+		MaxOpenConns:    cfg.Storage.MaxOpenConns,
+		MaxIdleConns:    cfg.Storage.MaxIdleConns,
+		ConnMaxLifetime: cfg.Storage.ConnMaxLifetime,
+		Tombstones:      cfg.Retention.Tombstones.Enabled,
+	}
+}
+
+func newMetrics(storage *sqlite.Sqlite) *metrics.Metrics {
+	m := metrics.New()
+
+	storage.SetObserver(m)
+	m.RegisterDB("sqlite", storage.Stats)
+
+	return m
+}
+
+func newTracing(cfg *config.Config) func(context.Context) error {
+	shutdown, err := tracing.Setup(context.Background(), tracing.Config{
+		ServiceName: cfg.Tracing.ServiceName,
+		Exporter:    cfg.Tracing.Exporter,
+		File:        cfg.Tracing.File,
+		Endpoint:    cfg.Tracing.Endpoint,
+		Insecure:    cfg.Tracing.Insecure,
+		SampleRatio: cfg.Tracing.SampleRatio,
+	})
+	if err != nil {
+		slog.Error("failed to init tracing", slogerr.Error(err))
+		os.Exit(1)
+	}
+
+	return shutdown
+}
+
+func newShortURLs(cfg *config.Config) *shorturl.Builder {
+	shortURLs, err := shorturl.New(cfg.BaseURL, cfg.AllowedHosts)
+	if err != nil {
+		slog.Error("failed to init short urls", slogerr.Error(err))
+		os.Exit(1)
+	}
+
+	return shortURLs
+}
+
+// newJWTVerifier returns nil when JWT authentication is not configured.
+func newJWTVerifier(cfg *config.Config) *jwtauth.Verifier {
+	if !cfg.JWT.Enabled() {
+		return nil
+	}
+
+	verifier, err := jwtauth.New(jwtauth.Config{
+		HMACSecret:    cfg.JWT.HMACSecret,
+		PublicKeyFile: cfg.JWT.PublicKeyFile,
+		JWKSFile:      cfg.JWT.JWKSFile,
+		Issuer:        cfg.JWT.Issuer,
+		Audience:      cfg.JWT.Audience,
+		ClockSkew:     cfg.JWT.ClockSkew,
+		RoleClaim:     cfg.JWT.RoleClaim,
+	})
+	if err != nil {
+		slog.Error("failed to init jwt verifier", slogerr.Error(err))
+		os.Exit(1)
+	}
+
+	return verifier
+}
+
+func newWebhooks(cfg *config.Config, storage *sqlite.Sqlite) *webhook.Dispatcher {
+	client := &http.Client{Timeout: cfg.Webhooks.Timeout}
+
+	return webhook.New(storage, client, webhook.Config{
+		Interval:    cfg.Webhooks.Interval,
+		Timeout:     cfg.Webhooks.Timeout,
+		MaxAttempts: cfg.Webhooks.MaxAttempts,
+		MinBackoff:  cfg.Webhooks.MinBackoff,
+		MaxBackoff:  cfg.Webhooks.MaxBackoff,
+		BatchSize:   cfg.Webhooks.BatchSize,
+		Buffer:      cfg.Webhooks.Buffer,
+	}, slog.Default())
+}
+
+// newGeoIP returns nil, which knows no countries, when no database is configured.
+func newGeoIP(cfg *config.Config) *geoip.DB {
+	if cfg.Analytics.GeoIPFile == "" {
+		return nil
+	}
+
+	db, err := geoip.Open(cfg.Analytics.GeoIPFile)
+	if err != nil {
+		slog.Error("failed to open geoip database", slogerr.Error(err))
+		os.Exit(1)
+	}
+
+	return db
+}
+
+func newClickRecorder(cfg *config.Config, storage *sqlite.Sqlite, clientIPs *clientip.Resolver, geoIP *geoip.DB) *analytics.Recorder {
+	return analytics.New(storage, clientIPs, geoIP, analytics.Config{
+		Buffer:        cfg.Analytics.Buffer,
+		BatchSize:     cfg.Analytics.BatchSize,
+		FlushInterval: cfg.Analytics.FlushInterval,
+	}, slog.Default())
+}
+
+func newBotDetector(cfg *config.Config) *botdetect.Detector {
+	bots, err := botdetect.New(cfg.Analytics.BotsFile, cfg.Analytics.BotsReloadInterval, slog.Default())
+	if err != nil {
+		slog.Error("failed to load bot patterns", slogerr.Error(err))
+		os.Exit(1)
+	}
+
+	return bots
+}
+
+func newClientIPs(cfg *config.Config) *clientip.Resolver {
+	clientIPs, err := clientip.New(cfg.RateLimit.TrustedProxies)
+	if err != nil {
+		slog.Error("failed to init client address resolution", slogerr.Error(err))
+		os.Exit(1)
+	}
+
+	return clientIPs
+}
+
+func newRate(rate config.Rate) ratelimit.Rate {
+	return ratelimit.Rate{
+		Requests: rate.Requests,
+		Period:   rate.Period,
+		Burst:    rate.Burst,
+	}
+}
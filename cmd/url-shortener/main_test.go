@@ -0,0 +1,1063 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+
+	"github.com/dkhrunov/url-shortener/internal/auth"
+	"github.com/dkhrunov/url-shortener/internal/config"
+	"github.com/dkhrunov/url-shortener/internal/lib/apikey"
+	"github.com/dkhrunov/url-shortener/internal/lib/clickhub"
+	"github.com/dkhrunov/url-shortener/internal/lib/clientip"
+	"github.com/dkhrunov/url-shortener/internal/lib/health"
+	"github.com/dkhrunov/url-shortener/internal/lib/jwtauth"
+	"github.com/dkhrunov/url-shortener/internal/lib/qrcode"
+	"github.com/dkhrunov/url-shortener/internal/lib/shorturl"
+	"github.com/dkhrunov/url-shortener/internal/lib/webhook"
+	storagepkg "github.com/dkhrunov/url-shortener/internal/storage"
+	"github.com/dkhrunov/url-shortener/internal/storage/sqlite"
+	"github.com/dkhrunov/url-shortener/internal/transport/http/common/response"
+	linkanalytics "github.com/dkhrunov/url-shortener/internal/transport/http/handlers/url/analytics"
+)
+
+func TestRouterPermissions(t *testing.T) {
+	storage := newTestStorage(t)
+
+	shortURLs, err := shorturl.New("", nil)
+	require.NoError(t, err)
+
+	cfg := &config.Config{
+		HTTPServer:  config.HTTPServer{User: "root", Password: "secret"},
+		Auth:        config.Auth{BasicAuthFallback: true},
+		ClickStream: config.ClickStream{Heartbeat: time.Minute},
+	}
+
+	router := newRouter(cfg, storage, shortURLs, newMetrics(storage), nil, newTestClientIPs(t), newWebhooks(cfg, storage), clickhub.New(16), newClickRecorder(cfg, storage, newTestClientIPs(t), nil), newBotDetector(cfg), &health.State{})
+
+	roles := []auth.Role{auth.RoleViewer, auth.RoleEditor, auth.RoleAdmin}
+
+	// One API key per role.
+	keys := make(map[auth.Role]string, len(roles))
+	for _, role := range roles {
+		userID, err := storage.SaveUser(context.Background(), string(role), "-", string(role))
+		require.NoError(t, err)
+
+		key, prefix, err := apikey.Generate()
+		require.NoError(t, err)
+
+		_, err = storage.SaveAPIKey(context.Background(), string(role), prefix, apikey.Hash(key), userID)
+		require.NoError(t, err)
+
+		keys[role] = key
+	}
+
+	all := roles
+	editors := []auth.Role{auth.RoleEditor, auth.RoleAdmin}
+	admins := []auth.Role{auth.RoleAdmin}
+
+	routes := []struct {
+		method  string
+		path    string
+		body    string
+		allowed []auth.Role
+	}{
+		{http.MethodGet, "/url/", "", all},
+		{http.MethodGet, "/url/test_alias", "", all},
+		{http.MethodGet, "/url/usage", "", all},
+		{http.MethodGet, "/url/test_alias/events", "", all},
+		{http.MethodGet, "/url/test_alias/analytics", "", all},
+		{http.MethodPost, "/url/", `{"url": "https://go.dev"}`, editors},
+		{http.MethodPatch, "/url/test_alias", `{"url": "https://go.dev"}`, editors},
+		{http.MethodDelete, "/url/test_alias", "", editors},
+		{http.MethodPost, "/url/test_alias/restore", "", editors},
+		{http.MethodGet, "/url/tags", "", all},
+		{http.MethodPost, "/url/tags/sale/delete", "", editors},
+		{http.MethodPost, "/url/tags/sale/expire", "", editors},
+		{http.MethodPost, "/url/tags/sale/retag", `{"tag": "spring-sale"}`, editors},
+		{http.MethodGet, "/audit", "", admins},
+		{http.MethodGet, "/events", "", admins},
+		{http.MethodGet, "/admin/domains", "", admins},
+		{http.MethodPost, "/admin/domains", `{"host": "brand.example"}`, admins},
+		{http.MethodDelete, "/admin/domains/1000", "", admins},
+		{http.MethodGet, "/admin/api-keys", "", admins},
+		{http.MethodPost, "/admin/api-keys", `{"name": "ci"}`, admins},
+		{http.MethodDelete, "/admin/api-keys/1000", "", admins},
+		{http.MethodGet, "/admin/users", "", admins},
+		{http.MethodPost, "/admin/users", `{"username": "bob", "password": "bob-password", "role": "viewer"}`, admins},
+		{http.MethodDelete, "/admin/users/1000", "", admins},
+		{http.MethodGet, "/admin/webhooks", "", admins},
+		{http.MethodPost, "/admin/webhooks", `{"url": "https://hooks.example", "events": ["link.created"]}`, admins},
+		{http.MethodDelete, "/admin/webhooks/1000", "", admins},
+		{http.MethodGet, "/admin/webhooks/1000/deliveries", "", admins},
+		{http.MethodGet, "/admin/blocked-clients", "", admins},
+		{http.MethodDelete, "/admin/blocked-clients?client=203.0.113.7", "", admins},
+	}
+
+	// The event streams of allowed roles are ended by the deadline.
+	newRequest := func(t *testing.T, method, path, body string) *http.Request {
+		ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+		t.Cleanup(cancel)
+
+		return httptest.NewRequest(method, path, strings.NewReader(body)).WithContext(ctx)
+	}
+
+	for _, route := range routes {
+		route := route
+
+		t.Run(fmt.Sprintf("%s %s without credentials", route.method, route.path), func(t *testing.T) {
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, newRequest(t, route.method, route.path, route.body))
+
+			assert.Equal(t, http.StatusUnauthorized, w.Code)
+		})
+
+		for _, role := range roles {
+			role := role
+
+			t.Run(fmt.Sprintf("%s %s as %s", route.method, route.path, role), func(t *testing.T) {
+				r := newRequest(t, route.method, route.path, route.body)
+				r.Header.Set("X-API-Key", keys[role])
+
+				w := httptest.NewRecorder()
+				router.ServeHTTP(w, r)
+
+				if slices.Contains(route.allowed, role) {
+					assert.NotEqual(t, http.StatusForbidden, w.Code)
+				} else {
+					assert.Equal(t, http.StatusForbidden, w.Code)
+					assert.JSONEq(t, `{"status": "Error", "error": "forbidden"}`, w.Body.String())
+				}
+			})
+		}
+	}
+}
+
+func TestRouterJWT(t *testing.T) {
+	const secret = "top-secret-hmac-key"
+
+	storage := newTestStorage(t)
+
+	shortURLs, err := shorturl.New("", nil)
+	require.NoError(t, err)
+
+	cfg := &config.Config{
+		HTTPServer: config.HTTPServer{User: "root", Password: "secret"},
+	}
+
+	verifier, err := jwtauth.New(jwtauth.Config{HMACSecret: secret, ClockSkew: time.Minute, RoleClaim: "role"})
+	require.NoError(t, err)
+
+	router := newRouter(cfg, storage, shortURLs, newMetrics(storage), verifier, newTestClientIPs(t), newWebhooks(cfg, storage), clickhub.New(16), newClickRecorder(cfg, storage, newTestClientIPs(t), nil), newBotDetector(cfg), &health.State{})
+
+	sign := func(sub, role string, exp time.Time) string {
+		token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+			"sub":  sub,
+			"role": role,
+			"exp":  exp.Unix(),
+		}).SignedString([]byte(secret))
+		require.NoError(t, err)
+
+		return token
+	}
+
+	now := time.Now()
+
+	cases := []struct {
+		name      string
+		method    string
+		path      string
+		token     string
+		status    int
+		respError string
+	}{
+		{
+			name:   "Editor creates link",
+			method: http.MethodPost,
+			path:   "/url/",
+			token:  sign("alice", "editor", now.Add(time.Hour)),
+			status: http.StatusOK,
+		},
+		{
+			name:   "Editor lists own links",
+			method: http.MethodGet,
+			path:   "/url/",
+			token:  sign("alice", "editor", now.Add(time.Hour)),
+			status: http.StatusOK,
+		},
+		{
+			name:      "Viewer can't create links",
+			method:    http.MethodPost,
+			path:      "/url/",
+			token:     sign("carol", "viewer", now.Add(time.Hour)),
+			status:    http.StatusForbidden,
+			respError: "forbidden",
+		},
+		{
+			name:      "Expired",
+			method:    http.MethodGet,
+			path:      "/url/",
+			token:     sign("alice", "editor", now.Add(-time.Hour)),
+			status:    http.StatusUnauthorized,
+			respError: "token expired",
+		},
+		{
+			name:      "Not accepted by the admin API",
+			method:    http.MethodGet,
+			path:      "/admin/users",
+			token:     sign("alice", "admin", now.Add(time.Hour)),
+			status:    http.StatusUnauthorized,
+			respError: "invalid api key",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			r := httptest.NewRequest(tc.method, tc.path, strings.NewReader(`{"url": "https://go.dev"}`))
+			r.Header.Set("Authorization", "Bearer "+tc.token)
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, r)
+
+			assert.Equal(t, tc.status, w.Code)
+
+			var resp response.Response
+
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+
+			assert.Equal(t, tc.respError, resp.Error)
+		})
+	}
+}
+
+func TestRouterRateLimit(t *testing.T) {
+	storage := newTestStorage(t)
+
+	_, err := storage.SaveURL(context.Background(), "", "https://go.dev", "go", 0, 0, nil, storagepkg.LinkLimits{})
+	require.NoError(t, err)
+
+	shortURLs, err := shorturl.New("", nil)
+	require.NoError(t, err)
+
+	cfg := &config.Config{
+		HTTPServer: config.HTTPServer{User: "root", Password: "secret"},
+		RateLimit: config.RateLimit{
+			Redirect: config.Rate{Requests: 2, Period: time.Minute},
+		},
+	}
+
+	router := newRouter(cfg, storage, shortURLs, newMetrics(storage), nil, newTestClientIPs(t), newWebhooks(cfg, storage), clickhub.New(16), newClickRecorder(cfg, storage, newTestClientIPs(t), nil), newBotDetector(cfg), &health.State{})
+
+	get := func(remoteAddr string) *httptest.ResponseRecorder {
+		r := httptest.NewRequest(http.MethodGet, "/go", nil)
+		r.RemoteAddr = remoteAddr
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, r)
+
+		return w
+	}
+
+	assert.Equal(t, http.StatusFound, get("203.0.113.7:1000").Code)
+	assert.Equal(t, http.StatusFound, get("203.0.113.7:1001").Code)
+
+	w := get("203.0.113.7:1002")
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+	assert.NotEmpty(t, w.Header().Get("Retry-After"))
+
+	// Other clients have their own limit.
+	assert.Equal(t, http.StatusFound, get("198.51.100.1:1000").Code)
+
+	// The /url group is not limited.
+	r := httptest.NewRequest(http.MethodGet, "/url/", nil)
+	r.RemoteAddr = "203.0.113.7:1003"
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, r)
+
+	assert.NotEqual(t, http.StatusTooManyRequests, w.Code)
+}
+
+func TestRouterClientRateLimit(t *testing.T) {
+	storage := newTestStorage(t)
+
+	shortURLs, err := shorturl.New("", nil)
+	require.NoError(t, err)
+
+	cfg := &config.Config{
+		HTTPServer: config.HTTPServer{User: "root", Password: "secret"},
+		Auth:       config.Auth{BasicAuthFallback: true},
+		RateLimit: config.RateLimit{
+			Client: config.Rate{Requests: 3, Period: time.Minute},
+		},
+	}
+
+	router := newRouter(cfg, storage, shortURLs, newMetrics(storage), nil, newTestClientIPs(t), newWebhooks(cfg, storage), clickhub.New(16), newClickRecorder(cfg, storage, newTestClientIPs(t), nil), newBotDetector(cfg), &health.State{})
+
+	serve := func(path, remoteAddr, password string) int {
+		r := httptest.NewRequest(http.MethodGet, path, nil)
+		r.RemoteAddr = remoteAddr
+		r.SetBasicAuth("root", password)
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, r)
+
+		return w.Code
+	}
+
+	// Failed attempts count against the limit, on every authenticated group.
+	assert.Equal(t, http.StatusUnauthorized, serve("/url/", "203.0.113.7:1000", "wrong"))
+	assert.Equal(t, http.StatusUnauthorized, serve("/admin/users", "203.0.113.7:1001", "wrong"))
+	assert.Equal(t, http.StatusUnauthorized, serve("/audit/", "203.0.113.7:1002", "wrong"))
+	assert.Equal(t, http.StatusTooManyRequests, serve("/events/", "203.0.113.7:1003", "wrong"))
+	assert.Equal(t, http.StatusTooManyRequests, serve("/url/", "203.0.113.7:1004", "secret"))
+
+	// Other clients have their own limit.
+	assert.Equal(t, http.StatusOK, serve("/url/", "198.51.100.1:1000", "secret"))
+
+	// The public routes are not limited by it.
+	assert.Equal(t, http.StatusNotFound, serve("/missing", "203.0.113.7:1005", ""))
+}
+
+func TestRouterSoftDelete(t *testing.T) {
+	storage := newTestStorage(t)
+
+	_, err := storage.SaveURL(context.Background(), "", "https://go.dev", "go", 0, 0, nil, storagepkg.LinkLimits{})
+	require.NoError(t, err)
+
+	shortURLs, err := shorturl.New("", nil)
+	require.NoError(t, err)
+
+	cfg := &config.Config{
+		HTTPServer: config.HTTPServer{User: "root", Password: "secret"},
+		Auth:       config.Auth{BasicAuthFallback: true},
+	}
+
+	router := newRouter(cfg, storage, shortURLs, newMetrics(storage), nil, newTestClientIPs(t), newWebhooks(cfg, storage), clickhub.New(16), newClickRecorder(cfg, storage, newTestClientIPs(t), nil), newBotDetector(cfg), &health.State{})
+
+	serve := func(method, path, body string) int {
+		r := httptest.NewRequest(method, path, strings.NewReader(body))
+		r.SetBasicAuth("root", "secret")
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, r)
+
+		return w.Code
+	}
+
+	assert.Equal(t, http.StatusOK, serve(http.MethodDelete, "/url/go", ""))
+	assert.Equal(t, http.StatusNotFound, serve(http.MethodGet, "/go", ""))
+
+	// Deleted links can't be deleted again, and keep their alias until purged.
+	assert.Equal(t, http.StatusNotFound, serve(http.MethodDelete, "/url/go", ""))
+	assert.Equal(t, http.StatusConflict, serve(http.MethodPost, "/url/", `{"url": "https://go.dev", "alias": "go"}`))
+
+	assert.Equal(t, http.StatusOK, serve(http.MethodPost, "/url/go/restore", ""))
+	assert.Equal(t, http.StatusFound, serve(http.MethodGet, "/go", ""))
+	assert.Equal(t, http.StatusNotFound, serve(http.MethodPost, "/url/go/restore", ""))
+
+	assert.Equal(t, http.StatusNotFound, serve(http.MethodDelete, "/url/missing", ""))
+}
+
+func TestRouterQR(t *testing.T) {
+	storage := newTestStorage(t)
+
+	_, err := storage.SaveDomain(context.Background(), "brand.example")
+	require.NoError(t, err)
+
+	_, err = storage.SaveURL(context.Background(), "brand.example", "https://brand.example/sale", "abc", 0, 0, nil, storagepkg.LinkLimits{})
+	require.NoError(t, err)
+	_, err = storage.SaveURL(context.Background(), "", "https://other.example", "abc", 0, 0, nil, storagepkg.LinkLimits{})
+	require.NoError(t, err)
+	_, err = storage.SaveURL(context.Background(), "", "https://go.dev", "go", 0, 0, nil, storagepkg.LinkLimits{})
+	require.NoError(t, err)
+
+	shortURLs, err := shorturl.New("https://sho.rt", nil)
+	require.NoError(t, err)
+
+	cfg := &config.Config{}
+
+	router := newRouter(cfg, storage, shortURLs, newMetrics(storage), nil, newTestClientIPs(t), newWebhooks(cfg, storage), clickhub.New(16), newClickRecorder(cfg, storage, newTestClientIPs(t), nil), newBotDetector(cfg), &health.State{})
+
+	get := func(host, path string) *httptest.ResponseRecorder {
+		r := httptest.NewRequest(http.MethodGet, path, nil)
+		r.Host = host
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, r)
+
+		return w
+	}
+
+	want := func(shortURL string) []byte {
+		img, err := qrcode.Encode(shortURL, qrcode.FormatSVG, qrcode.Options{Size: 256, Level: qrcode.LevelMedium, Margin: 4})
+		require.NoError(t, err)
+
+		return img
+	}
+
+	// The alias is resolved in the namespace of the domain the request was sent to, and
+	// the code encodes the link on that domain.
+	w := get("brand.example", "/abc/qr?format=svg")
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, want("https://brand.example/abc"), w.Body.Bytes())
+
+	w = get("sho.rt", "/abc/qr?format=svg")
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, want("https://sho.rt/abc"), w.Body.Bytes())
+
+	// Links of the default namespace aren't served on a registered domain.
+	assert.Equal(t, http.StatusNotFound, get("brand.example", "/go/qr").Code)
+	assert.Equal(t, http.StatusNotFound, get("sho.rt", "/missing/qr").Code)
+}
+
+func TestRouterTags(t *testing.T) {
+	storage := newTestStorage(t)
+
+	shortURLs, err := shorturl.New("", nil)
+	require.NoError(t, err)
+
+	cfg := &config.Config{
+		HTTPServer: config.HTTPServer{User: "root", Password: "secret"},
+		Auth:       config.Auth{BasicAuthFallback: true},
+	}
+
+	router := newRouter(cfg, storage, shortURLs, newMetrics(storage), nil, newTestClientIPs(t), newWebhooks(cfg, storage), clickhub.New(16), newClickRecorder(cfg, storage, newTestClientIPs(t), nil), newBotDetector(cfg), &health.State{})
+
+	serve := func(method, path, body string) (int, string) {
+		r := httptest.NewRequest(method, path, strings.NewReader(body))
+		r.SetBasicAuth("root", "secret")
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, r)
+
+		return w.Code, w.Body.String()
+	}
+
+	code, _ := serve(http.MethodPost, "/url/", `{"url": "https://go.dev", "alias": "go", "tags": ["Sale"]}`)
+	require.Equal(t, http.StatusOK, code)
+	code, _ = serve(http.MethodPost, "/url/", `{"url": "https://pkg.go.dev", "alias": "pkg"}`)
+	require.Equal(t, http.StatusOK, code)
+	code, _ = serve(http.MethodPatch, "/url/pkg", `{"tags": ["sale", "docs"]}`)
+	require.Equal(t, http.StatusOK, code)
+
+	code, body := serve(http.MethodGet, "/url/?tag=docs", "")
+	assert.Equal(t, http.StatusOK, code)
+	assert.Contains(t, body, `"alias":"pkg"`)
+	assert.NotContains(t, body, `"alias":"go"`)
+
+	code, body = serve(http.MethodGet, "/url/tags", "")
+	assert.Equal(t, http.StatusOK, code)
+	assert.Contains(t, body, `{"tag":"sale","links":2,"expired":0,"deleted":0}`)
+
+	code, _ = serve(http.MethodPost, "/url/tags/sale/retag", `{"tag": "spring-sale"}`)
+	assert.Equal(t, http.StatusOK, code)
+
+	code, _ = serve(http.MethodPost, "/url/tags/spring-sale/expire", "")
+	assert.Equal(t, http.StatusOK, code)
+	code, _ = serve(http.MethodGet, "/go", "")
+	assert.Equal(t, http.StatusGone, code)
+
+	code, _ = serve(http.MethodPost, "/url/tags/docs/delete", "")
+	assert.Equal(t, http.StatusOK, code)
+	code, _ = serve(http.MethodGet, "/pkg", "")
+	assert.Equal(t, http.StatusNotFound, code)
+
+	code, _ = serve(http.MethodPost, "/url/tags/docs/delete", "")
+	assert.Equal(t, http.StatusNotFound, code)
+}
+
+func TestRouterAudit(t *testing.T) {
+	storage := newTestStorage(t)
+
+	shortURLs, err := shorturl.New("", nil)
+	require.NoError(t, err)
+
+	cfg := &config.Config{
+		HTTPServer: config.HTTPServer{User: "root", Password: "secret"},
+		Auth:       config.Auth{BasicAuthFallback: true},
+	}
+
+	router := newRouter(cfg, storage, shortURLs, newMetrics(storage), nil, newTestClientIPs(t), newWebhooks(cfg, storage), clickhub.New(16), newClickRecorder(cfg, storage, newTestClientIPs(t), nil), newBotDetector(cfg), &health.State{})
+
+	serve := func(method, path, body string) (int, string) {
+		r := httptest.NewRequest(method, path, strings.NewReader(body))
+		r.SetBasicAuth("root", "secret")
+		r.RemoteAddr = "203.0.113.7:4242"
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, r)
+
+		return w.Code, w.Body.String()
+	}
+
+	for _, req := range []struct{ method, path, body string }{
+		{http.MethodPost, "/url/", `{"url": "https://go.dev", "alias": "go"}`},
+		{http.MethodPatch, "/url/go", `{"url": "https://go.dev/doc", "tags": ["docs"]}`},
+		{http.MethodDelete, "/url/go", ""},
+		{http.MethodPost, "/url/go/restore", ""},
+		{http.MethodPost, "/url/", `{"url": "https://pkg.go.dev", "alias": "pkg", "tags": ["sale"]}`},
+		{http.MethodPost, "/url/tags/sale/delete", ""},
+	} {
+		code, body := serve(req.method, req.path, req.body)
+		require.Equal(t, http.StatusOK, code, body)
+	}
+
+	code, body := serve(http.MethodGet, "/audit?alias=go", "")
+	require.Equal(t, http.StatusOK, code)
+
+	var resp struct {
+		Entries []struct {
+			Actor      string          `json:"actor"`
+			Action     string          `json:"action"`
+			Alias      string          `json:"alias"`
+			Before     json.RawMessage `json:"before"`
+			After      json.RawMessage `json:"after"`
+			RequestID  string          `json:"request_id"`
+			RemoteAddr string          `json:"remote_addr"`
+		} `json:"entries"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(body), &resp))
+	require.Len(t, resp.Entries, 4)
+
+	var actions []string
+	for _, e := range resp.Entries {
+		actions = append(actions, e.Action)
+
+		assert.Equal(t, "basic:root", e.Actor)
+		assert.Equal(t, "go", e.Alias)
+		assert.Equal(t, "203.0.113.7", e.RemoteAddr)
+		assert.NotEmpty(t, e.RequestID)
+	}
+	assert.Equal(t, []string{"restore", "delete", "update", "create"}, actions)
+
+	update := resp.Entries[2]
+	assert.JSONEq(t, `{"url": "https://go.dev"}`, string(update.Before))
+	assert.JSONEq(t, `{"url": "https://go.dev/doc", "tags": ["docs"]}`, string(update.After))
+
+	code, body = serve(http.MethodGet, "/audit?action=create", "")
+	require.Equal(t, http.StatusOK, code)
+	assert.Contains(t, body, `"alias":"pkg"`)
+	assert.Equal(t, 2, strings.Count(body, `"action":"create"`))
+
+	// Bulk operations are audited for every link they change.
+	code, body = serve(http.MethodGet, "/audit?alias=pkg&action=delete", "")
+	require.Equal(t, http.StatusOK, code)
+	require.NoError(t, json.Unmarshal([]byte(body), &resp))
+	require.Len(t, resp.Entries, 1)
+	assert.JSONEq(t, `{"url": "https://pkg.go.dev", "tags": ["sale"]}`, string(resp.Entries[0].Before))
+}
+
+func TestRouterWebhooks(t *testing.T) {
+	storage := newTestStorage(t)
+
+	shortURLs, err := shorturl.New("", nil)
+	require.NoError(t, err)
+
+	cfg := &config.Config{
+		HTTPServer: config.HTTPServer{User: "root", Password: "secret"},
+		Auth:       config.Auth{BasicAuthFallback: true},
+		Webhooks: config.Webhooks{
+			Interval:    10 * time.Millisecond,
+			Timeout:     time.Second,
+			MaxAttempts: 3,
+			MinBackoff:  time.Second,
+			MaxBackoff:  time.Second,
+			BatchSize:   16,
+			Buffer:      16,
+		},
+	}
+
+	var secret string
+	events := make(chan webhook.Event, 10)
+
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		timestamp, _ := strconv.ParseInt(r.Header.Get(webhook.HeaderTimestamp), 10, 64)
+		if !webhook.Verify(secret, r.Header.Get(webhook.HeaderSignature), timestamp, body) {
+			w.WriteHeader(http.StatusUnauthorized)
+
+			return
+		}
+
+		var e webhook.Event
+		_ = json.Unmarshal(body, &e)
+		events <- e
+	}))
+	defer receiver.Close()
+
+	metrics := newMetrics(storage)
+
+	webhooks := newWebhooks(cfg, storage)
+	go func() { _ = webhooks.Run() }()
+	defer func() { _ = webhooks.Stop(context.Background()) }()
+
+	router := newRouter(cfg, storage, shortURLs, metrics, nil, newTestClientIPs(t), webhooks, clickhub.New(16), newClickRecorder(cfg, storage, newTestClientIPs(t), nil), newBotDetector(cfg), &health.State{})
+
+	serve := func(method, path, body string) (int, string) {
+		r := httptest.NewRequest(method, path, strings.NewReader(body))
+		r.SetBasicAuth("root", "secret")
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, r)
+
+		return w.Code, w.Body.String()
+	}
+
+	code, body := serve(http.MethodPost, "/admin/webhooks",
+		`{"url": "`+receiver.URL+`", "events": ["link.created", "link.clicked", "link.deleted"]}`)
+	require.Equal(t, http.StatusCreated, code, body)
+
+	var created struct {
+		ID     int64  `json:"id"`
+		Secret string `json:"secret"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(body), &created))
+	require.NotEmpty(t, created.Secret)
+	secret = created.Secret
+
+	// Let the subscriptions cached before the webhook was created expire.
+	time.Sleep(2 * cfg.Webhooks.Interval)
+
+	for _, req := range []struct {
+		method, path, body string
+		status             int
+	}{
+		{http.MethodPost, "/url/", `{"url": "https://go.dev", "alias": "go"}`, http.StatusOK},
+		{http.MethodGet, "/go", "", http.StatusFound},
+		{http.MethodDelete, "/url/go", "", http.StatusOK},
+	} {
+		code, body := serve(req.method, req.path, req.body)
+		require.Equal(t, req.status, code, body)
+	}
+
+	var types []webhook.EventType
+	for len(types) < 3 {
+		select {
+		case e := <-events:
+			types = append(types, e.Type)
+
+			assert.Equal(t, "go", e.Link.Alias)
+			assert.Equal(t, "https://go.dev", e.Link.URL)
+		case <-time.After(2 * time.Second):
+			t.Fatalf("got events %v", types)
+		}
+	}
+	assert.ElementsMatch(t, []webhook.EventType{webhook.EventLinkCreated, webhook.EventLinkClicked, webhook.EventLinkDeleted}, types)
+
+	// Deliveries are logged once they are recorded.
+	require.Eventually(t, func() bool {
+		_, body := serve(http.MethodGet, fmt.Sprintf("/admin/webhooks/%d/deliveries", created.ID), "")
+		return strings.Count(body, `"status_code":200`) == 3
+	}, 2*time.Second, 10*time.Millisecond)
+
+	code, body = serve(http.MethodGet, "/admin/webhooks", "")
+	require.Equal(t, http.StatusOK, code)
+	assert.Contains(t, body, receiver.URL)
+	assert.NotContains(t, body, secret)
+
+	code, _ = serve(http.MethodDelete, fmt.Sprintf("/admin/webhooks/%d", created.ID), "")
+	require.Equal(t, http.StatusOK, code)
+
+	code, _ = serve(http.MethodGet, fmt.Sprintf("/admin/webhooks/%d/deliveries", created.ID), "")
+	assert.Equal(t, http.StatusNotFound, code)
+}
+
+func TestRouterClickStream(t *testing.T) {
+	storage := newTestStorage(t)
+
+	shortURLs, err := shorturl.New("", nil)
+	require.NoError(t, err)
+
+	cfg := &config.Config{
+		HTTPServer:  config.HTTPServer{User: "root", Password: "secret"},
+		Auth:        config.Auth{BasicAuthFallback: true},
+		ClickStream: config.ClickStream{Buffer: 16, Heartbeat: time.Minute},
+	}
+
+	clicks := clickhub.New(cfg.ClickStream.Buffer)
+
+	router := newRouter(cfg, storage, shortURLs, newMetrics(storage), nil, newTestClientIPs(t), newWebhooks(cfg, storage), clicks, newClickRecorder(cfg, storage, newTestClientIPs(t), nil), newBotDetector(cfg), &health.State{})
+
+	// Streams outlive the write timeout of the server.
+	server := httptest.NewUnstartedServer(router)
+	server.Config.WriteTimeout = 50 * time.Millisecond
+	server.Start()
+	defer server.Close()
+
+	client := &http.Client{
+		CheckRedirect: func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse },
+	}
+
+	request := func(method, path, body string, authorize func(r *http.Request)) *http.Response {
+		r, err := http.NewRequest(method, server.URL+path, strings.NewReader(body))
+		require.NoError(t, err)
+		authorize(r)
+
+		resp, err := client.Do(r)
+		require.NoError(t, err)
+
+		return resp
+	}
+	root := func(r *http.Request) { r.SetBasicAuth("root", "secret") }
+
+	resp := request(http.MethodPost, "/url/", `{"url": "https://go.dev", "alias": "go"}`, root)
+	resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	// Streams of all clicks are for admins only.
+	userID, err := storage.SaveUser(context.Background(), "viewer", "-", string(auth.RoleViewer))
+	require.NoError(t, err)
+	key, prefix, err := apikey.Generate()
+	require.NoError(t, err)
+	_, err = storage.SaveAPIKey(context.Background(), "viewer", prefix, apikey.Hash(key), userID)
+	require.NoError(t, err)
+
+	resp = request(http.MethodGet, "/events", "", func(r *http.Request) { r.Header.Set("X-API-Key", key) })
+	resp.Body.Close()
+	assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+
+	link := request(http.MethodGet, "/url/go/events", "", root)
+	defer link.Body.Close()
+	require.Equal(t, http.StatusOK, link.StatusCode)
+	assert.Equal(t, "text/event-stream", link.Header.Get("Content-Type"))
+
+	all := request(http.MethodGet, "/events", "", root)
+	defer all.Body.Close()
+	require.Equal(t, http.StatusOK, all.StatusCode)
+
+	require.Eventually(t, func() bool { return clicks.Subscribers() == 2 }, time.Second, time.Millisecond)
+	time.Sleep(2 * server.Config.WriteTimeout)
+
+	resp = request(http.MethodGet, "/go", "", func(r *http.Request) { r.Header.Set("Referer", "https://news.example/") })
+	resp.Body.Close()
+	require.Equal(t, http.StatusFound, resp.StatusCode)
+
+	for _, stream := range []*http.Response{link, all} {
+		lines := bufio.NewScanner(stream.Body)
+		for lines.Scan() && !strings.HasPrefix(lines.Text(), "data: ") {
+		}
+
+		var click clickhub.Click
+		require.NoError(t, json.Unmarshal([]byte(strings.TrimPrefix(lines.Text(), "data: ")), &click))
+		assert.Equal(t, "go", click.Alias)
+		assert.Equal(t, "https://go.dev", click.URL)
+		assert.Equal(t, "https://news.example/", click.Referrer)
+	}
+
+	// Shutting down ends the streams.
+	clicks.Close()
+
+	_, err = io.ReadAll(link.Body)
+	assert.NoError(t, err)
+}
+
+func TestRouterAnalytics(t *testing.T) {
+	storage := newTestStorage(t)
+
+	shortURLs, err := shorturl.New("", nil)
+	require.NoError(t, err)
+
+	cfg := &config.Config{
+		HTTPServer: config.HTTPServer{User: "root", Password: "secret"},
+		Auth:       config.Auth{BasicAuthFallback: true},
+		Analytics: config.Analytics{
+			GeoIPFile:     "../../internal/lib/geoip/testdata/country-test.mmdb",
+			Buffer:        16,
+			BatchSize:     16,
+			FlushInterval: 10 * time.Millisecond,
+		},
+	}
+
+	metrics := newMetrics(storage)
+
+	geoIP := newGeoIP(cfg)
+	defer geoIP.Close()
+
+	clickRecorder := newClickRecorder(cfg, storage, newTestClientIPs(t), geoIP)
+	go clickRecorder.Run()
+
+	router := newRouter(cfg, storage, shortURLs, metrics, nil, newTestClientIPs(t), newWebhooks(cfg, storage), clickhub.New(16), clickRecorder, newBotDetector(cfg), &health.State{})
+
+	serve := func(method, path, body string, header http.Header) (int, string) {
+		r := httptest.NewRequest(method, path, strings.NewReader(body))
+		r.SetBasicAuth("root", "secret")
+		r.RemoteAddr = "203.0.113.7:4242"
+		for k, v := range header {
+			r.Header[k] = v
+		}
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, r)
+
+		return w.Code, w.Body.String()
+	}
+
+	code, body := serve(http.MethodPost, "/url/", `{"url": "https://go.dev", "alias": "go"}`, nil)
+	require.Equal(t, http.StatusOK, code, body)
+
+	for _, referrer := range []string{"https://news.example/item?id=1", "https://news.example/", ""} {
+		code, _ := serve(http.MethodGet, "/go", "", http.Header{
+			"Referer":    {referrer},
+			"User-Agent": {"Mozilla/5.0 (iPhone; CPU iPhone OS 17_3 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.3 Mobile/15E148 Safari/604.1"},
+		})
+		require.Equal(t, http.StatusFound, code)
+	}
+
+	// Link previews and HEAD requests are redirected but counted as bots.
+	code, _ = serve(http.MethodGet, "/go", "", http.Header{"User-Agent": {"Slackbot-LinkExpanding 1.0 (+https://api.slack.com/robots)"}})
+	require.Equal(t, http.StatusFound, code)
+	code, _ = serve(http.MethodHead, "/go", "", http.Header{"User-Agent": {"Mozilla/5.0"}})
+	require.Equal(t, http.StatusFound, code)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	require.NoError(t, clickRecorder.Stop(ctx))
+
+	code, body = serve(http.MethodGet, "/url/go/analytics?interval=hour&from="+time.Now().Add(-time.Hour).UTC().Format(time.RFC3339), "", nil)
+	require.Equal(t, http.StatusOK, code, body)
+
+	var resp linkanalytics.Response
+	require.NoError(t, json.Unmarshal([]byte(body), &resp))
+
+	assert.Equal(t, int64(3), resp.Total)
+	assert.Equal(t, []linkanalytics.Count{{Value: "news.example", Clicks: 2}, {Value: "", Clicks: 1}}, resp.Referrers)
+	assert.Equal(t, []linkanalytics.Count{{Value: "Safari", Clicks: 3}}, resp.Browsers)
+	assert.Equal(t, []linkanalytics.Count{{Value: "iOS", Clicks: 3}}, resp.OS)
+	assert.Equal(t, []linkanalytics.Count{{Value: "mobile", Clicks: 3}}, resp.Devices)
+	assert.Equal(t, []linkanalytics.Count{{Value: "JP", Clicks: 3}}, resp.Countries)
+
+	var clicks int64
+	for _, b := range resp.Series {
+		clicks += b.Clicks
+	}
+	assert.Equal(t, int64(3), clicks)
+	assert.Len(t, resp.Series, 2)
+	assert.Equal(t, int64(2), resp.Bots)
+
+	code, body = serve(http.MethodGet, "/url/go/analytics?include_bots=true", "", nil)
+	require.Equal(t, http.StatusOK, code, body)
+
+	resp = linkanalytics.Response{}
+	require.NoError(t, json.Unmarshal([]byte(body), &resp))
+
+	assert.True(t, resp.IncludeBots)
+	assert.Equal(t, int64(5), resp.Total)
+	assert.Equal(t, int64(2), resp.Bots)
+	assert.Equal(t, []linkanalytics.Count{{Value: "JP", Clicks: 5}}, resp.Countries)
+}
+
+func TestRouterScanGuard(t *testing.T) {
+	storage := newTestStorage(t)
+
+	_, err := storage.SaveURL(context.Background(), "", "https://go.dev", "go", 0, 0, nil, storagepkg.LinkLimits{})
+	require.NoError(t, err)
+
+	shortURLs, err := shorturl.New("", nil)
+	require.NoError(t, err)
+
+	cfg := &config.Config{
+		HTTPServer: config.HTTPServer{User: "root", Password: "secret"},
+		ScanGuard:  config.ScanGuard{Threshold: 3, Window: time.Minute, BlockFor: time.Hour},
+	}
+
+	router := newRouter(cfg, storage, shortURLs, newMetrics(storage), nil, newTestClientIPs(t), newWebhooks(cfg, storage), clickhub.New(16), newClickRecorder(cfg, storage, newTestClientIPs(t), nil), newBotDetector(cfg), &health.State{})
+
+	serve := func(method, path, remoteAddr string) *httptest.ResponseRecorder {
+		r := httptest.NewRequest(method, path, nil)
+		r.RemoteAddr = remoteAddr
+		r.SetBasicAuth("root", "secret")
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, r)
+
+		return w
+	}
+
+	for _, alias := range []string{"a1", "a2", "a3"} {
+		assert.Equal(t, http.StatusNotFound, serve(http.MethodGet, "/"+alias, "203.0.113.7:1000").Code)
+	}
+
+	// Blocked even for existing links.
+	w := serve(http.MethodGet, "/go", "203.0.113.7:1000")
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+	assert.NotEmpty(t, w.Header().Get("Retry-After"))
+
+	// Other clients are not affected.
+	assert.Equal(t, http.StatusFound, serve(http.MethodGet, "/go", "198.51.100.1:1000").Code)
+
+	w = serve(http.MethodGet, "/admin/blocked-clients", "192.0.2.1:1000")
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"client":"203.0.113.7"`)
+
+	w = serve(http.MethodDelete, "/admin/blocked-clients?client=203.0.113.7", "192.0.2.1:1000")
+	require.Equal(t, http.StatusOK, w.Code)
+
+	assert.Equal(t, http.StatusFound, serve(http.MethodGet, "/go", "203.0.113.7:1000").Code)
+}
+
+func TestRouterMetrics(t *testing.T) {
+	storage := newTestStorage(t)
+
+	_, err := storage.SaveURL(context.Background(), "", "https://go.dev", "go", 0, 0, nil, storagepkg.LinkLimits{})
+	require.NoError(t, err)
+
+	shortURLs, err := shorturl.New("", nil)
+	require.NoError(t, err)
+
+	cfg := &config.Config{
+		HTTPServer: config.HTTPServer{User: "root", Password: "secret"},
+	}
+
+	router := newRouter(cfg, storage, shortURLs, newMetrics(storage), nil, newTestClientIPs(t), newWebhooks(cfg, storage), clickhub.New(16), newClickRecorder(cfg, storage, newTestClientIPs(t), nil), newBotDetector(cfg), &health.State{})
+
+	for _, path := range []string{"/go", "/missing"} {
+		router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, nil))
+	}
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+	require.Equal(t, http.StatusOK, w.Code)
+
+	body := w.Body.String()
+
+	assert.Contains(t, body, `url_shortener_http_requests_total{method="GET",route="/{alias}",status="302"} 1`)
+	assert.Contains(t, body, `url_shortener_http_requests_total{method="GET",route="/{alias}",status="404"} 1`)
+	assert.Contains(t, body, `url_shortener_redirects_total{result="hit"} 1`)
+	assert.Contains(t, body, `url_shortener_redirects_total{result="miss"} 1`)
+	assert.Contains(t, body, `url_shortener_storage_operation_duration_seconds_count{op="storage.sqlite.GetURLByHost"} 2`)
+	assert.Contains(t, body, `url_shortener_db_open_connections{db="sqlite"}`)
+
+	// Served by a listener of its own instead.
+	cfg.Metrics.Address = ":9090"
+
+	router = newRouter(cfg, storage, shortURLs, newMetrics(storage), nil, newTestClientIPs(t), newWebhooks(cfg, storage), clickhub.New(16), newClickRecorder(cfg, storage, newTestClientIPs(t), nil), newBotDetector(cfg), &health.State{})
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestRouterTracing(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+
+	storage := newTestStorage(t)
+
+	_, err := storage.SaveURL(context.Background(), "", "https://go.dev", "go", 0, 0, nil, storagepkg.LinkLimits{})
+	require.NoError(t, err)
+
+	shortURLs, err := shorturl.New("", nil)
+	require.NoError(t, err)
+
+	cfg := &config.Config{
+		HTTPServer: config.HTTPServer{User: "root", Password: "secret"},
+	}
+
+	router := newRouter(cfg, storage, shortURLs, newMetrics(storage), nil, newTestClientIPs(t), newWebhooks(cfg, storage), clickhub.New(16), newClickRecorder(cfg, storage, newTestClientIPs(t), nil), newBotDetector(cfg), &health.State{})
+
+	r := httptest.NewRequest(http.MethodGet, "/go", nil)
+	r.Header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, r)
+
+	require.Equal(t, http.StatusFound, w.Code)
+
+	spans := make(map[string]sdktrace.ReadOnlySpan)
+	for _, span := range recorder.Ended() {
+		spans[span.Name()] = span
+	}
+
+	request, ok := spans["GET /{alias}"]
+	require.True(t, ok)
+
+	get, ok := spans["storage.sqlite.GetURLByHost"]
+	require.True(t, ok)
+
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", request.SpanContext().TraceID().String())
+	assert.Equal(t, request.SpanContext().SpanID(), get.Parent().SpanID())
+}
+
+func TestRouterHealth(t *testing.T) {
+	storage := newTestStorage(t)
+
+	shortURLs, err := shorturl.New("", nil)
+	require.NoError(t, err)
+
+	cfg := &config.Config{
+		HTTPServer: config.HTTPServer{User: "root", Password: "secret"},
+		ScanGuard:  config.ScanGuard{Threshold: 1, Window: time.Minute, BlockFor: time.Hour},
+	}
+
+	healthState := &health.State{}
+
+	router := newRouter(cfg, storage, shortURLs, newMetrics(storage), nil, newTestClientIPs(t), newWebhooks(cfg, storage), clickhub.New(16), newClickRecorder(cfg, storage, newTestClientIPs(t), nil), newBotDetector(cfg), healthState)
+
+	get := func(path string) int {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+
+		return w.Code
+	}
+
+	// Probes are not alias lookups, so they don't count as misses either.
+	assert.Equal(t, http.StatusOK, get("/healthz"))
+	assert.Equal(t, http.StatusOK, get("/readyz"))
+	assert.Equal(t, http.StatusOK, get("/readyz"))
+
+	// A schema behind the code is not ready.
+	require.NoError(t, storage.MigrateDown(context.Background()))
+	assert.Equal(t, http.StatusServiceUnavailable, get("/readyz"))
+
+	require.NoError(t, storage.MigrateUp(context.Background()))
+	assert.Equal(t, http.StatusOK, get("/readyz"))
+
+	healthState.StartShutdown()
+
+	assert.Equal(t, http.StatusOK, get("/healthz"))
+	assert.Equal(t, http.StatusServiceUnavailable, get("/readyz"))
+}
+
+func newTestStorage(t *testing.T) *sqlite.Sqlite {
+	t.Helper()
+
+	storage, err := sqlite.New(filepath.Join(t.TempDir(), "storage.db"), sqlite.Options{})
+	require.NoError(t, err)
+
+	require.NoError(t, storage.MigrateUp(context.Background()))
+	require.NoError(t, storage.Prepare(context.Background()))
+
+	return storage
+}
+
+func newTestClientIPs(t *testing.T) *clientip.Resolver {
+	t.Helper()
+
+	clientIPs, err := clientip.New(nil)
+	require.NoError(t, err)
+
+	return clientIPs
+}
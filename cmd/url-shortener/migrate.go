@@ -0,0 +1,80 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strconv"
+
+	"github.com/dkhrunov/url-shortener/internal/config"
+	"github.com/dkhrunov/url-shortener/internal/lib/logger/slog/slogerr"
+	"github.com/dkhrunov/url-shortener/internal/storage/sqlite"
+)
+
+const migrateUsage = "usage: url-shortener migrate up | down | to <version> | status"
+
+// runMigrate runs the migrate subcommand and returns the exit code:
+//
+//	migrate up            apply all pending migrations
+//	migrate down          revert the last applied migration
+//	migrate to <version>  apply or revert migrations until the schema is at version
+//	migrate status        print the current and the latest schema version
+func runMigrate(cfg *config.Config, args []string) int {
+	log := newLoger(cfg.Env)
+
+	if len(args) == 0 {
+		fmt.Println(migrateUsage)
+
+		return 2
+	}
+
+	storage, err := sqlite.New(cfg.StoragePath, storageOptions(cfg))
+	if err != nil {
+		log.Error("failed to init storage", slogerr.Error(err))
+
+		return 1
+	}
+	defer storage.Close()
+
+	ctx := context.Background()
+
+	switch {
+	case args[0] == "up" && len(args) == 1:
+		err = storage.MigrateUp(ctx)
+	case args[0] == "down" && len(args) == 1:
+		err = storage.MigrateDown(ctx)
+	case args[0] == "to" && len(args) == 2:
+		version, convErr := strconv.Atoi(args[1])
+		if convErr != nil {
+			fmt.Println(migrateUsage)
+
+			return 2
+		}
+
+		err = storage.MigrateTo(ctx, version)
+	case args[0] == "status" && len(args) == 1:
+	default:
+		fmt.Println(migrateUsage)
+
+		return 2
+	}
+	if err != nil {
+		log.Error("failed to migrate storage", slogerr.Error(err))
+
+		return 1
+	}
+
+	version, err := storage.SchemaVersion(ctx)
+	if err != nil {
+		log.Error("failed to get schema version", slogerr.Error(err))
+
+		return 1
+	}
+
+	log.Info("storage schema",
+		slog.Int("version", version),
+		slog.Int("latest", sqlite.LatestSchemaVersion()),
+	)
+
+	return 0
+}
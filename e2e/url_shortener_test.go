@@ -45,7 +45,8 @@ func TestURLShortener_SaveHappyPath(t *testing.T) {
 		Status(http.StatusOK).
 		JSON().
 		Object().
-		ContainsKey("alias")
+		ContainsKey("alias").
+		ContainsKey("short_url")
 }
 
 func TestURLShortener_SaveRedirectDelete(t *testing.T) {
@@ -0,0 +1,128 @@
+package auth
+
+import "context"
+
+type Method string
+
+const (
+	MethodAPIKey Method = "api_key"
+	MethodBasic  Method = "basic"
+	MethodJWT    Method = "jwt"
+)
+
+type Role string
+
+const (
+	RoleViewer Role = "viewer"
+	RoleEditor Role = "editor"
+	RoleAdmin  Role = "admin"
+)
+
+type Permission string
+
+const (
+	PermLinkRead      Permission = "link:read"
+	PermLinkCreate    Permission = "link:create"
+	PermLinkUpdate    Permission = "link:update"
+	PermLinkDelete    Permission = "link:delete"
+	PermStatsRead     Permission = "stats:read"
+	PermDomainManage  Permission = "domain:manage"
+	PermAPIKeyManage  Permission = "apikey:manage"
+	PermUserManage    Permission = "user:manage"
+	PermClientManage  Permission = "client:manage"
+	PermAuditRead     Permission = "audit:read"
+	PermWebhookManage Permission = "webhook:manage"
+	PermEventsRead    Permission = "events:read"
+)
+
+var viewerPermissions = []Permission{
+	PermLinkRead,
+	PermStatsRead,
+}
+
+var editorPermissions = append([]Permission{
+	PermLinkCreate,
+	PermLinkUpdate,
+	PermLinkDelete,
+}, viewerPermissions...)
+
+var adminPermissions = append([]Permission{
+	PermDomainManage,
+	PermAPIKeyManage,
+	PermUserManage,
+	PermClientManage,
+	PermAuditRead,
+	PermWebhookManage,
+	PermEventsRead,
+}, editorPermissions...)
+
+var rolePermissions = map[Role][]Permission{
+	RoleViewer: viewerPermissions,
+	RoleEditor: editorPermissions,
+	RoleAdmin:  adminPermissions,
+}
+
+// Valid reports whether r is one of the known roles.
+func (r Role) Valid() bool {
+	_, ok := rolePermissions[r]
+	return ok
+}
+
+// Has reports whether the role grants the permission. Unknown roles grant nothing.
+func (r Role) Has(p Permission) bool {
+	for _, granted := range rolePermissions[r] {
+		if granted == p {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Identity describes who made an authenticated request.
+type Identity struct {
+	Method Method
+	// Name is the API key name, the BasicAuth user or the JWT subject.
+	Name string
+	// KeyID is the id of the API key, zero for other methods.
+	KeyID int64
+	// UserID is the id of the user account, zero for the built-in admin and for
+	// API keys that are not tied to a user.
+	UserID int64
+	Role   Role
+}
+
+func (id Identity) IsAdmin() bool {
+	return id.Role == RoleAdmin
+}
+
+// Can reports whether the identity's role grants the permission.
+func (id Identity) Can(p Permission) bool {
+	return id.Role.Has(p)
+}
+
+// CanManage reports whether the identity may change a link owned by ownerID.
+// Links without an owner can only be managed by admins.
+func (id Identity) CanManage(ownerID int64) bool {
+	return id.IsAdmin() || (ownerID != 0 && ownerID == id.UserID)
+}
+
+// OwnerFilter returns the owner to restrict link listings to, zero meaning all links.
+func (id Identity) OwnerFilter() int64 {
+	if id.IsAdmin() {
+		return 0
+	}
+
+	return id.UserID
+}
+
+type ctxKey struct{}
+
+func WithIdentity(ctx context.Context, id Identity) context.Context {
+	return context.WithValue(ctx, ctxKey{}, id)
+}
+
+func FromContext(ctx context.Context) (Identity, bool) {
+	id, ok := ctx.Value(ctxKey{}).(Identity)
+	return id, ok
+}
@@ -0,0 +1,60 @@
+package auth
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIdentityCanManage(t *testing.T) {
+	admin := Identity{Role: RoleAdmin}
+	user := Identity{Role: RoleEditor, UserID: 1}
+
+	assert.True(t, admin.CanManage(0))
+	assert.True(t, admin.CanManage(2))
+	assert.True(t, user.CanManage(1))
+	assert.False(t, user.CanManage(2))
+	assert.False(t, user.CanManage(0))
+	assert.False(t, Identity{Role: RoleEditor}.CanManage(0))
+
+	assert.Equal(t, int64(0), admin.OwnerFilter())
+	assert.Equal(t, int64(1), user.OwnerFilter())
+}
+
+func TestRoleHas(t *testing.T) {
+	cases := []struct {
+		role    Role
+		granted []Permission
+		denied  []Permission
+	}{
+		{
+			role:    RoleViewer,
+			granted: []Permission{PermLinkRead, PermStatsRead},
+			denied:  []Permission{PermLinkCreate, PermLinkUpdate, PermLinkDelete, PermDomainManage, PermAPIKeyManage, PermUserManage},
+		},
+		{
+			role:    RoleEditor,
+			granted: []Permission{PermLinkRead, PermStatsRead, PermLinkCreate, PermLinkUpdate, PermLinkDelete},
+			denied:  []Permission{PermDomainManage, PermAPIKeyManage, PermUserManage},
+		},
+		{
+			role:    RoleAdmin,
+			granted: []Permission{PermLinkRead, PermStatsRead, PermLinkCreate, PermLinkUpdate, PermLinkDelete, PermDomainManage, PermAPIKeyManage, PermUserManage},
+		},
+		{
+			role:   Role("user"),
+			denied: []Permission{PermLinkRead, PermLinkCreate},
+		},
+	}
+
+	for _, tc := range cases {
+		assert.Equal(t, len(tc.granted) > 0, tc.role.Valid())
+
+		for _, p := range tc.granted {
+			assert.True(t, tc.role.Has(p), "%s should have %s", tc.role, p)
+		}
+		for _, p := range tc.denied {
+			assert.False(t, tc.role.Has(p), "%s should not have %s", tc.role, p)
+		}
+	}
+}
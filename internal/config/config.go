@@ -1,6 +1,7 @@
 package config
 
 import (
+	"fmt"
 	"log"
 	"os"
 	"time"
@@ -11,7 +12,101 @@ import (
 type Config struct {
 	Env         string `yaml:"env" env-default:"local"`
 	StoragePath string `yaml:"storage_path" env-required:"true"`
+	// AutoMigrate applies pending schema migrations on startup. When disabled, run
+	// "url-shortener migrate up" before starting a new version.
+	AutoMigrate bool `yaml:"auto_migrate" env:"AUTO_MIGRATE" env-default:"true"`
 	HTTPServer  `yaml:"http_server"`
+	ShortURL    `yaml:"short_url"`
+	Auth        `yaml:"auth"`
+	Quota       `yaml:"quota"`
+	RateLimit   `yaml:"rate_limit"`
+	ScanGuard   `yaml:"scan_guard"`
+	Storage     Storage     `yaml:"storage"`
+	Retention   Retention   `yaml:"retention"`
+	Webhooks    Webhooks    `yaml:"webhooks"`
+	ClickStream ClickStream `yaml:"click_stream"`
+	Analytics   Analytics   `yaml:"analytics"`
+	Metrics     Metrics     `yaml:"metrics"`
+	Tracing     Tracing     `yaml:"tracing"`
+}
+
+type Storage struct {
+	// ReadTimeout and WriteTimeout bound a single storage operation that reads or
+	// writes. Requests whose operation runs out of time fail with 504.
+	ReadTimeout  time.Duration `yaml:"read_timeout" env-default:"2s"`
+	WriteTimeout time.Duration `yaml:"write_timeout" env-default:"3s"`
+	// JournalMode and Synchronous are the SQLite journal_mode and synchronous pragmas.
+	// WAL lets redirects read while a link is being written.
+	JournalMode string `yaml:"journal_mode" env-default:"wal"`
+	Synchronous string `yaml:"synchronous" env-default:"normal"`
+	// BusyTimeout is how long a write waits for the lock held by another one.
+	BusyTimeout     time.Duration `yaml:"busy_timeout" env-default:"2s"`
+	MaxOpenConns    int           `yaml:"max_open_conns" env-default:"16"`
+	MaxIdleConns    int           `yaml:"max_idle_conns" env-default:"16"`
+	ConnMaxLifetime time.Duration `yaml:"conn_max_lifetime" env-default:"0s"`
+}
+
+type Retention struct {
+	// Period is how long deleted links can be restored before they are purged and
+	// their aliases can be taken again. Zero keeps deleted links forever.
+	Period time.Duration `yaml:"period" env-default:"720h"`
+	// Interval is how often deleted links past the period are purged.
+	Interval   time.Duration `yaml:"interval" env-default:"1h"`
+	Tombstones Tombstones    `yaml:"tombstones"`
+	// Clicks is how long raw clicks are kept. The analytics are read from hourly
+	// rollups, which are kept as long as their links. Zero keeps raw clicks forever.
+	Clicks time.Duration `yaml:"clicks" env-default:"2160h"`
+}
+
+type Tombstones struct {
+	// Enabled keeps the aliases of purged links from being taken again, so traffic
+	// from old links can't be hijacked. Deleted aliases then get 410 Gone, not 404.
+	Enabled bool `yaml:"enabled" env-default:"false"`
+	// Period is how long an alias stays tombstoned. Zero keeps it forever.
+	Period time.Duration `yaml:"period" env-default:"0s"`
+}
+
+type Webhooks struct {
+	// Interval is how often pending deliveries are retried. New webhooks get events
+	// after at most an interval.
+	Interval time.Duration `yaml:"interval" env-default:"5s"`
+	// Timeout bounds a single delivery.
+	Timeout time.Duration `yaml:"timeout" env-default:"10s"`
+	// MaxAttempts is how many times an event is delivered before it is given up.
+	MaxAttempts int `yaml:"max_attempts" env-default:"8"`
+	// MinBackoff is the delay before the first retry, doubled for every next one up
+	// to MaxBackoff.
+	MinBackoff time.Duration `yaml:"min_backoff" env-default:"10s"`
+	MaxBackoff time.Duration `yaml:"max_backoff" env-default:"1h"`
+	// BatchSize is how many events are delivered at once.
+	BatchSize int `yaml:"batch_size" env-default:"16"`
+	// Buffer is how many events wait to be stored for delivery before newer ones are
+	// dropped.
+	Buffer int `yaml:"buffer" env-default:"1024"`
+}
+
+type ClickStream struct {
+	// Buffer is how many clicks wait for a stream client before newer ones are
+	// dropped for it.
+	Buffer int `yaml:"buffer" env-default:"64"`
+	// Heartbeat is how often idle streams get a comment so proxies keep them open.
+	Heartbeat time.Duration `yaml:"heartbeat" env-default:"15s"`
+}
+
+type Analytics struct {
+	// GeoIPFile is a MaxMind country database, e.g. GeoLite2-Country.mmdb, to look up
+	// the countries of clicks in. When empty, countries are not recorded.
+	GeoIPFile string `yaml:"geoip_file" env:"ANALYTICS_GEOIP_FILE"`
+	// Buffer is how many clicks wait to be saved before newer ones are dropped.
+	Buffer int `yaml:"buffer" env-default:"4096"`
+	// BatchSize is how many clicks are saved at once.
+	BatchSize int `yaml:"batch_size" env-default:"256"`
+	// FlushInterval is how long a click waits at most to be saved.
+	FlushInterval time.Duration `yaml:"flush_interval" env-default:"1s"`
+	// BotsFile lists User-Agent patterns of bots, one per line, replacing the built-in
+	// ones. It is reread every BotsReloadInterval when it changes.
+	BotsFile           string        `yaml:"bots_file" env:"ANALYTICS_BOTS_FILE"`
+	BotsReloadInterval time.Duration `yaml:"bots_reload_interval" env-default:"1m"`
 }
 
 type HTTPServer struct {
@@ -20,6 +115,105 @@ type HTTPServer struct {
 	IdleTimeout time.Duration `yaml:"idle_timeout" env-default:"60s"`
 	User        string        `yaml:"user" env-required:"true"`
 	Password    string        `yaml:"password" env-required:"true" env:"HTTP_SERVER_PASSWORD"`
+	// ShutdownDelay is how long /readyz fails before the server stops accepting
+	// requests on shutdown, so load balancers stop routing to it first.
+	ShutdownDelay time.Duration `yaml:"shutdown_delay" env-default:"5s"`
+	// ShutdownTimeout is the grace period for in-flight requests to finish and for
+	// the components to stop once shutdown begins.
+	ShutdownTimeout time.Duration `yaml:"shutdown_timeout" env-default:"15s"`
+}
+
+type ShortURL struct {
+	// BaseURL is the public address short links are served from, e.g. https://sho.rt.
+	// When empty, links are built from the host of the incoming request.
+	BaseURL string `yaml:"base_url" env:"BASE_URL"`
+	// AllowedHosts are the hosts a client may request short links for instead of the BaseURL host.
+	AllowedHosts []string `yaml:"allowed_hosts"`
+}
+
+type Auth struct {
+	// BasicAuthFallback lets /url clients authenticate with BasicAuth, either as the
+	// http_server user or as a user account, in addition to API keys. The admin API
+	// always accepts BasicAuth.
+	BasicAuthFallback bool `yaml:"basic_auth_fallback" env-default:"true"`
+	JWT               JWT  `yaml:"jwt"`
+}
+
+// JWT configures bearer tokens issued by an external identity provider for the /url
+// routes. Tokens are verified with local keys only; at least one key source must be
+// set to enable them.
+type JWT struct {
+	// HMACSecret verifies HS256 tokens.
+	HMACSecret string `yaml:"hmac_secret" env:"JWT_HMAC_SECRET"`
+	// PublicKeyFile is a PEM encoded RSA or Ed25519 public key verifying RS256 or EdDSA tokens.
+	PublicKeyFile string `yaml:"public_key_file"`
+	// JWKSFile is a JSON Web Key Set file, keys are matched by the kid header.
+	JWKSFile  string        `yaml:"jwks_file"`
+	Issuer    string        `yaml:"issuer"`
+	Audience  string        `yaml:"audience"`
+	ClockSkew time.Duration `yaml:"clock_skew" env-default:"30s"`
+	// RoleClaim is the claim holding one of the viewer, editor or admin roles.
+	RoleClaim string `yaml:"role_claim" env-default:"role"`
+}
+
+// Enabled reports whether any verification key is configured.
+func (j JWT) Enabled() bool {
+	return j.HMACSecret != "" || j.PublicKeyFile != "" || j.JWKSFile != ""
+}
+
+// Quota limits link creation per user, or per API key for keys without a user.
+// Zero means unlimited.
+type Quota struct {
+	LinksPerDay int `yaml:"links_per_day" env:"QUOTA_LINKS_PER_DAY"`
+	ActiveLinks int `yaml:"active_links" env:"QUOTA_ACTIVE_LINKS"`
+}
+
+type RateLimit struct {
+	// TrustedProxies are the addresses or CIDR ranges of the proxies whose
+	// X-Forwarded-For header is used to find the client address.
+	TrustedProxies []string `yaml:"trusted_proxies"`
+	// Redirect limits the public redirect and QR code routes per client address.
+	Redirect Rate `yaml:"redirect"`
+	// Client limits every authenticated route per client address before the
+	// credentials are checked, so failed attempts are throttled too.
+	Client Rate `yaml:"client"`
+	// API limits the /url routes per API key, or per client address for other clients.
+	API Rate `yaml:"api"`
+}
+
+// Rate allows Requests per Period with bursts of up to Burst requests. Zero Requests
+// disables the limit.
+type Rate struct {
+	Requests int           `yaml:"requests"`
+	Period   time.Duration `yaml:"period" env-default:"1m"`
+	Burst    int           `yaml:"burst"`
+}
+
+// ScanGuard blocks clients that get Threshold not found responses within Window
+// from the redirect routes for BlockFor. Zero Threshold disables blocking.
+type ScanGuard struct {
+	Threshold int           `yaml:"threshold"`
+	Window    time.Duration `yaml:"window" env-default:"1m"`
+	BlockFor  time.Duration `yaml:"block_for" env-default:"15m"`
+}
+
+type Metrics struct {
+	// Address of a separate listener serving /metrics, e.g. an admin port not
+	// exposed publicly. When empty /metrics is served by the main listener.
+	Address string `yaml:"address"`
+}
+
+type Tracing struct {
+	// Exporter is one of none, stdout, file or otlp.
+	Exporter string `yaml:"exporter" env:"TRACING_EXPORTER" env-default:"none"`
+	// File is where the file exporter writes spans to.
+	File string `yaml:"file" env-default:"traces.json"`
+	// Endpoint is the host:port of the OTLP/HTTP collector. When empty the standard
+	// OTEL_EXPORTER_OTLP_* environment variables apply.
+	Endpoint    string  `yaml:"endpoint"`
+	Insecure    bool    `yaml:"insecure"`
+	ServiceName string  `yaml:"service_name" env-default:"url-shortener"`
+	SampleRatio float64 `yaml:"sample_ratio" env-default:"1"`
 }
 
 func MustLoad() *Config {
@@ -38,5 +232,36 @@ func MustLoad() *Config {
 		log.Fatalf("cannot read config: %s", err)
 	}
 
+	if err := cfg.validate(); err != nil {
+		log.Fatalf("invalid config: %s", err)
+	}
+
 	return &cfg
 }
+
+// validate reports the settings the service can't run with.
+func (c *Config) validate() error {
+	type setting struct {
+		name     string
+		interval time.Duration
+	}
+
+	// The intervals drive tickers, which don't accept zero or negative periods.
+	intervals := []setting{
+		{"retention.interval", c.Retention.Interval},
+		{"webhooks.interval", c.Webhooks.Interval},
+		{"click_stream.heartbeat", c.ClickStream.Heartbeat},
+		{"analytics.flush_interval", c.Analytics.FlushInterval},
+	}
+	if c.Analytics.BotsFile != "" {
+		intervals = append(intervals, setting{"analytics.bots_reload_interval", c.Analytics.BotsReloadInterval})
+	}
+
+	for _, i := range intervals {
+		if i.interval <= 0 {
+			return fmt.Errorf("%s must be positive, got %s", i.name, i.interval)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,81 @@
+package config
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidate(t *testing.T) {
+	cases := []struct {
+		name    string
+		modify  func(c *Config)
+		wantErr string
+	}{
+		{
+			name:   "Defaults",
+			modify: func(c *Config) {},
+		},
+		{
+			name:    "Zero retention interval",
+			modify:  func(c *Config) { c.Retention.Interval = 0 },
+			wantErr: "retention.interval must be positive, got 0s",
+		},
+		{
+			name:    "Zero webhooks interval",
+			modify:  func(c *Config) { c.Webhooks.Interval = 0 },
+			wantErr: "webhooks.interval must be positive, got 0s",
+		},
+		{
+			name:    "Zero click stream heartbeat",
+			modify:  func(c *Config) { c.ClickStream.Heartbeat = 0 },
+			wantErr: "click_stream.heartbeat must be positive, got 0s",
+		},
+		{
+			name:    "Zero analytics flush interval",
+			modify:  func(c *Config) { c.Analytics.FlushInterval = 0 },
+			wantErr: "analytics.flush_interval must be positive, got 0s",
+		},
+		{
+			name: "Zero bots reload interval",
+			modify: func(c *Config) {
+				c.Analytics.BotsFile = "bots.txt"
+				c.Analytics.BotsReloadInterval = 0
+			},
+			wantErr: "analytics.bots_reload_interval must be positive, got 0s",
+		},
+		{
+			name:   "Zero bots reload interval without a bots file",
+			modify: func(c *Config) { c.Analytics.BotsReloadInterval = 0 },
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			cfg := validConfig()
+			tc.modify(cfg)
+
+			err := cfg.validate()
+			if tc.wantErr == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tc.wantErr)
+			}
+		})
+	}
+}
+
+// validConfig returns the config with the intervals at their defaults.
+func validConfig() *Config {
+	return &Config{
+		Retention:   Retention{Interval: time.Hour},
+		Webhooks:    Webhooks{Interval: 5 * time.Second},
+		ClickStream: ClickStream{Heartbeat: 15 * time.Second},
+		Analytics:   Analytics{FlushInterval: time.Second, BotsReloadInterval: time.Minute},
+	}
+}
@@ -0,0 +1,244 @@
+package analytics
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"sync/atomic"
+	"time"
+
+	"github.com/dkhrunov/url-shortener/internal/lib/hostname"
+	"github.com/dkhrunov/url-shortener/internal/lib/logger/slog/slogerr"
+	"github.com/dkhrunov/url-shortener/internal/lib/useragent"
+	"github.com/dkhrunov/url-shortener/internal/storage"
+)
+
+// Interval is the size of the time buckets of a click series.
+type Interval string
+
+const (
+	IntervalHour Interval = "hour"
+	IntervalDay  Interval = "day"
+	IntervalWeek Interval = "week"
+)
+
+// Valid reports whether i is a known interval.
+func (i Interval) Valid() bool {
+	switch i {
+	case IntervalHour, IntervalDay, IntervalWeek:
+		return true
+	}
+
+	return false
+}
+
+// Truncate returns the start of the bucket t falls into, in UTC. Weeks start on Monday.
+func (i Interval) Truncate(t time.Time) time.Time {
+	t = t.UTC()
+
+	switch i {
+	case IntervalDay:
+		return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	case IntervalWeek:
+		day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+
+		return day.AddDate(0, 0, -(int(day.Weekday())+6)%7)
+	default:
+		return t.Truncate(time.Hour)
+	}
+}
+
+// Next returns the start of the bucket following the one starting at start.
+func (i Interval) Next(start time.Time) time.Time {
+	switch i {
+	case IntervalDay:
+		return start.AddDate(0, 0, 1)
+	case IntervalWeek:
+		return start.AddDate(0, 0, 7)
+	default:
+		return start.Add(time.Hour)
+	}
+}
+
+// Fill returns the buckets of the interval from the one of from up to to, taking the
+// clicks from series and zero for buckets missing there, so that charts have no gaps.
+func Fill(series []storage.ClickBucket, i Interval, from, to time.Time) []storage.ClickBucket {
+	clicks := make(map[time.Time]int64, len(series))
+	for _, b := range series {
+		clicks[b.Start.UTC()] = b.Clicks
+	}
+
+	filled := []storage.ClickBucket{}
+	for start := i.Truncate(from); start.Before(to); start = i.Next(start) {
+		filled = append(filled, storage.ClickBucket{Start: start, Clicks: clicks[start]})
+	}
+
+	return filled
+}
+
+type Store interface {
+	SaveClicks(ctx context.Context, clicks []storage.Click) error
+}
+
+type ClientIPResolver interface {
+	IP(r *http.Request) string
+}
+
+type CountryResolver interface {
+	Country(ip string) string
+}
+
+type Config struct {
+	// Buffer is how many clicks wait to be saved before newer ones are dropped.
+	Buffer int
+	// BatchSize is how many clicks are saved at once.
+	BatchSize int
+	// FlushInterval is how long a click waits at most for its batch to fill up.
+	FlushInterval time.Duration
+}
+
+// Recorder saves the clicks of redirects in batches, in the background, so that a
+// redirect never waits for the storage. Clicks are dropped rather than delaying
+// redirects when the storage falls behind.
+type Recorder struct {
+	store     Store
+	ips       ClientIPResolver
+	countries CountryResolver
+	cfg       Config
+	log       *slog.Logger
+
+	clicks  chan click
+	dropped atomic.Int64
+
+	stop chan struct{}
+	done chan struct{}
+}
+
+// click is a recorded redirect before the details of the client are told.
+type click struct {
+	linkID    int64
+	at        time.Time
+	referrer  string
+	userAgent string
+	ip        string
+	bot       bool
+}
+
+func New(store Store, ips ClientIPResolver, countries CountryResolver, cfg Config, log *slog.Logger) *Recorder {
+	return &Recorder{
+		store:     store,
+		ips:       ips,
+		countries: countries,
+		cfg:       cfg,
+		log:       log.With(slog.String("component", "analytics")),
+		clicks:    make(chan click, cfg.Buffer),
+		stop:      make(chan struct{}),
+		done:      make(chan struct{}),
+	}
+}
+
+// Record queues the redirect of the request r to the link linkID to be saved, without
+// waiting for it. bot tells whether the request was made by a bot.
+func (rec *Recorder) Record(r *http.Request, linkID int64, bot bool) {
+	c := click{
+		linkID:    linkID,
+		at:        time.Now().UTC(),
+		referrer:  r.Referer(),
+		userAgent: r.UserAgent(),
+		ip:        rec.ips.IP(r),
+		bot:       bot,
+	}
+
+	select {
+	case rec.clicks <- c:
+	default:
+		rec.dropped.Add(1)
+	}
+}
+
+// Run saves the queued clicks whenever a batch fills up or every flush interval, until
+// Stop is called.
+func (rec *Recorder) Run() error {
+	defer close(rec.done)
+
+	ticker := time.NewTicker(rec.cfg.FlushInterval)
+	defer ticker.Stop()
+
+	batch := make([]storage.Click, 0, rec.cfg.BatchSize)
+	for {
+		select {
+		case c := <-rec.clicks:
+			batch = append(batch, rec.enrich(c))
+			if len(batch) < rec.cfg.BatchSize {
+				continue
+			}
+		case <-ticker.C:
+		case <-rec.stop:
+			for {
+				select {
+				case c := <-rec.clicks:
+					batch = append(batch, rec.enrich(c))
+				default:
+					rec.flush(batch)
+
+					return nil
+				}
+			}
+		}
+
+		batch = rec.flush(batch)
+	}
+}
+
+// Stop ends Run once the queued clicks are saved, or when ctx ends. Clicks recorded
+// after Stop are not saved.
+func (rec *Recorder) Stop(ctx context.Context) error {
+	close(rec.stop)
+
+	select {
+	case <-rec.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// enrich tells the referrer host, browser, OS, device and country of the click.
+func (rec *Recorder) enrich(c click) storage.Click {
+	agent := useragent.Parse(c.userAgent)
+
+	saved := storage.Click{
+		LinkID:  c.linkID,
+		At:      c.at,
+		Browser: agent.Browser,
+		OS:      agent.OS,
+		Device:  agent.Device,
+		Country: rec.countries.Country(c.ip),
+		Bot:     c.bot,
+	}
+
+	if u, err := url.Parse(c.referrer); err == nil {
+		saved.Referrer = hostname.Normalize(u.Host)
+	}
+
+	return saved
+}
+
+// flush saves the batch and returns it emptied. A failure is logged rather than
+// returned, the clicks of the batch are lost then.
+func (rec *Recorder) flush(batch []storage.Click) []storage.Click {
+	if dropped := rec.dropped.Swap(0); dropped > 0 {
+		rec.log.Warn("clicks dropped, the storage falls behind", slog.Int64("dropped", dropped))
+	}
+
+	if len(batch) == 0 {
+		return batch
+	}
+
+	if err := rec.store.SaveClicks(context.Background(), batch); err != nil {
+		rec.log.Error("failed to save clicks", slog.Int("clicks", len(batch)), slogerr.Error(err))
+	}
+
+	return batch[:0]
+}
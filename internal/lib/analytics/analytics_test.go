@@ -0,0 +1,178 @@
+package analytics_test
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/dkhrunov/url-shortener/internal/lib/analytics"
+	"github.com/dkhrunov/url-shortener/internal/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// store keeps the saved batches in memory.
+type store struct {
+	mu      sync.Mutex
+	batches [][]storage.Click
+}
+
+func (s *store) SaveClicks(_ context.Context, clicks []storage.Click) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.batches = append(s.batches, append([]storage.Click(nil), clicks...))
+
+	return nil
+}
+
+func (s *store) saved() [][]storage.Click {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.batches
+}
+
+type remoteAddr struct{}
+
+func (remoteAddr) IP(r *http.Request) string {
+	return r.RemoteAddr
+}
+
+type countries map[string]string
+
+func (c countries) Country(ip string) string {
+	return c[ip]
+}
+
+func newRequest(referrer, userAgent, ip string) *http.Request {
+	r := httptest.NewRequest(http.MethodGet, "/go", nil)
+	r.Header.Set("Referer", referrer)
+	r.Header.Set("User-Agent", userAgent)
+	r.RemoteAddr = ip
+
+	return r
+}
+
+func TestRecorder(t *testing.T) {
+	s := &store{}
+	rec := analytics.New(s, remoteAddr{}, countries{"203.0.113.7": "JP"}, analytics.Config{
+		Buffer:        8,
+		BatchSize:     2,
+		FlushInterval: time.Hour,
+	}, slog.Default())
+
+	go rec.Run()
+
+	const chrome = "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/122.0.0.0 Safari/537.36"
+
+	rec.Record(newRequest("https://News.Example:443/item?id=1", chrome, "203.0.113.7"), 1, false)
+	rec.Record(newRequest("", "curl/8.4.0", "192.0.2.1"), 2, true)
+
+	// A full batch is saved without waiting for the flush interval.
+	require.Eventually(t, func() bool { return len(s.saved()) == 1 }, time.Second, 10*time.Millisecond)
+
+	batch := s.saved()[0]
+	require.Len(t, batch, 2)
+	assert.WithinDuration(t, time.Now(), batch[0].At, time.Minute)
+	batch[0].At, batch[1].At = time.Time{}, time.Time{}
+	assert.Equal(t, []storage.Click{
+		{LinkID: 1, Referrer: "news.example", Browser: "Chrome", OS: "Windows", Device: "desktop", Country: "JP"},
+		{LinkID: 2, Bot: true},
+	}, batch)
+
+	// Stopping saves the clicks still queued.
+	rec.Record(newRequest("", chrome, "192.0.2.1"), 3, false)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	require.NoError(t, rec.Stop(ctx))
+
+	require.Len(t, s.saved(), 2)
+	assert.Equal(t, int64(3), s.saved()[1][0].LinkID)
+}
+
+func TestRecorderDrops(t *testing.T) {
+	s := &store{}
+	rec := analytics.New(s, remoteAddr{}, countries{}, analytics.Config{
+		Buffer:        1,
+		BatchSize:     10,
+		FlushInterval: time.Hour,
+	}, slog.Default())
+
+	// Redirects don't wait for a recorder that falls behind.
+	rec.Record(newRequest("", "", ""), 1, false)
+	rec.Record(newRequest("", "", ""), 2, false)
+
+	go rec.Run()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	require.NoError(t, rec.Stop(ctx))
+
+	require.Len(t, s.saved(), 1)
+	require.Len(t, s.saved()[0], 1)
+	assert.Equal(t, int64(1), s.saved()[0][0].LinkID)
+}
+
+func TestInterval(t *testing.T) {
+	// 2024-03-10 is a Sunday.
+	at := time.Date(2024, 3, 10, 23, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		interval analytics.Interval
+		start    time.Time
+		next     time.Time
+	}{
+		{
+			interval: analytics.IntervalHour,
+			start:    time.Date(2024, 3, 10, 23, 0, 0, 0, time.UTC),
+			next:     time.Date(2024, 3, 11, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			interval: analytics.IntervalDay,
+			start:    time.Date(2024, 3, 10, 0, 0, 0, 0, time.UTC),
+			next:     time.Date(2024, 3, 11, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			interval: analytics.IntervalWeek,
+			start:    time.Date(2024, 3, 4, 0, 0, 0, 0, time.UTC),
+			next:     time.Date(2024, 3, 11, 0, 0, 0, 0, time.UTC),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.interval), func(t *testing.T) {
+			assert.True(t, tt.interval.Valid())
+
+			start := tt.interval.Truncate(at)
+			assert.Equal(t, tt.start, start)
+			assert.Equal(t, tt.next, tt.interval.Next(start))
+		})
+	}
+
+	assert.False(t, analytics.Interval("month").Valid())
+}
+
+func TestFill(t *testing.T) {
+	day := func(d int) time.Time {
+		return time.Date(2024, 3, d, 0, 0, 0, 0, time.UTC)
+	}
+
+	series := []storage.ClickBucket{
+		{Start: day(5), Clicks: 3},
+		{Start: day(7), Clicks: 1},
+	}
+
+	assert.Equal(t, []storage.ClickBucket{
+		{Start: day(4), Clicks: 0},
+		{Start: day(5), Clicks: 3},
+		{Start: day(6), Clicks: 0},
+		{Start: day(7), Clicks: 1},
+	}, analytics.Fill(series, analytics.IntervalDay, day(4).Add(6*time.Hour), day(8)))
+
+	assert.Empty(t, analytics.Fill(nil, analytics.IntervalDay, day(8), day(8)))
+}
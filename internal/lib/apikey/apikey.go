@@ -0,0 +1,38 @@
+package apikey
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+)
+
+const (
+	keyPrefix   = "usk_"
+	secretBytes = 32
+	// PrefixLength is the number of leading secret characters kept in clear text
+	// so that keys can be told apart in listings.
+	PrefixLength = 8
+)
+
+// Generate returns a new random API key and its displayable prefix.
+func Generate() (key, prefix string, err error) {
+	const op = "lib.apikey.Generate"
+
+	b := make([]byte, secretBytes)
+	if _, err := rand.Read(b); err != nil {
+		return "", "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	key = keyPrefix + base64.RawURLEncoding.EncodeToString(b)
+
+	return key, key[:len(keyPrefix)+PrefixLength], nil
+}
+
+// Hash returns the value stored in place of the key. Keys are high-entropy
+// random strings, so a plain SHA-256 is enough to make a leaked table useless.
+func Hash(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,23 @@
+package apikey
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerate(t *testing.T) {
+	key1, prefix1, err := Generate()
+	require.NoError(t, err)
+
+	key2, _, err := Generate()
+	require.NoError(t, err)
+
+	assert.True(t, strings.HasPrefix(key1, prefix1))
+	assert.Len(t, prefix1, len(keyPrefix)+PrefixLength)
+	assert.NotEqual(t, key1, key2)
+	assert.NotEqual(t, Hash(key1), Hash(key2))
+	assert.Equal(t, Hash(key1), Hash(key1))
+}
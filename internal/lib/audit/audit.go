@@ -0,0 +1,135 @@
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/dkhrunov/url-shortener/internal/auth"
+	"github.com/dkhrunov/url-shortener/internal/lib/logger/slog/slogerr"
+	"github.com/dkhrunov/url-shortener/internal/storage"
+	"github.com/go-chi/chi/v5/middleware"
+)
+
+type Action string
+
+const (
+	ActionCreate  Action = "create"
+	ActionUpdate  Action = "update"
+	ActionDelete  Action = "delete"
+	ActionRestore Action = "restore"
+)
+
+// Valid reports whether a is a known action.
+func (a Action) Valid() bool {
+	switch a {
+	case ActionCreate, ActionUpdate, ActionDelete, ActionRestore:
+		return true
+	}
+
+	return false
+}
+
+// Event is a change of links made by a request.
+type Event struct {
+	Action Action
+	Domain string
+	Alias  string
+	// Before and After are the state of what changed, marshaled to JSON. Nil when
+	// there is none, e.g. before a link was created.
+	Before any
+	After  any
+}
+
+// Link is the state of a link recorded in Event.Before and Event.After.
+type Link struct {
+	URL       string     `json:"url,omitempty"`
+	Tags      []string   `json:"tags,omitempty"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+}
+
+// LinkOf returns the recorded state of u.
+func LinkOf(u storage.URL) Link {
+	return Link{URL: u.URL, Tags: u.Tags, ExpiresAt: u.ExpiresAt}
+}
+
+type Store interface {
+	SaveAuditEntries(ctx context.Context, entries []storage.AuditEntry) error
+}
+
+type ClientIPResolver interface {
+	IP(r *http.Request) string
+}
+
+// Recorder appends the events of requests to the audit log.
+type Recorder struct {
+	store Store
+	ips   ClientIPResolver
+}
+
+func New(store Store, ips ClientIPResolver) *Recorder {
+	return &Recorder{store: store, ips: ips}
+}
+
+// Record appends the event made by the request r to the audit log, with who made it and
+// from where. A failure is logged rather than returned, as the change is made already.
+// The event is recorded even when the request was canceled in the meantime.
+func (rec *Recorder) Record(r *http.Request, e Event) {
+	rec.RecordAll(r, []Event{e})
+}
+
+// RecordAll appends the events made by the request r to the audit log at once, e.g.
+// one for every link changed by a bulk operation, see Record.
+func (rec *Recorder) RecordAll(r *http.Request, events []Event) {
+	const op = "audit.Recorder.RecordAll"
+
+	identity, _ := auth.FromContext(r.Context())
+
+	log := slog.With(
+		slog.String("op", op),
+		slog.String("request_id", middleware.GetReqID(r.Context())),
+	)
+
+	entries := make([]storage.AuditEntry, 0, len(events))
+	for _, e := range events {
+		entry := storage.AuditEntry{
+			Actor:      string(identity.Method) + ":" + identity.Name,
+			UserID:     identity.UserID,
+			APIKeyID:   identity.KeyID,
+			Action:     string(e.Action),
+			Domain:     e.Domain,
+			Alias:      e.Alias,
+			RequestID:  middleware.GetReqID(r.Context()),
+			RemoteAddr: rec.ips.IP(r),
+		}
+
+		var err error
+		if entry.Before, err = marshal(e.Before); err != nil {
+			log.ErrorContext(r.Context(), "failed to marshal audit state", slog.String("alias", e.Alias), slogerr.Error(err))
+		}
+		if entry.After, err = marshal(e.After); err != nil {
+			log.ErrorContext(r.Context(), "failed to marshal audit state", slog.String("alias", e.Alias), slogerr.Error(err))
+		}
+
+		entries = append(entries, entry)
+	}
+
+	if err := rec.store.SaveAuditEntries(context.WithoutCancel(r.Context()), entries); err != nil {
+		log.ErrorContext(r.Context(), "failed to record audit entries", slog.Int("entries", len(entries)), slogerr.Error(err))
+	}
+}
+
+func marshal(v any) (string, error) {
+	if v == nil {
+		return "", nil
+	}
+
+	b, err := json.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+
+	return string(b), nil
+}
@@ -0,0 +1,108 @@
+package audit_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/dkhrunov/url-shortener/internal/auth"
+	"github.com/dkhrunov/url-shortener/internal/lib/audit"
+	"github.com/dkhrunov/url-shortener/internal/storage"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type store struct {
+	entries []storage.AuditEntry
+	ctxErr  error
+	err     error
+}
+
+func (s *store) SaveAuditEntries(ctx context.Context, entries []storage.AuditEntry) error {
+	s.entries = append(s.entries, entries...)
+	s.ctxErr = ctx.Err()
+
+	return s.err
+}
+
+type ips struct{}
+
+func (ips) IP(*http.Request) string {
+	return "203.0.113.7"
+}
+
+func TestRecord(t *testing.T) {
+	s := &store{}
+	rec := audit.New(s, ips{})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	ctx = context.WithValue(ctx, middleware.RequestIDKey, "req-1")
+	ctx = auth.WithIdentity(ctx, auth.Identity{Method: auth.MethodAPIKey, Name: "ci", KeyID: 3, UserID: 7})
+
+	r := httptest.NewRequest(http.MethodPatch, "/url/go", nil).WithContext(ctx)
+
+	// The change is made already when a client goes away, so it's still recorded.
+	cancel()
+
+	rec.Record(r, audit.Event{
+		Action: audit.ActionUpdate,
+		Domain: "brand.example",
+		Alias:  "go",
+		Before: audit.Link{URL: "https://go.dev"},
+		After:  audit.Link{URL: "https://go.dev/doc", Tags: []string{"docs"}},
+	})
+
+	require.Len(t, s.entries, 1)
+	assert.NoError(t, s.ctxErr)
+	assert.Equal(t, storage.AuditEntry{
+		Actor:      "api_key:ci",
+		UserID:     7,
+		APIKeyID:   3,
+		Action:     "update",
+		Domain:     "brand.example",
+		Alias:      "go",
+		Before:     `{"url":"https://go.dev"}`,
+		After:      `{"url":"https://go.dev/doc","tags":["docs"]}`,
+		RequestID:  "req-1",
+		RemoteAddr: "203.0.113.7",
+	}, s.entries[0])
+}
+
+func TestRecordWithoutState(t *testing.T) {
+	s := &store{err: errors.New("database is locked")}
+	rec := audit.New(s, ips{})
+
+	r := httptest.NewRequest(http.MethodDelete, "/url/go", nil)
+	r = r.WithContext(auth.WithIdentity(r.Context(), auth.Identity{Method: auth.MethodBasic, Name: "root"}))
+
+	// Failures are logged, not returned.
+	rec.Record(r, audit.Event{Action: audit.ActionDelete, Alias: "go", Before: audit.Link{URL: "https://go.dev"}})
+
+	require.Len(t, s.entries, 1)
+	assert.Equal(t, "basic:root", s.entries[0].Actor)
+	assert.Equal(t, `{"url":"https://go.dev"}`, s.entries[0].Before)
+	assert.Empty(t, s.entries[0].After)
+}
+
+func TestRecordAll(t *testing.T) {
+	s := &store{}
+	rec := audit.New(s, ips{})
+
+	r := httptest.NewRequest(http.MethodPost, "/url/tags/sale/delete", nil)
+	r = r.WithContext(auth.WithIdentity(r.Context(), auth.Identity{Method: auth.MethodBasic, Name: "root"}))
+
+	rec.RecordAll(r, []audit.Event{
+		{Action: audit.ActionDelete, Alias: "go", Before: audit.Link{URL: "https://go.dev", Tags: []string{"sale"}}},
+		{Action: audit.ActionDelete, Domain: "brand.example", Alias: "pkg", Before: audit.Link{URL: "https://pkg.go.dev", Tags: []string{"sale"}}},
+	})
+
+	require.Len(t, s.entries, 2)
+	assert.Equal(t, "go", s.entries[0].Alias)
+	assert.Equal(t, "brand.example", s.entries[1].Domain)
+	assert.Equal(t, "pkg", s.entries[1].Alias)
+	assert.Equal(t, `{"url":"https://pkg.go.dev","tags":["sale"]}`, s.entries[1].Before)
+	assert.Equal(t, "basic:root", s.entries[1].Actor)
+}
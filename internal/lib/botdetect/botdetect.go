@@ -0,0 +1,177 @@
+package botdetect
+
+import (
+	"bufio"
+	"context"
+	_ "embed"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"os"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/dkhrunov/url-shortener/internal/lib/logger/slog/slogerr"
+)
+
+// defaultPatterns are used unless a patterns file is configured.
+//
+//go:embed bots.txt
+var defaultPatterns string
+
+// prefetchHeaders are sent by browsers for requests made ahead of a click, e.g. to
+// prefetch or render a preview of a link, rather than for the click itself.
+var prefetchHeaders = []string{"Purpose", "Sec-Purpose", "X-Purpose", "X-Moz"}
+
+// Detector tells requests of bots, crawlers and link previews from those of people.
+type Detector struct {
+	path     string
+	interval time.Duration
+	log      *slog.Logger
+
+	patterns atomic.Pointer[[]string]
+	// modTime and size are of the patterns file when it was loaded.
+	modTime time.Time
+	size    int64
+
+	stop chan struct{}
+	done chan struct{}
+}
+
+// New returns a detector matching the built-in User-Agent patterns, or those of the
+// file at path when it is not empty, see Parse. Run rereads the file every interval.
+func New(path string, interval time.Duration, log *slog.Logger) (*Detector, error) {
+	const op = "lib.botdetect.New"
+
+	d := &Detector{
+		path:     path,
+		interval: interval,
+		log:      log.With(slog.String("component", "botdetect")),
+		stop:     make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+
+	if path == "" {
+		patterns := Parse(strings.NewReader(defaultPatterns))
+		d.patterns.Store(&patterns)
+
+		return d, nil
+	}
+
+	if _, err := d.reload(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return d, nil
+}
+
+// Parse reads User-Agent patterns, one per line. Blank lines and lines starting with #
+// are skipped. Patterns are matched case-insensitively anywhere in the header.
+func Parse(r io.Reader) []string {
+	patterns := []string{}
+
+	lines := bufio.NewScanner(r)
+	for lines.Scan() {
+		line := strings.TrimSpace(lines.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		patterns = append(patterns, strings.ToLower(line))
+	}
+
+	return patterns
+}
+
+// IsBot reports whether the request r was made by a bot rather than a person: a HEAD
+// request, a prefetch or preview, a request without a User-Agent or with one matching
+// a pattern.
+func (d *Detector) IsBot(r *http.Request) bool {
+	if r.Method == http.MethodHead {
+		return true
+	}
+
+	for _, name := range prefetchHeaders {
+		v := strings.ToLower(r.Header.Get(name))
+		if strings.Contains(v, "prefetch") || strings.Contains(v, "prerender") || strings.Contains(v, "preview") {
+			return true
+		}
+	}
+
+	ua := strings.ToLower(r.UserAgent())
+	if ua == "" {
+		return true
+	}
+
+	for _, pattern := range *d.patterns.Load() {
+		if strings.Contains(ua, pattern) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Run rereads the patterns file every interval when it changed, until Stop is called.
+// When the file fails to load, the patterns loaded before stay in use.
+func (d *Detector) Run() error {
+	defer close(d.done)
+
+	ticker := time.NewTicker(d.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-d.stop:
+			return nil
+		case <-ticker.C:
+		}
+
+		reloaded, err := d.reload()
+		if err != nil {
+			d.log.Error("failed to reload bot patterns", slog.String("path", d.path), slogerr.Error(err))
+
+			continue
+		}
+		if reloaded {
+			d.log.Info("reloaded bot patterns", slog.String("path", d.path), slog.Int("patterns", len(*d.patterns.Load())))
+		}
+	}
+}
+
+// Stop ends Run.
+func (d *Detector) Stop(ctx context.Context) error {
+	close(d.stop)
+
+	select {
+	case <-d.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// reload loads the patterns file unless it is unchanged since it was loaded last.
+func (d *Detector) reload() (bool, error) {
+	info, err := os.Stat(d.path)
+	if err != nil {
+		return false, err
+	}
+	if info.ModTime().Equal(d.modTime) && info.Size() == d.size {
+		return false, nil
+	}
+
+	f, err := os.Open(d.path)
+	if err != nil {
+		return false, err
+	}
+	defer f.Close()
+
+	patterns := Parse(f)
+	d.patterns.Store(&patterns)
+	d.modTime, d.size = info.ModTime(), info.Size()
+
+	return true, nil
+}
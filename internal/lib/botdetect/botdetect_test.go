@@ -0,0 +1,105 @@
+package botdetect_test
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dkhrunov/url-shortener/internal/lib/botdetect"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const chrome = "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/122.0.0.0 Safari/537.36"
+
+func TestIsBot(t *testing.T) {
+	d, err := botdetect.New("", time.Minute, slog.Default())
+	require.NoError(t, err)
+
+	tests := []struct {
+		name   string
+		method string
+		ua     string
+		header http.Header
+		want   bool
+	}{
+		{name: "browser", ua: chrome, want: false},
+		{name: "iphone", ua: "Mozilla/5.0 (iPhone; CPU iPhone OS 17_3 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.3 Mobile/15E148 Safari/604.1", want: false},
+		{name: "googlebot", ua: "Mozilla/5.0 (compatible; Googlebot/2.1; +http://www.google.com/bot.html)", want: true},
+		{name: "slack", ua: "Slackbot-LinkExpanding 1.0 (+https://api.slack.com/robots)", want: true},
+		{name: "facebook", ua: "facebookexternalhit/1.1 (+http://www.facebook.com/externalhit_uatext.php)", want: true},
+		{name: "whatsapp", ua: "WhatsApp/2.23.20.0 A", want: true},
+		{name: "telegram", ua: "TelegramBot (like TwitterBot)", want: true},
+		{name: "curl", ua: "curl/8.4.0", want: true},
+		{name: "no user agent", want: true},
+		{name: "head", method: http.MethodHead, ua: chrome, want: true},
+		{name: "purpose prefetch", ua: chrome, header: http.Header{"Purpose": {"prefetch"}}, want: true},
+		{name: "sec-purpose prerender", ua: chrome, header: http.Header{"Sec-Purpose": {"prefetch;prerender"}}, want: true},
+		{name: "safari preview", ua: chrome, header: http.Header{"X-Purpose": {"preview"}}, want: true},
+		{name: "firefox prefetch", ua: chrome, header: http.Header{"X-Moz": {"prefetch"}}, want: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			method := tt.method
+			if method == "" {
+				method = http.MethodGet
+			}
+
+			r := httptest.NewRequest(method, "/go", nil)
+			r.Header.Set("User-Agent", tt.ua)
+			for k, v := range tt.header {
+				r.Header[k] = v
+			}
+
+			assert.Equal(t, tt.want, d.IsBot(r))
+		})
+	}
+}
+
+func TestParse(t *testing.T) {
+	patterns := botdetect.Parse(strings.NewReader("# comment\n\n  MyCrawler  \nbot\n"))
+
+	assert.Equal(t, []string{"mycrawler", "bot"}, patterns)
+}
+
+func TestReload(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "bots.txt")
+	require.NoError(t, os.WriteFile(path, []byte("InternalMonitor\n"), 0o600))
+
+	d, err := botdetect.New(path, 10*time.Millisecond, slog.Default())
+	require.NoError(t, err)
+
+	request := func(ua string) *http.Request {
+		r := httptest.NewRequest(http.MethodGet, "/go", nil)
+		r.Header.Set("User-Agent", ua)
+
+		return r
+	}
+
+	// The file replaces the built-in patterns.
+	assert.True(t, d.IsBot(request("InternalMonitor/1.0")))
+	assert.False(t, d.IsBot(request("curl/8.4.0")))
+
+	go d.Run()
+
+	require.NoError(t, os.WriteFile(path, []byte("InternalMonitor\ncurl/\n"), 0o600))
+	require.Eventually(t, func() bool { return d.IsBot(request("curl/8.4.0")) }, time.Second, 10*time.Millisecond)
+
+	// A file that can't be read keeps the patterns loaded before.
+	require.NoError(t, os.Remove(path))
+	time.Sleep(50 * time.Millisecond)
+	assert.True(t, d.IsBot(request("curl/8.4.0")))
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	require.NoError(t, d.Stop(ctx))
+
+	_, err = botdetect.New(path, time.Minute, slog.Default())
+	assert.Error(t, err)
+}
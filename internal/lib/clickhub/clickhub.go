@@ -0,0 +1,133 @@
+package clickhub
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Click is a redirect of a link.
+type Click struct {
+	Domain    string `json:"domain,omitempty"`
+	Alias     string `json:"alias"`
+	URL       string `json:"url"`
+	Host      string `json:"host,omitempty"`
+	Referrer  string `json:"referrer,omitempty"`
+	UserAgent string `json:"user_agent,omitempty"`
+	// Bot is set for clicks of bots, crawlers and link previews.
+	Bot bool      `json:"bot,omitempty"`
+	At  time.Time `json:"at"`
+}
+
+// Hub fans the clicks published by redirects out to the subscribers in the process.
+// Every subscriber has a buffer of its own and clicks that don't fit are dropped, so a
+// slow subscriber never blocks a redirect nor other subscribers.
+type Hub struct {
+	buffer int
+
+	mu     sync.RWMutex
+	subs   map[*Subscription]struct{}
+	closed bool
+}
+
+// New returns a hub buffering up to buffer clicks per subscriber.
+func New(buffer int) *Hub {
+	return &Hub{
+		buffer: buffer,
+		subs:   make(map[*Subscription]struct{}),
+	}
+}
+
+// Subscription receives the clicks of a link, or of all links, on C until it is
+// closed.
+type Subscription struct {
+	// C is closed when the subscription or the hub is closed.
+	C <-chan Click
+
+	c       chan Click
+	hub     *Hub
+	domain  string
+	alias   string
+	dropped atomic.Int64
+}
+
+// Subscribe returns a subscription to the clicks of the link with the alias in the
+// domain namespace, or to the clicks of all links when alias is empty. Subscriptions
+// to a closed hub are closed already.
+func (h *Hub) Subscribe(domain, alias string) *Subscription {
+	c := make(chan Click, h.buffer)
+	sub := &Subscription{C: c, c: c, hub: h, domain: domain, alias: alias}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.closed {
+		close(c)
+
+		return sub
+	}
+
+	h.subs[sub] = struct{}{}
+
+	return sub
+}
+
+// Publish hands the click to the subscribers of its link without waiting for them.
+func (h *Hub) Publish(click Click) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	for sub := range h.subs {
+		if sub.alias != "" && (sub.alias != click.Alias || sub.domain != click.Domain) {
+			continue
+		}
+
+		select {
+		case sub.c <- click:
+		default:
+			sub.dropped.Add(1)
+		}
+	}
+}
+
+// Subscribers returns the number of open subscriptions.
+func (h *Hub) Subscribers() int {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	return len(h.subs)
+}
+
+// Close closes all subscriptions, ending the streams reading them, and closes the
+// subscriptions made from then on.
+func (h *Hub) Close() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for sub := range h.subs {
+		close(sub.c)
+	}
+
+	h.subs = make(map[*Subscription]struct{})
+	h.closed = true
+}
+
+// Close ends the subscription. It is safe to close a subscription more than once and
+// after the hub was closed.
+func (s *Subscription) Close() {
+	s.hub.mu.Lock()
+	defer s.hub.mu.Unlock()
+
+	if _, ok := s.hub.subs[s]; !ok {
+		return
+	}
+
+	delete(s.hub.subs, s)
+	close(s.c)
+}
+
+// Dropped returns the number of clicks dropped since the last call because the
+// subscriber fell behind, and resets it.
+func (s *Subscription) Dropped() int64 {
+	return s.dropped.Swap(0)
+}
@@ -0,0 +1,60 @@
+package clickhub_test
+
+import (
+	"testing"
+
+	"github.com/dkhrunov/url-shortener/internal/lib/clickhub"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHub(t *testing.T) {
+	hub := clickhub.New(2)
+
+	link := hub.Subscribe("", "go")
+	defer link.Close()
+	branded := hub.Subscribe("brand.example", "go")
+	defer branded.Close()
+	all := hub.Subscribe("", "")
+	defer all.Close()
+
+	assert.Equal(t, 3, hub.Subscribers())
+
+	hub.Publish(clickhub.Click{Alias: "go", URL: "https://go.dev"})
+	hub.Publish(clickhub.Click{Domain: "brand.example", Alias: "go", URL: "https://go.dev/brand"})
+	hub.Publish(clickhub.Click{Alias: "pkg", URL: "https://pkg.go.dev"})
+
+	require.Len(t, link.C, 1)
+	assert.Equal(t, "https://go.dev", (<-link.C).URL)
+
+	require.Len(t, branded.C, 1)
+	assert.Equal(t, "https://go.dev/brand", (<-branded.C).URL)
+
+	// The subscriber of all links fell behind: publishing didn't wait for it.
+	require.Len(t, all.C, 2)
+	assert.Equal(t, int64(1), all.Dropped())
+	assert.Zero(t, all.Dropped())
+	assert.Equal(t, "https://go.dev", (<-all.C).URL)
+
+	link.Close()
+	link.Close()
+	assert.Equal(t, 2, hub.Subscribers())
+
+	_, ok := <-link.C
+	assert.False(t, ok)
+
+	// Closing the hub ends all subscriptions, including later ones.
+	hub.Close()
+	assert.Zero(t, hub.Subscribers())
+
+	<-all.C
+	_, ok = <-all.C
+	assert.False(t, ok)
+
+	late := hub.Subscribe("", "go")
+	_, ok = <-late.C
+	assert.False(t, ok)
+	late.Close()
+
+	hub.Publish(clickhub.Click{Alias: "go"})
+}
@@ -0,0 +1,85 @@
+package clientip_test
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dkhrunov/url-shortener/internal/lib/clientip"
+)
+
+func TestResolverIP(t *testing.T) {
+	resolver, err := clientip.New([]string{"10.0.0.0/8", "192.168.1.1"})
+	require.NoError(t, err)
+
+	cases := []struct {
+		name       string
+		remoteAddr string
+		forwarded  []string
+		want       string
+	}{
+		{
+			name:       "Direct",
+			remoteAddr: "203.0.113.7:51234",
+			want:       "203.0.113.7",
+		},
+		{
+			name:       "Untrusted peer sets header",
+			remoteAddr: "203.0.113.7:51234",
+			forwarded:  []string{"198.51.100.1"},
+			want:       "203.0.113.7",
+		},
+		{
+			name:       "Trusted proxy",
+			remoteAddr: "10.1.2.3:8080",
+			forwarded:  []string{"198.51.100.1"},
+			want:       "198.51.100.1",
+		},
+		{
+			name:       "Chain of trusted proxies",
+			remoteAddr: "192.168.1.1:8080",
+			forwarded:  []string{"198.51.100.1, 10.0.0.5", "10.0.0.6"},
+			want:       "198.51.100.1",
+		},
+		{
+			name:       "Spoofed left part",
+			remoteAddr: "10.1.2.3:8080",
+			forwarded:  []string{"1.1.1.1, 198.51.100.1"},
+			want:       "198.51.100.1",
+		},
+		{
+			name:       "Garbage in header",
+			remoteAddr: "10.1.2.3:8080",
+			forwarded:  []string{"unknown"},
+			want:       "10.1.2.3",
+		},
+		{
+			name:       "IPv6",
+			remoteAddr: "[2001:db8::1]:443",
+			want:       "2001:db8::1",
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			r := httptest.NewRequest("GET", "/", nil)
+			r.RemoteAddr = tc.remoteAddr
+			for _, v := range tc.forwarded {
+				r.Header.Add("X-Forwarded-For", v)
+			}
+
+			assert.Equal(t, tc.want, resolver.IP(r))
+		})
+	}
+}
+
+func TestNewInvalidProxy(t *testing.T) {
+	_, err := clientip.New([]string{"not-an-ip"})
+	assert.Error(t, err)
+}
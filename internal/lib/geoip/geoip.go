@@ -0,0 +1,62 @@
+package geoip
+
+import (
+	"fmt"
+	"net"
+
+	"github.com/oschwald/maxminddb-golang"
+)
+
+// DB looks up the country of IP addresses in a local MaxMind database, such as
+// GeoLite2-Country or GeoIP2-Country. A nil DB knows no countries, so lookups can be
+// disabled by not opening one.
+type DB struct {
+	reader *maxminddb.Reader
+}
+
+// Open opens the database file at path.
+func Open(path string) (*DB, error) {
+	const op = "lib.geoip.Open"
+
+	reader, err := maxminddb.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return &DB{reader: reader}, nil
+}
+
+type record struct {
+	Country struct {
+		ISOCode string `maxminddb:"iso_code"`
+	} `maxminddb:"country"`
+}
+
+// Country returns the ISO 3166-1 alpha-2 code of the country of the address ip, or an
+// empty string when it is unknown.
+func (db *DB) Country(ip string) string {
+	if db == nil {
+		return ""
+	}
+
+	addr := net.ParseIP(ip)
+	if addr == nil {
+		return ""
+	}
+
+	var r record
+	if err := db.reader.Lookup(addr, &r); err != nil {
+		return ""
+	}
+
+	return r.Country.ISOCode
+}
+
+// Close closes the database file.
+func (db *DB) Close() error {
+	if db == nil {
+		return nil
+	}
+
+	return db.reader.Close()
+}
@@ -0,0 +1,45 @@
+package geoip
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCountry(t *testing.T) {
+	// testdata/country-test.mmdb maps 203.0.113.0/24 to JP, 198.51.100.0/24 to DE and
+	// 2001:db8::/32 to FR.
+	db, err := Open("testdata/country-test.mmdb")
+	require.NoError(t, err)
+	defer db.Close()
+
+	tests := []struct {
+		ip   string
+		want string
+	}{
+		{ip: "203.0.113.7", want: "JP"},
+		{ip: "198.51.100.1", want: "DE"},
+		{ip: "2001:db8::1", want: "FR"},
+		{ip: "192.0.2.1", want: ""},
+		{ip: "not an ip", want: ""},
+		{ip: "", want: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.ip, func(t *testing.T) {
+			assert.Equal(t, tt.want, db.Country(tt.ip))
+		})
+	}
+}
+
+func TestDisabled(t *testing.T) {
+	var db *DB
+
+	assert.Empty(t, db.Country("203.0.113.7"))
+	assert.NoError(t, db.Close())
+}
+
+func TestOpenMissing(t *testing.T) {
+	_, err := Open("testdata/missing.mmdb")
+	assert.Error(t, err)
+}
@@ -0,0 +1,17 @@
+package health
+
+import "sync/atomic"
+
+// State tracks whether the service is shutting down, so readiness probes fail and
+// traffic drains before the server stops.
+type State struct {
+	shuttingDown atomic.Bool
+}
+
+func (s *State) StartShutdown() {
+	s.shuttingDown.Store(true)
+}
+
+func (s *State) ShuttingDown() bool {
+	return s.shuttingDown.Load()
+}
@@ -0,0 +1,16 @@
+package hostname
+
+import (
+	"net"
+	"strings"
+)
+
+// Normalize strips the port and the trailing dot from host and lowercases it,
+// so that the value from a Host header can be compared with a registered domain.
+func Normalize(host string) string {
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+
+	return strings.ToLower(strings.TrimSuffix(host, "."))
+}
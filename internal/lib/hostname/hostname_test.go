@@ -0,0 +1,26 @@
+package hostname
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalize(t *testing.T) {
+	tests := []struct {
+		host string
+		want string
+	}{
+		{host: "sho.rt", want: "sho.rt"},
+		{host: "Sho.RT", want: "sho.rt"},
+		{host: "sho.rt:8080", want: "sho.rt"},
+		{host: "sho.rt.", want: "sho.rt"},
+		{host: "[::1]:8080", want: "::1"},
+		{host: "", want: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.host, func(t *testing.T) {
+			assert.Equal(t, tt.want, Normalize(tt.host))
+		})
+	}
+}
@@ -0,0 +1,237 @@
+package jwtauth
+
+import (
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+var (
+	ErrExpired          = errors.New("token is expired")
+	ErrNotValidYet      = errors.New("token is not valid yet")
+	ErrInvalidSignature = errors.New("token signature is invalid")
+	ErrInvalidClaims    = errors.New("token claims are invalid")
+	ErrUnknownKey       = errors.New("token signing key is unknown")
+	ErrMalformed        = errors.New("token is malformed")
+	ErrNoKeys           = errors.New("no verification keys configured")
+)
+
+var algorithms = []string{
+	jwt.SigningMethodHS256.Alg(),
+	jwt.SigningMethodRS256.Alg(),
+	jwt.SigningMethodEdDSA.Alg(),
+}
+
+type Config struct {
+	// HMACSecret verifies HS256 tokens.
+	HMACSecret string
+	// PublicKeyFile is a PEM encoded RSA or Ed25519 public key verifying RS256 or EdDSA tokens.
+	PublicKeyFile string
+	// JWKSFile is a local JSON Web Key Set. Its keys are picked by the kid header of a token.
+	JWKSFile string
+	// Issuer and Audience, when set, must match the iss and aud claims.
+	Issuer   string
+	Audience string
+	// ClockSkew is the tolerance for the exp, nbf and iat claims.
+	ClockSkew time.Duration
+	// RoleClaim is the name of the claim holding the role.
+	RoleClaim string
+}
+
+// Claims are the parts of a verified token the service cares about.
+type Claims struct {
+	Subject string
+	Role    string
+}
+
+// Verifier checks the signature and the registered claims of JWTs with local keys only.
+type Verifier struct {
+	parser    *jwt.Parser
+	roleClaim string
+
+	hmacSecret []byte
+	rsaKey     *rsa.PublicKey
+	edKey      ed25519.PublicKey
+	jwks       map[string]any
+}
+
+func New(cfg Config) (*Verifier, error) {
+	const op = "lib.jwtauth.New"
+
+	v := &Verifier{
+		roleClaim: cfg.RoleClaim,
+	}
+
+	if cfg.HMACSecret != "" {
+		v.hmacSecret = []byte(cfg.HMACSecret)
+	}
+
+	if cfg.PublicKeyFile != "" {
+		data, err := os.ReadFile(cfg.PublicKeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+
+		key, err := parsePublicKey(data)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %s: %w", op, cfg.PublicKeyFile, err)
+		}
+
+		switch key := key.(type) {
+		case *rsa.PublicKey:
+			v.rsaKey = key
+		case ed25519.PublicKey:
+			v.edKey = key
+		}
+	}
+
+	if cfg.JWKSFile != "" {
+		data, err := os.ReadFile(cfg.JWKSFile)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+
+		v.jwks, err = ParseJWKS(data)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %s: %w", op, cfg.JWKSFile, err)
+		}
+	}
+
+	if v.hmacSecret == nil && v.rsaKey == nil && v.edKey == nil && len(v.jwks) == 0 {
+		return nil, fmt.Errorf("%s: %w", op, ErrNoKeys)
+	}
+
+	opts := []jwt.ParserOption{
+		jwt.WithValidMethods(algorithms),
+		jwt.WithLeeway(cfg.ClockSkew),
+		jwt.WithExpirationRequired(),
+		jwt.WithIssuedAt(),
+	}
+	if cfg.Issuer != "" {
+		opts = append(opts, jwt.WithIssuer(cfg.Issuer))
+	}
+	if cfg.Audience != "" {
+		opts = append(opts, jwt.WithAudience(cfg.Audience))
+	}
+
+	v.parser = jwt.NewParser(opts...)
+
+	return v, nil
+}
+
+// Verify parses the token, checks it and returns its claims. The returned error
+// wraps one of the package errors describing why the token was rejected.
+func (v *Verifier) Verify(token string) (Claims, error) {
+	claims := jwt.MapClaims{}
+
+	_, err := v.parser.ParseWithClaims(token, claims, v.key)
+	if err != nil {
+		return Claims{}, classify(err)
+	}
+
+	sub, _ := claims.GetSubject()
+	if sub == "" {
+		return Claims{}, fmt.Errorf("%w: sub is missing", ErrInvalidClaims)
+	}
+
+	var role string
+	if v.roleClaim != "" {
+		if value, ok := claims[v.roleClaim]; ok {
+			if role, ok = value.(string); !ok {
+				return Claims{}, fmt.Errorf("%w: %s is not a string", ErrInvalidClaims, v.roleClaim)
+			}
+		}
+	}
+
+	return Claims{Subject: sub, Role: role}, nil
+}
+
+// key returns the verification key for the token. A kid found in the JWKS wins over
+// the statically configured keys.
+func (v *Verifier) key(t *jwt.Token) (any, error) {
+	if kid, ok := t.Header["kid"].(string); ok {
+		if key, ok := v.jwks[kid]; ok {
+			if !keyMatches(t.Method, key) {
+				return nil, fmt.Errorf("%w: key %q doesn't match alg %s", ErrUnknownKey, kid, t.Method.Alg())
+			}
+
+			return key, nil
+		}
+	}
+
+	switch t.Method.(type) {
+	case *jwt.SigningMethodHMAC:
+		if v.hmacSecret != nil {
+			return v.hmacSecret, nil
+		}
+	case *jwt.SigningMethodRSA:
+		if v.rsaKey != nil {
+			return v.rsaKey, nil
+		}
+	case *jwt.SigningMethodEd25519:
+		if v.edKey != nil {
+			return v.edKey, nil
+		}
+	}
+
+	return nil, fmt.Errorf("%w: no key for alg %s", ErrUnknownKey, t.Method.Alg())
+}
+
+func keyMatches(method jwt.SigningMethod, key any) bool {
+	switch method.(type) {
+	case *jwt.SigningMethodHMAC:
+		_, ok := key.([]byte)
+		return ok
+	case *jwt.SigningMethodRSA:
+		_, ok := key.(*rsa.PublicKey)
+		return ok
+	case *jwt.SigningMethodEd25519:
+		_, ok := key.(ed25519.PublicKey)
+		return ok
+	}
+
+	return false
+}
+
+func classify(err error) error {
+	switch {
+	case errors.Is(err, ErrUnknownKey):
+		return err
+	case errors.Is(err, jwt.ErrTokenExpired):
+		return fmt.Errorf("%w: %w", ErrExpired, err)
+	case errors.Is(err, jwt.ErrTokenNotValidYet), errors.Is(err, jwt.ErrTokenUsedBeforeIssued):
+		return fmt.Errorf("%w: %w", ErrNotValidYet, err)
+	case errors.Is(err, jwt.ErrTokenSignatureInvalid), errors.Is(err, jwt.ErrTokenUnverifiable):
+		return fmt.Errorf("%w: %w", ErrInvalidSignature, err)
+	case errors.Is(err, jwt.ErrTokenInvalidClaims):
+		return fmt.Errorf("%w: %w", ErrInvalidClaims, err)
+	default:
+		return fmt.Errorf("%w: %w", ErrMalformed, err)
+	}
+}
+
+func parsePublicKey(data []byte) (any, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("no PEM block found")
+	}
+
+	key, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+
+	switch key.(type) {
+	case *rsa.PublicKey, ed25519.PublicKey:
+		return key, nil
+	default:
+		return nil, fmt.Errorf("unsupported key type %T", key)
+	}
+}
@@ -0,0 +1,211 @@
+package jwtauth_test
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dkhrunov/url-shortener/internal/lib/jwtauth"
+)
+
+const secret = "top-secret-hmac-key"
+
+func TestVerifier(t *testing.T) {
+	dir := t.TempDir()
+
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	edPub, edKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	_, otherEdKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	pubFile := filepath.Join(dir, "rsa.pem")
+	der, err := x509.MarshalPKIXPublicKey(&rsaKey.PublicKey)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(pubFile, pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}), 0o600))
+
+	b64 := base64.RawURLEncoding.EncodeToString
+	jwksFile := filepath.Join(dir, "jwks.json")
+	jwks := fmt.Sprintf(`{"keys": [
+		{"kty": "OKP", "crv": "Ed25519", "kid": "ed-1", "x": %q},
+		{"kty": "RSA", "kid": "rsa-1", "n": %q, "e": %q},
+		{"kty": "RSA", "kid": "enc-1", "use": "enc", "n": "", "e": ""}
+	]}`, b64(edPub), b64(rsaKey.N.Bytes()), b64(big.NewInt(int64(rsaKey.E)).Bytes()))
+	require.NoError(t, os.WriteFile(jwksFile, []byte(jwks), 0o600))
+
+	verifier, err := jwtauth.New(jwtauth.Config{
+		HMACSecret:    secret,
+		PublicKeyFile: pubFile,
+		JWKSFile:      jwksFile,
+		Issuer:        "https://sso.example",
+		Audience:      "url-shortener",
+		ClockSkew:     30 * time.Second,
+		RoleClaim:     "role",
+	})
+	require.NoError(t, err)
+
+	now := time.Now()
+
+	claims := func(mutate func(jwt.MapClaims)) jwt.MapClaims {
+		c := jwt.MapClaims{
+			"sub":  "alice",
+			"role": "editor",
+			"iss":  "https://sso.example",
+			"aud":  "url-shortener",
+			"iat":  now.Unix(),
+			"exp":  now.Add(time.Hour).Unix(),
+		}
+		if mutate != nil {
+			mutate(c)
+		}
+		return c
+	}
+
+	sign := func(method jwt.SigningMethod, kid string, key any, c jwt.MapClaims) string {
+		token := jwt.NewWithClaims(method, c)
+		if kid != "" {
+			token.Header["kid"] = kid
+		}
+
+		s, err := token.SignedString(key)
+		require.NoError(t, err)
+
+		return s
+	}
+
+	cases := []struct {
+		name   string
+		token  string
+		claims jwtauth.Claims
+		err    error
+	}{
+		{
+			name:   "HS256",
+			token:  sign(jwt.SigningMethodHS256, "", []byte(secret), claims(nil)),
+			claims: jwtauth.Claims{Subject: "alice", Role: "editor"},
+		},
+		{
+			name:   "RS256 with public key file",
+			token:  sign(jwt.SigningMethodRS256, "", rsaKey, claims(nil)),
+			claims: jwtauth.Claims{Subject: "alice", Role: "editor"},
+		},
+		{
+			name:   "RS256 with JWKS",
+			token:  sign(jwt.SigningMethodRS256, "rsa-1", rsaKey, claims(nil)),
+			claims: jwtauth.Claims{Subject: "alice", Role: "editor"},
+		},
+		{
+			name:   "EdDSA with JWKS",
+			token:  sign(jwt.SigningMethodEdDSA, "ed-1", edKey, claims(nil)),
+			claims: jwtauth.Claims{Subject: "alice", Role: "editor"},
+		},
+		{
+			name:   "Without role",
+			token:  sign(jwt.SigningMethodHS256, "", []byte(secret), claims(func(c jwt.MapClaims) { delete(c, "role") })),
+			claims: jwtauth.Claims{Subject: "alice"},
+		},
+		{
+			name:   "Expired within clock skew",
+			token:  sign(jwt.SigningMethodHS256, "", []byte(secret), claims(func(c jwt.MapClaims) { c["exp"] = now.Add(-10 * time.Second).Unix() })),
+			claims: jwtauth.Claims{Subject: "alice", Role: "editor"},
+		},
+		{
+			name:  "Expired",
+			token: sign(jwt.SigningMethodHS256, "", []byte(secret), claims(func(c jwt.MapClaims) { c["exp"] = now.Add(-time.Minute).Unix() })),
+			err:   jwtauth.ErrExpired,
+		},
+		{
+			name:  "Without exp",
+			token: sign(jwt.SigningMethodHS256, "", []byte(secret), claims(func(c jwt.MapClaims) { delete(c, "exp") })),
+			err:   jwtauth.ErrInvalidClaims,
+		},
+		{
+			name:  "Not valid yet",
+			token: sign(jwt.SigningMethodHS256, "", []byte(secret), claims(func(c jwt.MapClaims) { c["nbf"] = now.Add(time.Minute).Unix() })),
+			err:   jwtauth.ErrNotValidYet,
+		},
+		{
+			name:  "Wrong issuer",
+			token: sign(jwt.SigningMethodHS256, "", []byte(secret), claims(func(c jwt.MapClaims) { c["iss"] = "https://evil.example" })),
+			err:   jwtauth.ErrInvalidClaims,
+		},
+		{
+			name:  "Wrong audience",
+			token: sign(jwt.SigningMethodHS256, "", []byte(secret), claims(func(c jwt.MapClaims) { c["aud"] = "other" })),
+			err:   jwtauth.ErrInvalidClaims,
+		},
+		{
+			name:  "Without subject",
+			token: sign(jwt.SigningMethodHS256, "", []byte(secret), claims(func(c jwt.MapClaims) { delete(c, "sub") })),
+			err:   jwtauth.ErrInvalidClaims,
+		},
+		{
+			name:  "Wrong secret",
+			token: sign(jwt.SigningMethodHS256, "", []byte("another-secret"), claims(nil)),
+			err:   jwtauth.ErrInvalidSignature,
+		},
+		{
+			name:  "Wrong Ed25519 key",
+			token: sign(jwt.SigningMethodEdDSA, "ed-1", otherEdKey, claims(nil)),
+			err:   jwtauth.ErrInvalidSignature,
+		},
+		{
+			name:  "Unknown kid without static key",
+			token: sign(jwt.SigningMethodEdDSA, "ed-2", edKey, claims(nil)),
+			err:   jwtauth.ErrUnknownKey,
+		},
+		{
+			name:  "Kid of another key type",
+			token: sign(jwt.SigningMethodHS256, "rsa-1", []byte(secret), claims(nil)),
+			err:   jwtauth.ErrUnknownKey,
+		},
+		{
+			name:  "Unsupported algorithm",
+			token: sign(jwt.SigningMethodHS512, "", []byte(secret), claims(nil)),
+			err:   jwtauth.ErrInvalidSignature,
+		},
+		{
+			name:  "Malformed",
+			token: "not.a.jwt",
+			err:   jwtauth.ErrMalformed,
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := verifier.Verify(tc.token)
+			if tc.err != nil {
+				assert.ErrorIs(t, err, tc.err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tc.claims, got)
+		})
+	}
+}
+
+func TestNewWithoutKeys(t *testing.T) {
+	_, err := jwtauth.New(jwtauth.Config{})
+	assert.ErrorIs(t, err, jwtauth.ErrNoKeys)
+}
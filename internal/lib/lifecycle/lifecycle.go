@@ -0,0 +1,79 @@
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// Lifecycle runs the long-lived parts of the service and stops them in order.
+type Lifecycle struct {
+	mu    sync.Mutex
+	steps []step
+
+	failed   chan error
+	failOnce sync.Once
+}
+
+type step struct {
+	name string
+	stop func(ctx context.Context) error
+}
+
+func New() *Lifecycle {
+	return &Lifecycle{
+		failed: make(chan error, 1),
+	}
+}
+
+// Go runs fn, e.g. a server's ListenAndServe, in a goroutine. An error from fn ends
+// Wait, so the service shuts down instead of running without it.
+func (l *Lifecycle) Go(name string, fn func() error) {
+	go func() {
+		if err := fn(); err != nil {
+			l.failOnce.Do(func() {
+				l.failed <- fmt.Errorf("%s: %w", name, err)
+			})
+		}
+	}()
+}
+
+// Wait blocks until ctx is done, e.g. on a signal, or until a func run with Go fails,
+// and returns the error of that func.
+func (l *Lifecycle) Wait(ctx context.Context) error {
+	select {
+	case <-ctx.Done():
+		return nil
+	case err := <-l.failed:
+		return err
+	}
+}
+
+// OnStop registers a step of the shutdown.
+func (l *Lifecycle) OnStop(name string, stop func(ctx context.Context) error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.steps = append(l.steps, step{name: name, stop: stop})
+}
+
+// Stop runs the steps in reverse order of registration, like deferred calls, so each
+// component stops before the ones it was built on: servers drain their requests before
+// workers flush, and workers flush before the storage closes. Every step runs, even
+// after an earlier one failed or ctx expired, and the errors of all of them are returned.
+func (l *Lifecycle) Stop(ctx context.Context) error {
+	l.mu.Lock()
+	steps := l.steps
+	l.steps = nil
+	l.mu.Unlock()
+
+	var errs []error
+	for i := len(steps) - 1; i >= 0; i-- {
+		if err := steps[i].stop(ctx); err != nil {
+			errs = append(errs, fmt.Errorf("stop %s: %w", steps[i].name, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
@@ -0,0 +1,63 @@
+package lifecycle_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/dkhrunov/url-shortener/internal/lib/lifecycle"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStop(t *testing.T) {
+	app := lifecycle.New()
+
+	var stopped []string
+	stopFn := func(name string, err error) func(context.Context) error {
+		return func(context.Context) error {
+			stopped = append(stopped, name)
+
+			return err
+		}
+	}
+
+	app.OnStop("storage", stopFn("storage", errors.New("database is locked")))
+	app.OnStop("worker", stopFn("worker", nil))
+	app.OnStop("server", stopFn("server", context.DeadlineExceeded))
+
+	err := app.Stop(context.Background())
+	require.Error(t, err)
+
+	// Steps run in reverse order and a failing step doesn't stop the others.
+	assert.Equal(t, []string{"server", "worker", "storage"}, stopped)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.ErrorContains(t, err, "stop server: context deadline exceeded")
+	assert.ErrorContains(t, err, "stop storage: database is locked")
+
+	// Steps run once.
+	require.NoError(t, app.Stop(context.Background()))
+	assert.Len(t, stopped, 3)
+}
+
+func TestWait(t *testing.T) {
+	t.Run("Context done", func(t *testing.T) {
+		app := lifecycle.New()
+		app.Go("server", func() error { return nil })
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+
+		assert.NoError(t, app.Wait(ctx))
+	})
+
+	t.Run("Failed", func(t *testing.T) {
+		app := lifecycle.New()
+		app.Go("server", func() error { return errors.New("address already in use") })
+		app.Go("metrics server", func() error { return errors.New("address already in use") })
+
+		err := app.Wait(context.Background())
+		assert.ErrorContains(t, err, "server: address already in use")
+	})
+}
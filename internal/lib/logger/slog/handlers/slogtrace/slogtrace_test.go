@@ -0,0 +1,47 @@
+package slogtrace_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/dkhrunov/url-shortener/internal/lib/logger/slog/handlers/slogtrace"
+)
+
+func TestTraceHandler(t *testing.T) {
+	traceID, err := trace.TraceIDFromHex("4bf92f3577b34da6a3ce929d0e0e4736")
+	require.NoError(t, err)
+
+	spanID, err := trace.SpanIDFromHex("00f067aa0ba902b7")
+	require.NoError(t, err)
+
+	ctx := trace.ContextWithSpanContext(context.Background(), trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID: traceID,
+		SpanID:  spanID,
+	}))
+
+	var buf bytes.Buffer
+
+	log := slog.New(slogtrace.NewTraceHandler(slog.NewJSONHandler(&buf, nil))).With(slog.String("op", "test"))
+
+	log.InfoContext(ctx, "traced")
+	log.Info("not traced")
+
+	dec := json.NewDecoder(&buf)
+
+	var traced, notTraced map[string]any
+
+	require.NoError(t, dec.Decode(&traced))
+	require.NoError(t, dec.Decode(&notTraced))
+
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", traced["trace_id"])
+	assert.Equal(t, "00f067aa0ba902b7", traced["span_id"])
+	assert.Equal(t, "test", traced["op"])
+	assert.NotContains(t, notTraced, "trace_id")
+}
@@ -0,0 +1,113 @@
+package metrics
+
+import (
+	"database/sql"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "url_shortener"
+
+// Metrics holds the collectors of the service in a registry of its own.
+type Metrics struct {
+	registry *prometheus.Registry
+
+	requests        *prometheus.CounterVec
+	requestDuration *prometheus.HistogramVec
+	redirects       *prometheus.CounterVec
+	links           *prometheus.CounterVec
+	storageDuration *prometheus.HistogramVec
+}
+
+func New() *Metrics {
+	m := &Metrics{
+		registry: prometheus.NewRegistry(),
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "http_requests_total",
+			Help:      "HTTP requests by route pattern, method and status.",
+		}, []string{"route", "method", "status"}),
+		requestDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "http_request_duration_seconds",
+			Help:      "HTTP request latency by route pattern, method and status.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"route", "method", "status"}),
+		redirects: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "redirects_total",
+			Help:      "Redirects by result, hit or miss.",
+		}, []string{"result"}),
+		links: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "links_total",
+			Help:      "Link changes by event, created or deleted.",
+		}, []string{"event"}),
+		storageDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "storage_operation_duration_seconds",
+			Help:      "Storage operation latency by operation.",
+			Buckets:   []float64{.0005, .001, .0025, .005, .01, .025, .05, .1, .25, .5, 1},
+		}, []string{"op"}),
+	}
+
+	m.registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		m.requests,
+		m.requestDuration,
+		m.redirects,
+		m.links,
+		m.storageDuration,
+	)
+
+	return m
+}
+
+// Handler serves the metrics in the Prometheus exposition format.
+func (m *Metrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{})
+}
+
+// RegisterDB exports the connection pool stats of a database.
+func (m *Metrics) RegisterDB(name string, stats func() sql.DBStats) {
+	m.registry.MustRegister(newDBCollector(name, stats))
+}
+
+func (m *Metrics) ObserveRequest(route, method string, status int, d time.Duration) {
+	code := strconv.Itoa(status)
+
+	m.requests.WithLabelValues(route, method, code).Inc()
+	m.requestDuration.WithLabelValues(route, method, code).Observe(d.Seconds())
+}
+
+func (m *Metrics) CountRedirect(hit bool) {
+	if hit {
+		m.redirects.WithLabelValues("hit").Inc()
+		return
+	}
+
+	m.redirects.WithLabelValues("miss").Inc()
+}
+
+func (m *Metrics) CountLinkCreated() {
+	m.links.WithLabelValues("created").Inc()
+}
+
+func (m *Metrics) CountLinkDeleted() {
+	m.links.WithLabelValues("deleted").Inc()
+}
+
+// CountLinksDeleted counts links deleted at once, e.g. all links with a tag.
+func (m *Metrics) CountLinksDeleted(n int64) {
+	m.links.WithLabelValues("deleted").Add(float64(n))
+}
+
+func (m *Metrics) ObserveStorage(op string, d time.Duration) {
+	m.storageDuration.WithLabelValues(op).Observe(d.Seconds())
+}
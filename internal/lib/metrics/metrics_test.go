@@ -0,0 +1,51 @@
+package metrics_test
+
+import (
+	"database/sql"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dkhrunov/url-shortener/internal/lib/metrics"
+)
+
+func TestMetrics(t *testing.T) {
+	m := metrics.New()
+
+	m.ObserveRequest("/{alias}", http.MethodGet, http.StatusFound, 3*time.Millisecond)
+	m.CountRedirect(true)
+	m.CountRedirect(false)
+	m.CountLinkCreated()
+	m.CountLinkDeleted()
+	m.ObserveStorage("storage.sqlite.GetURL", time.Millisecond)
+	m.RegisterDB("sqlite", func() sql.DBStats {
+		return sql.DBStats{OpenConnections: 2, InUse: 1, Idle: 1, WaitCount: 5}
+	})
+
+	w := httptest.NewRecorder()
+	m.Handler().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+	require.Equal(t, http.StatusOK, w.Code)
+
+	body := w.Body.String()
+
+	for _, line := range []string{
+		`url_shortener_http_requests_total{method="GET",route="/{alias}",status="302"} 1`,
+		`url_shortener_http_request_duration_seconds_count{method="GET",route="/{alias}",status="302"} 1`,
+		`url_shortener_redirects_total{result="hit"} 1`,
+		`url_shortener_redirects_total{result="miss"} 1`,
+		`url_shortener_links_total{event="created"} 1`,
+		`url_shortener_links_total{event="deleted"} 1`,
+		`url_shortener_storage_operation_duration_seconds_count{op="storage.sqlite.GetURL"} 1`,
+		`url_shortener_db_open_connections{db="sqlite"} 2`,
+		`url_shortener_db_in_use_connections{db="sqlite"} 1`,
+		`url_shortener_db_wait_count_total{db="sqlite"} 5`,
+		`go_goroutines`,
+	} {
+		assert.Contains(t, body, line)
+	}
+}
@@ -0,0 +1,166 @@
+package qrcode
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"strings"
+
+	goqrcode "github.com/skip2/go-qrcode"
+)
+
+var (
+	ErrInvalidLevel = errors.New("invalid error correction level")
+	ErrSizeTooSmall = errors.New("size is too small for the qr code")
+)
+
+type Format string
+
+const (
+	FormatPNG Format = "png"
+	FormatSVG Format = "svg"
+)
+
+type Level int
+
+const (
+	LevelLow Level = iota
+	LevelMedium
+	LevelQuartile
+	LevelHigh
+)
+
+type Options struct {
+	// Size is the width and height of the resulting image in pixels.
+	Size int
+	// Level is the error correction level of the code.
+	Level Level
+	// Margin is the width of the quiet zone around the code in modules.
+	Margin int
+}
+
+// ParseLevel converts one of the standard level letters (L, M, Q, H) to the recovery level.
+func ParseLevel(level string) (Level, error) {
+	switch strings.ToUpper(level) {
+	case "L":
+		return LevelLow, nil
+	case "M":
+		return LevelMedium, nil
+	case "Q":
+		return LevelQuartile, nil
+	case "H":
+		return LevelHigh, nil
+	default:
+		return LevelMedium, ErrInvalidLevel
+	}
+}
+
+// Encode renders content as a QR code image in the given format.
+func Encode(content string, format Format, opts Options) ([]byte, error) {
+	const op = "lib.qrcode.Encode"
+
+	modules, err := bitmap(content, opts)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	switch format {
+	case FormatPNG:
+		return encodePNG(modules, opts.Size)
+	case FormatSVG:
+		return encodeSVG(modules, opts.Size), nil
+	default:
+		return nil, fmt.Errorf("%s: unsupported format %q", op, format)
+	}
+}
+
+var recoveryLevels = map[Level]goqrcode.RecoveryLevel{
+	LevelLow:      goqrcode.Low,
+	LevelMedium:   goqrcode.Medium,
+	LevelQuartile: goqrcode.High,
+	LevelHigh:     goqrcode.Highest,
+}
+
+// bitmap returns the code modules surrounded by the requested quiet zone.
+func bitmap(content string, opts Options) ([][]bool, error) {
+	code, err := goqrcode.New(content, recoveryLevels[opts.Level])
+	if err != nil {
+		return nil, err
+	}
+
+	code.DisableBorder = true
+	src := code.Bitmap()
+
+	n := len(src) + 2*opts.Margin
+	if opts.Size < n {
+		return nil, ErrSizeTooSmall
+	}
+
+	dst := make([][]bool, n)
+	for y := range dst {
+		dst[y] = make([]bool, n)
+	}
+	for y, row := range src {
+		copy(dst[y+opts.Margin][opts.Margin:], row)
+	}
+
+	return dst, nil
+}
+
+func encodePNG(modules [][]bool, size int) ([]byte, error) {
+	n := len(modules)
+	scale := size / n
+	// The leftover pixels are split evenly around the code to keep modules sharp.
+	offset := (size - n*scale) / 2
+
+	img := image.NewPaletted(image.Rect(0, 0, size, size), color.Palette{color.White, color.Black})
+
+	for y, row := range modules {
+		for x, dark := range row {
+			if !dark {
+				continue
+			}
+
+			for dy := 0; dy < scale; dy++ {
+				for dx := 0; dx < scale; dx++ {
+					img.SetColorIndex(offset+x*scale+dx, offset+y*scale+dy, 1)
+				}
+			}
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+func encodeSVG(modules [][]bool, size int) []byte {
+	n := len(modules)
+
+	var buf bytes.Buffer
+
+	fmt.Fprintf(&buf,
+		`<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d" shape-rendering="crispEdges">`,
+		size, size, n, n,
+	)
+	fmt.Fprintf(&buf, `<rect width="%d" height="%d" fill="#fff"/>`, n, n)
+	buf.WriteString(`<path fill="#000" d="`)
+
+	for y, row := range modules {
+		for x, dark := range row {
+			if dark {
+				fmt.Fprintf(&buf, "M%d %dh1v1h-1z", x, y)
+			}
+		}
+	}
+
+	buf.WriteString(`"/></svg>`)
+
+	return buf.Bytes()
+}
@@ -0,0 +1,80 @@
+package qrcode
+
+import (
+	"bytes"
+	"image/png"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEncode(t *testing.T) {
+	tests := []struct {
+		name   string
+		format Format
+		opts   Options
+		err    error
+	}{
+		{
+			name:   "png",
+			format: FormatPNG,
+			opts:   Options{Size: 256, Level: LevelMedium, Margin: 4},
+		},
+		{
+			name:   "png without margin",
+			format: FormatPNG,
+			opts:   Options{Size: 100, Level: LevelLow, Margin: 0},
+		},
+		{
+			name:   "svg",
+			format: FormatSVG,
+			opts:   Options{Size: 512, Level: LevelHigh, Margin: 2},
+		},
+		{
+			name:   "size too small",
+			format: FormatPNG,
+			opts:   Options{Size: 10, Level: LevelMedium, Margin: 4},
+			err:    ErrSizeTooSmall,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := Encode("http://localhost:8080/abc123", tt.format, tt.opts)
+			if tt.err != nil {
+				require.ErrorIs(t, err, tt.err)
+				return
+			}
+			require.NoError(t, err)
+
+			switch tt.format {
+			case FormatPNG:
+				img, err := png.Decode(bytes.NewReader(data))
+				require.NoError(t, err)
+
+				assert.Equal(t, tt.opts.Size, img.Bounds().Dx())
+				assert.Equal(t, tt.opts.Size, img.Bounds().Dy())
+			case FormatSVG:
+				assert.True(t, strings.HasPrefix(string(data), "<svg"))
+				assert.Contains(t, string(data), `width="512"`)
+			}
+		})
+	}
+}
+
+func TestParseLevel(t *testing.T) {
+	for in, want := range map[string]Level{
+		"L": LevelLow,
+		"m": LevelMedium,
+		"Q": LevelQuartile,
+		"h": LevelHigh,
+	} {
+		got, err := ParseLevel(in)
+		require.NoError(t, err)
+		assert.Equal(t, want, got)
+	}
+
+	_, err := ParseLevel("X")
+	assert.ErrorIs(t, err, ErrInvalidLevel)
+}
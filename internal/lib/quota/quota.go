@@ -0,0 +1,73 @@
+package quota
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/dkhrunov/url-shortener/internal/storage"
+)
+
+// The errors are those of the storage, which enforces the limits when links are saved.
+var (
+	ErrDailyExceeded  = storage.ErrDailyQuotaExceeded
+	ErrActiveExceeded = storage.ErrActiveQuotaExceeded
+)
+
+const (
+	HeaderDailyLimit      = "X-Quota-Daily-Limit"
+	HeaderDailyRemaining  = "X-Quota-Daily-Remaining"
+	HeaderDailyReset      = "X-Quota-Daily-Reset"
+	HeaderActiveLimit     = "X-Quota-Active-Limit"
+	HeaderActiveRemaining = "X-Quota-Active-Remaining"
+)
+
+// Limits caps link creation for a single user or API key. Zero means unlimited.
+type Limits struct {
+	LinksPerDay int
+	ActiveLinks int
+}
+
+// Usage is what a user or an API key has used so far.
+type Usage struct {
+	LinksToday  int
+	ActiveLinks int
+}
+
+func (l Limits) Enabled() bool {
+	return l.LinksPerDay > 0 || l.ActiveLinks > 0
+}
+
+// Remaining returns how many links are left under each limit, -1 meaning unlimited.
+func (l Limits) Remaining(u Usage) (daily, active int) {
+	return remaining(l.LinksPerDay, u.LinksToday), remaining(l.ActiveLinks, u.ActiveLinks)
+}
+
+// SetHeaders describes the configured limits and the usage in response headers.
+func (l Limits) SetHeaders(h http.Header, u Usage, now time.Time) {
+	daily, active := l.Remaining(u)
+
+	if l.LinksPerDay > 0 {
+		h.Set(HeaderDailyLimit, strconv.Itoa(l.LinksPerDay))
+		h.Set(HeaderDailyRemaining, strconv.Itoa(daily))
+		h.Set(HeaderDailyReset, strconv.FormatInt(NextReset(now).Unix(), 10))
+	}
+	if l.ActiveLinks > 0 {
+		h.Set(HeaderActiveLimit, strconv.Itoa(l.ActiveLinks))
+		h.Set(HeaderActiveRemaining, strconv.Itoa(active))
+	}
+}
+
+// NextReset returns when the daily quota starts over, the next midnight UTC.
+func NextReset(now time.Time) time.Time {
+	y, m, d := now.UTC().Date()
+	return time.Date(y, m, d+1, 0, 0, 0, 0, time.UTC)
+}
+
+func remaining(limit, used int) int {
+	if limit <= 0 {
+		return -1
+	}
+
+	return max(limit-used, 0)
+}
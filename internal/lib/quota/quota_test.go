@@ -0,0 +1,24 @@
+package quota_test
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/dkhrunov/url-shortener/internal/lib/quota"
+)
+
+func TestLimitsSetHeaders(t *testing.T) {
+	now := time.Date(2024, 3, 31, 18, 30, 0, 0, time.UTC)
+
+	h := http.Header{}
+	quota.Limits{LinksPerDay: 10}.SetHeaders(h, quota.Usage{LinksToday: 12, ActiveLinks: 3}, now)
+
+	assert.Equal(t, "10", h.Get(quota.HeaderDailyLimit))
+	assert.Equal(t, "0", h.Get(quota.HeaderDailyRemaining))
+	assert.Equal(t, "1711929600", h.Get(quota.HeaderDailyReset))
+	assert.Empty(t, h.Get(quota.HeaderActiveLimit))
+	assert.Empty(t, h.Get(quota.HeaderActiveRemaining))
+}
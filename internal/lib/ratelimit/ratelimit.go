@@ -0,0 +1,119 @@
+package ratelimit
+
+import (
+	"math"
+	"sync"
+	"time"
+)
+
+// Rate allows Requests per Period on average with bursts of up to Burst requests.
+// A zero Burst means Requests.
+type Rate struct {
+	Requests int
+	Period   time.Duration
+	Burst    int
+}
+
+func (r Rate) Enabled() bool {
+	return r.Requests > 0 && r.Period > 0
+}
+
+func (r Rate) burst() int {
+	if r.Burst > 0 {
+		return r.Burst
+	}
+
+	return r.Requests
+}
+
+// interval is the time it takes to refill one token.
+func (r Rate) interval() time.Duration {
+	return r.Period / time.Duration(r.Requests)
+}
+
+// Result is the outcome of taking a token from a bucket.
+type Result struct {
+	Allowed   bool
+	Limit     int
+	Remaining int
+	// RetryAfter is how long to wait for the next token when the request is not allowed.
+	RetryAfter time.Duration
+	// ResetAfter is how long it takes for the bucket to be full again.
+	ResetAfter time.Duration
+}
+
+// Store keeps token buckets. Memory is the implementation for a single instance; a
+// shared store lets several instances enforce a common limit.
+type Store interface {
+	Allow(key string, rate Rate) (Result, error)
+}
+
+type bucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// Memory is an in-process Store. Buckets that are full again are dropped, so a
+// Memory should only be used with a single Rate.
+type Memory struct {
+	mu        sync.Mutex
+	buckets   map[string]*bucket
+	now       func() time.Time
+	lastSweep time.Time
+}
+
+func NewMemory() *Memory {
+	return &Memory{
+		buckets: make(map[string]*bucket),
+		now:     time.Now,
+	}
+}
+
+func (m *Memory) Allow(key string, rate Rate) (Result, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := m.now()
+	burst := float64(rate.burst())
+	interval := rate.interval()
+
+	m.sweep(now, time.Duration(burst)*interval)
+
+	b, ok := m.buckets[key]
+	if !ok {
+		b = &bucket{tokens: burst, last: now}
+		m.buckets[key] = b
+	}
+
+	b.tokens = math.Min(burst, b.tokens+float64(now.Sub(b.last))/float64(interval))
+	b.last = now
+
+	res := Result{Limit: rate.burst()}
+
+	if b.tokens >= 1 {
+		b.tokens--
+		res.Allowed = true
+	} else {
+		res.RetryAfter = time.Duration((1 - b.tokens) * float64(interval))
+	}
+
+	res.Remaining = int(b.tokens)
+	res.ResetAfter = time.Duration((burst - b.tokens) * float64(interval))
+
+	return res, nil
+}
+
+// sweep drops buckets idle for longer than it takes to refill them, at most once per
+// that period, to keep the map from growing with every client ever seen.
+func (m *Memory) sweep(now time.Time, idle time.Duration) {
+	if now.Sub(m.lastSweep) < idle {
+		return
+	}
+	m.lastSweep = now
+
+	for key, b := range m.buckets {
+		if now.Sub(b.last) >= idle {
+			delete(m.buckets, key)
+		}
+	}
+}
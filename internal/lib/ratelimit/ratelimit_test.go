@@ -0,0 +1,73 @@
+package ratelimit
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoryAllow(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	m := NewMemory()
+	m.now = func() time.Time { return now }
+
+	rate := Rate{Requests: 60, Period: time.Minute, Burst: 3}
+
+	for i := 2; i >= 0; i-- {
+		res, err := m.Allow("ip:1.2.3.4", rate)
+		require.NoError(t, err)
+		assert.True(t, res.Allowed)
+		assert.Equal(t, 3, res.Limit)
+		assert.Equal(t, i, res.Remaining)
+	}
+
+	res, err := m.Allow("ip:1.2.3.4", rate)
+	require.NoError(t, err)
+	assert.False(t, res.Allowed)
+	assert.Equal(t, time.Second, res.RetryAfter)
+	assert.Equal(t, 3*time.Second, res.ResetAfter)
+
+	// Other keys have their own bucket.
+	res, err = m.Allow("ip:5.6.7.8", rate)
+	require.NoError(t, err)
+	assert.True(t, res.Allowed)
+
+	// One token is back after a second.
+	now = now.Add(time.Second)
+
+	res, err = m.Allow("ip:1.2.3.4", rate)
+	require.NoError(t, err)
+	assert.True(t, res.Allowed)
+	assert.Equal(t, 0, res.Remaining)
+
+	// The bucket never holds more than the burst.
+	now = now.Add(time.Hour)
+
+	res, err = m.Allow("ip:1.2.3.4", rate)
+	require.NoError(t, err)
+	assert.True(t, res.Allowed)
+	assert.Equal(t, 2, res.Remaining)
+}
+
+func TestMemorySweep(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	m := NewMemory()
+	m.now = func() time.Time { return now }
+
+	rate := Rate{Requests: 10, Period: time.Second}
+
+	_, err := m.Allow("a", rate)
+	require.NoError(t, err)
+
+	now = now.Add(2 * time.Second)
+
+	_, err = m.Allow("b", rate)
+	require.NoError(t, err)
+
+	assert.NotContains(t, m.buckets, "a")
+	assert.Contains(t, m.buckets, "b")
+}
@@ -0,0 +1,110 @@
+package retention
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/dkhrunov/url-shortener/internal/lib/logger/slog/slogerr"
+)
+
+type Purger interface {
+	PurgeDeletedURLs(ctx context.Context, retention time.Duration) (int64, error)
+	PurgeTombstones(ctx context.Context, period time.Duration) (int64, error)
+	PurgeClicks(ctx context.Context, retention time.Duration) (int64, error)
+}
+
+type Config struct {
+	// Period is how long deleted links are kept before they are purged. Zero keeps
+	// deleted links forever.
+	Period time.Duration
+	// TombstonePeriod is how long the aliases of purged links stay tombstoned. Zero
+	// keeps tombstones forever.
+	TombstonePeriod time.Duration
+	// ClickPeriod is how long raw clicks are kept. Zero keeps them forever.
+	ClickPeriod time.Duration
+	// Interval is how often the worker purges.
+	Interval time.Duration
+}
+
+// Worker purges deleted links once they were deleted longer than the retention period
+// ago, so their aliases can be used again, expired alias tombstones and old raw clicks.
+type Worker struct {
+	purger Purger
+	cfg    Config
+	log    *slog.Logger
+
+	stop chan struct{}
+	done chan struct{}
+}
+
+func New(purger Purger, cfg Config, log *slog.Logger) *Worker {
+	return &Worker{
+		purger: purger,
+		cfg:    cfg,
+		log:    log.With(slog.String("component", "retention")),
+		stop:   make(chan struct{}),
+		done:   make(chan struct{}),
+	}
+}
+
+// Run purges right away and then every interval, until Stop is called.
+func (w *Worker) Run() error {
+	defer close(w.done)
+
+	ticker := time.NewTicker(w.cfg.Interval)
+	defer ticker.Stop()
+
+	for {
+		w.purge()
+
+		select {
+		case <-w.stop:
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// Stop ends Run, waiting for a purge in progress to finish or for ctx to end.
+func (w *Worker) Stop(ctx context.Context) error {
+	close(w.stop)
+
+	select {
+	case <-w.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (w *Worker) purge() {
+	ctx := context.Background()
+
+	if w.cfg.Period > 0 {
+		purged, err := w.purger.PurgeDeletedURLs(ctx, w.cfg.Period)
+		if err != nil {
+			w.log.Error("failed to purge deleted urls", slogerr.Error(err))
+		} else if purged > 0 {
+			w.log.Info("purged deleted urls", slog.Int64("count", purged))
+		}
+	}
+
+	if w.cfg.TombstonePeriod > 0 {
+		purged, err := w.purger.PurgeTombstones(ctx, w.cfg.TombstonePeriod)
+		if err != nil {
+			w.log.Error("failed to purge tombstones", slogerr.Error(err))
+		} else if purged > 0 {
+			w.log.Info("purged tombstones", slog.Int64("count", purged))
+		}
+	}
+
+	if w.cfg.ClickPeriod > 0 {
+		purged, err := w.purger.PurgeClicks(ctx, w.cfg.ClickPeriod)
+		if err != nil {
+			w.log.Error("failed to purge clicks", slogerr.Error(err))
+		} else if purged > 0 {
+			w.log.Info("purged clicks", slog.Int64("count", purged))
+		}
+	}
+}
@@ -0,0 +1,98 @@
+package retention_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/dkhrunov/url-shortener/internal/lib/retention"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type purger struct {
+	calls      atomic.Int32
+	retention  atomic.Int64
+	tombstones atomic.Int32
+	clicks     atomic.Int64
+}
+
+func (p *purger) PurgeDeletedURLs(_ context.Context, retention time.Duration) (int64, error) {
+	p.retention.Store(int64(retention))
+
+	if p.calls.Add(1) == 1 {
+		return 0, errors.New("database is locked")
+	}
+
+	return 1, nil
+}
+
+func (p *purger) PurgeTombstones(_ context.Context, period time.Duration) (int64, error) {
+	if period != 365*24*time.Hour {
+		return 0, errors.New("unexpected period")
+	}
+
+	p.tombstones.Add(1)
+
+	return 0, nil
+}
+
+func (p *purger) PurgeClicks(_ context.Context, retention time.Duration) (int64, error) {
+	p.clicks.Store(int64(retention))
+
+	return 0, nil
+}
+
+func TestWorker(t *testing.T) {
+	p := &purger{}
+	log := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+	w := retention.New(p, retention.Config{
+		Period:          24 * time.Hour,
+		TombstonePeriod: 365 * 24 * time.Hour,
+		ClickPeriod:     90 * 24 * time.Hour,
+		Interval:        10 * time.Millisecond,
+	}, log)
+
+	done := make(chan error)
+	go func() { done <- w.Run() }()
+
+	// A failed purge is retried on the next tick.
+	require.Eventually(t, func() bool { return p.calls.Load() >= 3 }, time.Second, 5*time.Millisecond)
+	assert.Equal(t, int64(24*time.Hour), p.retention.Load())
+	assert.Positive(t, p.tombstones.Load())
+	assert.Equal(t, int64(90*24*time.Hour), p.clicks.Load())
+
+	require.NoError(t, w.Stop(context.Background()))
+	require.NoError(t, <-done)
+
+	calls := p.calls.Load()
+	time.Sleep(30 * time.Millisecond)
+	assert.Equal(t, calls, p.calls.Load())
+}
+
+func TestWorkerClicksOnly(t *testing.T) {
+	p := &purger{}
+	log := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+	w := retention.New(p, retention.Config{
+		ClickPeriod: time.Hour,
+		Interval:    10 * time.Millisecond,
+	}, log)
+
+	done := make(chan error)
+	go func() { done <- w.Run() }()
+
+	// Deleted links are kept forever without a period, raw clicks are still purged.
+	require.Eventually(t, func() bool { return p.clicks.Load() == int64(time.Hour) }, time.Second, 5*time.Millisecond)
+
+	require.NoError(t, w.Stop(context.Background()))
+	require.NoError(t, <-done)
+
+	assert.Zero(t, p.calls.Load())
+	assert.Zero(t, p.tombstones.Load())
+}
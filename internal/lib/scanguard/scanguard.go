@@ -0,0 +1,166 @@
+package scanguard
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// Config sets when a client is considered to be scanning for aliases.
+type Config struct {
+	// Threshold is the number of not found responses within Window that blocks a client.
+	Threshold int
+	Window    time.Duration
+	// BlockFor is how long a client stays blocked.
+	BlockFor time.Duration
+}
+
+// Block describes a blocked client.
+type Block struct {
+	Client    string    `json:"client"`
+	Misses    int       `json:"misses"`
+	BlockedAt time.Time `json:"blocked_at"`
+	Until     time.Time `json:"until"`
+}
+
+type window struct {
+	start  time.Time
+	misses int
+}
+
+// Guard counts not found responses per client in fixed windows and blocks clients
+// reaching the threshold for a while. State is kept in memory.
+type Guard struct {
+	cfg Config
+	now func() time.Time
+
+	mu        sync.Mutex
+	windows   map[string]*window
+	blocks    map[string]Block
+	lastSweep time.Time
+}
+
+// Enabled reports whether clients are blocked at all.
+func (c Config) Enabled() bool {
+	return c.Threshold > 0
+}
+
+func New(cfg Config) *Guard {
+	return &Guard{
+		cfg:     cfg,
+		now:     time.Now,
+		windows: make(map[string]*window),
+		blocks:  make(map[string]Block),
+	}
+}
+
+// Blocked returns the block of the client, if it is currently blocked.
+func (g *Guard) Blocked(client string) (Block, bool) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	b, ok := g.blocks[client]
+	if !ok {
+		return Block{}, false
+	}
+	if !g.now().Before(b.Until) {
+		delete(g.blocks, client)
+		return Block{}, false
+	}
+
+	return b, true
+}
+
+// Miss records a not found response for the client. It reports whether the client
+// got blocked by it.
+func (g *Guard) Miss(client string) (Block, bool) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	now := g.now()
+
+	g.sweep(now)
+
+	w, ok := g.windows[client]
+	if !ok || now.Sub(w.start) >= g.cfg.Window {
+		w = &window{start: now}
+		g.windows[client] = w
+	}
+
+	w.misses++
+
+	if w.misses < g.cfg.Threshold {
+		return Block{}, false
+	}
+
+	b := Block{
+		Client:    client,
+		Misses:    w.misses,
+		BlockedAt: now,
+		Until:     now.Add(g.cfg.BlockFor),
+	}
+
+	g.blocks[client] = b
+	delete(g.windows, client)
+
+	return b, true
+}
+
+// List returns the currently blocked clients, the most recently blocked first.
+func (g *Guard) List() []Block {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	now := g.now()
+
+	blocks := make([]Block, 0, len(g.blocks))
+	for client, b := range g.blocks {
+		if !now.Before(b.Until) {
+			delete(g.blocks, client)
+			continue
+		}
+
+		blocks = append(blocks, b)
+	}
+
+	sort.Slice(blocks, func(i, j int) bool {
+		if blocks[i].BlockedAt.Equal(blocks[j].BlockedAt) {
+			return blocks[i].Client < blocks[j].Client
+		}
+
+		return blocks[i].BlockedAt.After(blocks[j].BlockedAt)
+	})
+
+	return blocks
+}
+
+// Unblock lifts the block of the client. It reports whether the client was blocked.
+func (g *Guard) Unblock(client string) bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	b, ok := g.blocks[client]
+	delete(g.blocks, client)
+
+	return ok && g.now().Before(b.Until)
+}
+
+// sweep drops finished windows and expired blocks, at most once per window.
+func (g *Guard) sweep(now time.Time) {
+	if now.Sub(g.lastSweep) < g.cfg.Window {
+		return
+	}
+	g.lastSweep = now
+
+	for client, w := range g.windows {
+		if now.Sub(w.start) >= g.cfg.Window {
+			delete(g.windows, client)
+		}
+	}
+
+	for client, b := range g.blocks {
+		if !now.Before(b.Until) {
+			delete(g.blocks, client)
+		}
+	}
+}
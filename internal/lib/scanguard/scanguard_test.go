@@ -0,0 +1,62 @@
+package scanguard
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGuard(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	g := New(Config{Threshold: 3, Window: time.Minute, BlockFor: 10 * time.Minute})
+	g.now = func() time.Time { return now }
+
+	const client = "203.0.113.7"
+
+	// Misses in an earlier window are forgotten.
+	g.Miss(client)
+	g.Miss(client)
+	now = now.Add(time.Minute)
+
+	_, blocked := g.Miss(client)
+	assert.False(t, blocked)
+	_, blocked = g.Miss(client)
+	assert.False(t, blocked)
+
+	_, ok := g.Blocked(client)
+	assert.False(t, ok)
+
+	b, blocked := g.Miss(client)
+	require.True(t, blocked)
+	assert.Equal(t, Block{Client: client, Misses: 3, BlockedAt: now, Until: now.Add(10 * time.Minute)}, b)
+
+	_, ok = g.Blocked(client)
+	assert.True(t, ok)
+
+	_, ok = g.Blocked("198.51.100.1")
+	assert.False(t, ok)
+
+	assert.Equal(t, []Block{b}, g.List())
+
+	// The block expires.
+	now = now.Add(10 * time.Minute)
+
+	_, ok = g.Blocked(client)
+	assert.False(t, ok)
+	assert.Empty(t, g.List())
+}
+
+func TestGuardUnblock(t *testing.T) {
+	g := New(Config{Threshold: 1, Window: time.Minute, BlockFor: time.Hour})
+
+	g.Miss("203.0.113.7")
+
+	assert.True(t, g.Unblock("203.0.113.7"))
+	assert.False(t, g.Unblock("203.0.113.7"))
+
+	_, ok := g.Blocked("203.0.113.7")
+	assert.False(t, ok)
+}
@@ -0,0 +1,85 @@
+package shorturl
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+var ErrHostNotAllowed = errors.New("host is not allowed")
+
+// Builder makes public short links for aliases.
+type Builder struct {
+	base  *url.URL
+	hosts map[string]struct{}
+}
+
+// New creates a Builder. When baseURL is empty, links are built from the scheme and host
+// of the incoming request. allowedHosts lists the hosts a client may explicitly ask for.
+func New(baseURL string, allowedHosts []string) (*Builder, error) {
+	const op = "lib.shorturl.New"
+
+	b := &Builder{
+		hosts: make(map[string]struct{}, len(allowedHosts)),
+	}
+
+	if baseURL != "" {
+		base, err := url.Parse(baseURL)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		if base.Scheme == "" || base.Host == "" {
+			return nil, fmt.Errorf("%s: base url must be absolute: %s", op, baseURL)
+		}
+
+		base.Path = strings.TrimSuffix(base.Path, "/")
+		b.base = base
+	}
+
+	for _, host := range allowedHosts {
+		b.hosts[strings.ToLower(host)] = struct{}{}
+	}
+
+	return b, nil
+}
+
+// Build returns the short link for alias in the namespace of domain, where an empty domain
+// is the default namespace served from the base URL. Links on a registered domain are built
+// with that domain as the host. A non-empty host overrides it and must be one of the allowed hosts.
+func (b *Builder) Build(r *http.Request, domain, host, alias string) (string, error) {
+	u := b.baseFor(r)
+
+	if host == "" {
+		host = domain
+	} else if host != domain {
+		if _, ok := b.hosts[strings.ToLower(host)]; !ok {
+			return "", ErrHostNotAllowed
+		}
+	}
+
+	if host != "" {
+		u.Host = host
+	}
+
+	u.Path += "/" + alias
+
+	return u.String(), nil
+}
+
+func (b *Builder) baseFor(r *http.Request) url.URL {
+	if b.base != nil {
+		return *b.base
+	}
+
+	scheme := "http"
+	if r.TLS != nil {
+		scheme = "https"
+	}
+
+	return url.URL{
+		Scheme: scheme,
+		Host:   r.Host,
+	}
+}
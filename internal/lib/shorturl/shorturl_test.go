@@ -0,0 +1,85 @@
+package shorturl
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuild(t *testing.T) {
+	tests := []struct {
+		name    string
+		baseURL string
+		hosts   []string
+		domain  string
+		host    string
+		want    string
+		err     error
+	}{
+		{
+			name: "From request",
+			want: "http://example.com/abc",
+		},
+		{
+			name:    "Base URL",
+			baseURL: "https://sho.rt",
+			want:    "https://sho.rt/abc",
+		},
+		{
+			name:    "Base URL with path",
+			baseURL: "https://example.org/s/",
+			want:    "https://example.org/s/abc",
+		},
+		{
+			name:    "Allowed host",
+			baseURL: "https://sho.rt",
+			hosts:   []string{"go.example.org"},
+			host:    "GO.example.org",
+			want:    "https://GO.example.org/abc",
+		},
+		{
+			name:    "Domain",
+			baseURL: "https://sho.rt",
+			domain:  "brand.example",
+			want:    "https://brand.example/abc",
+		},
+		{
+			name:    "Domain host",
+			baseURL: "https://sho.rt",
+			domain:  "brand.example",
+			host:    "brand.example",
+			want:    "https://brand.example/abc",
+		},
+		{
+			name:    "Host not allowed",
+			baseURL: "https://sho.rt",
+			hosts:   []string{"go.example.org"},
+			host:    "evil.com",
+			err:     ErrHostNotAllowed,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, err := New(tt.baseURL, tt.hosts)
+			require.NoError(t, err)
+
+			r := httptest.NewRequest("GET", "/url", nil)
+
+			got, err := b.Build(r, tt.domain, tt.host, "abc")
+			if tt.err != nil {
+				assert.ErrorIs(t, err, tt.err)
+				return
+			}
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestNewInvalidBaseURL(t *testing.T) {
+	_, err := New("sho.rt", nil)
+	assert.Error(t, err)
+}
@@ -0,0 +1,83 @@
+package tags
+
+import (
+	"errors"
+	"slices"
+	"strings"
+)
+
+const (
+	// MaxLength is the longest a tag may be.
+	MaxLength = 32
+	// MaxPerLink is the most tags a link may have.
+	MaxPerLink = 10
+)
+
+var (
+	ErrInvalid = errors.New("tags may only contain letters, digits, '-' and '_' and be up to 32 characters long")
+	ErrTooMany = errors.New("a link may have up to 10 tags")
+)
+
+// Normalize lowercases and trims a tag, so that tags differing only in case are the
+// same tag.
+func Normalize(tag string) string {
+	return strings.ToLower(strings.TrimSpace(tag))
+}
+
+// NormalizeList normalizes the tags of a link, drops duplicates and sorts them. It
+// fails when a tag is not valid or there are more than MaxPerLink of them.
+func NormalizeList(tags []string) ([]string, error) {
+	normalized := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		tag = Normalize(tag)
+		if !Valid(tag) {
+			return nil, ErrInvalid
+		}
+
+		normalized = append(normalized, tag)
+	}
+
+	slices.Sort(normalized)
+	normalized = slices.Compact(normalized)
+
+	if len(normalized) > MaxPerLink {
+		return nil, ErrTooMany
+	}
+
+	return normalized, nil
+}
+
+// Replace returns the sorted tags of a link with tag replaced by newTag, keeping one of
+// them when the link has both. The tags are expected to be normalized.
+func Replace(tags []string, tag, newTag string) []string {
+	replaced := make([]string, 0, len(tags))
+	for _, t := range tags {
+		if t == tag {
+			t = newTag
+		}
+
+		replaced = append(replaced, t)
+	}
+
+	slices.Sort(replaced)
+
+	return slices.Compact(replaced)
+}
+
+// Valid reports whether a normalized tag is not empty, at most MaxLength long and
+// made of lowercase letters, digits, '-' and '_'.
+func Valid(tag string) bool {
+	if tag == "" || len(tag) > MaxLength {
+		return false
+	}
+
+	for _, c := range tag {
+		switch {
+		case c >= 'a' && c <= 'z', c >= '0' && c <= '9', c == '-', c == '_':
+		default:
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,56 @@
+package tags
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalizeList(t *testing.T) {
+	tests := []struct {
+		name    string
+		tags    []string
+		want    []string
+		wantErr error
+	}{
+		{name: "Empty", tags: nil, want: []string{}},
+		{name: "Sorted", tags: []string{"team-b", "spring_sale"}, want: []string{"spring_sale", "team-b"}},
+		{name: "Case and spaces", tags: []string{" Promo ", "promo", "PROMO"}, want: []string{"promo"}},
+		{name: "Blank", tags: []string{" "}, wantErr: ErrInvalid},
+		{name: "Punctuation", tags: []string{"a,b"}, wantErr: ErrInvalid},
+		{name: "Too long", tags: []string{strings.Repeat("a", MaxLength+1)}, wantErr: ErrInvalid},
+		{name: "Longest", tags: []string{strings.Repeat("a", MaxLength)}, want: []string{strings.Repeat("a", MaxLength)}},
+		{
+			name:    "Too many",
+			tags:    []string{"a", "b", "c", "d", "e", "f", "g", "h", "i", "j", "k"},
+			wantErr: ErrTooMany,
+		},
+		{
+			name: "Duplicates don't count",
+			tags: []string{"a", "b", "c", "d", "e", "f", "g", "h", "i", "j", "J"},
+			want: []string{"a", "b", "c", "d", "e", "f", "g", "h", "i", "j"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NormalizeList(tt.tags)
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestReplace(t *testing.T) {
+	assert.Equal(t, []string{"docs", "spring-sale"}, Replace([]string{"docs", "sale"}, "sale", "spring-sale"))
+	assert.Equal(t, []string{"a-sale", "docs"}, Replace([]string{"docs", "sale"}, "sale", "a-sale"))
+	assert.Equal(t, []string{"promo"}, Replace([]string{"promo", "sale"}, "sale", "promo"))
+	assert.Equal(t, []string{"docs"}, Replace([]string{"docs"}, "sale", "promo"))
+}
@@ -0,0 +1,43 @@
+package tracing_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+
+	"github.com/dkhrunov/url-shortener/internal/lib/tracing"
+)
+
+func TestSetupFile(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "spans.json")
+
+	shutdown, err := tracing.Setup(context.Background(), tracing.Config{
+		ServiceName: "url-shortener",
+		Exporter:    tracing.ExporterFile,
+		File:        file,
+		SampleRatio: 1,
+	})
+	require.NoError(t, err)
+
+	_, span := otel.Tracer("test").Start(context.Background(), "storage.sqlite.GetURL")
+	span.End()
+
+	require.NoError(t, shutdown(context.Background()))
+
+	spans, err := os.ReadFile(file)
+	require.NoError(t, err)
+
+	assert.Contains(t, string(spans), `"Name":"storage.sqlite.GetURL"`)
+	assert.Contains(t, string(spans), `"Value":"url-shortener"`)
+}
+
+func TestSetupUnknownExporter(t *testing.T) {
+	_, err := tracing.Setup(context.Background(), tracing.Config{Exporter: "jaeger"})
+
+	assert.ErrorIs(t, err, tracing.ErrUnknownExporter)
+}
@@ -0,0 +1,79 @@
+package useragent
+
+import "strings"
+
+// Devices reported by Parse.
+const (
+	DeviceDesktop = "desktop"
+	DeviceMobile  = "mobile"
+	DeviceTablet  = "tablet"
+)
+
+// Agent is what a User-Agent header tells about the client. Fields are empty when
+// they can't be told.
+type Agent struct {
+	Browser string
+	OS      string
+	Device  string
+}
+
+// rule names a browser or OS when the header contains any of the tokens.
+type rule struct {
+	name   string
+	tokens []string
+}
+
+// browsers are tried in order: most browsers also claim to be the ones they are based
+// on, e.g. Edge sends Chrome and Safari tokens too.
+var browsers = []rule{
+	{"Edge", []string{"Edg/", "EdgA/", "EdgiOS/", "Edge/"}},
+	{"Opera", []string{"OPR/", "Opera"}},
+	{"Samsung Internet", []string{"SamsungBrowser/"}},
+	{"Yandex Browser", []string{"YaBrowser/"}},
+	{"Firefox", []string{"Firefox/", "FxiOS/"}},
+	{"Chrome", []string{"Chrome/", "CriOS/", "Chromium/"}},
+	{"Safari", []string{"Safari/"}},
+	{"Internet Explorer", []string{"MSIE ", "Trident/"}},
+}
+
+var systems = []rule{
+	{"Windows", []string{"Windows"}},
+	{"iPadOS", []string{"iPad"}},
+	{"iOS", []string{"iPhone", "iPod"}},
+	{"Android", []string{"Android"}},
+	{"ChromeOS", []string{"CrOS"}},
+	{"macOS", []string{"Macintosh", "Mac OS X"}},
+	{"Linux", []string{"Linux"}},
+}
+
+// Parse tells the browser, OS and device from a User-Agent header. It knows the common
+// browsers only and doesn't look at versions.
+func Parse(ua string) Agent {
+	a := Agent{
+		Browser: match(ua, browsers),
+		OS:      match(ua, systems),
+	}
+
+	switch {
+	case a.OS == "iPadOS", a.OS == "Android" && !strings.Contains(ua, "Mobile"), strings.Contains(ua, "Tablet"):
+		a.Device = DeviceTablet
+	case a.OS == "iOS", a.OS == "Android", strings.Contains(ua, "Mobi"):
+		a.Device = DeviceMobile
+	case a.OS != "":
+		a.Device = DeviceDesktop
+	}
+
+	return a
+}
+
+func match(ua string, rules []rule) string {
+	for _, r := range rules {
+		for _, token := range r.tokens {
+			if strings.Contains(ua, token) {
+				return r.name
+			}
+		}
+	}
+
+	return ""
+}
@@ -0,0 +1,80 @@
+package useragent
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name string
+		ua   string
+		want Agent
+	}{
+		{
+			name: "chrome on windows",
+			ua:   "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/122.0.0.0 Safari/537.36",
+			want: Agent{Browser: "Chrome", OS: "Windows", Device: DeviceDesktop},
+		},
+		{
+			name: "edge on windows",
+			ua:   "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/122.0.0.0 Safari/537.36 Edg/122.0.2365.66",
+			want: Agent{Browser: "Edge", OS: "Windows", Device: DeviceDesktop},
+		},
+		{
+			name: "firefox on linux",
+			ua:   "Mozilla/5.0 (X11; Ubuntu; Linux x86_64; rv:123.0) Gecko/20100101 Firefox/123.0",
+			want: Agent{Browser: "Firefox", OS: "Linux", Device: DeviceDesktop},
+		},
+		{
+			name: "safari on mac",
+			ua:   "Mozilla/5.0 (Macintosh; Intel Mac OS X 14_3) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.3 Safari/605.1.15",
+			want: Agent{Browser: "Safari", OS: "macOS", Device: DeviceDesktop},
+		},
+		{
+			name: "safari on iphone",
+			ua:   "Mozilla/5.0 (iPhone; CPU iPhone OS 17_3 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.3 Mobile/15E148 Safari/604.1",
+			want: Agent{Browser: "Safari", OS: "iOS", Device: DeviceMobile},
+		},
+		{
+			name: "chrome on ipad",
+			ua:   "Mozilla/5.0 (iPad; CPU OS 17_3 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) CriOS/122.0.6261.89 Mobile/15E148 Safari/604.1",
+			want: Agent{Browser: "Chrome", OS: "iPadOS", Device: DeviceTablet},
+		},
+		{
+			name: "samsung on android phone",
+			ua:   "Mozilla/5.0 (Linux; Android 14; SM-S918B) AppleWebKit/537.36 (KHTML, like Gecko) SamsungBrowser/24.0 Chrome/117.0.0.0 Mobile Safari/537.36",
+			want: Agent{Browser: "Samsung Internet", OS: "Android", Device: DeviceMobile},
+		},
+		{
+			name: "chrome on android tablet",
+			ua:   "Mozilla/5.0 (Linux; Android 13; SM-X700) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/122.0.0.0 Safari/537.36",
+			want: Agent{Browser: "Chrome", OS: "Android", Device: DeviceTablet},
+		},
+		{
+			name: "chrome on chromebook",
+			ua:   "Mozilla/5.0 (X11; CrOS x86_64 14541.0.0) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/122.0.0.0 Safari/537.36",
+			want: Agent{Browser: "Chrome", OS: "ChromeOS", Device: DeviceDesktop},
+		},
+		{
+			name: "internet explorer",
+			ua:   "Mozilla/5.0 (Windows NT 10.0; WOW64; Trident/7.0; rv:11.0) like Gecko",
+			want: Agent{Browser: "Internet Explorer", OS: "Windows", Device: DeviceDesktop},
+		},
+		{
+			name: "curl",
+			ua:   "curl/8.4.0",
+			want: Agent{},
+		},
+		{
+			name: "empty",
+			want: Agent{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, Parse(tt.ua))
+		})
+	}
+}
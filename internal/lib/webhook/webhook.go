@@ -0,0 +1,438 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"slices"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/dkhrunov/url-shortener/internal/lib/logger/slog/slogerr"
+	"github.com/dkhrunov/url-shortener/internal/storage"
+)
+
+type EventType string
+
+const (
+	EventLinkCreated EventType = "link.created"
+	EventLinkDeleted EventType = "link.deleted"
+	EventLinkClicked EventType = "link.clicked"
+)
+
+// Valid reports whether t is a known event type.
+func (t EventType) Valid() bool {
+	switch t {
+	case EventLinkCreated, EventLinkDeleted, EventLinkClicked:
+		return true
+	}
+
+	return false
+}
+
+// eventTypes are all the known event types.
+var eventTypes = []string{string(EventLinkCreated), string(EventLinkDeleted), string(EventLinkClicked)}
+
+// Event is sent to the webhooks subscribed to its type as the JSON payload.
+type Event struct {
+	Type       EventType `json:"type"`
+	OccurredAt time.Time `json:"occurred_at"`
+	Link       Link      `json:"link"`
+}
+
+// Link is the link an event happened to. Clicks also report the host the link was
+// requested on.
+type Link struct {
+	Domain string   `json:"domain,omitempty"`
+	Host   string   `json:"host,omitempty"`
+	Alias  string   `json:"alias"`
+	URL    string   `json:"url,omitempty"`
+	Tags   []string `json:"tags,omitempty"`
+}
+
+// Headers sent with every delivery.
+const (
+	HeaderEvent     = "X-Webhook-Event"
+	HeaderDelivery  = "X-Webhook-Delivery"
+	HeaderTimestamp = "X-Webhook-Timestamp"
+	HeaderSignature = "X-Webhook-Signature"
+)
+
+const (
+	secretPrefix = "whsec_"
+	secretBytes  = 32
+	// maxResponseBody is how much of a response is read, so that the connection can
+	// be reused.
+	maxResponseBody = 64 << 10
+)
+
+// GenerateSecret returns a new random secret to sign the payloads of a webhook.
+func GenerateSecret() (string, error) {
+	const op = "lib.webhook.GenerateSecret"
+
+	b := make([]byte, secretBytes)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	return secretPrefix + base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// Sign returns the signature of a payload sent at the unix timestamp, as sent in
+// HeaderSignature: sha256= and the hex HMAC-SHA256 of "timestamp.payload". Signing
+// the timestamp lets receivers reject replayed deliveries.
+func Sign(secret string, timestamp int64, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(payload)
+
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify reports whether signature is the signature of a payload sent at timestamp.
+func Verify(secret, signature string, timestamp int64, payload []byte) bool {
+	return hmac.Equal([]byte(signature), []byte(Sign(secret, timestamp, payload)))
+}
+
+type Store interface {
+	SubscribedWebhookEvents(ctx context.Context) ([]string, error)
+	EnqueueWebhookEvent(ctx context.Context, event string, payload []byte) (int64, error)
+	DueWebhookMessages(ctx context.Context, limit int) ([]storage.WebhookMessage, error)
+	RecordWebhookDelivery(ctx context.Context, d storage.WebhookDelivery, retryAt time.Time) error
+}
+
+type Config struct {
+	// Interval is how often the outbox is checked for messages due for delivery.
+	// Subscriptions are cached for as long, so a new webhook gets events after at
+	// most an interval.
+	Interval time.Duration
+	// Timeout bounds a single delivery.
+	Timeout time.Duration
+	// MaxAttempts is how many times a message is delivered before it is given up.
+	MaxAttempts int
+	// MinBackoff is the delay before the first retry, doubled for every next one
+	// up to MaxBackoff.
+	MinBackoff time.Duration
+	MaxBackoff time.Duration
+	// BatchSize is how many messages are delivered at once.
+	BatchSize int
+	// Buffer is how many events wait to be put into the outbox before newer ones are
+	// dropped.
+	Buffer int
+}
+
+// Dispatcher puts link events into the outbox of the webhooks subscribed to them and
+// delivers the outbox, retrying failed deliveries with exponential backoff. As the
+// outbox is stored, deliveries survive restarts.
+type Dispatcher struct {
+	store  Store
+	client *http.Client
+	cfg    Config
+	log    *slog.Logger
+
+	mu          sync.Mutex
+	subscribed  []string
+	refreshedAt time.Time
+
+	events  chan Event
+	dropped atomic.Int64
+
+	wake chan struct{}
+	stop chan struct{}
+	done chan struct{}
+}
+
+func New(store Store, client *http.Client, cfg Config, log *slog.Logger) *Dispatcher {
+	return &Dispatcher{
+		store:  store,
+		client: client,
+		cfg:    cfg,
+		log:    log.With(slog.String("component", "webhooks")),
+		// Until the subscriptions are loaded, events of every type are kept.
+		subscribed: eventTypes,
+		events:     make(chan Event, cfg.Buffer),
+		wake:       make(chan struct{}, 1),
+		stop:       make(chan struct{}),
+		done:       make(chan struct{}),
+	}
+}
+
+// Notify queues the event to be put into the outbox of the webhooks subscribed to it,
+// without waiting for the storage, as clicks are notified on every redirect. Events no
+// webhook subscribed to are dropped right away, and so are events that don't fit the
+// buffer when the storage falls behind.
+func (d *Dispatcher) Notify(ctx context.Context, e Event) {
+	if !d.isSubscribed(ctx, e.Type) {
+		return
+	}
+
+	if e.OccurredAt.IsZero() {
+		e.OccurredAt = time.Now().UTC()
+	}
+
+	select {
+	case d.events <- e:
+	default:
+		d.dropped.Add(1)
+	}
+}
+
+// isSubscribed reports whether any webhook subscribed to the event type, refreshing
+// the subscriptions once they are older than the interval. Only one caller refreshes
+// them, the others meanwhile use those loaded before. When they can't be loaded,
+// events of every type are kept until the next refresh, to not lose any.
+func (d *Dispatcher) isSubscribed(ctx context.Context, t EventType) bool {
+	d.mu.Lock()
+	subscribed := d.subscribed
+	stale := time.Since(d.refreshedAt) >= d.cfg.Interval
+	if stale {
+		d.refreshedAt = time.Now()
+	}
+	d.mu.Unlock()
+
+	if !stale {
+		return slices.Contains(subscribed, string(t))
+	}
+
+	subscribed, err := d.store.SubscribedWebhookEvents(context.WithoutCancel(ctx))
+	if err != nil {
+		d.log.ErrorContext(ctx, "failed to load webhook subscriptions", slogerr.Error(err))
+
+		subscribed = eventTypes
+	}
+
+	d.mu.Lock()
+	d.subscribed = subscribed
+	d.mu.Unlock()
+
+	return slices.Contains(subscribed, string(t))
+}
+
+// Run puts the notified events into the outbox and delivers the messages due right
+// away and then every interval or as soon as an event is enqueued, until Stop is
+// called.
+func (d *Dispatcher) Run() error {
+	defer close(d.done)
+
+	enqueued := make(chan struct{})
+	go func() {
+		defer close(enqueued)
+
+		d.enqueue()
+	}()
+	defer func() { <-enqueued }()
+
+	ticker := time.NewTicker(d.cfg.Interval)
+	defer ticker.Stop()
+
+	for {
+		d.dispatch()
+
+		select {
+		case <-d.stop:
+			return nil
+		case <-ticker.C:
+		case <-d.wake:
+		}
+	}
+}
+
+// Stop ends Run, waiting for the queued events to be put into the outbox and for the
+// deliveries in progress to finish, or for ctx to end. Undelivered messages stay in the
+// outbox for the next run.
+func (d *Dispatcher) Stop(ctx context.Context) error {
+	close(d.stop)
+
+	select {
+	case <-d.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// enqueue puts the notified events into the outbox until Stop is called, and then the
+// events still queued.
+func (d *Dispatcher) enqueue() {
+	for {
+		select {
+		case e := <-d.events:
+			d.save(e)
+		case <-d.stop:
+			for {
+				select {
+				case e := <-d.events:
+					d.save(e)
+				default:
+					return
+				}
+			}
+		}
+	}
+}
+
+// save puts the event into the outbox and wakes up the delivery. A failure is logged
+// rather than returned, as the event happened already.
+func (d *Dispatcher) save(e Event) {
+	log := d.log.With(
+		slog.String("event", string(e.Type)),
+		slog.String("alias", e.Link.Alias),
+	)
+
+	if dropped := d.dropped.Swap(0); dropped > 0 {
+		d.log.Warn("webhook events dropped, the storage falls behind", slog.Int64("dropped", dropped))
+	}
+
+	payload, err := json.Marshal(e)
+	if err != nil {
+		log.Error("failed to marshal event", slogerr.Error(err))
+
+		return
+	}
+
+	enqueued, err := d.store.EnqueueWebhookEvent(context.Background(), string(e.Type), payload)
+	if err != nil {
+		log.Error("failed to enqueue event", slogerr.Error(err))
+
+		return
+	}
+
+	if enqueued > 0 {
+		select {
+		case d.wake <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// dispatch delivers batches of due messages until there are none left or Stop is
+// called.
+func (d *Dispatcher) dispatch() {
+	ctx := context.Background()
+
+	for {
+		messages, err := d.store.DueWebhookMessages(ctx, d.cfg.BatchSize)
+		if err != nil {
+			d.log.Error("failed to load webhook messages", slogerr.Error(err))
+
+			return
+		}
+
+		var wg sync.WaitGroup
+		for _, m := range messages {
+			wg.Add(1)
+			go func(m storage.WebhookMessage) {
+				defer wg.Done()
+
+				d.deliver(ctx, m)
+			}(m)
+		}
+		wg.Wait()
+
+		if len(messages) < d.cfg.BatchSize {
+			return
+		}
+
+		select {
+		case <-d.stop:
+			return
+		default:
+		}
+	}
+}
+
+// deliver sends the message to its webhook and logs the attempt, scheduling a retry
+// when it failed and attempts are left.
+func (d *Dispatcher) deliver(ctx context.Context, m storage.WebhookMessage) {
+	log := d.log.With(
+		slog.Int64("webhook_id", m.WebhookID),
+		slog.Int64("message_id", m.ID),
+		slog.String("event", m.Event),
+	)
+
+	start := time.Now()
+	status, err := d.send(ctx, m)
+
+	delivery := storage.WebhookDelivery{
+		WebhookID:  m.WebhookID,
+		MessageID:  m.ID,
+		Event:      m.Event,
+		Attempt:    m.Attempts + 1,
+		StatusCode: status,
+		Duration:   time.Since(start),
+	}
+
+	var retryAt time.Time
+	if err != nil {
+		delivery.Error = err.Error()
+
+		if delivery.Attempt < d.cfg.MaxAttempts {
+			retryAt = time.Now().Add(d.backoff(delivery.Attempt))
+
+			log.Warn("webhook delivery failed", slog.Int("attempt", delivery.Attempt), slogerr.Error(err))
+		} else {
+			log.Error("webhook delivery failed, giving up", slog.Int("attempt", delivery.Attempt), slogerr.Error(err))
+		}
+	}
+
+	if err := d.store.RecordWebhookDelivery(ctx, delivery, retryAt); err != nil {
+		log.Error("failed to record webhook delivery", slogerr.Error(err))
+	}
+}
+
+// send posts the signed payload of the message to its webhook and returns the status
+// it answered with. Statuses other than 2xx are errors.
+func (d *Dispatcher) send(ctx context.Context, m storage.WebhookMessage) (int, error) {
+	ctx, cancel := context.WithTimeout(ctx, d.cfg.Timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, m.URL, bytes.NewReader(m.Payload))
+	if err != nil {
+		return 0, err
+	}
+
+	timestamp := time.Now().Unix()
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "url-shortener-webhooks")
+	req.Header.Set(HeaderEvent, m.Event)
+	req.Header.Set(HeaderDelivery, strconv.FormatInt(m.ID, 10))
+	req.Header.Set(HeaderTimestamp, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(HeaderSignature, Sign(m.Secret, timestamp, m.Payload))
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, maxResponseBody))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+
+	return resp.StatusCode, nil
+}
+
+// backoff returns the delay before the retry following the attempt.
+func (d *Dispatcher) backoff(attempt int) time.Duration {
+	delay := d.cfg.MinBackoff
+	for i := 1; i < attempt && delay < d.cfg.MaxBackoff; i++ {
+		delay *= 2
+	}
+
+	return min(delay, d.cfg.MaxBackoff)
+}
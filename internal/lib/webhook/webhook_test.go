@@ -0,0 +1,291 @@
+package webhook_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/dkhrunov/url-shortener/internal/lib/webhook"
+	"github.com/dkhrunov/url-shortener/internal/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type hook struct {
+	url, secret string
+	events      []string
+}
+
+type message struct {
+	storage.WebhookMessage
+	retryAt time.Time
+}
+
+// store keeps the outbox in memory.
+type store struct {
+	mu         sync.Mutex
+	hooks      map[int64]hook
+	outbox     map[int64]*message
+	deliveries []storage.WebhookDelivery
+	nextID     int64
+
+	// subscribedErr fails loading the subscriptions, counted in subscribedLoads.
+	subscribedErr   error
+	subscribedLoads int
+}
+
+func newStore(hooks map[int64]hook) *store {
+	return &store{hooks: hooks, outbox: map[int64]*message{}}
+}
+
+func (s *store) SubscribedWebhookEvents(context.Context) ([]string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.subscribedLoads++
+	if s.subscribedErr != nil {
+		return nil, s.subscribedErr
+	}
+
+	events := []string{}
+	for _, h := range s.hooks {
+		events = append(events, h.events...)
+	}
+
+	return events, nil
+}
+
+func (s *store) EnqueueWebhookEvent(_ context.Context, event string, payload []byte) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var enqueued int64
+	for id, h := range s.hooks {
+		if !slices.Contains(h.events, event) {
+			continue
+		}
+
+		s.nextID++
+		s.outbox[s.nextID] = &message{WebhookMessage: storage.WebhookMessage{
+			ID: s.nextID, WebhookID: id, URL: h.url, Secret: h.secret, Event: event, Payload: payload,
+		}}
+		enqueued++
+	}
+
+	return enqueued, nil
+}
+
+func (s *store) DueWebhookMessages(_ context.Context, limit int) ([]storage.WebhookMessage, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	messages := []storage.WebhookMessage{}
+	for _, m := range s.outbox {
+		if len(messages) < limit && !m.retryAt.After(time.Now()) {
+			messages = append(messages, m.WebhookMessage)
+		}
+	}
+
+	return messages, nil
+}
+
+func (s *store) RecordWebhookDelivery(_ context.Context, d storage.WebhookDelivery, retryAt time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.deliveries = append(s.deliveries, d)
+
+	if retryAt.IsZero() {
+		delete(s.outbox, d.MessageID)
+	} else {
+		s.outbox[d.MessageID].Attempts = d.Attempt
+		s.outbox[d.MessageID].retryAt = retryAt
+	}
+
+	return nil
+}
+
+func (s *store) state() (pending int, deliveries []storage.WebhookDelivery) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return len(s.outbox), slices.Clone(s.deliveries)
+}
+
+func TestDispatcher(t *testing.T) {
+	var (
+		received atomic.Int32
+		events   = make(chan webhook.Event, 10)
+	)
+
+	// The receiver is down for the first delivery.
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if received.Add(1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+
+			return
+		}
+
+		body, _ := io.ReadAll(r.Body)
+		timestamp, _ := strconv.ParseInt(r.Header.Get(webhook.HeaderTimestamp), 10, 64)
+		if !webhook.Verify("secret", r.Header.Get(webhook.HeaderSignature), timestamp, body) {
+			w.WriteHeader(http.StatusUnauthorized)
+
+			return
+		}
+
+		var e webhook.Event
+		_ = json.Unmarshal(body, &e)
+		events <- e
+	}))
+	defer receiver.Close()
+
+	// The other receiver never answers with success.
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer failing.Close()
+
+	s := newStore(map[int64]hook{
+		1: {url: receiver.URL, secret: "secret", events: []string{"link.created", "link.clicked"}},
+		2: {url: failing.URL, secret: "other", events: []string{"link.deleted"}},
+	})
+
+	d := webhook.New(s, receiver.Client(), webhook.Config{
+		Interval:    10 * time.Millisecond,
+		Timeout:     time.Second,
+		MaxAttempts: 3,
+		MinBackoff:  5 * time.Millisecond,
+		MaxBackoff:  20 * time.Millisecond,
+		BatchSize:   2,
+		Buffer:      10,
+	}, slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	done := make(chan error)
+	go func() { done <- d.Run() }()
+
+	d.Notify(context.Background(), webhook.Event{
+		Type: webhook.EventLinkCreated,
+		Link: webhook.Link{Alias: "go", URL: "https://go.dev"},
+	})
+	d.Notify(context.Background(), webhook.Event{
+		Type: webhook.EventLinkDeleted,
+		Link: webhook.Link{Alias: "go"},
+	})
+
+	// The created event is delivered on the retry.
+	select {
+	case e := <-events:
+		assert.Equal(t, webhook.EventLinkCreated, e.Type)
+		assert.Equal(t, "https://go.dev", e.Link.URL)
+		assert.False(t, e.OccurredAt.IsZero())
+	case <-time.After(2 * time.Second):
+		t.Fatal("event not delivered")
+	}
+
+	// The deleted event is given up after the last attempt.
+	require.Eventually(t, func() bool {
+		pending, _ := s.state()
+		return pending == 0
+	}, 2*time.Second, 5*time.Millisecond)
+
+	require.NoError(t, d.Stop(context.Background()))
+	require.NoError(t, <-done)
+
+	_, deliveries := s.state()
+	require.Len(t, deliveries, 5)
+
+	var created, deleted []storage.WebhookDelivery
+	for _, delivery := range deliveries {
+		if delivery.WebhookID == 1 {
+			created = append(created, delivery)
+		} else {
+			deleted = append(deleted, delivery)
+		}
+	}
+
+	require.Len(t, created, 2)
+	assert.Equal(t, http.StatusServiceUnavailable, created[0].StatusCode)
+	assert.Equal(t, "unexpected status 503", created[0].Error)
+	assert.Equal(t, 2, created[1].Attempt)
+	assert.Equal(t, http.StatusOK, created[1].StatusCode)
+	assert.Empty(t, created[1].Error)
+
+	require.Len(t, deleted, 3)
+	assert.Equal(t, 3, deleted[2].Attempt)
+	assert.Equal(t, http.StatusInternalServerError, deleted[2].StatusCode)
+}
+
+func TestDispatcher_Unsubscribed(t *testing.T) {
+	s := newStore(map[int64]hook{
+		1: {url: "http://127.0.0.1:0", secret: "secret", events: []string{"link.created"}},
+	})
+
+	d := webhook.New(s, http.DefaultClient, webhook.Config{Interval: time.Hour, BatchSize: 10, Buffer: 10}, slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	go func() { _ = d.Run() }()
+
+	d.Notify(context.Background(), webhook.Event{Type: webhook.EventLinkClicked, Link: webhook.Link{Alias: "go"}})
+
+	require.NoError(t, d.Stop(context.Background()))
+
+	pending, _ := s.state()
+	assert.Zero(t, pending)
+}
+
+func TestDispatcher_SubscriptionsFail(t *testing.T) {
+	s := newStore(map[int64]hook{
+		1: {url: "http://127.0.0.1:0", secret: "secret", events: []string{"link.created"}},
+	})
+	s.subscribedErr = errors.New("database is locked")
+
+	d := webhook.New(s, http.DefaultClient, webhook.Config{Interval: time.Hour, BatchSize: 10, Buffer: 10}, slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	// Events are kept while the subscriptions can't be loaded, which is retried
+	// only after the interval rather than on every event.
+	d.Notify(context.Background(), webhook.Event{Type: webhook.EventLinkCreated, Link: webhook.Link{Alias: "go"}})
+	d.Notify(context.Background(), webhook.Event{Type: webhook.EventLinkClicked, Link: webhook.Link{Alias: "go"}})
+
+	s.mu.Lock()
+	assert.Equal(t, 1, s.subscribedLoads)
+	s.mu.Unlock()
+
+	// The events queued before Stop are put into the outbox of their webhooks.
+	go func() { _ = d.Run() }()
+	require.NoError(t, d.Stop(context.Background()))
+
+	pending, _ := s.state()
+	assert.Equal(t, 1, pending)
+}
+
+func TestSign(t *testing.T) {
+	payload := []byte(`{"type":"link.created"}`)
+
+	signature := webhook.Sign("secret", 1700000000, payload)
+	assert.Regexp(t, `^sha256=[0-9a-f]{64}$`, signature)
+
+	assert.True(t, webhook.Verify("secret", signature, 1700000000, payload))
+	assert.False(t, webhook.Verify("other", signature, 1700000000, payload))
+	assert.False(t, webhook.Verify("secret", signature, 1700000001, payload))
+	assert.False(t, webhook.Verify("secret", signature, 1700000000, []byte(`{}`)))
+}
+
+func TestGenerateSecret(t *testing.T) {
+	a, err := webhook.GenerateSecret()
+	require.NoError(t, err)
+	b, err := webhook.GenerateSecret()
+	require.NoError(t, err)
+
+	assert.Regexp(t, `^whsec_[A-Za-z0-9_-]{43}$`, a)
+	assert.NotEqual(t, a, b)
+}
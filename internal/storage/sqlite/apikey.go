@@ -0,0 +1,162 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/dkhrunov/url-shortener/internal/lib/zero"
+	"github.com/dkhrunov/url-shortener/internal/storage"
+)
+
+var saveAPIKeyQuery = query(`--sql
+	INSERT INTO api_key(name, prefix, key_hash, user_id)
+	SELECT ?, ?, ?, NULLIF(?, 0)
+	WHERE ? = 0 OR EXISTS(SELECT 1 FROM user WHERE id = ?)
+`)
+
+// SaveAPIKey stores a key acting on behalf of the user userID. Keys with a zero userID
+// are not tied to any user.
+func (s *Sqlite) SaveAPIKey(ctx context.Context, name, prefix, keyHash string, userID int64) (int64, error) {
+	const op = "storage.sqlite.SaveAPIKey"
+
+	ctx, done := s.begin(ctx, op, s.opts.WriteTimeout)
+	defer done()
+
+	stmt, err := s.stmt(saveAPIKeyQuery)
+	if err != nil {
+		return zero.Zero[int64](), fmt.Errorf("%s: %w", op, err)
+	}
+
+	res, err := stmt.ExecContext(ctx, name, prefix, keyHash, userID, userID, userID)
+	if err != nil {
+		return zero.Zero[int64](), fmt.Errorf("%s: %w", op, err)
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return zero.Zero[int64](), fmt.Errorf("%s: failed to get rows affected: %w", op, err)
+	}
+	if affected == 0 {
+		return zero.Zero[int64](), fmt.Errorf("%s: %w", op, storage.ErrUserNotFound)
+	}
+
+	id, err := res.LastInsertId()
+	if err != nil {
+		return 0, fmt.Errorf("%s: failed to get last insert id: %w", op, err)
+	}
+
+	return id, nil
+}
+
+var listAPIKeysQuery = query(`--sql
+	SELECT id, name, prefix, IFNULL(user_id, 0), created_at, last_used_at, revoked_at
+	FROM api_key ORDER BY id
+`)
+
+func (s *Sqlite) ListAPIKeys(ctx context.Context) ([]storage.APIKey, error) {
+	const op = "storage.sqlite.ListAPIKeys"
+
+	ctx, done := s.begin(ctx, op, s.opts.ReadTimeout)
+	defer done()
+
+	stmt, err := s.stmt(listAPIKeysQuery)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	rows, err := stmt.QueryContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%s: execute statement: %w", op, err)
+	}
+	defer rows.Close()
+
+	keys := []storage.APIKey{}
+	for rows.Next() {
+		var (
+			k                 storage.APIKey
+			lastUsed, revoked sql.NullTime
+		)
+		if err := rows.Scan(&k.ID, &k.Name, &k.Prefix, &k.UserID, &k.CreatedAt, &lastUsed, &revoked); err != nil {
+			return nil, fmt.Errorf("%s: scan row: %w", op, err)
+		}
+
+		k.LastUsedAt = nullTime(lastUsed)
+		k.RevokedAt = nullTime(revoked)
+
+		keys = append(keys, k)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return keys, nil
+}
+
+var useAPIKeyQuery = query(`--sql
+	UPDATE api_key SET last_used_at = CURRENT_TIMESTAMP
+	WHERE key_hash = ? AND revoked_at IS NULL
+	RETURNING id, name, prefix, IFNULL(user_id, 0), created_at, last_used_at
+`)
+
+// UseAPIKey finds an active API key by its hash and records that it was used.
+func (s *Sqlite) UseAPIKey(ctx context.Context, keyHash string) (storage.APIKey, error) {
+	const op = "storage.sqlite.UseAPIKey"
+
+	ctx, done := s.begin(ctx, op, s.opts.WriteTimeout)
+	defer done()
+
+	stmt, err := s.stmt(useAPIKeyQuery)
+	if err != nil {
+		return zero.Zero[storage.APIKey](), fmt.Errorf("%s: %w", op, err)
+	}
+
+	var (
+		k        storage.APIKey
+		lastUsed sql.NullTime
+	)
+	err = stmt.QueryRowContext(ctx, keyHash).Scan(&k.ID, &k.Name, &k.Prefix, &k.UserID, &k.CreatedAt, &lastUsed)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return zero.Zero[storage.APIKey](), fmt.Errorf("%s: %w", op, storage.ErrAPIKeyNotFound)
+		}
+
+		return zero.Zero[storage.APIKey](), fmt.Errorf("%s: execute statement: %w", op, err)
+	}
+
+	k.LastUsedAt = nullTime(lastUsed)
+
+	return k, nil
+}
+
+var revokeAPIKeyQuery = query(`--sql
+	UPDATE api_key SET revoked_at = CURRENT_TIMESTAMP WHERE id = ? AND revoked_at IS NULL
+`)
+
+func (s *Sqlite) RevokeAPIKey(ctx context.Context, id int64) error {
+	const op = "storage.sqlite.RevokeAPIKey"
+
+	ctx, done := s.begin(ctx, op, s.opts.WriteTimeout)
+	defer done()
+
+	stmt, err := s.stmt(revokeAPIKeyQuery)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	res, err := stmt.ExecContext(ctx, id)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("%s: failed to get rows affected: %w", op, err)
+	}
+	if affected == 0 {
+		return fmt.Errorf("%s: %w", op, storage.ErrAPIKeyNotFound)
+	}
+
+	return nil
+}
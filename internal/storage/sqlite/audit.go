@@ -0,0 +1,124 @@
+package sqlite
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/dkhrunov/url-shortener/internal/storage"
+)
+
+var saveAuditEntryQuery = query(`--sql
+	INSERT INTO audit_log(
+		actor, user_id, api_key_id, action, domain, alias, before, after, request_id, remote_addr
+	) VALUES (?, NULLIF(?, 0), NULLIF(?, 0), ?, ?, ?, NULLIF(?, ''), NULLIF(?, ''), ?, ?)
+`)
+
+// SaveAuditEntries appends entries to the audit log at once, all of them or none. Their
+// ids and creation times are set by the storage.
+func (s *Sqlite) SaveAuditEntries(ctx context.Context, entries []storage.AuditEntry) error {
+	const op = "storage.sqlite.SaveAuditEntries"
+
+	ctx, done := s.begin(ctx, op, s.opts.WriteTimeout)
+	defer done()
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	stmt, err := s.stmt(saveAuditEntryQuery)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	stmt = tx.StmtContext(ctx, stmt)
+
+	for _, e := range entries {
+		_, err = stmt.ExecContext(ctx,
+			e.Actor, e.UserID, e.APIKeyID, e.Action, e.Domain, e.Alias, e.Before, e.After, e.RequestID, e.RemoteAddr,
+		)
+		if err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+var listAuditEntriesQuery = query(`--sql
+	SELECT
+		id, created_at, actor, IFNULL(user_id, 0), IFNULL(api_key_id, 0), action, domain, alias,
+		IFNULL(before, ''), IFNULL(after, ''), request_id, remote_addr
+	FROM audit_log
+	WHERE (? = '' OR actor = ?)
+		AND (? = '' OR action = ?)
+		AND (? = '' OR domain = ?)
+		AND (? = '' OR alias = ?)
+		AND (? = '' OR created_at >= ?)
+		AND (? = '' OR created_at < ?)
+	ORDER BY id DESC LIMIT ? OFFSET ?
+`)
+
+// ListAuditEntries returns a page of the audit entries matching the filter, newest first.
+func (s *Sqlite) ListAuditEntries(ctx context.Context, f storage.AuditFilter, limit, offset int) ([]storage.AuditEntry, error) {
+	const op = "storage.sqlite.ListAuditEntries"
+
+	ctx, done := s.begin(ctx, op, s.opts.ReadTimeout)
+	defer done()
+
+	stmt, err := s.stmt(listAuditEntriesQuery)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	since, until := timestamp(f.Since), timestamp(f.Until)
+
+	rows, err := stmt.QueryContext(ctx,
+		f.Actor, f.Actor,
+		f.Action, f.Action,
+		f.Domain, f.Domain,
+		f.Alias, f.Alias,
+		since, since,
+		until, until,
+		limit, offset,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%s: execute statement: %w", op, err)
+	}
+	defer rows.Close()
+
+	entries := []storage.AuditEntry{}
+	for rows.Next() {
+		var e storage.AuditEntry
+		err := rows.Scan(
+			&e.ID, &e.CreatedAt, &e.Actor, &e.UserID, &e.APIKeyID, &e.Action, &e.Domain, &e.Alias,
+			&e.Before, &e.After, &e.RequestID, &e.RemoteAddr,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("%s: scan row: %w", op, err)
+		}
+
+		entries = append(entries, e)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return entries, nil
+}
+
+// timestamp formats t like CURRENT_TIMESTAMP, so that the two can be compared. The zero
+// time is formatted as an empty string.
+func timestamp(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+
+	return t.UTC().Format(time.DateTime)
+}
@@ -0,0 +1,74 @@
+package sqlite
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/dkhrunov/url-shortener/internal/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuditLog(t *testing.T) {
+	ctx := context.Background()
+
+	s, err := New(filepath.Join(t.TempDir(), "storage.db"), Options{})
+	require.NoError(t, err)
+	require.NoError(t, s.MigrateUp(ctx))
+	require.NoError(t, s.Prepare(ctx))
+
+	require.NoError(t, s.SaveAuditEntries(ctx, []storage.AuditEntry{{
+		Actor:     "basic:root",
+		Action:    "create",
+		Alias:     "go",
+		After:     `{"url":"https://go.dev"}`,
+		RequestID: "req-1",
+	}}))
+	require.NoError(t, s.SaveAuditEntries(ctx, []storage.AuditEntry{{
+		Actor:      "api_key:ci",
+		UserID:     7,
+		APIKeyID:   3,
+		Action:     "delete",
+		Domain:     "brand.example",
+		Alias:      "go",
+		Before:     `{"url":"https://go.dev"}`,
+		RemoteAddr: "203.0.113.7",
+	}}))
+
+	entries, err := s.ListAuditEntries(ctx, storage.AuditFilter{}, 10, 0)
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+	assert.Equal(t, "delete", entries[0].Action)
+	assert.Equal(t, int64(3), entries[0].APIKeyID)
+	assert.Empty(t, entries[0].After)
+	assert.Equal(t, "create", entries[1].Action)
+	assert.Zero(t, entries[1].UserID)
+	assert.WithinDuration(t, time.Now(), entries[1].CreatedAt, time.Minute)
+
+	entries, err = s.ListAuditEntries(ctx, storage.AuditFilter{Actor: "api_key:ci", Domain: "brand.example"}, 10, 0)
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, "203.0.113.7", entries[0].RemoteAddr)
+
+	entries, err = s.ListAuditEntries(ctx, storage.AuditFilter{Action: "create", Alias: "go"}, 10, 0)
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, "req-1", entries[0].RequestID)
+
+	entries, err = s.ListAuditEntries(ctx, storage.AuditFilter{Since: time.Now().Add(-time.Hour), Until: time.Now().Add(time.Hour)}, 1, 1)
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, "create", entries[0].Action)
+
+	entries, err = s.ListAuditEntries(ctx, storage.AuditFilter{Until: time.Now().Add(-time.Hour)}, 10, 0)
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+
+	// Entries can't be changed or removed.
+	_, err = s.db.ExecContext(ctx, `UPDATE audit_log SET actor = 'basic:someone'`)
+	assert.ErrorContains(t, err, "append-only")
+	_, err = s.db.ExecContext(ctx, `DELETE FROM audit_log`)
+	assert.ErrorContains(t, err, "append-only")
+}
@@ -0,0 +1,216 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/dkhrunov/url-shortener/internal/lib/zero"
+	"github.com/dkhrunov/url-shortener/internal/storage"
+)
+
+// seriesQueries select the clicks per time bucket for each interval of ClickAnalytics.
+// Weeks start on Monday.
+var seriesQueries = map[string]string{
+	"hour": seriesQuery(`strftime('%Y-%m-%d %H:00:00', hour)`),
+	"day":  seriesQuery(`strftime('%Y-%m-%d 00:00:00', hour)`),
+	"week": seriesQuery(`strftime('%Y-%m-%d 00:00:00', hour, 'weekday 0', '-6 days')`),
+}
+
+// seriesQuery returns the query of the clicks per time bucket, where bucket formats the
+// start of the bucket of a click_rollup row.
+func seriesQuery(bucket string) string {
+	return query(`--sql
+		SELECT ` + bucket + ` AS bucket, SUM(clicks) FROM click_rollup
+		WHERE url_id = ? AND dimension = '' AND hour >= ? AND hour < ? AND (? OR bot = 0)
+		GROUP BY bucket ORDER BY bucket
+	`)
+}
+
+var saveClickQuery = query(`--sql
+	INSERT INTO click(url_id, created_at, referrer, browser, os, device, country, bot)
+	VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+`)
+
+// SaveClicks saves a batch of clicks at once. The rollups read by ClickAnalytics are
+// updated along with them.
+func (s *Sqlite) SaveClicks(ctx context.Context, clicks []storage.Click) error {
+	const op = "storage.sqlite.SaveClicks"
+
+	ctx, done := s.begin(ctx, op, s.opts.WriteTimeout)
+	defer done()
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	stmt, err := s.stmt(saveClickQuery)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	stmt = tx.StmtContext(ctx, stmt)
+
+	for _, c := range clicks {
+		_, err = stmt.ExecContext(ctx, c.LinkID, timestamp(c.At), c.Referrer, c.Browser, c.OS, c.Device, c.Country, c.Bot)
+		if err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+var purgeClicksQuery = query(`--sql
+	DELETE FROM click WHERE created_at < datetime('now', ?)
+`)
+
+// PurgeClicks removes the raw clicks older than retention. The rollups read by
+// ClickAnalytics are kept, so the analytics of links don't change. It returns the
+// number of clicks removed.
+func (s *Sqlite) PurgeClicks(ctx context.Context, retention time.Duration) (int64, error) {
+	const op = "storage.sqlite.PurgeClicks"
+
+	ctx, done := s.begin(ctx, op, s.opts.WriteTimeout)
+	defer done()
+
+	stmt, err := s.stmt(purgeClicksQuery)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	res, err := stmt.ExecContext(ctx, sinceModifier(retention))
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	purged, err := res.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("%s: failed to get rows affected: %w", op, err)
+	}
+
+	return purged, nil
+}
+
+var botClicksQuery = query(`--sql
+	SELECT IFNULL(SUM(clicks), 0) FROM click_rollup
+	WHERE url_id = ? AND dimension = '' AND hour >= ? AND hour < ? AND bot = 1
+`)
+
+// ClickAnalytics reports the clicks of a link selected by q, from the hourly rollups.
+func (s *Sqlite) ClickAnalytics(ctx context.Context, q storage.AnalyticsQuery) (storage.Analytics, error) {
+	const op = "storage.sqlite.ClickAnalytics"
+
+	series, ok := seriesQueries[q.Interval]
+	if !ok {
+		return zero.Zero[storage.Analytics](), fmt.Errorf("%s: unknown interval %q", op, q.Interval)
+	}
+
+	ctx, done := s.begin(ctx, op, s.opts.ReadTimeout)
+	defer done()
+
+	// The report is read in a transaction to be consistent with clicks saved meanwhile.
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return zero.Zero[storage.Analytics](), fmt.Errorf("%s: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	from, to := timestamp(q.From), timestamp(q.To)
+
+	stmt, err := s.stmt(series)
+	if err != nil {
+		return zero.Zero[storage.Analytics](), fmt.Errorf("%s: %w", op, err)
+	}
+
+	rows, err := tx.StmtContext(ctx, stmt).QueryContext(ctx, q.LinkID, from, to, q.IncludeBots)
+	if err != nil {
+		return zero.Zero[storage.Analytics](), fmt.Errorf("%s: execute statement: %w", op, err)
+	}
+	defer rows.Close()
+
+	a := storage.Analytics{Series: []storage.ClickBucket{}}
+	for rows.Next() {
+		var (
+			b     storage.ClickBucket
+			start string
+		)
+		if err := rows.Scan(&start, &b.Clicks); err != nil {
+			return zero.Zero[storage.Analytics](), fmt.Errorf("%s: scan row: %w", op, err)
+		}
+
+		if b.Start, err = time.Parse(time.DateTime, start); err != nil {
+			return zero.Zero[storage.Analytics](), fmt.Errorf("%s: parse bucket: %w", op, err)
+		}
+
+		a.Total += b.Clicks
+		a.Series = append(a.Series, b)
+	}
+	if err := rows.Err(); err != nil {
+		return zero.Zero[storage.Analytics](), fmt.Errorf("%s: %w", op, err)
+	}
+
+	stmt, err = s.stmt(botClicksQuery)
+	if err != nil {
+		return zero.Zero[storage.Analytics](), fmt.Errorf("%s: %w", op, err)
+	}
+
+	err = tx.StmtContext(ctx, stmt).QueryRowContext(ctx, q.LinkID, from, to).Scan(&a.Bots)
+	if err != nil {
+		return zero.Zero[storage.Analytics](), fmt.Errorf("%s: %w", op, err)
+	}
+
+	for dimension, counts := range map[string]*[]storage.ClickCount{
+		"referrer": &a.Referrers,
+		"browser":  &a.Browsers,
+		"os":       &a.OS,
+		"device":   &a.Devices,
+		"country":  &a.Countries,
+	} {
+		if *counts, err = s.clickBreakdown(ctx, tx, q, dimension, from, to); err != nil {
+			return zero.Zero[storage.Analytics](), fmt.Errorf("%s: %s: %w", op, dimension, err)
+		}
+	}
+
+	return a, nil
+}
+
+var clickBreakdownQuery = query(`--sql
+	SELECT value, SUM(clicks) AS total FROM click_rollup
+	WHERE url_id = ? AND dimension = ? AND hour >= ? AND hour < ? AND (? OR bot = 0)
+	GROUP BY value ORDER BY total DESC, value LIMIT ?
+`)
+
+// clickBreakdown returns the values of the dimension with the most clicks in the
+// range from to, most clicked first.
+func (s *Sqlite) clickBreakdown(ctx context.Context, tx *sql.Tx, q storage.AnalyticsQuery, dimension, from, to string) ([]storage.ClickCount, error) {
+	stmt, err := s.stmt(clickBreakdownQuery)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := tx.StmtContext(ctx, stmt).QueryContext(ctx, q.LinkID, dimension, from, to, q.IncludeBots, q.Limit)
+	if err != nil {
+		return nil, fmt.Errorf("execute statement: %w", err)
+	}
+	defer rows.Close()
+
+	counts := []storage.ClickCount{}
+	for rows.Next() {
+		var c storage.ClickCount
+		if err := rows.Scan(&c.Value, &c.Clicks); err != nil {
+			return nil, fmt.Errorf("scan row: %w", err)
+		}
+
+		counts = append(counts, c)
+	}
+
+	return counts, rows.Err()
+}
@@ -0,0 +1,132 @@
+package sqlite
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/dkhrunov/url-shortener/internal/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClickAnalytics(t *testing.T) {
+	ctx := context.Background()
+
+	s, err := New(filepath.Join(t.TempDir(), "storage.db"), Options{})
+	require.NoError(t, err)
+	require.NoError(t, s.MigrateUp(ctx))
+	require.NoError(t, s.Prepare(ctx))
+
+	id, err := s.SaveURL(ctx, "", "https://go.dev", "go", 0, 0, nil, storage.LinkLimits{})
+	require.NoError(t, err)
+	other, err := s.SaveURL(ctx, "", "https://pkg.go.dev", "pkg", 0, 0, nil, storage.LinkLimits{})
+	require.NoError(t, err)
+
+	// 2024-03-04 is a Monday.
+	at := func(s string) time.Time {
+		t.Helper()
+
+		at, err := time.Parse(time.DateTime, s)
+		require.NoError(t, err)
+
+		return at
+	}
+
+	require.NoError(t, s.SaveClicks(ctx, []storage.Click{
+		{LinkID: id, At: at("2024-03-04 10:15:00"), Referrer: "news.example", Browser: "Chrome", OS: "Windows", Device: "desktop", Country: "US"},
+		{LinkID: id, At: at("2024-03-04 10:45:00"), Referrer: "news.example", Browser: "Firefox", OS: "Linux", Device: "desktop", Country: "DE"},
+		{LinkID: id, At: at("2024-03-05 09:00:00"), Browser: "Safari", OS: "iOS", Device: "mobile", Country: "US"},
+		{LinkID: other, At: at("2024-03-05 09:00:00"), Browser: "Chrome"},
+	}))
+	require.NoError(t, s.SaveClicks(ctx, []storage.Click{
+		{LinkID: id, At: at("2024-03-10 23:30:00"), Referrer: "social.example", Browser: "Chrome", OS: "Android", Device: "mobile", Country: "US"},
+		{LinkID: id, At: at("2024-03-11 01:00:00"), Browser: "Chrome", OS: "Windows", Device: "desktop", Country: "US"},
+		{LinkID: id, At: at("2024-03-11 01:30:00"), Referrer: "slack.com", Country: "US", Bot: true},
+	}))
+
+	q := storage.AnalyticsQuery{
+		LinkID:   id,
+		From:     at("2024-03-04 00:00:00"),
+		To:       at("2024-03-18 00:00:00"),
+		Interval: "day",
+		Limit:    2,
+	}
+
+	a, err := s.ClickAnalytics(ctx, q)
+	require.NoError(t, err)
+
+	// Bots are counted apart.
+	assert.Equal(t, int64(5), a.Total)
+	assert.Equal(t, int64(1), a.Bots)
+	assert.Equal(t, []storage.ClickBucket{
+		{Start: at("2024-03-04 00:00:00"), Clicks: 2},
+		{Start: at("2024-03-05 00:00:00"), Clicks: 1},
+		{Start: at("2024-03-10 00:00:00"), Clicks: 1},
+		{Start: at("2024-03-11 00:00:00"), Clicks: 1},
+	}, a.Series)
+	// Direct visits have no referrer.
+	assert.Equal(t, []storage.ClickCount{{Value: "", Clicks: 2}, {Value: "news.example", Clicks: 2}}, a.Referrers)
+	assert.Equal(t, []storage.ClickCount{{Value: "Chrome", Clicks: 3}, {Value: "Firefox", Clicks: 1}}, a.Browsers)
+	assert.Equal(t, []storage.ClickCount{{Value: "Windows", Clicks: 2}, {Value: "Android", Clicks: 1}}, a.OS)
+	assert.Equal(t, []storage.ClickCount{{Value: "desktop", Clicks: 3}, {Value: "mobile", Clicks: 2}}, a.Devices)
+	assert.Equal(t, []storage.ClickCount{{Value: "US", Clicks: 4}, {Value: "DE", Clicks: 1}}, a.Countries)
+
+	// Weeks start on Monday, so Sunday's click belongs to the first week.
+	q.Interval = "week"
+	a, err = s.ClickAnalytics(ctx, q)
+	require.NoError(t, err)
+	assert.Equal(t, []storage.ClickBucket{
+		{Start: at("2024-03-04 00:00:00"), Clicks: 4},
+		{Start: at("2024-03-11 00:00:00"), Clicks: 1},
+	}, a.Series)
+
+	q.IncludeBots = true
+	a, err = s.ClickAnalytics(ctx, q)
+	require.NoError(t, err)
+	assert.Equal(t, int64(6), a.Total)
+	assert.Equal(t, int64(1), a.Bots)
+	assert.Equal(t, []storage.ClickBucket{
+		{Start: at("2024-03-04 00:00:00"), Clicks: 4},
+		{Start: at("2024-03-11 00:00:00"), Clicks: 2},
+	}, a.Series)
+	assert.Equal(t, []storage.ClickCount{{Value: "US", Clicks: 5}, {Value: "DE", Clicks: 1}}, a.Countries)
+	q.IncludeBots = false
+
+	q.Interval = "hour"
+	q.To = at("2024-03-05 00:00:00")
+	a, err = s.ClickAnalytics(ctx, q)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), a.Total)
+	assert.Equal(t, []storage.ClickBucket{{Start: at("2024-03-04 10:00:00"), Clicks: 2}}, a.Series)
+
+	q.Interval = "month"
+	_, err = s.ClickAnalytics(ctx, q)
+	assert.Error(t, err)
+
+	// Raw clicks past the retention are purged, the analytics are kept.
+	require.NoError(t, s.SaveClicks(ctx, []storage.Click{{LinkID: other, At: time.Now()}}))
+
+	purged, err := s.PurgeClicks(ctx, 24*time.Hour)
+	require.NoError(t, err)
+	assert.Equal(t, int64(7), purged)
+
+	q.Interval = "hour"
+	a, err = s.ClickAnalytics(ctx, q)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), a.Total)
+
+	// Purging a link drops its clicks.
+	require.NoError(t, s.DeleteURL(ctx, "", "go"))
+	_, err = s.db.ExecContext(ctx, `UPDATE url SET deleted_at = datetime('now', '-2 hours') WHERE id = ?`, id)
+	require.NoError(t, err)
+	_, err = s.PurgeDeletedURLs(ctx, time.Hour)
+	require.NoError(t, err)
+
+	var clicks, rollups int
+	require.NoError(t, s.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM click`).Scan(&clicks))
+	require.NoError(t, s.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM click_rollup WHERE url_id = ?`, id).Scan(&rollups))
+	assert.Equal(t, 1, clicks)
+	assert.Zero(t, rollups)
+}
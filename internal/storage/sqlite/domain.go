@@ -0,0 +1,161 @@
+package sqlite
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/dkhrunov/url-shortener/internal/lib/zero"
+	"github.com/dkhrunov/url-shortener/internal/storage"
+	"github.com/mattn/go-sqlite3"
+)
+
+var saveDomainQuery = query(`--sql
+	INSERT INTO domain(host) VALUES(?)
+`)
+
+func (s *Sqlite) SaveDomain(ctx context.Context, host string) (int64, error) {
+	const op = "storage.sqlite.SaveDomain"
+
+	ctx, done := s.begin(ctx, op, s.opts.WriteTimeout)
+	defer done()
+
+	stmt, err := s.stmt(saveDomainQuery)
+	if err != nil {
+		return zero.Zero[int64](), fmt.Errorf("%s: %w", op, err)
+	}
+
+	res, err := stmt.ExecContext(ctx, host)
+	if err != nil {
+		if sqliteErr, ok := err.(sqlite3.Error); ok && sqliteErr.ExtendedCode == sqlite3.ErrConstraintUnique {
+			return zero.Zero[int64](), fmt.Errorf("%s: %w", op, storage.ErrDomainExist)
+		}
+
+		return zero.Zero[int64](), fmt.Errorf("%s: %w", op, err)
+	}
+
+	id, err := res.LastInsertId()
+	if err != nil {
+		return 0, fmt.Errorf("%s: failed to get last insert id: %w", op, err)
+	}
+
+	return id, nil
+}
+
+var listDomainsQuery = query(`--sql
+	SELECT id, host, created_at FROM domain ORDER BY host
+`)
+
+func (s *Sqlite) ListDomains(ctx context.Context) ([]storage.Domain, error) {
+	const op = "storage.sqlite.ListDomains"
+
+	ctx, done := s.begin(ctx, op, s.opts.ReadTimeout)
+	defer done()
+
+	stmt, err := s.stmt(listDomainsQuery)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	rows, err := stmt.QueryContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%s: execute statement: %w", op, err)
+	}
+	defer rows.Close()
+
+	domains := []storage.Domain{}
+	for rows.Next() {
+		var d storage.Domain
+		if err := rows.Scan(&d.ID, &d.Host, &d.CreatedAt); err != nil {
+			return nil, fmt.Errorf("%s: scan row: %w", op, err)
+		}
+
+		domains = append(domains, d)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return domains, nil
+}
+
+var (
+	domainInUseQuery = query(`--sql
+		SELECT EXISTS(
+			SELECT 1 FROM url
+			WHERE domain = (SELECT host FROM domain WHERE id = ?) AND deleted_at IS NULL
+		)
+	`)
+	purgeDomainURLsQuery = query(`--sql
+		DELETE FROM url WHERE domain = (SELECT host FROM domain WHERE id = ?)
+	`)
+	deleteDomainQuery = query(`--sql
+		DELETE FROM domain WHERE id = ?
+	`)
+)
+
+// DeleteDomain removes a registered domain. Domains that still have links can't be
+// removed; their deleted links are purged with them.
+func (s *Sqlite) DeleteDomain(ctx context.Context, id int64) error {
+	const op = "storage.sqlite.DeleteDomain"
+
+	ctx, done := s.begin(ctx, op, s.opts.WriteTimeout)
+	defer done()
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	if err := s.lock(ctx, tx); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	stmt, err := s.stmt(domainInUseQuery)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	var inUse bool
+	err = tx.StmtContext(ctx, stmt).QueryRowContext(ctx, id).Scan(&inUse)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if inUse {
+		return fmt.Errorf("%s: %w", op, storage.ErrDomainInUse)
+	}
+
+	stmt, err = s.stmt(purgeDomainURLsQuery)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	_, err = tx.StmtContext(ctx, stmt).ExecContext(ctx, id)
+	if err != nil {
+		return fmt.Errorf("%s: failed to purge deleted links: %w", op, err)
+	}
+
+	stmt, err = s.stmt(deleteDomainQuery)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	res, err := tx.StmtContext(ctx, stmt).ExecContext(ctx, id)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("%s: failed to get rows affected: %w", op, err)
+	}
+	if affected == 0 {
+		return fmt.Errorf("%s: %w", op, storage.ErrDomainNotFound)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,352 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+	"regexp"
+	"sort"
+	"strconv"
+)
+
+//go:embed migrations/*.sql
+var migrationFS embed.FS
+
+var ErrUnknownMigration = errors.New("unknown migration version")
+
+// migration is a numbered schema change, read from migrations/NNNN_name.up.sql and
+// the matching .down.sql file.
+type migration struct {
+	version int
+	name    string
+	up      string
+	down    string
+}
+
+var migrationFile = regexp.MustCompile(`^(\d+)_(\w+)\.(up|down)\.sql$`)
+
+var migrations = mustLoadMigrations()
+
+func mustLoadMigrations() []migration {
+	m, err := loadMigrations(migrationFS)
+	if err != nil {
+		panic(err)
+	}
+
+	return m
+}
+
+// loadMigrations reads the migrations in fsys. Versions must start at 1 with no gaps,
+// and each one must have both an up and a down file.
+func loadMigrations(fsys fs.FS) ([]migration, error) {
+	entries, err := fs.ReadDir(fsys, "migrations")
+	if err != nil {
+		return nil, err
+	}
+
+	byVersion := make(map[int]*migration)
+
+	for _, e := range entries {
+		parts := migrationFile.FindStringSubmatch(e.Name())
+		if parts == nil {
+			return nil, fmt.Errorf("unexpected migration file %q", e.Name())
+		}
+
+		version, err := strconv.Atoi(parts[1])
+		if err != nil {
+			return nil, fmt.Errorf("migration file %q: %w", e.Name(), err)
+		}
+
+		sqlText, err := fs.ReadFile(fsys, "migrations/"+e.Name())
+		if err != nil {
+			return nil, err
+		}
+
+		m, ok := byVersion[version]
+		if !ok {
+			m = &migration{version: version, name: parts[2]}
+			byVersion[version] = m
+		}
+		if m.name != parts[2] {
+			return nil, fmt.Errorf("migration %d has files named %q and %q", version, m.name, parts[2])
+		}
+
+		if parts[3] == "up" {
+			m.up = string(sqlText)
+		} else {
+			m.down = string(sqlText)
+		}
+	}
+
+	result := make([]migration, 0, len(byVersion))
+	for _, m := range byVersion {
+		result = append(result, *m)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].version < result[j].version
+	})
+
+	for i, m := range result {
+		if m.version != i+1 {
+			return nil, fmt.Errorf("migration %d is missing", i+1)
+		}
+		if m.up == "" || m.down == "" {
+			return nil, fmt.Errorf("migration %d needs both an up and a down file", m.version)
+		}
+	}
+
+	return result, nil
+}
+
+// LatestSchemaVersion is the version the database has once all migrations are applied.
+func LatestSchemaVersion() int {
+	return len(migrations)
+}
+
+// SchemaVersion returns the version of the last applied migration, zero when none is.
+func (s *Sqlite) SchemaVersion(ctx context.Context) (int, error) {
+	const op = "storage.sqlite.SchemaVersion"
+
+	v, err := schemaVersion(ctx, s.db)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return v, nil
+}
+
+// PendingMigrations returns the number of migrations not applied yet.
+func (s *Sqlite) PendingMigrations(ctx context.Context) (int, error) {
+	const op = "storage.sqlite.PendingMigrations"
+
+	v, err := schemaVersion(ctx, s.db)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return max(LatestSchemaVersion()-v, 0), nil
+}
+
+// MigrateUp applies all pending migrations.
+func (s *Sqlite) MigrateUp(ctx context.Context) error {
+	return s.MigrateTo(ctx, LatestSchemaVersion())
+}
+
+// MigrateDown reverts the last applied migration, if any.
+func (s *Sqlite) MigrateDown(ctx context.Context) error {
+	const op = "storage.sqlite.MigrateDown"
+
+	v, err := schemaVersion(ctx, s.db)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if v == 0 {
+		return nil
+	}
+
+	return s.MigrateTo(ctx, v-1)
+}
+
+// MigrateTo applies or reverts migrations until the schema is at version. Each
+// migration runs in a transaction of its own, so a failing one leaves the schema
+// at the version before it.
+func (s *Sqlite) MigrateTo(ctx context.Context, version int) error {
+	const op = "storage.sqlite.MigrateTo"
+
+	if version < 0 || version > LatestSchemaVersion() {
+		return fmt.Errorf("%s: %w: %d", op, ErrUnknownMigration, version)
+	}
+
+	_, err := s.db.ExecContext(ctx, `--sql
+		CREATE TABLE IF NOT EXISTS schema_migrations(
+			version INTEGER PRIMARY KEY,
+			applied_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+		)
+	`)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	current, err := schemaVersion(ctx, s.db)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if current == 0 && version > 0 {
+		if err := upgradeLegacy(ctx, s.db); err != nil {
+			return fmt.Errorf("%s: legacy schema: %w", op, err)
+		}
+	}
+
+	for ; current < version; current++ {
+		if err := s.applyMigration(ctx, migrations[current], true); err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	for ; current > version; current-- {
+		if err := s.applyMigration(ctx, migrations[current-1], false); err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	return nil
+}
+
+func (s *Sqlite) applyMigration(ctx context.Context, m migration, up bool) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if up {
+		_, err = tx.ExecContext(ctx, m.up)
+		if err == nil {
+			_, err = tx.ExecContext(ctx, `INSERT INTO schema_migrations(version) VALUES(?)`, m.version)
+		}
+	} else {
+		_, err = tx.ExecContext(ctx, m.down)
+		if err == nil {
+			_, err = tx.ExecContext(ctx, `DELETE FROM schema_migrations WHERE version = ?`, m.version)
+		}
+	}
+	if err != nil {
+		return fmt.Errorf("migration %d_%s: %w", m.version, m.name, err)
+	}
+
+	return tx.Commit()
+}
+
+type queryer interface {
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+func schemaVersion(ctx context.Context, q queryer) (int, error) {
+	exists, err := tableExists(ctx, q, "schema_migrations")
+	if err != nil || !exists {
+		return 0, err
+	}
+
+	var v int
+	err = q.QueryRowContext(ctx, `--sql
+		SELECT COALESCE(MAX(version), 0) FROM schema_migrations
+	`).Scan(&v)
+
+	return v, err
+}
+
+func tableExists(ctx context.Context, q queryer, table string) (bool, error) {
+	var n int
+	err := q.QueryRowContext(ctx, `--sql
+		SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name = ?
+	`, table).Scan(&n)
+
+	return n > 0, err
+}
+
+// upgradeLegacy brings a database created before migrations were introduced, whose
+// tables were created and upgraded in place on startup, to the layout of the first
+// migration. Empty databases are left alone.
+func upgradeLegacy(ctx context.Context, db *sql.DB) error {
+	legacy, err := tableExists(ctx, db, "url")
+	if err != nil || !legacy {
+		return err
+	}
+
+	if err := scopeAliasesByDomain(ctx, db); err != nil {
+		return err
+	}
+
+	if err := addColumn(ctx, db, "url", "owner_id", "INTEGER REFERENCES user(id)"); err != nil {
+		return err
+	}
+
+	if err := addColumn(ctx, db, "url", "api_key_id", "INTEGER REFERENCES api_key(id)"); err != nil {
+		return err
+	}
+
+	if ok, err := tableExists(ctx, db, "api_key"); err != nil {
+		return err
+	} else if ok {
+		if err := addColumn(ctx, db, "api_key", "user_id", "INTEGER REFERENCES user(id)"); err != nil {
+			return err
+		}
+	}
+
+	if ok, err := tableExists(ctx, db, "user"); err != nil {
+		return err
+	} else if ok {
+		// The user role of earlier versions is called editor now.
+		if _, err := db.ExecContext(ctx, `UPDATE user SET role = 'editor' WHERE role = 'user'`); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// addColumn adds a column to a table created by an earlier version, unless it is
+// already there.
+func addColumn(ctx context.Context, db *sql.DB, table, column, definition string) error {
+	var n int
+	err := db.QueryRowContext(ctx, `--sql
+		SELECT COUNT(*) FROM pragma_table_info(?) WHERE name = ?
+	`, table, column).Scan(&n)
+	if err != nil {
+		return err
+	}
+	if n > 0 {
+		return nil
+	}
+
+	_, err = db.ExecContext(ctx, fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s %s", table, column, definition))
+
+	return err
+}
+
+// scopeAliasesByDomain upgrades a url table created before multi-domain support,
+// where aliases were globally unique, to the per-domain layout.
+func scopeAliasesByDomain(ctx context.Context, db *sql.DB) error {
+	var n int
+	err := db.QueryRowContext(ctx, `--sql
+		SELECT COUNT(*) FROM pragma_table_info('url') WHERE name = 'domain'
+	`).Scan(&n)
+	if err != nil {
+		return err
+	}
+	if n > 0 {
+		return nil
+	}
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.ExecContext(ctx, `--sql
+		ALTER TABLE url RENAME TO url_global;
+
+		CREATE TABLE url(
+			id INTEGER PRIMARY KEY,
+			domain TEXT NOT NULL DEFAULT '',
+			alias TEXT NOT NULL,
+			url TEXT NOT NULL,
+			UNIQUE(domain, alias)
+		);
+
+		INSERT INTO url(id, alias, url) SELECT id, alias, url FROM url_global;
+
+		DROP TABLE url_global;
+	`)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
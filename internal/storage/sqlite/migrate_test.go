@@ -0,0 +1,145 @@
+package sqlite
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+
+	"github.com/dkhrunov/url-shortener/internal/storage"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMigrate(t *testing.T) {
+	ctx := context.Background()
+
+	s, err := New(filepath.Join(t.TempDir(), "storage.db"), Options{})
+	require.NoError(t, err)
+
+	pending, err := s.PendingMigrations(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, LatestSchemaVersion(), pending)
+
+	require.NoError(t, s.MigrateUp(ctx))
+	require.NoError(t, s.Prepare(ctx))
+
+	version, err := s.SchemaVersion(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, LatestSchemaVersion(), version)
+
+	_, err = s.SaveURL(ctx, "", "https://go.dev", "go", 0, 0, nil, storage.LinkLimits{})
+	require.NoError(t, err)
+
+	// Applying again is a no-op.
+	require.NoError(t, s.MigrateUp(ctx))
+
+	require.NoError(t, s.MigrateTo(ctx, 0))
+
+	version, err = s.SchemaVersion(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 0, version)
+
+	exists, err := tableExists(ctx, s.db, "url")
+	require.NoError(t, err)
+	assert.False(t, exists)
+
+	require.NoError(t, s.MigrateDown(ctx))
+
+	assert.ErrorIs(t, s.MigrateTo(ctx, LatestSchemaVersion()+1), ErrUnknownMigration)
+	assert.ErrorIs(t, s.MigrateTo(ctx, -1), ErrUnknownMigration)
+}
+
+func TestMigrateLegacy(t *testing.T) {
+	ctx := context.Background()
+
+	s, err := New(filepath.Join(t.TempDir(), "storage.db"), Options{})
+	require.NoError(t, err)
+
+	// The schema of the first release, before domains and owners.
+	_, err = s.db.Exec(`--sql
+		CREATE TABLE url(
+			id INTEGER PRIMARY KEY,
+			alias TEXT NOT NULL UNIQUE,
+			url TEXT NOT NULL
+		);
+		CREATE INDEX idx_alias ON url(alias);
+		INSERT INTO url(alias, url) VALUES ('go', 'https://go.dev');
+	`)
+	require.NoError(t, err)
+
+	require.NoError(t, s.MigrateUp(ctx))
+	require.NoError(t, s.Prepare(ctx))
+
+	u, err := s.GetLink(ctx, "", "go")
+	require.NoError(t, err)
+	assert.Equal(t, "https://go.dev", u.URL)
+
+	_, err = s.SaveURL(ctx, "", "https://pkg.go.dev", "pkg", 0, 0, nil, storage.LinkLimits{})
+	require.NoError(t, err)
+}
+
+func TestLoadMigrations(t *testing.T) {
+	file := func(s string) *fstest.MapFile {
+		return &fstest.MapFile{Data: []byte(s)}
+	}
+
+	cases := []struct {
+		name    string
+		fsys    fstest.MapFS
+		wantErr bool
+	}{
+		{
+			name: "Valid",
+			fsys: fstest.MapFS{
+				"migrations/0001_init.up.sql":   file("CREATE TABLE a(id INTEGER)"),
+				"migrations/0001_init.down.sql": file("DROP TABLE a"),
+				"migrations/0002_more.up.sql":   file("CREATE TABLE b(id INTEGER)"),
+				"migrations/0002_more.down.sql": file("DROP TABLE b"),
+			},
+		},
+		{
+			name: "Missing down",
+			fsys: fstest.MapFS{
+				"migrations/0001_init.up.sql": file("CREATE TABLE a(id INTEGER)"),
+			},
+			wantErr: true,
+		},
+		{
+			name: "Gap",
+			fsys: fstest.MapFS{
+				"migrations/0002_more.up.sql":   file("CREATE TABLE b(id INTEGER)"),
+				"migrations/0002_more.down.sql": file("DROP TABLE b"),
+			},
+			wantErr: true,
+		},
+		{
+			name: "Unexpected file",
+			fsys: fstest.MapFS{
+				"migrations/init.sql": file("CREATE TABLE a(id INTEGER)"),
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			m, err := loadMigrations(tc.fsys)
+			if tc.wantErr {
+				assert.Error(t, err)
+
+				return
+			}
+
+			require.NoError(t, err)
+			require.Len(t, m, 2)
+			assert.Equal(t, 2, m[1].version)
+			assert.Equal(t, "more", m[1].name)
+		})
+	}
+}
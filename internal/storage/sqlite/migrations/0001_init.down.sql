@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS quota_usage;
+DROP TABLE IF EXISTS domain;
+DROP TABLE IF EXISTS url;
+DROP TABLE IF EXISTS api_key;
+DROP TABLE IF EXISTS user;
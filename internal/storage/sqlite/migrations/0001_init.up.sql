@@ -0,0 +1,46 @@
+-- Tables are created only if missing: databases created before migrations were
+-- introduced already have them, upgraded to this layout.
+CREATE TABLE IF NOT EXISTS user(
+	id INTEGER PRIMARY KEY,
+	username TEXT NOT NULL UNIQUE,
+	password_hash TEXT NOT NULL,
+	role TEXT NOT NULL,
+	created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS api_key(
+	id INTEGER PRIMARY KEY,
+	name TEXT NOT NULL,
+	prefix TEXT NOT NULL,
+	key_hash TEXT NOT NULL UNIQUE,
+	created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	last_used_at DATETIME,
+	revoked_at DATETIME,
+	user_id INTEGER REFERENCES user(id)
+);
+
+CREATE TABLE IF NOT EXISTS url(
+	id INTEGER PRIMARY KEY,
+	domain TEXT NOT NULL DEFAULT '',
+	alias TEXT NOT NULL,
+	url TEXT NOT NULL,
+	owner_id INTEGER REFERENCES user(id),
+	api_key_id INTEGER REFERENCES api_key(id),
+	UNIQUE(domain, alias)
+);
+
+CREATE INDEX IF NOT EXISTS idx_url_owner ON url(owner_id);
+CREATE INDEX IF NOT EXISTS idx_url_api_key ON url(api_key_id);
+
+CREATE TABLE IF NOT EXISTS domain(
+	id INTEGER PRIMARY KEY,
+	host TEXT NOT NULL UNIQUE,
+	created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS quota_usage(
+	subject TEXT NOT NULL,
+	day DATE NOT NULL,
+	links INTEGER NOT NULL DEFAULT 0,
+	PRIMARY KEY(subject, day)
+);
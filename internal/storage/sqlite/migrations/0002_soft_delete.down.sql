@@ -0,0 +1,5 @@
+DELETE FROM url WHERE deleted_at IS NOT NULL;
+
+DROP INDEX idx_url_deleted_at;
+
+ALTER TABLE url DROP COLUMN deleted_at;
@@ -0,0 +1,4 @@
+-- Deleted links are kept until the retention period ends, so they can be restored.
+ALTER TABLE url ADD COLUMN deleted_at DATETIME;
+
+CREATE INDEX idx_url_deleted_at ON url(deleted_at) WHERE deleted_at IS NOT NULL;
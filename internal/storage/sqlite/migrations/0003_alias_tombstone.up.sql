@@ -0,0 +1,9 @@
+-- Aliases of purged links that can't be taken again, see Options.Tombstones.
+CREATE TABLE alias_tombstone(
+	domain TEXT NOT NULL,
+	alias TEXT NOT NULL,
+	deleted_at DATETIME NOT NULL,
+	PRIMARY KEY(domain, alias)
+);
+
+CREATE INDEX idx_alias_tombstone_deleted_at ON alias_tombstone(deleted_at);
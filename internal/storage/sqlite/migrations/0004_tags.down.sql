@@ -0,0 +1,7 @@
+ALTER TABLE url DROP COLUMN expires_at;
+
+DROP TRIGGER url_tag_purge;
+
+DROP TABLE url_tag;
+
+DROP TABLE tag;
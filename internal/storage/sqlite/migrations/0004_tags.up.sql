@@ -0,0 +1,22 @@
+-- Tags group links by campaign or team; a link has up to 10 of them.
+CREATE TABLE tag(
+	id INTEGER PRIMARY KEY,
+	name TEXT NOT NULL UNIQUE
+);
+
+CREATE TABLE url_tag(
+	url_id INTEGER NOT NULL REFERENCES url(id),
+	tag_id INTEGER NOT NULL REFERENCES tag(id),
+	PRIMARY KEY(url_id, tag_id)
+);
+
+CREATE INDEX idx_url_tag_tag ON url_tag(tag_id);
+
+-- Foreign keys are not enforced, so the tags of purged links are removed here.
+CREATE TRIGGER url_tag_purge AFTER DELETE ON url
+BEGIN
+	DELETE FROM url_tag WHERE url_id = OLD.id;
+END;
+
+-- Expired links stop resolving like deleted ones but stay listed, see ExpireURLsByTag.
+ALTER TABLE url ADD COLUMN expires_at DATETIME;
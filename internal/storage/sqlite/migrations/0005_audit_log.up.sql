@@ -0,0 +1,29 @@
+-- Who changed which link and how. Entries are only ever added.
+CREATE TABLE audit_log(
+	id INTEGER PRIMARY KEY,
+	created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	actor TEXT NOT NULL,
+	user_id INTEGER,
+	api_key_id INTEGER,
+	action TEXT NOT NULL,
+	domain TEXT NOT NULL DEFAULT '',
+	alias TEXT NOT NULL DEFAULT '',
+	before TEXT,
+	after TEXT,
+	request_id TEXT NOT NULL DEFAULT '',
+	remote_addr TEXT NOT NULL DEFAULT ''
+);
+
+CREATE INDEX idx_audit_log_created_at ON audit_log(created_at);
+CREATE INDEX idx_audit_log_alias ON audit_log(domain, alias);
+CREATE INDEX idx_audit_log_actor ON audit_log(actor);
+
+CREATE TRIGGER audit_log_no_update BEFORE UPDATE ON audit_log
+BEGIN
+	SELECT RAISE(ABORT, 'audit log is append-only');
+END;
+
+CREATE TRIGGER audit_log_no_delete BEFORE DELETE ON audit_log
+BEGIN
+	SELECT RAISE(ABORT, 'audit log is append-only');
+END;
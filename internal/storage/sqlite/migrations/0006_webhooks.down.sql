@@ -0,0 +1,5 @@
+DROP TRIGGER webhook_purge;
+
+DROP TABLE webhook_delivery;
+DROP TABLE webhook_outbox;
+DROP TABLE webhook;
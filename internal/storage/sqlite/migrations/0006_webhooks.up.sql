@@ -0,0 +1,45 @@
+-- Endpoints notified of link events, with the comma separated event types they
+-- subscribed to.
+CREATE TABLE webhook(
+	id INTEGER PRIMARY KEY,
+	url TEXT NOT NULL,
+	secret TEXT NOT NULL,
+	events TEXT NOT NULL,
+	created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Events waiting to be delivered to a webhook, kept until the delivery succeeds or
+-- runs out of attempts.
+CREATE TABLE webhook_outbox(
+	id INTEGER PRIMARY KEY,
+	webhook_id INTEGER NOT NULL,
+	event TEXT NOT NULL,
+	payload TEXT NOT NULL,
+	attempts INTEGER NOT NULL DEFAULT 0,
+	next_attempt_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_webhook_outbox_next_attempt_at ON webhook_outbox(next_attempt_at);
+
+-- Every delivery attempt of an event to a webhook.
+CREATE TABLE webhook_delivery(
+	id INTEGER PRIMARY KEY,
+	webhook_id INTEGER NOT NULL,
+	message_id INTEGER NOT NULL,
+	event TEXT NOT NULL,
+	attempt INTEGER NOT NULL,
+	status_code INTEGER NOT NULL DEFAULT 0,
+	error TEXT NOT NULL DEFAULT '',
+	duration_ms INTEGER NOT NULL DEFAULT 0,
+	created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_webhook_delivery_webhook ON webhook_delivery(webhook_id, id);
+
+-- Deleting a webhook drops its pending events and delivery log.
+CREATE TRIGGER webhook_purge AFTER DELETE ON webhook
+BEGIN
+	DELETE FROM webhook_outbox WHERE webhook_id = OLD.id;
+	DELETE FROM webhook_delivery WHERE webhook_id = OLD.id;
+END;
@@ -0,0 +1,5 @@
+DROP TRIGGER click_purge;
+DROP TRIGGER click_rollup_insert;
+
+DROP TABLE click_rollup;
+DROP TABLE click;
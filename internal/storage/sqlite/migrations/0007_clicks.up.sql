@@ -0,0 +1,47 @@
+-- Every redirect of a link, with the referrer host and the browser, OS, device and
+-- country of the client. Unknown values are empty.
+CREATE TABLE click(
+	id INTEGER PRIMARY KEY,
+	url_id INTEGER NOT NULL,
+	created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	referrer TEXT NOT NULL DEFAULT '',
+	browser TEXT NOT NULL DEFAULT '',
+	os TEXT NOT NULL DEFAULT '',
+	device TEXT NOT NULL DEFAULT '',
+	country TEXT NOT NULL DEFAULT ''
+);
+
+CREATE INDEX idx_click_url ON click(url_id, created_at);
+
+-- Clicks of a link per hour, in total under the empty dimension and per value of the
+-- referrer, browser, os, device and country dimensions. Reports read the rollups
+-- rather than the raw clicks, and coarser time buckets are summed up from them.
+CREATE TABLE click_rollup(
+	url_id INTEGER NOT NULL,
+	hour DATETIME NOT NULL,
+	dimension TEXT NOT NULL,
+	value TEXT NOT NULL,
+	clicks INTEGER NOT NULL,
+	PRIMARY KEY(url_id, dimension, hour, value)
+) WITHOUT ROWID;
+
+-- The rollups are kept up to date with every click saved.
+CREATE TRIGGER click_rollup_insert AFTER INSERT ON click
+BEGIN
+	INSERT INTO click_rollup(url_id, hour, dimension, value, clicks)
+	VALUES
+		(NEW.url_id, strftime('%Y-%m-%d %H:00:00', NEW.created_at), '', '', 1),
+		(NEW.url_id, strftime('%Y-%m-%d %H:00:00', NEW.created_at), 'referrer', NEW.referrer, 1),
+		(NEW.url_id, strftime('%Y-%m-%d %H:00:00', NEW.created_at), 'browser', NEW.browser, 1),
+		(NEW.url_id, strftime('%Y-%m-%d %H:00:00', NEW.created_at), 'os', NEW.os, 1),
+		(NEW.url_id, strftime('%Y-%m-%d %H:00:00', NEW.created_at), 'device', NEW.device, 1),
+		(NEW.url_id, strftime('%Y-%m-%d %H:00:00', NEW.created_at), 'country', NEW.country, 1)
+	ON CONFLICT(url_id, dimension, hour, value) DO UPDATE SET clicks = clicks + 1;
+END;
+
+-- Foreign keys are not enforced, so the clicks of purged links are removed here.
+CREATE TRIGGER click_purge AFTER DELETE ON url
+BEGIN
+	DELETE FROM click WHERE url_id = OLD.id;
+	DELETE FROM click_rollup WHERE url_id = OLD.id;
+END;
@@ -0,0 +1,40 @@
+DROP TRIGGER click_purge;
+DROP TRIGGER click_rollup_insert;
+
+CREATE TABLE click_rollup_all(
+	url_id INTEGER NOT NULL,
+	hour DATETIME NOT NULL,
+	dimension TEXT NOT NULL,
+	value TEXT NOT NULL,
+	clicks INTEGER NOT NULL,
+	PRIMARY KEY(url_id, dimension, hour, value)
+) WITHOUT ROWID;
+
+INSERT INTO click_rollup_all(url_id, hour, dimension, value, clicks)
+SELECT url_id, hour, dimension, value, SUM(clicks) FROM click_rollup
+GROUP BY url_id, dimension, hour, value;
+
+DROP TABLE click_rollup;
+
+ALTER TABLE click_rollup_all RENAME TO click_rollup;
+
+CREATE TRIGGER click_rollup_insert AFTER INSERT ON click
+BEGIN
+	INSERT INTO click_rollup(url_id, hour, dimension, value, clicks)
+	VALUES
+		(NEW.url_id, strftime('%Y-%m-%d %H:00:00', NEW.created_at), '', '', 1),
+		(NEW.url_id, strftime('%Y-%m-%d %H:00:00', NEW.created_at), 'referrer', NEW.referrer, 1),
+		(NEW.url_id, strftime('%Y-%m-%d %H:00:00', NEW.created_at), 'browser', NEW.browser, 1),
+		(NEW.url_id, strftime('%Y-%m-%d %H:00:00', NEW.created_at), 'os', NEW.os, 1),
+		(NEW.url_id, strftime('%Y-%m-%d %H:00:00', NEW.created_at), 'device', NEW.device, 1),
+		(NEW.url_id, strftime('%Y-%m-%d %H:00:00', NEW.created_at), 'country', NEW.country, 1)
+	ON CONFLICT(url_id, dimension, hour, value) DO UPDATE SET clicks = clicks + 1;
+END;
+
+CREATE TRIGGER click_purge AFTER DELETE ON url
+BEGIN
+	DELETE FROM click WHERE url_id = OLD.id;
+	DELETE FROM click_rollup WHERE url_id = OLD.id;
+END;
+
+ALTER TABLE click DROP COLUMN bot;
@@ -0,0 +1,42 @@
+-- Clicks of bots, crawlers and link previews are kept apart from those of people.
+ALTER TABLE click ADD COLUMN bot INTEGER NOT NULL DEFAULT 0;
+
+-- The triggers are recreated along with the rollups they refer to.
+DROP TRIGGER click_purge;
+DROP TRIGGER click_rollup_insert;
+
+CREATE TABLE click_rollup_bot(
+	url_id INTEGER NOT NULL,
+	hour DATETIME NOT NULL,
+	dimension TEXT NOT NULL,
+	bot INTEGER NOT NULL,
+	value TEXT NOT NULL,
+	clicks INTEGER NOT NULL,
+	PRIMARY KEY(url_id, dimension, hour, bot, value)
+) WITHOUT ROWID;
+
+INSERT INTO click_rollup_bot(url_id, hour, dimension, bot, value, clicks)
+SELECT url_id, hour, dimension, 0, value, clicks FROM click_rollup;
+
+DROP TABLE click_rollup;
+
+ALTER TABLE click_rollup_bot RENAME TO click_rollup;
+
+CREATE TRIGGER click_rollup_insert AFTER INSERT ON click
+BEGIN
+	INSERT INTO click_rollup(url_id, hour, dimension, bot, value, clicks)
+	VALUES
+		(NEW.url_id, strftime('%Y-%m-%d %H:00:00', NEW.created_at), '', NEW.bot, '', 1),
+		(NEW.url_id, strftime('%Y-%m-%d %H:00:00', NEW.created_at), 'referrer', NEW.bot, NEW.referrer, 1),
+		(NEW.url_id, strftime('%Y-%m-%d %H:00:00', NEW.created_at), 'browser', NEW.bot, NEW.browser, 1),
+		(NEW.url_id, strftime('%Y-%m-%d %H:00:00', NEW.created_at), 'os', NEW.bot, NEW.os, 1),
+		(NEW.url_id, strftime('%Y-%m-%d %H:00:00', NEW.created_at), 'device', NEW.bot, NEW.device, 1),
+		(NEW.url_id, strftime('%Y-%m-%d %H:00:00', NEW.created_at), 'country', NEW.bot, NEW.country, 1)
+	ON CONFLICT(url_id, dimension, hour, bot, value) DO UPDATE SET clicks = clicks + 1;
+END;
+
+CREATE TRIGGER click_purge AFTER DELETE ON url
+BEGIN
+	DELETE FROM click WHERE url_id = OLD.id;
+	DELETE FROM click_rollup WHERE url_id = OLD.id;
+END;
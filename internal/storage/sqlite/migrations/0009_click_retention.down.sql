@@ -0,0 +1 @@
+DROP INDEX idx_click_created;
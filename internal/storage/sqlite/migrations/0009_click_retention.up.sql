@@ -0,0 +1,3 @@
+-- Raw clicks are purged once they are older than the click retention period, see
+-- PurgeClicks. The rollups are kept with their links.
+CREATE INDEX idx_click_created ON click(created_at);
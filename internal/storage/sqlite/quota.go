@@ -0,0 +1,93 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strconv"
+
+	"github.com/dkhrunov/url-shortener/internal/lib/zero"
+	"github.com/dkhrunov/url-shortener/internal/storage"
+)
+
+// userLinkUsageQuery and keyLinkUsageQuery select the link quota usage of a user and
+// of an API key.
+var (
+	userLinkUsageQuery = linkUsageQuery("owner_id")
+	keyLinkUsageQuery  = linkUsageQuery("api_key_id")
+)
+
+// linkUsageQuery returns the query of the link quota usage of whom column is the id of.
+func linkUsageQuery(column string) string {
+	return query(`--sql
+		SELECT
+			(SELECT IFNULL(SUM(links), 0) FROM quota_usage WHERE subject = ? AND day = date('now')),
+			(SELECT COUNT(*) FROM url WHERE ` + column + ` = ? AND deleted_at IS NULL)
+	`)
+}
+
+// LinkUsage returns the link quota usage of the user userID or, when userID is zero,
+// of the API key apiKeyID. Links created with a key of a user count towards the user.
+func (s *Sqlite) LinkUsage(ctx context.Context, userID, apiKeyID int64) (storage.LinkUsage, error) {
+	const op = "storage.sqlite.LinkUsage"
+
+	ctx, done := s.begin(ctx, op, s.opts.ReadTimeout)
+	defer done()
+
+	usage, err := s.linkUsage(ctx, nil, userID, apiKeyID)
+	if err != nil {
+		return zero.Zero[storage.LinkUsage](), fmt.Errorf("%s: %w", op, err)
+	}
+
+	return usage, nil
+}
+
+// linkUsage reads the link quota usage, see LinkUsage, within tx unless it is nil.
+func (s *Sqlite) linkUsage(ctx context.Context, tx *sql.Tx, userID, apiKeyID int64) (storage.LinkUsage, error) {
+	q, id := userLinkUsageQuery, userID
+	if userID == 0 {
+		q, id = keyLinkUsageQuery, apiKeyID
+	}
+
+	stmt, err := s.stmt(q)
+	if err != nil {
+		return zero.Zero[storage.LinkUsage](), err
+	}
+	if tx != nil {
+		stmt = tx.StmtContext(ctx, stmt)
+	}
+
+	var usage storage.LinkUsage
+
+	err = stmt.QueryRowContext(ctx, quotaSubject(userID, apiKeyID), id).Scan(&usage.LinksToday, &usage.ActiveLinks)
+	if err != nil {
+		return zero.Zero[storage.LinkUsage](), err
+	}
+
+	return usage, nil
+}
+
+// checkLimits reports which of the limits the usage is over, if any.
+func checkLimits(limits storage.LinkLimits, usage storage.LinkUsage) error {
+	if limits.LinksPerDay > 0 && usage.LinksToday > limits.LinksPerDay {
+		return storage.ErrDailyQuotaExceeded
+	}
+	if limits.ActiveLinks > 0 && usage.ActiveLinks > limits.ActiveLinks {
+		return storage.ErrActiveQuotaExceeded
+	}
+
+	return nil
+}
+
+// quotaSubject names who link quotas are tracked for: the user when there is one,
+// the API key otherwise. It's empty when there is neither.
+func quotaSubject(userID, apiKeyID int64) string {
+	switch {
+	case userID != 0:
+		return "user:" + strconv.FormatInt(userID, 10)
+	case apiKeyID != 0:
+		return "key:" + strconv.FormatInt(apiKeyID, 10)
+	default:
+		return ""
+	}
+}
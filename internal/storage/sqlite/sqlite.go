@@ -1,59 +1,304 @@
 package sqlite
 
 import (
+	"context"
 	"database/sql"
 	"errors"
 	"fmt"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/dkhrunov/url-shortener/internal/lib/zero"
 	"github.com/dkhrunov/url-shortener/internal/storage"
 	"github.com/mattn/go-sqlite3"
+	"go.opentelemetry.io/otel"
+	semconv "go.opentelemetry.io/otel/semconv/v1.24.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var tracer = otel.Tracer("github.com/dkhrunov/url-shortener/internal/storage/sqlite")
+
+var (
+	journalModes = []string{"DELETE", "TRUNCATE", "PERSIST", "MEMORY", "WAL", "OFF"}
+	syncLevels   = []string{"OFF", "NORMAL", "FULL", "EXTRA"}
 )
 
 type Sqlite struct {
-	db *sql.DB
+	db       *sql.DB
+	opts     Options
+	observer Observer
+
+	// stmts are the prepared statements by query, see Prepare.
+	stmts map[string]*sql.Stmt
+}
+
+type Options struct {
+	// ReadTimeout and WriteTimeout bound a single operation that reads or writes,
+	// so a locked database fails the request instead of holding it. Zero means the
+	// operation only ends with the context it was called with. Waiting for a lock held
+	// by another connection can't be interrupted and is bounded by the busy timeout.
+	ReadTimeout  time.Duration
+	WriteTimeout time.Duration
+
+	// JournalMode and Synchronous set the journal_mode and synchronous pragmas of each
+	// connection, e.g. WAL and NORMAL. Empty keeps the SQLite default.
+	JournalMode string
+	Synchronous string
+	// BusyTimeout is how long a connection waits for a lock held by another one before
+	// failing with "database is locked". Zero keeps the driver default of 5 seconds.
+	BusyTimeout time.Duration
+
+	// MaxOpenConns, MaxIdleConns and ConnMaxLifetime limit the connection pool. Zero
+	// keeps the database/sql default.
+	MaxOpenConns    int
+	MaxIdleConns    int
+	ConnMaxLifetime time.Duration
+
+	// Tombstones keeps the aliases of purged links, so they can't be taken again until
+	// the tombstones are purged, see PurgeTombstones. Deleted and tombstoned aliases
+	// are then reported as gone rather than not found.
+	Tombstones bool
+}
+
+// Observer is told how long each storage operation took, by its op name.
+type Observer interface {
+	ObserveStorage(op string, d time.Duration)
 }
 
-func New(storagePath string) (*Sqlite, error) {
+// New opens the database. The schema is set up by the migrations, see MigrateUp, after
+// which the queries are prepared, see Prepare.
+func New(storagePath string, opts Options) (*Sqlite, error) {
 	const op = "storage.sqlite.New"
 
-	db, err := sql.Open("sqlite3", storagePath)
+	if opts.JournalMode != "" && !slices.Contains(journalModes, strings.ToUpper(opts.JournalMode)) {
+		return nil, fmt.Errorf("%s: unknown journal mode %q", op, opts.JournalMode)
+	}
+	if opts.Synchronous != "" && !slices.Contains(syncLevels, strings.ToUpper(opts.Synchronous)) {
+		return nil, fmt.Errorf("%s: unknown synchronous level %q", op, opts.Synchronous)
+	}
+
+	db, err := sql.Open("sqlite3", dsn(storagePath, opts))
 	if err != nil {
 		return nil, fmt.Errorf("%s: %w", op, err)
 	}
 
-	stmt, err := db.Prepare(`--sql
-		CREATE TABLE IF NOT EXISTS url(
-			id INTEGER PRIMARY KEY,
-			alias TEXT NOT NULL UNIQUE,
-			url TEXT NOT NULL
-		);
+	if opts.MaxOpenConns > 0 {
+		db.SetMaxOpenConns(opts.MaxOpenConns)
+	}
+	if opts.MaxIdleConns > 0 {
+		db.SetMaxIdleConns(opts.MaxIdleConns)
+	}
+	if opts.ConnMaxLifetime > 0 {
+		db.SetConnMaxLifetime(opts.ConnMaxLifetime)
+	}
 
-		CREATE INDEX IF NOT EXISTS idx_alias ON url(alias);
-	`)
-	if err != nil {
-		return nil, fmt.Errorf("%s: %w", op, err)
+	return &Sqlite{db: db, opts: opts, stmts: make(map[string]*sql.Stmt)}, nil
+}
+
+// dsn adds the connection pragmas of opts to the database path as driver parameters.
+func dsn(storagePath string, opts Options) string {
+	params := url.Values{}
+	if opts.JournalMode != "" {
+		params.Set("_journal_mode", strings.ToUpper(opts.JournalMode))
+	}
+	if opts.Synchronous != "" {
+		params.Set("_synchronous", strings.ToUpper(opts.Synchronous))
+	}
+	if opts.BusyTimeout > 0 {
+		params.Set("_busy_timeout", strconv.FormatInt(opts.BusyTimeout.Milliseconds(), 10))
+	}
+
+	if len(params) == 0 {
+		return storagePath
+	}
+
+	sep := "?"
+	if strings.Contains(storagePath, "?") {
+		sep = "&"
 	}
 
-	_, err = stmt.Exec()
+	return storagePath + sep + params.Encode()
+}
+
+// Close closes the prepared statements and the database.
+func (s *Sqlite) Close() error {
+	const op = "storage.sqlite.Close"
+
+	var errs []error
+	for _, stmt := range s.stmts {
+		errs = append(errs, stmt.Close())
+	}
+
+	errs = append(errs, s.db.Close())
+
+	if err := errors.Join(errs...); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// errNotPrepared is returned by the queries of a storage Prepare wasn't called on.
+var errNotPrepared = errors.New("storage.sqlite: queries are not prepared, see Prepare")
+
+// queries are all the queries of the storage, see query. The migrations run before the
+// queries are prepared, so they run their own.
+var queries []string
+
+// query registers a query of the storage to be prepared by Prepare and returns it.
+func query(q string) string {
+	queries = append(queries, q)
+
+	return q
+}
+
+// Prepare prepares the queries of the storage, which are then shared by all calls until
+// Close. It can't be done in New, as the tables only exist once the migrations ran, and
+// must be done before the storage is used. It fails on the first query that doesn't fit
+// the schema.
+func (s *Sqlite) Prepare(ctx context.Context) error {
+	const op = "storage.sqlite.Prepare"
+
+	for _, q := range queries {
+		if _, ok := s.stmts[q]; ok {
+			continue
+		}
+
+		stmt, err := s.db.PrepareContext(ctx, q)
+		if err != nil {
+			return fmt.Errorf("%s: %w\n%s", op, err, q)
+		}
+
+		s.stmts[q] = stmt
+	}
+
+	return nil
+}
+
+// stmt returns the statement of a query registered with query, prepared by Prepare,
+// or errNotPrepared when Prepare wasn't called. Statements are bound to a transaction
+// with Tx.StmtContext.
+func (s *Sqlite) stmt(q string) (*sql.Stmt, error) {
+	stmt, ok := s.stmts[q]
+	if !ok {
+		return nil, errNotPrepared
+	}
+
+	return stmt, nil
+}
+
+// lockQuery changes nothing, but as a write it takes the write lock, see lock.
+var lockQuery = query(`--sql
+	UPDATE url SET id = id WHERE 0
+`)
+
+// lock takes the write lock within tx, waiting up to the busy timeout for it. It must
+// come first in transactions that read before they write: in WAL mode such a
+// transaction fails with SQLITE_BUSY, without waiting, when another write commits
+// between its first read and its first write.
+func (s *Sqlite) lock(ctx context.Context, tx *sql.Tx) error {
+	stmt, err := s.stmt(lockQuery)
 	if err != nil {
-		return nil, fmt.Errorf("%s: %w", op, err)
+		return err
+	}
+
+	if _, err := tx.StmtContext(ctx, stmt).ExecContext(ctx); err != nil {
+		return fmt.Errorf("failed to lock: %w", err)
 	}
 
-	return &Sqlite{db: db}, nil
+	return nil
 }
 
-func (s *Sqlite) SaveURL(urlToSave, alias string) (int64, error) {
-	const op = "storage.sqlite.SaveURL"
+// SetObserver sets the observer of storage operations. It must be called before the
+// storage is used.
+func (s *Sqlite) SetObserver(o Observer) {
+	s.observer = o
+}
+
+// Stats returns the connection pool stats.
+func (s *Sqlite) Stats() sql.DBStats {
+	return s.db.Stats()
+}
+
+// Ping checks that the database can be reached.
+func (s *Sqlite) Ping(ctx context.Context) error {
+	const op = "storage.sqlite.Ping"
+
+	if err := s.db.PingContext(ctx); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// begin starts a span for the operation op and limits it to timeout, when it is not
+// zero. The returned func ends both and tells the observer how long the operation took.
+func (s *Sqlite) begin(ctx context.Context, op string, timeout time.Duration) (context.Context, func()) {
+	cancel := context.CancelFunc(func() {})
+	if timeout > 0 {
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+	}
 
-	stmt, err := s.db.Prepare(`--sql
-		INSERT INTO url(url, alias) VALUES(?, ?)
+	ctx, span := tracer.Start(ctx, op, trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(semconv.DBSystemSqlite),
+	)
+
+	start := time.Now()
+
+	return ctx, func() {
+		span.End()
+		cancel()
+
+		if s.observer != nil {
+			s.observer.ObserveStorage(op, time.Since(start))
+		}
+	}
+}
+
+var (
+	saveURLQuery = query(`--sql
+		INSERT INTO url(domain, url, alias, owner_id, api_key_id)
+		SELECT ?, ?, ?, NULLIF(?, 0), NULLIF(?, 0)
+		WHERE ? = '' OR EXISTS(SELECT 1 FROM domain WHERE host = ?)
+	`)
+	tombstonedQuery = query(`--sql
+		SELECT EXISTS(SELECT 1 FROM alias_tombstone WHERE domain = ? AND alias = ?)
 	`)
+	countLinkQuery = query(`--sql
+		INSERT INTO quota_usage(subject, day, links) VALUES (?, date('now'), 1)
+		ON CONFLICT(subject, day) DO UPDATE SET links = links + 1
+	`)
+)
+
+// SaveURL stores a link owned by the user ownerID, or by nobody when ownerID is zero.
+// apiKeyID is the key the link was created with, zero when it wasn't created with one.
+// The link is counted towards the quota of its creator and is not saved when that goes
+// over limits, reported as storage.ErrDailyQuotaExceeded or ErrActiveQuotaExceeded.
+// Tombstoned aliases are reported as storage.ErrURLGone.
+func (s *Sqlite) SaveURL(ctx context.Context, domain, urlToSave, alias string, ownerID, apiKeyID int64, tags []string, limits storage.LinkLimits) (int64, error) {
+	const op = "storage.sqlite.SaveURL"
+
+	ctx, done := s.begin(ctx, op, s.opts.WriteTimeout)
+	defer done()
+
+	tx, err := s.db.BeginTx(ctx, nil)
 	if err != nil {
 		return zero.Zero[int64](), fmt.Errorf("%s: %w", op, err)
 	}
+	defer tx.Rollback()
 
-	res, err := stmt.Exec(urlToSave, alias)
+	stmt, err := s.stmt(saveURLQuery)
+	if err != nil {
+		return zero.Zero[int64](), fmt.Errorf("%s: %w", op, err)
+	}
+
+	// The link is written before anything is checked, which takes the write lock, so
+	// that the checks see the links saved concurrently. Links outside the default
+	// namespace may only be saved on registered domains.
+	res, err := tx.StmtContext(ctx, stmt).ExecContext(ctx, domain, urlToSave, alias, ownerID, apiKeyID, domain, domain)
 	if err != nil {
 		if sqliteErr, ok := err.(sqlite3.Error); ok && sqliteErr.ExtendedCode == sqlite3.ErrConstraintUnique {
 			return zero.Zero[int64](), fmt.Errorf("%s: %w", op, storage.ErrURLExist)
@@ -62,26 +307,90 @@ func (s *Sqlite) SaveURL(urlToSave, alias string) (int64, error) {
 		return zero.Zero[int64](), fmt.Errorf("%s: %w", op, err)
 	}
 
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return zero.Zero[int64](), fmt.Errorf("%s: failed to get rows affected: %w", op, err)
+	}
+	if affected == 0 {
+		return zero.Zero[int64](), fmt.Errorf("%s: %w", op, storage.ErrDomainNotFound)
+	}
+
 	id, err := res.LastInsertId()
 	if err != nil {
 		return 0, fmt.Errorf("%s: failed to get last insert id: %w", op, err)
 	}
 
+	if s.opts.Tombstones {
+		stmt, err = s.stmt(tombstonedQuery)
+		if err != nil {
+			return zero.Zero[int64](), fmt.Errorf("%s: %w", op, err)
+		}
+
+		var tombstoned bool
+		err = tx.StmtContext(ctx, stmt).QueryRowContext(ctx, domain, alias).Scan(&tombstoned)
+		if err != nil {
+			return zero.Zero[int64](), fmt.Errorf("%s: %w", op, err)
+		}
+		if tombstoned {
+			return zero.Zero[int64](), fmt.Errorf("%s: %w", op, storage.ErrURLGone)
+		}
+	}
+
+	if err := s.setTags(ctx, tx, id, tags); err != nil {
+		return zero.Zero[int64](), fmt.Errorf("%s: %w", op, err)
+	}
+
+	if subject := quotaSubject(ownerID, apiKeyID); subject != "" {
+		stmt, err = s.stmt(countLinkQuery)
+		if err != nil {
+			return zero.Zero[int64](), fmt.Errorf("%s: %w", op, err)
+		}
+
+		_, err = tx.StmtContext(ctx, stmt).ExecContext(ctx, subject)
+		if err != nil {
+			return zero.Zero[int64](), fmt.Errorf("%s: failed to record usage: %w", op, err)
+		}
+
+		// The usage includes the link, which is rolled back when it goes over a limit.
+		usage, err := s.linkUsage(ctx, tx, ownerID, apiKeyID)
+		if err != nil {
+			return zero.Zero[int64](), fmt.Errorf("%s: failed to get usage: %w", op, err)
+		}
+		if err := checkLimits(limits, usage); err != nil {
+			return zero.Zero[int64](), fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return zero.Zero[int64](), fmt.Errorf("%s: %w", op, err)
+	}
+
 	return id, nil
 }
 
-func (s *Sqlite) GetURL(alias string) (string, error) {
+var getURLQuery = query(`--sql
+	SELECT url, IFNULL(expires_at <= CURRENT_TIMESTAMP, 0) FROM url
+	WHERE domain = ? AND alias = ? AND deleted_at IS NULL
+`)
+
+// GetURL returns the URL of a link in use. Expired links are reported as
+// storage.ErrURLGone.
+func (s *Sqlite) GetURL(ctx context.Context, domain, alias string) (string, error) {
 	const op = "storage.sqlite.GetURL"
 
-	stmt, err := s.db.Prepare(`--sql
-		SELECT url FROM url WHERE alias = ?
-	`)
+	ctx, done := s.begin(ctx, op, s.opts.ReadTimeout)
+	defer done()
+
+	stmt, err := s.stmt(getURLQuery)
 	if err != nil {
 		return zero.Zero[string](), fmt.Errorf("%s: %w", op, err)
 	}
 
-	var resURL string
-	err = stmt.QueryRow(alias).Scan(&resURL)
+	var (
+		resURL  string
+		expired bool
+	)
+	err = stmt.QueryRowContext(ctx, domain, alias).Scan(&resURL, &expired)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return zero.Zero[string](), fmt.Errorf("%s: %w", op, storage.ErrURLNotFound)
@@ -89,24 +398,424 @@ func (s *Sqlite) GetURL(alias string) (string, error) {
 
 		return zero.Zero[string](), fmt.Errorf("%s: execute statement: %w", op, err)
 	}
+	if expired {
+		return zero.Zero[string](), fmt.Errorf("%s: %w", op, storage.ErrURLGone)
+	}
 
 	return resURL, nil
 }
 
-func (s *Sqlite) DeleteURL(alias string) error {
-	const op = "storage.sqlite.DeleteURL"
+var getURLByHostQuery = query(`--sql
+	WITH ns(domain) AS (SELECT COALESCE((SELECT host FROM domain WHERE host = ?), ''))
+	SELECT id, domain, url, deleted_at IS NOT NULL AS gone, IFNULL(expires_at <= CURRENT_TIMESTAMP, 0) FROM url
+	WHERE alias = ? AND domain = (SELECT domain FROM ns)
+	UNION ALL
+	SELECT 0, domain, '', 1, 0 FROM alias_tombstone
+	WHERE alias = ? AND domain = (SELECT domain FROM ns)
+	ORDER BY gone LIMIT 1
+`)
+
+// GetURLByHost looks alias up in the namespace of host when host is a registered domain,
+// and in the default namespace otherwise, returning the id, domain and URL of the link.
+// Expired links are reported as storage.ErrURLGone, and so are aliases of deleted links
+// with Options.Tombstones.
+func (s *Sqlite) GetURLByHost(ctx context.Context, host, alias string) (storage.URL, error) {
+	const op = "storage.sqlite.GetURLByHost"
+
+	ctx, done := s.begin(ctx, op, s.opts.ReadTimeout)
+	defer done()
+
+	stmt, err := s.stmt(getURLByHostQuery)
+	if err != nil {
+		return zero.Zero[storage.URL](), fmt.Errorf("%s: %w", op, err)
+	}
+
+	var (
+		link    = storage.URL{Alias: alias}
+		gone    bool
+		expired bool
+	)
+	err = stmt.QueryRowContext(ctx, host, alias, alias).Scan(&link.ID, &link.Domain, &link.URL, &gone, &expired)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return zero.Zero[storage.URL](), fmt.Errorf("%s: %w", op, storage.ErrURLNotFound)
+		}
+
+		return zero.Zero[storage.URL](), fmt.Errorf("%s: execute statement: %w", op, err)
+	}
+	if gone && !s.opts.Tombstones {
+		return zero.Zero[storage.URL](), fmt.Errorf("%s: %w", op, storage.ErrURLNotFound)
+	}
+	if gone || expired {
+		return zero.Zero[storage.URL](), fmt.Errorf("%s: %w", op, storage.ErrURLGone)
+	}
+
+	return link, nil
+}
+
+var getLinkQuery = query(`--sql
+	SELECT id, domain, alias, url, IFNULL(owner_id, 0), expires_at, ` + tagsColumn + ` FROM url
+	WHERE domain = ? AND alias = ? AND deleted_at IS NULL
+`)
+
+func (s *Sqlite) GetLink(ctx context.Context, domain, alias string) (storage.URL, error) {
+	const op = "storage.sqlite.GetLink"
+
+	ctx, done := s.begin(ctx, op, s.opts.ReadTimeout)
+	defer done()
+
+	stmt, err := s.stmt(getLinkQuery)
+	if err != nil {
+		return zero.Zero[storage.URL](), fmt.Errorf("%s: %w", op, err)
+	}
+
+	u, err := scanLink(stmt.QueryRowContext(ctx, domain, alias))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return zero.Zero[storage.URL](), fmt.Errorf("%s: %w", op, storage.ErrURLNotFound)
+		}
+
+		return zero.Zero[storage.URL](), fmt.Errorf("%s: execute statement: %w", op, err)
+	}
+
+	return u, nil
+}
 
-	stmt, err := s.db.Prepare(`--sql
-		DELETE FROM url WHERE alias = ?
+var (
+	linkIDQuery = query(`--sql
+		SELECT id FROM url WHERE domain = ? AND alias = ? AND deleted_at IS NULL
 	`)
+	updateURLQuery = query(`--sql
+		UPDATE url SET url = ? WHERE id = ?
+	`)
+)
+
+// UpdateURL changes the target and the tags of a link, see storage.LinkUpdate.
+func (s *Sqlite) UpdateURL(ctx context.Context, domain, alias string, upd storage.LinkUpdate) error {
+	const op = "storage.sqlite.UpdateURL"
+
+	ctx, done := s.begin(ctx, op, s.opts.WriteTimeout)
+	defer done()
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	if err := s.lock(ctx, tx); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	stmt, err := s.stmt(linkIDQuery)
 	if err != nil {
 		return fmt.Errorf("%s: %w", op, err)
 	}
 
-	_, err = stmt.Exec(alias)
+	var id int64
+	err = tx.StmtContext(ctx, stmt).QueryRowContext(ctx, domain, alias).Scan(&id)
 	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("%s: %w", op, storage.ErrURLNotFound)
+		}
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if upd.URL != "" {
+		stmt, err = s.stmt(updateURLQuery)
+		if err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+
+		_, err = tx.StmtContext(ctx, stmt).ExecContext(ctx, upd.URL, id)
+		if err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	if upd.Tags != nil {
+		if err := s.setTags(ctx, tx, id, *upd.Tags); err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
 		return fmt.Errorf("%s: %w", op, err)
 	}
 
 	return nil
 }
+
+var deleteURLQuery = query(`--sql
+	UPDATE url SET deleted_at = CURRENT_TIMESTAMP
+	WHERE domain = ? AND alias = ? AND deleted_at IS NULL
+`)
+
+// DeleteURL soft deletes a link: it stops resolving but can be restored until it is
+// purged, see PurgeDeletedURLs. Its alias stays taken until then.
+func (s *Sqlite) DeleteURL(ctx context.Context, domain, alias string) error {
+	const op = "storage.sqlite.DeleteURL"
+
+	ctx, done := s.begin(ctx, op, s.opts.WriteTimeout)
+	defer done()
+
+	stmt, err := s.stmt(deleteURLQuery)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	res, err := stmt.ExecContext(ctx, domain, alias)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("%s: failed to get rows affected: %w", op, err)
+	}
+	if affected == 0 {
+		return fmt.Errorf("%s: %w", op, storage.ErrURLNotFound)
+	}
+
+	return nil
+}
+
+var getDeletedURLQuery = query(`--sql
+	SELECT id, domain, alias, url, IFNULL(owner_id, 0), deleted_at FROM url
+	WHERE domain = ? AND alias = ? AND deleted_at IS NOT NULL
+`)
+
+// GetDeletedURL returns a soft deleted link that was not purged yet.
+func (s *Sqlite) GetDeletedURL(ctx context.Context, domain, alias string) (storage.URL, error) {
+	const op = "storage.sqlite.GetDeletedURL"
+
+	ctx, done := s.begin(ctx, op, s.opts.ReadTimeout)
+	defer done()
+
+	stmt, err := s.stmt(getDeletedURLQuery)
+	if err != nil {
+		return zero.Zero[storage.URL](), fmt.Errorf("%s: %w", op, err)
+	}
+
+	var (
+		u         storage.URL
+		deletedAt sql.NullTime
+	)
+	err = stmt.QueryRowContext(ctx, domain, alias).Scan(&u.ID, &u.Domain, &u.Alias, &u.URL, &u.OwnerID, &deletedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return zero.Zero[storage.URL](), fmt.Errorf("%s: %w", op, storage.ErrURLNotFound)
+		}
+
+		return zero.Zero[storage.URL](), fmt.Errorf("%s: execute statement: %w", op, err)
+	}
+
+	u.DeletedAt = nullTime(deletedAt)
+
+	return u, nil
+}
+
+var restoreURLQuery = query(`--sql
+	UPDATE url SET deleted_at = NULL
+	WHERE domain = ? AND alias = ? AND deleted_at IS NOT NULL
+	RETURNING IFNULL(owner_id, 0), IFNULL(api_key_id, 0)
+`)
+
+// RestoreURL undoes the soft delete of a link that was not purged yet. The link is
+// active again and is not restored when that goes over limits.ActiveLinks of its
+// creator, reported as storage.ErrActiveQuotaExceeded.
+func (s *Sqlite) RestoreURL(ctx context.Context, domain, alias string, limits storage.LinkLimits) error {
+	const op = "storage.sqlite.RestoreURL"
+
+	ctx, done := s.begin(ctx, op, s.opts.WriteTimeout)
+	defer done()
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	// The link is restored before the quota is checked, as in SaveURL, so that the
+	// check sees the links saved or restored concurrently.
+	var ownerID, apiKeyID int64
+
+	stmt, err := s.stmt(restoreURLQuery)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	err = tx.StmtContext(ctx, stmt).QueryRowContext(ctx, domain, alias).Scan(&ownerID, &apiKeyID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("%s: %w", op, storage.ErrURLNotFound)
+	}
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	// Restoring a link doesn't create one, so only the active links are limited.
+	if limits.ActiveLinks > 0 && quotaSubject(ownerID, apiKeyID) != "" {
+		usage, err := s.linkUsage(ctx, tx, ownerID, apiKeyID)
+		if err != nil {
+			return fmt.Errorf("%s: failed to get usage: %w", op, err)
+		}
+		if err := checkLimits(storage.LinkLimits{ActiveLinks: limits.ActiveLinks}, usage); err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+var (
+	keepTombstonesQuery = query(`--sql
+		INSERT OR REPLACE INTO alias_tombstone(domain, alias, deleted_at)
+		SELECT domain, alias, deleted_at FROM url WHERE deleted_at < datetime('now', ?)
+	`)
+	purgeDeletedURLsQuery = query(`--sql
+		DELETE FROM url WHERE deleted_at < datetime('now', ?)
+	`)
+)
+
+// PurgeDeletedURLs removes the links soft deleted longer than retention ago, which
+// makes their aliases available again unless Options.Tombstones is set. It returns
+// the number of links removed.
+func (s *Sqlite) PurgeDeletedURLs(ctx context.Context, retention time.Duration) (int64, error) {
+	const op = "storage.sqlite.PurgeDeletedURLs"
+
+	ctx, done := s.begin(ctx, op, s.opts.WriteTimeout)
+	defer done()
+
+	before := sinceModifier(retention)
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	if s.opts.Tombstones {
+		stmt, err := s.stmt(keepTombstonesQuery)
+		if err != nil {
+			return 0, fmt.Errorf("%s: %w", op, err)
+		}
+
+		_, err = tx.StmtContext(ctx, stmt).ExecContext(ctx, before)
+		if err != nil {
+			return 0, fmt.Errorf("%s: failed to keep tombstones: %w", op, err)
+		}
+	}
+
+	stmt, err := s.stmt(purgeDeletedURLsQuery)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	res, err := tx.StmtContext(ctx, stmt).ExecContext(ctx, before)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	purged, err := res.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("%s: failed to get rows affected: %w", op, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return purged, nil
+}
+
+var purgeTombstonesQuery = query(`--sql
+	DELETE FROM alias_tombstone WHERE deleted_at < datetime('now', ?)
+`)
+
+// PurgeTombstones removes the tombstones of aliases deleted longer than period ago, so
+// the aliases can be taken again. It returns the number of tombstones removed.
+func (s *Sqlite) PurgeTombstones(ctx context.Context, period time.Duration) (int64, error) {
+	const op = "storage.sqlite.PurgeTombstones"
+
+	ctx, done := s.begin(ctx, op, s.opts.WriteTimeout)
+	defer done()
+
+	stmt, err := s.stmt(purgeTombstonesQuery)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	res, err := stmt.ExecContext(ctx, sinceModifier(period))
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	purged, err := res.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("%s: failed to get rows affected: %w", op, err)
+	}
+
+	return purged, nil
+}
+
+// sinceModifier returns the datetime() modifier for the time d ago.
+func sinceModifier(d time.Duration) string {
+	return fmt.Sprintf("-%d seconds", int64(d.Seconds()))
+}
+
+var listURLsQuery = query(`--sql
+	SELECT id, domain, alias, url, IFNULL(owner_id, 0), expires_at, ` + tagsColumn + ` FROM url
+	WHERE domain = ? AND (? = 0 OR owner_id = ?) AND deleted_at IS NULL
+		AND (? = '' OR id IN (
+			SELECT url_id FROM url_tag WHERE tag_id = (SELECT id FROM tag WHERE name = ?)
+		))
+	ORDER BY id LIMIT ? OFFSET ?
+`)
+
+// ListURLs returns a page of links on domain. When ownerID is not zero, only the links
+// of that user are returned, and when tag is not empty, only the links with that tag.
+func (s *Sqlite) ListURLs(ctx context.Context, domain string, ownerID int64, tag string, limit, offset int) ([]storage.URL, error) {
+	const op = "storage.sqlite.ListURLs"
+
+	ctx, done := s.begin(ctx, op, s.opts.ReadTimeout)
+	defer done()
+
+	stmt, err := s.stmt(listURLsQuery)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	rows, err := stmt.QueryContext(ctx, domain, ownerID, ownerID, tag, tag, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("%s: execute statement: %w", op, err)
+	}
+	defer rows.Close()
+
+	urls := []storage.URL{}
+	for rows.Next() {
+		u, err := scanLink(rows)
+		if err != nil {
+			return nil, fmt.Errorf("%s: scan row: %w", op, err)
+		}
+
+		urls = append(urls, u)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return urls, nil
+}
+
+func nullTime(t sql.NullTime) *time.Time {
+	if !t.Valid {
+		return nil
+	}
+
+	return &t.Time
+}
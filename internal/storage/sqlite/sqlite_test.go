@@ -0,0 +1,331 @@
+package sqlite
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/dkhrunov/url-shortener/internal/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBeginTimeout(t *testing.T) {
+	s, err := New(filepath.Join(t.TempDir(), "storage.db"), Options{})
+	require.NoError(t, err)
+
+	ctx, done := s.begin(context.Background(), "test", 50*time.Millisecond)
+	defer done()
+
+	start := time.Now()
+
+	// Counts forever, until the statement is interrupted.
+	_, err = s.db.ExecContext(ctx, `--sql
+		WITH RECURSIVE c(x) AS (SELECT 1 UNION ALL SELECT x + 1 FROM c) SELECT COUNT(*) FROM c
+	`)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Less(t, time.Since(start), 2*time.Second)
+}
+
+func TestNewOptions(t *testing.T) {
+	ctx := context.Background()
+
+	s, err := New(filepath.Join(t.TempDir(), "storage.db"), Options{
+		JournalMode:  "wal",
+		Synchronous:  "normal",
+		BusyTimeout:  time.Second,
+		MaxOpenConns: 4,
+	})
+	require.NoError(t, err)
+	t.Cleanup(func() { s.Close() })
+
+	var journalMode string
+	require.NoError(t, s.db.QueryRowContext(ctx, "PRAGMA journal_mode").Scan(&journalMode))
+	assert.Equal(t, "wal", journalMode)
+
+	var synchronous, busyTimeout int
+	require.NoError(t, s.db.QueryRowContext(ctx, "PRAGMA synchronous").Scan(&synchronous))
+	assert.Equal(t, 1, synchronous)
+	require.NoError(t, s.db.QueryRowContext(ctx, "PRAGMA busy_timeout").Scan(&busyTimeout))
+	assert.Equal(t, 1000, busyTimeout)
+
+	assert.Equal(t, 4, s.Stats().MaxOpenConnections)
+
+	_, err = New("storage.db", Options{JournalMode: "fast"})
+	assert.Error(t, err)
+
+	_, err = New("storage.db", Options{Synchronous: "sometimes"})
+	assert.Error(t, err)
+}
+
+func TestDSN(t *testing.T) {
+	assert.Equal(t, "storage.db", dsn("storage.db", Options{}))
+	assert.Equal(t,
+		"storage.db?_busy_timeout=1500&_journal_mode=WAL&_synchronous=NORMAL",
+		dsn("storage.db", Options{JournalMode: "wal", Synchronous: "normal", BusyTimeout: 1500 * time.Millisecond}),
+	)
+	assert.Equal(t,
+		"file:storage.db?cache=shared&_journal_mode=WAL",
+		dsn("file:storage.db?cache=shared", Options{JournalMode: "wal"}),
+	)
+}
+
+func TestPreparedStatements(t *testing.T) {
+	ctx := context.Background()
+
+	s, err := New(filepath.Join(t.TempDir(), "storage.db"), Options{})
+	require.NoError(t, err)
+
+	// The queries don't fit a database without the schema, and fail until prepared.
+	assert.Error(t, s.Prepare(ctx))
+
+	_, err = s.GetURLByHost(ctx, "sho.rt", "go")
+	assert.ErrorIs(t, err, errNotPrepared)
+
+	require.NoError(t, s.MigrateUp(ctx))
+	require.NoError(t, s.Prepare(ctx))
+
+	// Every query is prepared up front, once.
+	assert.Len(t, s.stmts, len(queries))
+
+	_, err = s.SaveURL(ctx, "", "https://go.dev", "go", 0, 0, nil, storage.LinkLimits{})
+	require.NoError(t, err)
+
+	for i := 0; i < 3; i++ {
+		u, err := s.GetURLByHost(ctx, "sho.rt", "go")
+		require.NoError(t, err)
+		assert.Equal(t, "https://go.dev", u.URL)
+	}
+
+	assert.Len(t, s.stmts, len(queries))
+
+	require.NoError(t, s.Close())
+
+	_, err = s.GetURLByHost(ctx, "sho.rt", "go")
+	assert.Error(t, err)
+}
+
+func TestSoftDelete(t *testing.T) {
+	ctx := context.Background()
+
+	s, err := New(filepath.Join(t.TempDir(), "storage.db"), Options{})
+	require.NoError(t, err)
+	require.NoError(t, s.MigrateUp(ctx))
+	require.NoError(t, s.Prepare(ctx))
+
+	_, err = s.SaveURL(ctx, "", "https://go.dev", "go", 0, 0, nil, storage.LinkLimits{})
+	require.NoError(t, err)
+
+	require.NoError(t, s.DeleteURL(ctx, "", "go"))
+	assert.ErrorIs(t, s.DeleteURL(ctx, "", "go"), storage.ErrURLNotFound)
+
+	_, err = s.GetURL(ctx, "", "go")
+	assert.ErrorIs(t, err, storage.ErrURLNotFound)
+
+	_, err = s.GetURLByHost(ctx, "sho.rt", "go")
+	assert.ErrorIs(t, err, storage.ErrURLNotFound)
+
+	u, err := s.GetDeletedURL(ctx, "", "go")
+	require.NoError(t, err)
+	assert.NotNil(t, u.DeletedAt)
+
+	// Links deleted within the retention period are kept.
+	purged, err := s.PurgeDeletedURLs(ctx, time.Hour)
+	require.NoError(t, err)
+	assert.Zero(t, purged)
+
+	_, err = s.db.ExecContext(ctx, `UPDATE url SET deleted_at = datetime('now', '-2 hours')`)
+	require.NoError(t, err)
+
+	purged, err = s.PurgeDeletedURLs(ctx, time.Hour)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), purged)
+
+	assert.ErrorIs(t, s.RestoreURL(ctx, "", "go", storage.LinkLimits{}), storage.ErrURLNotFound)
+
+	// The alias is free again.
+	_, err = s.SaveURL(ctx, "", "https://pkg.go.dev", "go", 0, 0, nil, storage.LinkLimits{})
+	require.NoError(t, err)
+}
+
+func TestTombstones(t *testing.T) {
+	ctx := context.Background()
+
+	s, err := New(filepath.Join(t.TempDir(), "storage.db"), Options{Tombstones: true})
+	require.NoError(t, err)
+	require.NoError(t, s.MigrateUp(ctx))
+	require.NoError(t, s.Prepare(ctx))
+
+	_, err = s.SaveURL(ctx, "", "https://go.dev", "go", 0, 0, nil, storage.LinkLimits{})
+	require.NoError(t, err)
+	require.NoError(t, s.DeleteURL(ctx, "", "go"))
+
+	_, err = s.GetURLByHost(ctx, "sho.rt", "go")
+	assert.ErrorIs(t, err, storage.ErrURLGone)
+
+	_, err = s.db.ExecContext(ctx, `UPDATE url SET deleted_at = datetime('now', '-2 hours')`)
+	require.NoError(t, err)
+
+	purged, err := s.PurgeDeletedURLs(ctx, time.Hour)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), purged)
+
+	_, err = s.GetURLByHost(ctx, "sho.rt", "go")
+	assert.ErrorIs(t, err, storage.ErrURLGone)
+
+	_, err = s.SaveURL(ctx, "", "https://evil.example", "go", 0, 0, nil, storage.LinkLimits{})
+	assert.ErrorIs(t, err, storage.ErrURLGone)
+
+	// Tombstones last for their own period, counted from the delete.
+	purged, err = s.PurgeTombstones(ctx, 3*time.Hour)
+	require.NoError(t, err)
+	assert.Zero(t, purged)
+
+	purged, err = s.PurgeTombstones(ctx, time.Hour)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), purged)
+
+	_, err = s.GetURLByHost(ctx, "sho.rt", "go")
+	assert.ErrorIs(t, err, storage.ErrURLNotFound)
+
+	_, err = s.SaveURL(ctx, "", "https://pkg.go.dev", "go", 0, 0, nil, storage.LinkLimits{})
+	require.NoError(t, err)
+}
+
+func TestLinkQuota(t *testing.T) {
+	ctx := context.Background()
+
+	s, err := New(filepath.Join(t.TempDir(), "storage.db"), Options{})
+	require.NoError(t, err)
+	require.NoError(t, s.MigrateUp(ctx))
+	require.NoError(t, s.Prepare(ctx))
+
+	userID, err := s.SaveUser(ctx, "alice", "hash", "editor")
+	require.NoError(t, err)
+
+	limits := storage.LinkLimits{LinksPerDay: 2, ActiveLinks: 1}
+
+	_, err = s.SaveURL(ctx, "", "https://go.dev", "go", userID, 0, nil, limits)
+	require.NoError(t, err)
+
+	// The link over a limit isn't saved, nor counted.
+	_, err = s.SaveURL(ctx, "", "https://pkg.go.dev", "pkg", userID, 0, nil, limits)
+	assert.ErrorIs(t, err, storage.ErrActiveQuotaExceeded)
+	assert.ErrorIs(t, err, storage.ErrQuotaExceeded)
+
+	_, err = s.GetURL(ctx, "", "pkg")
+	assert.ErrorIs(t, err, storage.ErrURLNotFound)
+
+	usage, err := s.LinkUsage(ctx, userID, 0)
+	require.NoError(t, err)
+	assert.Equal(t, storage.LinkUsage{LinksToday: 1, ActiveLinks: 1}, usage)
+
+	// Deleted links still count towards the daily limit.
+	require.NoError(t, s.DeleteURL(ctx, "", "go"))
+
+	_, err = s.SaveURL(ctx, "", "https://pkg.go.dev", "pkg", userID, 0, nil, limits)
+	require.NoError(t, err)
+
+	require.NoError(t, s.DeleteURL(ctx, "", "pkg"))
+
+	_, err = s.SaveURL(ctx, "", "https://go.dev/play", "play", userID, 0, nil, limits)
+	assert.ErrorIs(t, err, storage.ErrDailyQuotaExceeded)
+
+	// Without limits, the link is only counted.
+	_, err = s.SaveURL(ctx, "", "https://go.dev/play", "play", userID, 0, nil, storage.LinkLimits{})
+	require.NoError(t, err)
+
+	usage, err = s.LinkUsage(ctx, userID, 0)
+	require.NoError(t, err)
+	assert.Equal(t, storage.LinkUsage{LinksToday: 3, ActiveLinks: 1}, usage)
+
+	// Restored links are active again, and stay deleted over the active limit.
+	err = s.RestoreURL(ctx, "", "pkg", limits)
+	assert.ErrorIs(t, err, storage.ErrActiveQuotaExceeded)
+
+	_, err = s.GetDeletedURL(ctx, "", "pkg")
+	require.NoError(t, err)
+
+	require.NoError(t, s.DeleteURL(ctx, "", "play"))
+	require.NoError(t, s.RestoreURL(ctx, "", "pkg", limits))
+
+	usage, err = s.LinkUsage(ctx, userID, 0)
+	require.NoError(t, err)
+	assert.Equal(t, storage.LinkUsage{LinksToday: 3, ActiveLinks: 1}, usage)
+}
+
+// BenchmarkRedirect compares redirect lookups preparing their statement on every call,
+// as the storage used to, with the shared prepared statement, in the default rollback
+// journal and in WAL mode.
+func BenchmarkRedirect(b *testing.B) {
+	modes := []struct {
+		name string
+		opts Options
+	}{
+		{name: "journal=delete", opts: Options{JournalMode: "delete"}},
+		{name: "journal=wal", opts: Options{JournalMode: "wal", Synchronous: "normal"}},
+	}
+
+	for _, mode := range modes {
+		s := newBenchStorage(b, mode.opts)
+
+		b.Run(mode.name+"/prepare-per-call", func(b *testing.B) {
+			b.RunParallel(func(pb *testing.PB) {
+				for pb.Next() {
+					if _, err := getURLByHostUnprepared(s, "sho.rt", "go"); err != nil {
+						b.Error(err)
+					}
+				}
+			})
+		})
+
+		b.Run(mode.name+"/prepared", func(b *testing.B) {
+			b.RunParallel(func(pb *testing.PB) {
+				for pb.Next() {
+					if _, err := s.GetURLByHost(context.Background(), "sho.rt", "go"); err != nil {
+						b.Error(err)
+					}
+				}
+			})
+		})
+	}
+}
+
+func newBenchStorage(b *testing.B, opts Options) *Sqlite {
+	b.Helper()
+
+	ctx := context.Background()
+
+	s, err := New(filepath.Join(b.TempDir(), "storage.db"), opts)
+	require.NoError(b, err)
+	b.Cleanup(func() { s.Close() })
+
+	require.NoError(b, s.MigrateUp(ctx))
+	require.NoError(b, s.Prepare(ctx))
+
+	_, err = s.SaveURL(ctx, "", "https://go.dev", "go", 0, 0, nil, storage.LinkLimits{})
+	require.NoError(b, err)
+
+	return s
+}
+
+// getURLByHostUnprepared runs the query of GetURLByHost preparing it on every call, as
+// the storage did before statements were shared.
+func getURLByHostUnprepared(s *Sqlite, host, alias string) (storage.URL, error) {
+	ctx := context.Background()
+
+	stmt, err := s.db.PrepareContext(ctx, getURLByHostQuery)
+	if err != nil {
+		return storage.URL{}, err
+	}
+	defer stmt.Close()
+
+	var (
+		link          = storage.URL{Alias: alias}
+		gone, expired bool
+	)
+	err = stmt.QueryRowContext(ctx, host, alias, alias).Scan(&link.ID, &link.Domain, &link.URL, &gone, &expired)
+
+	return link, err
+}
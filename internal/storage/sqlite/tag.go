@@ -0,0 +1,357 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/dkhrunov/url-shortener/internal/storage"
+)
+
+// tagsColumn selects the tags of the link in the url row as a comma separated list,
+// see scanLink. Tags can't contain commas.
+const tagsColumn = `(
+	SELECT GROUP_CONCAT(tag.name) FROM url_tag JOIN tag ON tag.id = url_tag.tag_id
+	WHERE url_tag.url_id = url.id
+)`
+
+// taggedURLs matches the links with the tag given as the first parameter, of the user
+// given as the second and third parameters or of everybody when that is zero.
+const taggedURLs = `id IN (
+	SELECT url_id FROM url_tag WHERE tag_id = (SELECT id FROM tag WHERE name = ?)
+) AND (? = 0 OR owner_id = ?)`
+
+type scanner interface {
+	Scan(dest ...any) error
+}
+
+// scanLink scans the id, domain, alias, url, owner, expiry and tags columns of a link.
+func scanLink(row scanner) (storage.URL, error) {
+	var (
+		u         storage.URL
+		expiresAt sql.NullTime
+		tags      sql.NullString
+	)
+	if err := row.Scan(&u.ID, &u.Domain, &u.Alias, &u.URL, &u.OwnerID, &expiresAt, &tags); err != nil {
+		return storage.URL{}, err
+	}
+
+	u.ExpiresAt = nullTime(expiresAt)
+	u.Tags = []string{}
+	if tags.Valid {
+		u.Tags = strings.Split(tags.String, ",")
+		slices.Sort(u.Tags)
+	}
+
+	return u, nil
+}
+
+var taggedLinksQuery = query(`--sql
+	SELECT id, domain, alias, url, IFNULL(owner_id, 0), expires_at, ` + tagsColumn + ` FROM url
+	WHERE (? OR deleted_at IS NULL) AND ` + taggedURLs + `
+	ORDER BY id
+`)
+
+// taggedLinks returns the links with the tag, see taggedURLs. Deleted links are left
+// out unless withDeleted is set.
+func (s *Sqlite) taggedLinks(ctx context.Context, tx *sql.Tx, tag string, ownerID int64, withDeleted bool) ([]storage.URL, error) {
+	stmt, err := s.stmt(taggedLinksQuery)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := tx.StmtContext(ctx, stmt).QueryContext(ctx, withDeleted, tag, ownerID, ownerID)
+	if err != nil {
+		return nil, fmt.Errorf("execute statement: %w", err)
+	}
+	defer rows.Close()
+
+	links := []storage.URL{}
+	for rows.Next() {
+		u, err := scanLink(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan row: %w", err)
+		}
+
+		links = append(links, u)
+	}
+
+	return links, rows.Err()
+}
+
+var (
+	clearTagsQuery = query(`--sql
+		DELETE FROM url_tag WHERE url_id = ?
+	`)
+	saveTagQuery = query(`--sql
+		INSERT INTO tag(name) VALUES (?) ON CONFLICT(name) DO NOTHING
+	`)
+	tagLinkQuery = query(`--sql
+		INSERT INTO url_tag(url_id, tag_id) SELECT ?, id FROM tag WHERE name = ?
+	`)
+)
+
+// setTags replaces the tags of the link urlID. The tags are expected to be normalized.
+func (s *Sqlite) setTags(ctx context.Context, tx *sql.Tx, urlID int64, tags []string) error {
+	clearTags, err := s.stmt(clearTagsQuery)
+	if err != nil {
+		return err
+	}
+
+	saveTag, err := s.stmt(saveTagQuery)
+	if err != nil {
+		return err
+	}
+
+	tagLink, err := s.stmt(tagLinkQuery)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.StmtContext(ctx, clearTags).ExecContext(ctx, urlID)
+	if err != nil {
+		return fmt.Errorf("failed to clear tags: %w", err)
+	}
+
+	saveTag, tagLink = tx.StmtContext(ctx, saveTag), tx.StmtContext(ctx, tagLink)
+
+	for _, tag := range tags {
+		_, err = saveTag.ExecContext(ctx, tag)
+		if err != nil {
+			return fmt.Errorf("failed to save tag: %w", err)
+		}
+
+		_, err = tagLink.ExecContext(ctx, urlID, tag)
+		if err != nil {
+			return fmt.Errorf("failed to tag link: %w", err)
+		}
+	}
+
+	return nil
+}
+
+var tagStatsQuery = query(`--sql
+	SELECT
+		tag.name,
+		SUM(url.deleted_at IS NULL),
+		SUM(url.deleted_at IS NULL AND IFNULL(url.expires_at <= CURRENT_TIMESTAMP, 0)),
+		SUM(url.deleted_at IS NOT NULL)
+	FROM tag
+	JOIN url_tag ON url_tag.tag_id = tag.id
+	JOIN url ON url.id = url_tag.url_id
+	WHERE ? = 0 OR url.owner_id = ?
+	GROUP BY tag.name
+	ORDER BY tag.name
+`)
+
+// TagStats sums up the links of each tag in use, by tag name. When ownerID is not zero,
+// only the links of that user are counted.
+func (s *Sqlite) TagStats(ctx context.Context, ownerID int64) ([]storage.TagStats, error) {
+	const op = "storage.sqlite.TagStats"
+
+	ctx, done := s.begin(ctx, op, s.opts.ReadTimeout)
+	defer done()
+
+	stmt, err := s.stmt(tagStatsQuery)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	rows, err := stmt.QueryContext(ctx, ownerID, ownerID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: execute statement: %w", op, err)
+	}
+	defer rows.Close()
+
+	stats := []storage.TagStats{}
+	for rows.Next() {
+		var t storage.TagStats
+		if err := rows.Scan(&t.Name, &t.Links, &t.Expired, &t.Deleted); err != nil {
+			return nil, fmt.Errorf("%s: scan row: %w", op, err)
+		}
+
+		stats = append(stats, t)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return stats, nil
+}
+
+var deleteURLsByTagQuery = query(`--sql
+	UPDATE url SET deleted_at = CURRENT_TIMESTAMP
+	WHERE deleted_at IS NULL AND ` + taggedURLs)
+
+// DeleteURLsByTag soft deletes the links with tag, see DeleteURL. When ownerID is not
+// zero, only the links of that user are deleted. It returns the links deleted, as they
+// were before.
+func (s *Sqlite) DeleteURLsByTag(ctx context.Context, tag string, ownerID int64) ([]storage.URL, error) {
+	const op = "storage.sqlite.DeleteURLsByTag"
+
+	ctx, done := s.begin(ctx, op, s.opts.WriteTimeout)
+	defer done()
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	if err := s.lock(ctx, tx); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	links, err := s.taggedLinks(ctx, tx, tag, ownerID, false)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	if len(links) == 0 {
+		return links, nil
+	}
+
+	stmt, err := s.stmt(deleteURLsByTagQuery)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	_, err = tx.StmtContext(ctx, stmt).ExecContext(ctx, tag, ownerID, ownerID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return links, nil
+}
+
+var expireURLsByTagQuery = query(`--sql
+	UPDATE url SET expires_at = datetime(?)
+	WHERE deleted_at IS NULL AND ` + taggedURLs)
+
+// ExpireURLsByTag makes the links with tag stop resolving at the time at. When ownerID
+// is not zero, only the links of that user expire. It returns the links changed, as
+// they were before.
+func (s *Sqlite) ExpireURLsByTag(ctx context.Context, tag string, ownerID int64, at time.Time) ([]storage.URL, error) {
+	const op = "storage.sqlite.ExpireURLsByTag"
+
+	ctx, done := s.begin(ctx, op, s.opts.WriteTimeout)
+	defer done()
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	if err := s.lock(ctx, tx); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	links, err := s.taggedLinks(ctx, tx, tag, ownerID, false)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	if len(links) == 0 {
+		return links, nil
+	}
+
+	stmt, err := s.stmt(expireURLsByTagQuery)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	_, err = tx.StmtContext(ctx, stmt).ExecContext(ctx, timestamp(at), tag, ownerID, ownerID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return links, nil
+}
+
+var (
+	retagURLsQuery = query(`--sql
+		INSERT INTO url_tag(url_id, tag_id)
+		SELECT id, (SELECT id FROM tag WHERE name = ?) FROM url WHERE ` + taggedURLs + `
+		ON CONFLICT(url_id, tag_id) DO NOTHING
+	`)
+	untagURLsQuery = query(`--sql
+		DELETE FROM url_tag
+		WHERE tag_id = (SELECT id FROM tag WHERE name = ?)
+			AND url_id IN (SELECT id FROM url WHERE ? = 0 OR owner_id = ?)
+	`)
+)
+
+// RetagURLs replaces tag with newTag on the links with tag, deleted ones included so
+// that they keep their tags when restored. When ownerID is not zero, only the links of
+// that user are retagged. It returns the links retagged, as they were before.
+func (s *Sqlite) RetagURLs(ctx context.Context, tag, newTag string, ownerID int64) ([]storage.URL, error) {
+	const op = "storage.sqlite.RetagURLs"
+
+	ctx, done := s.begin(ctx, op, s.opts.WriteTimeout)
+	defer done()
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	if err := s.lock(ctx, tx); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	links, err := s.taggedLinks(ctx, tx, tag, ownerID, true)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	if len(links) == 0 || tag == newTag {
+		return links, nil
+	}
+
+	stmt, err := s.stmt(saveTagQuery)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	_, err = tx.StmtContext(ctx, stmt).ExecContext(ctx, newTag)
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to save tag: %w", op, err)
+	}
+
+	stmt, err = s.stmt(retagURLsQuery)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	// Links that already have both tags keep one of them.
+	_, err = tx.StmtContext(ctx, stmt).ExecContext(ctx, newTag, tag, ownerID, ownerID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to tag links: %w", op, err)
+	}
+
+	stmt, err = s.stmt(untagURLsQuery)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	_, err = tx.StmtContext(ctx, stmt).ExecContext(ctx, tag, ownerID, ownerID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to untag links: %w", op, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return links, nil
+}
@@ -0,0 +1,189 @@
+package sqlite
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/dkhrunov/url-shortener/internal/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTags(t *testing.T) {
+	ctx := context.Background()
+
+	s, err := New(filepath.Join(t.TempDir(), "storage.db"), Options{})
+	require.NoError(t, err)
+	require.NoError(t, s.MigrateUp(ctx))
+	require.NoError(t, s.Prepare(ctx))
+
+	_, err = s.db.ExecContext(ctx, `INSERT INTO user(id, username, password_hash, role) VALUES (7, 'ann', '', 'editor')`)
+	require.NoError(t, err)
+
+	_, err = s.SaveURL(ctx, "", "https://go.dev", "go", 7, 0, []string{"docs", "sale"}, storage.LinkLimits{})
+	require.NoError(t, err)
+	_, err = s.SaveURL(ctx, "", "https://pkg.go.dev", "pkg", 7, 0, []string{"sale"}, storage.LinkLimits{})
+	require.NoError(t, err)
+	_, err = s.SaveURL(ctx, "", "https://example.com", "ex", 0, 0, []string{"sale"}, storage.LinkLimits{})
+	require.NoError(t, err)
+
+	u, err := s.GetLink(ctx, "", "go")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"docs", "sale"}, u.Tags)
+	assert.Nil(t, u.ExpiresAt)
+
+	urls, err := s.ListURLs(ctx, "", 7, "sale", 10, 0)
+	require.NoError(t, err)
+	require.Len(t, urls, 2)
+	assert.Equal(t, "go", urls[0].Alias)
+	assert.Equal(t, "pkg", urls[1].Alias)
+
+	urls, err = s.ListURLs(ctx, "", 0, "missing", 10, 0)
+	require.NoError(t, err)
+	assert.Empty(t, urls)
+
+	// Updating the target keeps the tags, and an empty list removes them.
+	require.NoError(t, s.UpdateURL(ctx, "", "pkg", storage.LinkUpdate{URL: "https://pkg.go.dev/std"}))
+	u, err = s.GetLink(ctx, "", "pkg")
+	require.NoError(t, err)
+	assert.Equal(t, "https://pkg.go.dev/std", u.URL)
+	assert.Equal(t, []string{"sale"}, u.Tags)
+
+	require.NoError(t, s.UpdateURL(ctx, "", "pkg", storage.LinkUpdate{Tags: &[]string{}}))
+	u, err = s.GetLink(ctx, "", "pkg")
+	require.NoError(t, err)
+	assert.Equal(t, "https://pkg.go.dev/std", u.URL)
+	assert.Empty(t, u.Tags)
+
+	assert.ErrorIs(t, s.UpdateURL(ctx, "", "missing", storage.LinkUpdate{URL: "https://go.dev"}), storage.ErrURLNotFound)
+
+	// Bulk operations of a user leave the links of others alone.
+	retagged, err := s.RetagURLs(ctx, "sale", "spring-sale", 7)
+	require.NoError(t, err)
+	require.Len(t, retagged, 1)
+	assert.Equal(t, "go", retagged[0].Alias)
+	assert.Equal(t, []string{"docs", "sale"}, retagged[0].Tags)
+
+	stats, err := s.TagStats(ctx, 0)
+	require.NoError(t, err)
+	assert.Equal(t, []storage.TagStats{
+		{Name: "docs", Links: 1},
+		{Name: "sale", Links: 1},
+		{Name: "spring-sale", Links: 1},
+	}, stats)
+
+	expired, err := s.ExpireURLsByTag(ctx, "spring-sale", 7, time.Now().Add(-time.Minute))
+	require.NoError(t, err)
+	require.Len(t, expired, 1)
+	assert.Equal(t, "go", expired[0].Alias)
+	assert.Nil(t, expired[0].ExpiresAt)
+
+	_, err = s.GetURLByHost(ctx, "sho.rt", "go")
+	assert.ErrorIs(t, err, storage.ErrURLGone)
+	_, err = s.GetURL(ctx, "", "go")
+	assert.ErrorIs(t, err, storage.ErrURLGone)
+
+	u, err = s.GetLink(ctx, "", "go")
+	require.NoError(t, err)
+	assert.NotNil(t, u.ExpiresAt)
+
+	// Links expiring later still resolve until then.
+	_, err = s.ExpireURLsByTag(ctx, "sale", 0, time.Now().Add(time.Hour))
+	require.NoError(t, err)
+
+	link, err := s.GetURLByHost(ctx, "sho.rt", "ex")
+	require.NoError(t, err)
+	assert.Equal(t, "https://example.com", link.URL)
+
+	target, err := s.GetURL(ctx, "", "ex")
+	require.NoError(t, err)
+	assert.Equal(t, "https://example.com", target)
+
+	deleted, err := s.DeleteURLsByTag(ctx, "sale", 7)
+	require.NoError(t, err)
+	assert.Empty(t, deleted)
+
+	deleted, err = s.DeleteURLsByTag(ctx, "sale", 0)
+	require.NoError(t, err)
+	require.Len(t, deleted, 1)
+	assert.Equal(t, "ex", deleted[0].Alias)
+	assert.Equal(t, "https://example.com", deleted[0].URL)
+	assert.Equal(t, []string{"sale"}, deleted[0].Tags)
+
+	stats, err = s.TagStats(ctx, 7)
+	require.NoError(t, err)
+	assert.Equal(t, []storage.TagStats{
+		{Name: "docs", Links: 1, Expired: 1},
+		{Name: "spring-sale", Links: 1, Expired: 1},
+	}, stats)
+
+	stats, err = s.TagStats(ctx, 0)
+	require.NoError(t, err)
+	assert.Contains(t, stats, storage.TagStats{Name: "sale", Deleted: 1})
+
+	// Purged links lose their tags.
+	_, err = s.db.ExecContext(ctx, `UPDATE url SET deleted_at = datetime('now', '-2 hours') WHERE deleted_at IS NOT NULL`)
+	require.NoError(t, err)
+	_, err = s.PurgeDeletedURLs(ctx, time.Hour)
+	require.NoError(t, err)
+
+	var tagged int
+	require.NoError(t, s.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM url_tag`).Scan(&tagged))
+	assert.Equal(t, 2, tagged)
+}
+
+// TestTagsConcurrent runs bulk tag operations alongside link updates in WAL mode,
+// where a transaction that reads before it writes fails with SQLITE_BUSY once another
+// write commits in between, unless it takes the write lock up front.
+func TestTagsConcurrent(t *testing.T) {
+	ctx := context.Background()
+
+	s, err := New(filepath.Join(t.TempDir(), "storage.db"), Options{JournalMode: "wal", BusyTimeout: 5 * time.Second})
+	require.NoError(t, err)
+	require.NoError(t, s.MigrateUp(ctx))
+	require.NoError(t, s.Prepare(ctx))
+
+	const workers, rounds = 16, 50
+
+	for i := 0; i < workers; i++ {
+		_, err = s.SaveURL(ctx, "", "https://go.dev", fmt.Sprintf("go%d", i), 0, 0, []string{"sale"}, storage.LinkLimits{})
+		require.NoError(t, err)
+		_, err = s.SaveURL(ctx, "", "https://go.dev", fmt.Sprintf("old%d", i), 0, 0, []string{fmt.Sprintf("old%d", i)}, storage.LinkLimits{})
+		require.NoError(t, err)
+	}
+
+	errs := make(chan error, workers*rounds*4)
+
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		i := i
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			tags := []string{"sale", "docs"}
+
+			for j := 0; j < rounds; j++ {
+				_, err := s.ExpireURLsByTag(ctx, "sale", 0, time.Now().Add(time.Hour))
+				errs <- err
+				_, err = s.RetagURLs(ctx, "docs", "guide", 0)
+				errs <- err
+				errs <- s.UpdateURL(ctx, "", fmt.Sprintf("go%d", i), storage.LinkUpdate{Tags: &tags})
+				_, err = s.DeleteURLsByTag(ctx, fmt.Sprintf("old%d", i), 0)
+				errs <- err
+			}
+		}()
+	}
+
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		require.NoError(t, err)
+	}
+}
@@ -0,0 +1,209 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/dkhrunov/url-shortener/internal/lib/zero"
+	"github.com/dkhrunov/url-shortener/internal/storage"
+	"github.com/mattn/go-sqlite3"
+)
+
+var saveUserQuery = query(`--sql
+	INSERT INTO user(username, password_hash, role) VALUES(?, ?, ?)
+`)
+
+func (s *Sqlite) SaveUser(ctx context.Context, username, passwordHash, role string) (int64, error) {
+	const op = "storage.sqlite.SaveUser"
+
+	ctx, done := s.begin(ctx, op, s.opts.WriteTimeout)
+	defer done()
+
+	stmt, err := s.stmt(saveUserQuery)
+	if err != nil {
+		return zero.Zero[int64](), fmt.Errorf("%s: %w", op, err)
+	}
+
+	res, err := stmt.ExecContext(ctx, username, passwordHash, role)
+	if err != nil {
+		if sqliteErr, ok := err.(sqlite3.Error); ok && sqliteErr.ExtendedCode == sqlite3.ErrConstraintUnique {
+			return zero.Zero[int64](), fmt.Errorf("%s: %w", op, storage.ErrUserExist)
+		}
+
+		return zero.Zero[int64](), fmt.Errorf("%s: %w", op, err)
+	}
+
+	id, err := res.LastInsertId()
+	if err != nil {
+		return 0, fmt.Errorf("%s: failed to get last insert id: %w", op, err)
+	}
+
+	return id, nil
+}
+
+var (
+	getUserQuery = query(`--sql
+		SELECT id, username, password_hash, role, created_at FROM user WHERE id = ?
+	`)
+	getUserByUsernameQuery = query(`--sql
+		SELECT id, username, password_hash, role, created_at FROM user WHERE username = ?
+	`)
+)
+
+func (s *Sqlite) GetUser(ctx context.Context, id int64) (storage.User, error) {
+	const op = "storage.sqlite.GetUser"
+
+	ctx, done := s.begin(ctx, op, s.opts.ReadTimeout)
+	defer done()
+
+	u, err := s.getUser(ctx, getUserQuery, id)
+	if err != nil {
+		return zero.Zero[storage.User](), fmt.Errorf("%s: %w", op, err)
+	}
+
+	return u, nil
+}
+
+func (s *Sqlite) GetUserByUsername(ctx context.Context, username string) (storage.User, error) {
+	const op = "storage.sqlite.GetUserByUsername"
+
+	ctx, done := s.begin(ctx, op, s.opts.ReadTimeout)
+	defer done()
+
+	u, err := s.getUser(ctx, getUserByUsernameQuery, username)
+	if err != nil {
+		return zero.Zero[storage.User](), fmt.Errorf("%s: %w", op, err)
+	}
+
+	return u, nil
+}
+
+func (s *Sqlite) getUser(ctx context.Context, query string, arg any) (storage.User, error) {
+	var u storage.User
+
+	stmt, err := s.stmt(query)
+	if err != nil {
+		return zero.Zero[storage.User](), err
+	}
+
+	err = stmt.QueryRowContext(ctx, arg).Scan(&u.ID, &u.Username, &u.PasswordHash, &u.Role, &u.CreatedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return zero.Zero[storage.User](), storage.ErrUserNotFound
+		}
+
+		return zero.Zero[storage.User](), fmt.Errorf("execute statement: %w", err)
+	}
+
+	return u, nil
+}
+
+var listUsersQuery = query(`--sql
+	SELECT id, username, role, created_at FROM user ORDER BY id
+`)
+
+func (s *Sqlite) ListUsers(ctx context.Context) ([]storage.User, error) {
+	const op = "storage.sqlite.ListUsers"
+
+	ctx, done := s.begin(ctx, op, s.opts.ReadTimeout)
+	defer done()
+
+	stmt, err := s.stmt(listUsersQuery)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	rows, err := stmt.QueryContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%s: execute statement: %w", op, err)
+	}
+	defer rows.Close()
+
+	users := []storage.User{}
+	for rows.Next() {
+		var u storage.User
+		if err := rows.Scan(&u.ID, &u.Username, &u.Role, &u.CreatedAt); err != nil {
+			return nil, fmt.Errorf("%s: scan row: %w", op, err)
+		}
+
+		users = append(users, u)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return users, nil
+}
+
+var (
+	revokeUserAPIKeysQuery = query(`--sql
+		UPDATE api_key SET revoked_at = CURRENT_TIMESTAMP WHERE user_id = ? AND revoked_at IS NULL
+	`)
+	disownURLsQuery = query(`--sql
+		UPDATE url SET owner_id = NULL WHERE owner_id = ?
+	`)
+	deleteUserQuery = query(`--sql
+		DELETE FROM user WHERE id = ?
+	`)
+)
+
+// DeleteUser removes a user and revokes their API keys. The links of the user are kept
+// without an owner, so only admins can manage them afterwards.
+func (s *Sqlite) DeleteUser(ctx context.Context, id int64) error {
+	const op = "storage.sqlite.DeleteUser"
+
+	ctx, done := s.begin(ctx, op, s.opts.WriteTimeout)
+	defer done()
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	stmt, err := s.stmt(revokeUserAPIKeysQuery)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	_, err = tx.StmtContext(ctx, stmt).ExecContext(ctx, id)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	stmt, err = s.stmt(disownURLsQuery)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	_, err = tx.StmtContext(ctx, stmt).ExecContext(ctx, id)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	stmt, err = s.stmt(deleteUserQuery)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	res, err := tx.StmtContext(ctx, stmt).ExecContext(ctx, id)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("%s: failed to get rows affected: %w", op, err)
+	}
+	if affected == 0 {
+		return fmt.Errorf("%s: %w", op, storage.ErrUserNotFound)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,386 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/dkhrunov/url-shortener/internal/lib/zero"
+	"github.com/dkhrunov/url-shortener/internal/storage"
+)
+
+var saveWebhookQuery = query(`--sql
+	INSERT INTO webhook(url, secret, events) VALUES (?, ?, ?)
+`)
+
+// SaveWebhook stores an endpoint notified of the given event types.
+func (s *Sqlite) SaveWebhook(ctx context.Context, url, secret string, events []string) (int64, error) {
+	const op = "storage.sqlite.SaveWebhook"
+
+	ctx, done := s.begin(ctx, op, s.opts.WriteTimeout)
+	defer done()
+
+	stmt, err := s.stmt(saveWebhookQuery)
+	if err != nil {
+		return zero.Zero[int64](), fmt.Errorf("%s: %w", op, err)
+	}
+
+	res, err := stmt.ExecContext(ctx, url, secret, strings.Join(events, ","))
+	if err != nil {
+		return zero.Zero[int64](), fmt.Errorf("%s: %w", op, err)
+	}
+
+	id, err := res.LastInsertId()
+	if err != nil {
+		return zero.Zero[int64](), fmt.Errorf("%s: failed to get last insert id: %w", op, err)
+	}
+
+	return id, nil
+}
+
+var getWebhookQuery = query(`--sql
+	SELECT id, url, secret, events, created_at FROM webhook WHERE id = ?
+`)
+
+// GetWebhook returns the webhook with the id, or storage.ErrWebhookNotFound.
+func (s *Sqlite) GetWebhook(ctx context.Context, id int64) (storage.Webhook, error) {
+	const op = "storage.sqlite.GetWebhook"
+
+	ctx, done := s.begin(ctx, op, s.opts.ReadTimeout)
+	defer done()
+
+	stmt, err := s.stmt(getWebhookQuery)
+	if err != nil {
+		return zero.Zero[storage.Webhook](), fmt.Errorf("%s: %w", op, err)
+	}
+
+	w, err := scanWebhook(stmt.QueryRowContext(ctx, id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return zero.Zero[storage.Webhook](), fmt.Errorf("%s: %w", op, storage.ErrWebhookNotFound)
+	}
+	if err != nil {
+		return zero.Zero[storage.Webhook](), fmt.Errorf("%s: %w", op, err)
+	}
+
+	return w, nil
+}
+
+var listWebhooksQuery = query(`--sql
+	SELECT id, url, secret, events, created_at FROM webhook ORDER BY id
+`)
+
+func (s *Sqlite) ListWebhooks(ctx context.Context) ([]storage.Webhook, error) {
+	const op = "storage.sqlite.ListWebhooks"
+
+	ctx, done := s.begin(ctx, op, s.opts.ReadTimeout)
+	defer done()
+
+	stmt, err := s.stmt(listWebhooksQuery)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	rows, err := stmt.QueryContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%s: execute statement: %w", op, err)
+	}
+	defer rows.Close()
+
+	webhooks := []storage.Webhook{}
+	for rows.Next() {
+		w, err := scanWebhook(rows)
+		if err != nil {
+			return nil, fmt.Errorf("%s: scan row: %w", op, err)
+		}
+
+		webhooks = append(webhooks, w)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return webhooks, nil
+}
+
+var deleteWebhookQuery = query(`--sql
+	DELETE FROM webhook WHERE id = ?
+`)
+
+// DeleteWebhook deletes the webhook with its pending events and delivery log.
+func (s *Sqlite) DeleteWebhook(ctx context.Context, id int64) error {
+	const op = "storage.sqlite.DeleteWebhook"
+
+	ctx, done := s.begin(ctx, op, s.opts.WriteTimeout)
+	defer done()
+
+	stmt, err := s.stmt(deleteWebhookQuery)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	res, err := stmt.ExecContext(ctx, id)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("%s: failed to get rows affected: %w", op, err)
+	}
+	if affected == 0 {
+		return fmt.Errorf("%s: %w", op, storage.ErrWebhookNotFound)
+	}
+
+	return nil
+}
+
+var subscribedWebhookEventsQuery = query(`--sql
+	SELECT DISTINCT events FROM webhook
+`)
+
+// SubscribedWebhookEvents returns the event types any webhook subscribed to.
+func (s *Sqlite) SubscribedWebhookEvents(ctx context.Context) ([]string, error) {
+	const op = "storage.sqlite.SubscribedWebhookEvents"
+
+	ctx, done := s.begin(ctx, op, s.opts.ReadTimeout)
+	defer done()
+
+	stmt, err := s.stmt(subscribedWebhookEventsQuery)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	rows, err := stmt.QueryContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%s: execute statement: %w", op, err)
+	}
+	defer rows.Close()
+
+	events := []string{}
+	for rows.Next() {
+		var list string
+		if err := rows.Scan(&list); err != nil {
+			return nil, fmt.Errorf("%s: scan row: %w", op, err)
+		}
+
+		for _, e := range strings.Split(list, ",") {
+			if !slices.Contains(events, e) {
+				events = append(events, e)
+			}
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return events, nil
+}
+
+var enqueueWebhookEventQuery = query(`--sql
+	INSERT INTO webhook_outbox(webhook_id, event, payload)
+	SELECT id, ?, ? FROM webhook WHERE instr(',' || events || ',', ',' || ? || ',') > 0
+`)
+
+// EnqueueWebhookEvent adds the event to the outbox of every webhook subscribed to it
+// and returns how many there are.
+func (s *Sqlite) EnqueueWebhookEvent(ctx context.Context, event string, payload []byte) (int64, error) {
+	const op = "storage.sqlite.EnqueueWebhookEvent"
+
+	ctx, done := s.begin(ctx, op, s.opts.WriteTimeout)
+	defer done()
+
+	stmt, err := s.stmt(enqueueWebhookEventQuery)
+	if err != nil {
+		return zero.Zero[int64](), fmt.Errorf("%s: %w", op, err)
+	}
+
+	res, err := stmt.ExecContext(ctx, event, string(payload), event)
+	if err != nil {
+		return zero.Zero[int64](), fmt.Errorf("%s: %w", op, err)
+	}
+
+	enqueued, err := res.RowsAffected()
+	if err != nil {
+		return zero.Zero[int64](), fmt.Errorf("%s: failed to get rows affected: %w", op, err)
+	}
+
+	return enqueued, nil
+}
+
+var dueWebhookMessagesQuery = query(`--sql
+	SELECT o.id, o.webhook_id, w.url, w.secret, o.event, o.payload, o.attempts, o.created_at
+	FROM webhook_outbox o JOIN webhook w ON w.id = o.webhook_id
+	WHERE o.next_attempt_at <= CURRENT_TIMESTAMP
+	ORDER BY o.next_attempt_at, o.id LIMIT ?
+`)
+
+// DueWebhookMessages returns up to limit outbox messages due for delivery, the ones
+// waiting longest first.
+func (s *Sqlite) DueWebhookMessages(ctx context.Context, limit int) ([]storage.WebhookMessage, error) {
+	const op = "storage.sqlite.DueWebhookMessages"
+
+	ctx, done := s.begin(ctx, op, s.opts.ReadTimeout)
+	defer done()
+
+	stmt, err := s.stmt(dueWebhookMessagesQuery)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	rows, err := stmt.QueryContext(ctx, limit)
+	if err != nil {
+		return nil, fmt.Errorf("%s: execute statement: %w", op, err)
+	}
+	defer rows.Close()
+
+	messages := []storage.WebhookMessage{}
+	for rows.Next() {
+		var (
+			m       storage.WebhookMessage
+			payload string
+		)
+		err := rows.Scan(&m.ID, &m.WebhookID, &m.URL, &m.Secret, &m.Event, &payload, &m.Attempts, &m.CreatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("%s: scan row: %w", op, err)
+		}
+
+		m.Payload = []byte(payload)
+
+		messages = append(messages, m)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return messages, nil
+}
+
+var (
+	recordWebhookDeliveryQuery = query(`--sql
+		INSERT INTO webhook_delivery(webhook_id, message_id, event, attempt, status_code, error, duration_ms)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+	`)
+	deleteWebhookMessageQuery = query(`--sql
+		DELETE FROM webhook_outbox WHERE id = ?
+	`)
+	retryWebhookMessageQuery = query(`--sql
+		UPDATE webhook_outbox SET attempts = ?, next_attempt_at = ? WHERE id = ?
+	`)
+)
+
+// RecordWebhookDelivery logs the delivery attempt d of an outbox message. The message
+// is attempted again at retryAt, or leaves the outbox when retryAt is zero, because it
+// was delivered or ran out of attempts.
+func (s *Sqlite) RecordWebhookDelivery(ctx context.Context, d storage.WebhookDelivery, retryAt time.Time) error {
+	const op = "storage.sqlite.RecordWebhookDelivery"
+
+	ctx, done := s.begin(ctx, op, s.opts.WriteTimeout)
+	defer done()
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	stmt, err := s.stmt(recordWebhookDeliveryQuery)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	_, err = tx.StmtContext(ctx, stmt).ExecContext(ctx, d.WebhookID, d.MessageID, d.Event, d.Attempt, d.StatusCode, d.Error, d.Duration.Milliseconds())
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if retryAt.IsZero() {
+		stmt, err = s.stmt(deleteWebhookMessageQuery)
+		if err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+
+		_, err = tx.StmtContext(ctx, stmt).ExecContext(ctx, d.MessageID)
+	} else {
+		stmt, err = s.stmt(retryWebhookMessageQuery)
+		if err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+
+		_, err = tx.StmtContext(ctx, stmt).ExecContext(ctx, d.Attempt, timestamp(retryAt), d.MessageID)
+	}
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+var listWebhookDeliveriesQuery = query(`--sql
+	SELECT id, webhook_id, message_id, event, attempt, status_code, error, duration_ms, created_at
+	FROM webhook_delivery WHERE webhook_id = ?
+	ORDER BY id DESC LIMIT ? OFFSET ?
+`)
+
+// ListWebhookDeliveries returns a page of the delivery log of a webhook, newest first.
+func (s *Sqlite) ListWebhookDeliveries(ctx context.Context, webhookID int64, limit, offset int) ([]storage.WebhookDelivery, error) {
+	const op = "storage.sqlite.ListWebhookDeliveries"
+
+	ctx, done := s.begin(ctx, op, s.opts.ReadTimeout)
+	defer done()
+
+	stmt, err := s.stmt(listWebhookDeliveriesQuery)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	rows, err := stmt.QueryContext(ctx, webhookID, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("%s: execute statement: %w", op, err)
+	}
+	defer rows.Close()
+
+	deliveries := []storage.WebhookDelivery{}
+	for rows.Next() {
+		var (
+			d          storage.WebhookDelivery
+			durationMS int64
+		)
+		err := rows.Scan(
+			&d.ID, &d.WebhookID, &d.MessageID, &d.Event, &d.Attempt, &d.StatusCode, &d.Error, &durationMS, &d.CreatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("%s: scan row: %w", op, err)
+		}
+
+		d.Duration = time.Duration(durationMS) * time.Millisecond
+
+		deliveries = append(deliveries, d)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return deliveries, nil
+}
+
+func scanWebhook(row scanner) (storage.Webhook, error) {
+	var (
+		w      storage.Webhook
+		events string
+	)
+	if err := row.Scan(&w.ID, &w.URL, &w.Secret, &events, &w.CreatedAt); err != nil {
+		return zero.Zero[storage.Webhook](), err
+	}
+
+	w.Events = strings.Split(events, ",")
+
+	return w, nil
+}
@@ -0,0 +1,111 @@
+package sqlite
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/dkhrunov/url-shortener/internal/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWebhooks(t *testing.T) {
+	ctx := context.Background()
+
+	s, err := New(filepath.Join(t.TempDir(), "storage.db"), Options{})
+	require.NoError(t, err)
+	require.NoError(t, s.MigrateUp(ctx))
+	require.NoError(t, s.Prepare(ctx))
+
+	created, err := s.SaveWebhook(ctx, "https://hooks.example/a", "secret-a", []string{"link.created", "link.deleted"})
+	require.NoError(t, err)
+	clicked, err := s.SaveWebhook(ctx, "https://hooks.example/b", "secret-b", []string{"link.clicked"})
+	require.NoError(t, err)
+
+	webhooks, err := s.ListWebhooks(ctx)
+	require.NoError(t, err)
+	require.Len(t, webhooks, 2)
+	assert.Equal(t, []string{"link.created", "link.deleted"}, webhooks[0].Events)
+
+	w, err := s.GetWebhook(ctx, clicked)
+	require.NoError(t, err)
+	assert.Equal(t, "secret-b", w.Secret)
+
+	events, err := s.SubscribedWebhookEvents(ctx)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"link.created", "link.deleted", "link.clicked"}, events)
+
+	// Only subscribed webhooks get the event.
+	enqueued, err := s.EnqueueWebhookEvent(ctx, "link.created", []byte(`{"type":"link.created"}`))
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), enqueued)
+
+	enqueued, err = s.EnqueueWebhookEvent(ctx, "link.updated", []byte(`{}`))
+	require.NoError(t, err)
+	assert.Zero(t, enqueued)
+
+	messages, err := s.DueWebhookMessages(ctx, 10)
+	require.NoError(t, err)
+	require.Len(t, messages, 1)
+	assert.Equal(t, created, messages[0].WebhookID)
+	assert.Equal(t, "https://hooks.example/a", messages[0].URL)
+	assert.Equal(t, `{"type":"link.created"}`, string(messages[0].Payload))
+	assert.Zero(t, messages[0].Attempts)
+
+	// A failed delivery is retried later.
+	require.NoError(t, s.RecordWebhookDelivery(ctx, storage.WebhookDelivery{
+		WebhookID:  created,
+		MessageID:  messages[0].ID,
+		Event:      "link.created",
+		Attempt:    1,
+		StatusCode: 503,
+		Error:      "unexpected status 503",
+		Duration:   120 * time.Millisecond,
+	}, time.Now().Add(time.Hour)))
+
+	messages, err = s.DueWebhookMessages(ctx, 10)
+	require.NoError(t, err)
+	assert.Empty(t, messages)
+
+	deliveries, err := s.ListWebhookDeliveries(ctx, created, 10, 0)
+	require.NoError(t, err)
+	require.Len(t, deliveries, 1)
+	assert.Equal(t, 503, deliveries[0].StatusCode)
+	assert.Equal(t, 120*time.Millisecond, deliveries[0].Duration)
+
+	// A successful delivery leaves the outbox.
+	require.NoError(t, s.RecordWebhookDelivery(ctx, storage.WebhookDelivery{
+		WebhookID:  created,
+		MessageID:  deliveries[0].MessageID,
+		Event:      "link.created",
+		Attempt:    2,
+		StatusCode: 200,
+	}, time.Time{}))
+
+	var pending int
+	require.NoError(t, s.db.QueryRow(`SELECT COUNT(*) FROM webhook_outbox`).Scan(&pending))
+	assert.Zero(t, pending)
+
+	deliveries, err = s.ListWebhookDeliveries(ctx, created, 10, 0)
+	require.NoError(t, err)
+	require.Len(t, deliveries, 2)
+	assert.Equal(t, 2, deliveries[0].Attempt)
+
+	// Deleting a webhook drops its outbox and delivery log.
+	_, err = s.EnqueueWebhookEvent(ctx, "link.deleted", []byte(`{}`))
+	require.NoError(t, err)
+	require.NoError(t, s.DeleteWebhook(ctx, created))
+	assert.ErrorIs(t, s.DeleteWebhook(ctx, created), storage.ErrWebhookNotFound)
+
+	_, err = s.GetWebhook(ctx, created)
+	assert.ErrorIs(t, err, storage.ErrWebhookNotFound)
+
+	require.NoError(t, s.db.QueryRow(`SELECT COUNT(*) FROM webhook_outbox`).Scan(&pending))
+	assert.Zero(t, pending)
+
+	deliveries, err = s.ListWebhookDeliveries(ctx, created, 10, 0)
+	require.NoError(t, err)
+	assert.Empty(t, deliveries)
+}
@@ -1,8 +1,221 @@
 package storage
 
-import "errors"
+import (
+	"errors"
+	"fmt"
+	"time"
+)
 
 var (
-	ErrURLNotFound = errors.New("url not found")
-	ErrURLExist    = errors.New("url already exists")
+	ErrURLNotFound     = errors.New("url not found")
+	ErrURLExist        = errors.New("url already exists")
+	ErrURLGone         = errors.New("url deleted")
+	ErrDomainNotFound  = errors.New("domain not found")
+	ErrDomainExist     = errors.New("domain already exists")
+	ErrDomainInUse     = errors.New("domain has links")
+	ErrAPIKeyNotFound  = errors.New("api key not found")
+	ErrUserNotFound    = errors.New("user not found")
+	ErrUserExist       = errors.New("user already exists")
+	ErrWebhookNotFound = errors.New("webhook not found")
+	ErrQuotaExceeded   = errors.New("link quota exceeded")
+	// ErrDailyQuotaExceeded and ErrActiveQuotaExceeded tell which limit of LinkLimits
+	// was exceeded. Both are ErrQuotaExceeded.
+	ErrDailyQuotaExceeded  = fmt.Errorf("daily %w", ErrQuotaExceeded)
+	ErrActiveQuotaExceeded = fmt.Errorf("active %w", ErrQuotaExceeded)
 )
+
+type URL struct {
+	ID     int64
+	Domain string
+	Alias  string
+	URL    string
+	// OwnerID is the id of the user who owns the link, zero for links without an owner.
+	OwnerID int64
+	// DeletedAt is when the link was soft deleted, nil for links in use.
+	DeletedAt *time.Time
+	// ExpiresAt is when the link stops resolving, nil for links that don't expire.
+	ExpiresAt *time.Time
+	// Tags are the sorted tags of the link.
+	Tags []string
+}
+
+// LinkUpdate is a change of a link.
+type LinkUpdate struct {
+	// URL is the new target of the link, empty to keep it.
+	URL string
+	// Tags replace the tags of the link when not nil.
+	Tags *[]string
+}
+
+// TagStats sums up the links with a tag.
+type TagStats struct {
+	Name string
+	// Links is the number of links with the tag that are not deleted, expired ones included.
+	Links int
+	// Expired is the number of those links that expired.
+	Expired int
+	// Deleted is the number of soft deleted links with the tag that were not purged yet.
+	Deleted int
+}
+
+type Domain struct {
+	ID        int64
+	Host      string
+	CreatedAt time.Time
+}
+
+type APIKey struct {
+	ID     int64
+	Name   string
+	Prefix string
+	// UserID is the id of the user the key acts for, zero for keys without a user.
+	UserID     int64
+	CreatedAt  time.Time
+	LastUsedAt *time.Time
+	RevokedAt  *time.Time
+}
+
+type User struct {
+	ID           int64
+	Username     string
+	PasswordHash string
+	Role         string
+	CreatedAt    time.Time
+}
+
+// LinkLimits caps the links a user or an API key creates. Zero means unlimited.
+type LinkLimits struct {
+	LinksPerDay int
+	ActiveLinks int
+}
+
+// LinkUsage is what a user or an API key has used of its link quota.
+type LinkUsage struct {
+	// LinksToday is the number of links created since midnight UTC, deleted ones included.
+	LinksToday int
+	// ActiveLinks is the number of links that currently exist.
+	ActiveLinks int
+}
+
+// AuditEntry records a change of links.
+type AuditEntry struct {
+	ID        int64
+	CreatedAt time.Time
+	// Actor is who made the change as method:name, e.g. basic:root or api_key:ci.
+	Actor string
+	// UserID and APIKeyID identify the actor, zero when it isn't a user or a key.
+	UserID   int64
+	APIKeyID int64
+	Action   string
+	Domain   string
+	// Alias is empty for changes of all links with a tag.
+	Alias string
+	// Before and After are the JSON state of what changed, empty when there is none.
+	Before     string
+	After      string
+	RequestID  string
+	RemoteAddr string
+}
+
+// AuditFilter narrows down audit entries. Zero fields match any entry.
+type AuditFilter struct {
+	Actor  string
+	Action string
+	Domain string
+	Alias  string
+	// Since and Until bound when the entries were created, Until excluded.
+	Since time.Time
+	Until time.Time
+}
+
+// Webhook is an endpoint notified of link events.
+type Webhook struct {
+	ID  int64
+	URL string
+	// Secret signs the payloads sent to the webhook.
+	Secret string
+	// Events are the event types the webhook subscribed to.
+	Events    []string
+	CreatedAt time.Time
+}
+
+// WebhookMessage is an event waiting in the outbox to be delivered to a webhook.
+type WebhookMessage struct {
+	ID        int64
+	WebhookID int64
+	URL       string
+	Secret    string
+	Event     string
+	Payload   []byte
+	// Attempts is the number of failed deliveries so far.
+	Attempts  int
+	CreatedAt time.Time
+}
+
+// WebhookDelivery is an attempt to deliver a message to a webhook.
+type WebhookDelivery struct {
+	ID        int64
+	WebhookID int64
+	MessageID int64
+	Event     string
+	Attempt   int
+	// StatusCode is the status the webhook answered with, zero when it didn't answer.
+	StatusCode int
+	// Error is why the delivery failed, empty when it succeeded.
+	Error     string
+	Duration  time.Duration
+	CreatedAt time.Time
+}
+
+// Click is a redirect of a link. Unknown client details are empty.
+type Click struct {
+	LinkID int64
+	At     time.Time
+	// Referrer is the host of the page the link was followed from.
+	Referrer string
+	Browser  string
+	OS       string
+	Device   string
+	// Country is the ISO 3166-1 alpha-2 code of the client's country.
+	Country string
+	// Bot is set for clicks of bots, crawlers and link previews rather than people.
+	Bot bool
+}
+
+// AnalyticsQuery selects the clicks of a link between From and To, rounded to hours,
+// summed up per Interval of hour, day or week. Limit caps each breakdown. Clicks of
+// bots are left out unless IncludeBots is set.
+type AnalyticsQuery struct {
+	LinkID      int64
+	From        time.Time
+	To          time.Time
+	Interval    string
+	Limit       int
+	IncludeBots bool
+}
+
+// Analytics is the report of a link's clicks, see AnalyticsQuery.
+type Analytics struct {
+	Total int64
+	// Bots is the number of clicks of bots, whether they are included or not.
+	Bots      int64
+	Referrers []ClickCount
+	Browsers  []ClickCount
+	OS        []ClickCount
+	Devices   []ClickCount
+	Countries []ClickCount
+	// Series are the buckets with clicks, oldest first.
+	Series []ClickBucket
+}
+
+// ClickCount is the number of clicks with a value of a dimension, like a browser.
+type ClickCount struct {
+	Value  string
+	Clicks int64
+}
+
+// ClickBucket is the number of clicks in the time bucket starting at Start.
+type ClickBucket struct {
+	Start  time.Time
+	Clicks int64
+}
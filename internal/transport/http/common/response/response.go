@@ -1,7 +1,10 @@
 package response
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"net/http"
 	"strings"
 
 	"github.com/go-playground/validator/v10"
@@ -35,9 +38,9 @@ func ValidationError(errs validator.ValidationErrors) Response {
 
 	for _, err := range errs {
 		switch err.ActualTag() {
-		case "required":
+		case "required", "required_without":
 			errMsgs = append(errMsgs, fmt.Sprintf("field %s is a required field", err.Field()))
-		case "url":
+		case "url", "http_url":
 			errMsgs = append(errMsgs, fmt.Sprintf("field %s is not a valid URL", err.Field()))
 		default:
 			errMsgs = append(errMsgs, fmt.Sprintf("field %s is not valid", err.Field()))
@@ -49,3 +52,17 @@ func ValidationError(errs validator.ValidationErrors) Response {
 		Error:  strings.Join(errMsgs, ", "),
 	}
 }
+
+// Unavailable returns the status and response for an error caused by the storage not
+// answering in time rather than by the request: 504 when the operation or the request
+// ran out of time, 503 when the request was canceled. ok is false for other errors.
+func Unavailable(err error) (status int, resp Response, ok bool) {
+	switch {
+	case errors.Is(err, context.DeadlineExceeded):
+		return http.StatusGatewayTimeout, Error("storage timed out"), true
+	case errors.Is(err, context.Canceled):
+		return http.StatusServiceUnavailable, Error("request canceled"), true
+	}
+
+	return 0, Response{}, false
+}
@@ -0,0 +1,99 @@
+package sse
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/dkhrunov/url-shortener/internal/lib/clickhub"
+)
+
+// Event types written to the stream.
+const (
+	EventClick = "click"
+	// EventDropped reports how many clicks were dropped because the client read the
+	// stream slower than they came in.
+	EventDropped = "dropped"
+)
+
+// Dropped is the data of EventDropped.
+type Dropped struct {
+	Count int64 `json:"count"`
+}
+
+// Stream writes the clicks of the subscription to w as server-sent events until the
+// subscription is closed or the request ends. A comment is written every heartbeat
+// so that proxies keep idle streams open. Streams are exempt from the write timeout
+// of the server.
+func Stream(w http.ResponseWriter, r *http.Request, sub *clickhub.Subscription, heartbeat time.Duration) error {
+	rc := http.NewResponseController(w)
+
+	if err := rc.SetWriteDeadline(time.Time{}); err != nil && !errors.Is(err, http.ErrNotSupported) {
+		return err
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	// Keeps nginx from buffering the stream.
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+
+	if err := rc.Flush(); err != nil {
+		return err
+	}
+
+	ticker := time.NewTicker(heartbeat)
+	defer ticker.Stop()
+
+	var id int64
+	for {
+		select {
+		case <-r.Context().Done():
+			return nil
+		case <-ticker.C:
+			if _, err := fmt.Fprint(w, ": ping\n\n"); err != nil {
+				return err
+			}
+		case click, ok := <-sub.C:
+			if !ok {
+				return nil
+			}
+
+			if dropped := sub.Dropped(); dropped > 0 {
+				if err := write(w, 0, EventDropped, Dropped{Count: dropped}); err != nil {
+					return err
+				}
+			}
+
+			id++
+			if err := write(w, id, EventClick, click); err != nil {
+				return err
+			}
+		}
+
+		if err := rc.Flush(); err != nil {
+			return err
+		}
+	}
+}
+
+// write writes an event with the JSON data, and with the id unless it is zero.
+func write(w http.ResponseWriter, id int64, event string, data any) error {
+	b, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+
+	if id != 0 {
+		if _, err := fmt.Fprintf(w, "id: %d\n", id); err != nil {
+			return err
+		}
+	}
+
+	_, err = fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event, b)
+
+	return err
+}
@@ -0,0 +1,71 @@
+package sse_test
+
+import (
+	"bufio"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/dkhrunov/url-shortener/internal/lib/clickhub"
+	"github.com/dkhrunov/url-shortener/internal/transport/http/common/sse"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStream(t *testing.T) {
+	hub := clickhub.New(1)
+
+	// The subscriber falls behind before the stream starts.
+	sub := hub.Subscribe("", "go")
+	defer sub.Close()
+
+	hub.Publish(clickhub.Click{Alias: "go", URL: "https://go.dev"})
+	hub.Publish(clickhub.Click{Alias: "go", URL: "https://go.dev/doc"})
+	hub.Publish(clickhub.Click{Alias: "go", URL: "https://go.dev/blog"})
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.NoError(t, sse.Stream(w, r, sub, 20*time.Millisecond))
+	}))
+	defer server.Close()
+
+	resp, err := http.Get(server.URL)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "text/event-stream", resp.Header.Get("Content-Type"))
+	assert.Equal(t, "no-cache", resp.Header.Get("Cache-Control"))
+
+	lines := bufio.NewScanner(resp.Body)
+	next := func() string {
+		require.True(t, lines.Scan())
+		return lines.Text()
+	}
+
+	assert.Equal(t, "event: dropped", next())
+	assert.Equal(t, `data: {"count":2}`, next())
+	assert.Equal(t, "", next())
+	assert.Equal(t, "id: 1", next())
+	assert.Equal(t, "event: click", next())
+	assert.Contains(t, next(), `"url":"https://go.dev"`)
+	assert.Equal(t, "", next())
+
+	// Idle streams get heartbeats.
+	assert.Equal(t, ": ping", next())
+	assert.Equal(t, "", next())
+
+	hub.Publish(clickhub.Click{Alias: "go", URL: "https://go.dev/doc"})
+
+	line := next()
+	for line == ": ping" || line == "" {
+		line = next()
+	}
+	assert.Equal(t, "id: 2", line)
+
+	// Streams end with the subscription.
+	hub.Close()
+	for lines.Scan() {
+	}
+	assert.NoError(t, lines.Err())
+}
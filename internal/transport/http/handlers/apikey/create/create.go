@@ -0,0 +1,118 @@
+package create
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/dkhrunov/url-shortener/internal/lib/apikey"
+	"github.com/dkhrunov/url-shortener/internal/lib/logger/slog/slogerr"
+	"github.com/dkhrunov/url-shortener/internal/storage"
+	"github.com/dkhrunov/url-shortener/internal/transport/http/common/response"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+	"github.com/go-playground/validator/v10"
+)
+
+type Request struct {
+	Name string `json:"name" validate:"required"`
+	// UserID is the user the key acts for. Keys without a user act with the admin role.
+	UserID int64 `json:"user_id,omitempty"`
+}
+
+type Response struct {
+	response.Response
+	ID     int64  `json:"id,omitempty"`
+	Name   string `json:"name,omitempty"`
+	Prefix string `json:"prefix,omitempty"`
+	UserID int64  `json:"user_id,omitempty"`
+	// Key is returned only once, on creation.
+	Key string `json:"key,omitempty"`
+}
+
+type APIKeySaver interface {
+	SaveAPIKey(ctx context.Context, name, prefix, keyHash string, userID int64) (int64, error)
+}
+
+func New(apiKeySaver APIKeySaver) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.apikey.create.New"
+
+		log := slog.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		var req Request
+
+		err := render.DecodeJSON(r.Body, &req)
+		if err != nil {
+			log.ErrorContext(r.Context(), "failed to decode request body", slogerr.Error(err))
+
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, response.Error("failed to decode request"))
+
+			return
+		}
+
+		if err := validator.New().Struct(req); err != nil {
+			validateErr := err.(validator.ValidationErrors)
+
+			log.ErrorContext(r.Context(), "invalid request", slogerr.Error(err))
+
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, response.ValidationError(validateErr))
+
+			return
+		}
+
+		key, prefix, err := apikey.Generate()
+		if err != nil {
+			log.ErrorContext(r.Context(), "failed to generate api key", slogerr.Error(err))
+
+			render.Status(r, http.StatusInternalServerError)
+			render.JSON(w, r, response.Error("failed to create api key"))
+
+			return
+		}
+
+		id, err := apiKeySaver.SaveAPIKey(r.Context(), req.Name, prefix, apikey.Hash(key), req.UserID)
+		if errors.Is(err, storage.ErrUserNotFound) {
+			log.InfoContext(r.Context(), "user not found", slog.Int64("user_id", req.UserID))
+
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, response.Error("user not found"))
+
+			return
+		}
+		if status, resp, ok := response.Unavailable(err); ok {
+			log.WarnContext(r.Context(), "storage unavailable", slogerr.Error(err))
+
+			render.Status(r, status)
+			render.JSON(w, r, resp)
+
+			return
+		}
+		if err != nil {
+			log.ErrorContext(r.Context(), "failed to save api key", slogerr.Error(err))
+
+			render.Status(r, http.StatusInternalServerError)
+			render.JSON(w, r, response.Error("failed to create api key"))
+
+			return
+		}
+
+		log.InfoContext(r.Context(), "api key created", slog.Int64("id", id), slog.String("prefix", prefix))
+
+		render.Status(r, http.StatusCreated)
+		render.JSON(w, r, Response{
+			Response: response.OK(),
+			ID:       id,
+			Name:     req.Name,
+			Prefix:   prefix,
+			UserID:   req.UserID,
+			Key:      key,
+		})
+	}
+}
@@ -0,0 +1,117 @@
+package create_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dkhrunov/url-shortener/internal/lib/apikey"
+	"github.com/dkhrunov/url-shortener/internal/storage"
+	"github.com/dkhrunov/url-shortener/internal/transport/http/handlers/apikey/create"
+	"github.com/dkhrunov/url-shortener/internal/transport/http/handlers/apikey/create/mocks"
+)
+
+func TestCreateHandler(t *testing.T) {
+	cases := []struct {
+		name      string
+		keyName   string
+		userID    int64
+		respError string
+		mockError error
+		status    int
+	}{
+		{
+			name:    "Success",
+			keyName: "ci",
+			status:  http.StatusCreated,
+		},
+		{
+			name:    "For user",
+			keyName: "ci",
+			userID:  3,
+			status:  http.StatusCreated,
+		},
+		{
+			name:      "User not found",
+			keyName:   "ci",
+			userID:    3,
+			respError: "user not found",
+			mockError: storage.ErrUserNotFound,
+			status:    http.StatusBadRequest,
+		},
+		{
+			name:      "Empty name",
+			keyName:   "",
+			respError: "field Name is a required field",
+			status:    http.StatusBadRequest,
+		},
+		{
+			name:      "SaveAPIKey Error",
+			keyName:   "ci",
+			respError: "failed to create api key",
+			mockError: errors.New("unexpected error"),
+			status:    http.StatusInternalServerError,
+		},
+		{
+			name:      "SaveAPIKey timed out",
+			keyName:   "ci",
+			respError: "storage timed out",
+			mockError: fmt.Errorf("storage.sqlite.SaveAPIKey: %w", context.DeadlineExceeded),
+			status:    http.StatusGatewayTimeout,
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			apiKeySaverMock := mocks.NewAPIKeySaver(t)
+
+			var savedHash string
+			if tc.respError == "" || tc.mockError != nil {
+				apiKeySaverMock.EXPECT().
+					SaveAPIKey(mock.Anything, tc.keyName, mock.AnythingOfType("string"), mock.AnythingOfType("string"), tc.userID).
+					Run(func(_ context.Context, _, _, keyHash string, _ int64) { savedHash = keyHash }).
+					Return(int64(1), tc.mockError).
+					Once()
+			}
+
+			handler := create.New(apiKeySaverMock)
+
+			input := fmt.Sprintf(`{"name": "%s", "user_id": %d}`, tc.keyName, tc.userID)
+
+			req, err := http.NewRequest(http.MethodPost, "/admin/api-keys", bytes.NewReader([]byte(input)))
+			require.NoError(t, err)
+
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, req)
+
+			assert.Equal(t, tc.status, rr.Code)
+
+			var resp create.Response
+
+			require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+
+			assert.Equal(t, tc.respError, resp.Error)
+
+			if tc.respError == "" {
+				assert.True(t, strings.HasPrefix(resp.Key, resp.Prefix))
+				assert.Equal(t, apikey.Hash(resp.Key), savedHash)
+			} else {
+				assert.Empty(t, resp.Key)
+			}
+		})
+	}
+}
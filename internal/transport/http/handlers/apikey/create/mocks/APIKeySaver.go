@@ -0,0 +1,96 @@
+// Code generated by mockery v2.39.1. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// APIKeySaver is an autogenerated mock type for the APIKeySaver type
+type APIKeySaver struct {
+	mock.Mock
+}
+
+type APIKeySaver_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *APIKeySaver) EXPECT() *APIKeySaver_Expecter {
+	return &APIKeySaver_Expecter{mock: &_m.Mock}
+}
+
+// SaveAPIKey provides a mock function with given fields: ctx, name, prefix, keyHash, userID
+func (_m *APIKeySaver) SaveAPIKey(ctx context.Context, name string, prefix string, keyHash string, userID int64) (int64, error) {
+	ret := _m.Called(ctx, name, prefix, keyHash, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SaveAPIKey")
+	}
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string, int64) (int64, error)); ok {
+		return rf(ctx, name, prefix, keyHash, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string, int64) int64); ok {
+		r0 = rf(ctx, name, prefix, keyHash, userID)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, string, int64) error); ok {
+		r1 = rf(ctx, name, prefix, keyHash, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// APIKeySaver_SaveAPIKey_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SaveAPIKey'
+type APIKeySaver_SaveAPIKey_Call struct {
+	*mock.Call
+}
+
+// SaveAPIKey is a helper method to define mock.On call
+//   - ctx context.Context
+//   - name string
+//   - prefix string
+//   - keyHash string
+//   - userID int64
+func (_e *APIKeySaver_Expecter) SaveAPIKey(ctx interface{}, name interface{}, prefix interface{}, keyHash interface{}, userID interface{}) *APIKeySaver_SaveAPIKey_Call {
+	return &APIKeySaver_SaveAPIKey_Call{Call: _e.mock.On("SaveAPIKey", ctx, name, prefix, keyHash, userID)}
+}
+
+func (_c *APIKeySaver_SaveAPIKey_Call) Run(run func(ctx context.Context, name string, prefix string, keyHash string, userID int64)) *APIKeySaver_SaveAPIKey_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(string), args[3].(string), args[4].(int64))
+	})
+	return _c
+}
+
+func (_c *APIKeySaver_SaveAPIKey_Call) Return(_a0 int64, _a1 error) *APIKeySaver_SaveAPIKey_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *APIKeySaver_SaveAPIKey_Call) RunAndReturn(run func(context.Context, string, string, string, int64) (int64, error)) *APIKeySaver_SaveAPIKey_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewAPIKeySaver creates a new instance of APIKeySaver. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewAPIKeySaver(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *APIKeySaver {
+	mock := &APIKeySaver{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
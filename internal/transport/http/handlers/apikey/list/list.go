@@ -0,0 +1,80 @@
+package list
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/dkhrunov/url-shortener/internal/lib/logger/slog/slogerr"
+	"github.com/dkhrunov/url-shortener/internal/storage"
+	"github.com/dkhrunov/url-shortener/internal/transport/http/common/response"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+)
+
+type Item struct {
+	ID         int64      `json:"id"`
+	Name       string     `json:"name"`
+	Prefix     string     `json:"prefix"`
+	UserID     int64      `json:"user_id,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+	LastUsedAt *time.Time `json:"last_used_at"`
+	RevokedAt  *time.Time `json:"revoked_at"`
+}
+
+type Response struct {
+	response.Response
+	Keys []Item `json:"keys"`
+}
+
+type APIKeyLister interface {
+	ListAPIKeys(ctx context.Context) ([]storage.APIKey, error)
+}
+
+func New(apiKeyLister APIKeyLister) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.apikey.list.New"
+
+		log := slog.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		keys, err := apiKeyLister.ListAPIKeys(r.Context())
+		if status, resp, ok := response.Unavailable(err); ok {
+			log.WarnContext(r.Context(), "storage unavailable", slogerr.Error(err))
+
+			render.Status(r, status)
+			render.JSON(w, r, resp)
+
+			return
+		}
+		if err != nil {
+			log.ErrorContext(r.Context(), "failed to list api keys", slogerr.Error(err))
+
+			render.Status(r, http.StatusInternalServerError)
+			render.JSON(w, r, response.Error("failed to list api keys"))
+
+			return
+		}
+
+		items := make([]Item, 0, len(keys))
+		for _, k := range keys {
+			items = append(items, Item{
+				ID:         k.ID,
+				Name:       k.Name,
+				Prefix:     k.Prefix,
+				UserID:     k.UserID,
+				CreatedAt:  k.CreatedAt,
+				LastUsedAt: k.LastUsedAt,
+				RevokedAt:  k.RevokedAt,
+			})
+		}
+
+		render.JSON(w, r, Response{
+			Response: response.OK(),
+			Keys:     items,
+		})
+	}
+}
@@ -0,0 +1,88 @@
+package list_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/dkhrunov/url-shortener/internal/storage"
+	"github.com/dkhrunov/url-shortener/internal/transport/http/handlers/apikey/list"
+	"github.com/dkhrunov/url-shortener/internal/transport/http/handlers/apikey/list/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestListHandler(t *testing.T) {
+	now := time.Now().UTC().Truncate(time.Second)
+
+	cases := []struct {
+		name      string
+		keys      []storage.APIKey
+		respError string
+		mockError error
+		status    int
+	}{
+		{
+			name: "Success",
+			keys: []storage.APIKey{
+				{ID: 1, Name: "ci", Prefix: "usk_abcdefgh", CreatedAt: now, LastUsedAt: &now},
+				{ID: 2, Name: "old", Prefix: "usk_ijklmnop", CreatedAt: now, RevokedAt: &now},
+			},
+			status: http.StatusOK,
+		},
+		{
+			name:      "Failed",
+			respError: "failed to list api keys",
+			mockError: errors.New("unexpected error"),
+			status:    http.StatusInternalServerError,
+		},
+		{
+			name:      "ListAPIKeys timed out",
+			respError: "storage timed out",
+			mockError: fmt.Errorf("storage.sqlite.ListAPIKeys: %w", context.DeadlineExceeded),
+			status:    http.StatusGatewayTimeout,
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			apiKeyListerMock := mocks.NewAPIKeyLister(t)
+
+			apiKeyListerMock.EXPECT().
+				ListAPIKeys(mock.Anything).
+				Return(tc.keys, tc.mockError).
+				Once()
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodGet, "/admin/api-keys", nil)
+
+			handler := list.New(apiKeyListerMock)
+			handler.ServeHTTP(w, r)
+
+			assert.Equal(t, tc.status, w.Code)
+
+			var resp list.Response
+
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+
+			assert.Equal(t, tc.respError, resp.Error)
+
+			require.Len(t, resp.Keys, len(tc.keys))
+			for i, k := range resp.Keys {
+				assert.Equal(t, tc.keys[i].Prefix, k.Prefix)
+				assert.Equal(t, tc.keys[i].LastUsedAt, k.LastUsedAt)
+				assert.Equal(t, tc.keys[i].RevokedAt, k.RevokedAt)
+			}
+		})
+	}
+}
@@ -0,0 +1,96 @@
+// Code generated by mockery v2.39.1. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+
+	storage "github.com/dkhrunov/url-shortener/internal/storage"
+)
+
+// APIKeyLister is an autogenerated mock type for the APIKeyLister type
+type APIKeyLister struct {
+	mock.Mock
+}
+
+type APIKeyLister_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *APIKeyLister) EXPECT() *APIKeyLister_Expecter {
+	return &APIKeyLister_Expecter{mock: &_m.Mock}
+}
+
+// ListAPIKeys provides a mock function with given fields: ctx
+func (_m *APIKeyLister) ListAPIKeys(ctx context.Context) ([]storage.APIKey, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListAPIKeys")
+	}
+
+	var r0 []storage.APIKey
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) ([]storage.APIKey, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) []storage.APIKey); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]storage.APIKey)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// APIKeyLister_ListAPIKeys_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListAPIKeys'
+type APIKeyLister_ListAPIKeys_Call struct {
+	*mock.Call
+}
+
+// ListAPIKeys is a helper method to define mock.On call
+//   - ctx context.Context
+func (_e *APIKeyLister_Expecter) ListAPIKeys(ctx interface{}) *APIKeyLister_ListAPIKeys_Call {
+	return &APIKeyLister_ListAPIKeys_Call{Call: _e.mock.On("ListAPIKeys", ctx)}
+}
+
+func (_c *APIKeyLister_ListAPIKeys_Call) Run(run func(ctx context.Context)) *APIKeyLister_ListAPIKeys_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context))
+	})
+	return _c
+}
+
+func (_c *APIKeyLister_ListAPIKeys_Call) Return(_a0 []storage.APIKey, _a1 error) *APIKeyLister_ListAPIKeys_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *APIKeyLister_ListAPIKeys_Call) RunAndReturn(run func(context.Context) ([]storage.APIKey, error)) *APIKeyLister_ListAPIKeys_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewAPIKeyLister creates a new instance of APIKeyLister. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewAPIKeyLister(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *APIKeyLister {
+	mock := &APIKeyLister{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,186 @@
+package list
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/dkhrunov/url-shortener/internal/lib/audit"
+	"github.com/dkhrunov/url-shortener/internal/lib/hostname"
+	"github.com/dkhrunov/url-shortener/internal/lib/logger/slog/slogerr"
+	"github.com/dkhrunov/url-shortener/internal/storage"
+	"github.com/dkhrunov/url-shortener/internal/transport/http/common/response"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+)
+
+const (
+	defaultLimit = 50
+	maxLimit     = 1000
+)
+
+type Item struct {
+	ID         int64           `json:"id"`
+	CreatedAt  time.Time       `json:"created_at"`
+	Actor      string          `json:"actor"`
+	UserID     int64           `json:"user_id,omitempty"`
+	APIKeyID   int64           `json:"api_key_id,omitempty"`
+	Action     string          `json:"action"`
+	Domain     string          `json:"domain,omitempty"`
+	Alias      string          `json:"alias,omitempty"`
+	Before     json.RawMessage `json:"before,omitempty"`
+	After      json.RawMessage `json:"after,omitempty"`
+	RequestID  string          `json:"request_id,omitempty"`
+	RemoteAddr string          `json:"remote_addr,omitempty"`
+}
+
+type Response struct {
+	response.Response
+	Entries []Item `json:"entries"`
+	Limit   int    `json:"limit"`
+	Offset  int    `json:"offset"`
+}
+
+type AuditLister interface {
+	ListAuditEntries(ctx context.Context, f storage.AuditFilter, limit, offset int) ([]storage.AuditEntry, error)
+}
+
+// New returns the handler listing the audit log, newest entries first. Entries can be
+// filtered by actor, action, domain, alias and an RFC 3339 time range [since, until).
+func New(auditLister AuditLister) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.audit.list.New"
+
+		log := slog.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		q := r.URL.Query()
+
+		limit, err := intParam(q.Get("limit"), defaultLimit)
+		if err != nil || limit < 1 || limit > maxLimit {
+			log.InfoContext(r.Context(), "invalid limit", slog.String("limit", q.Get("limit")))
+
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, response.Error("invalid limit"))
+
+			return
+		}
+
+		offset, err := intParam(q.Get("offset"), 0)
+		if err != nil || offset < 0 {
+			log.InfoContext(r.Context(), "invalid offset", slog.String("offset", q.Get("offset")))
+
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, response.Error("invalid offset"))
+
+			return
+		}
+
+		filter := storage.AuditFilter{
+			Actor:  q.Get("actor"),
+			Action: q.Get("action"),
+			Domain: hostname.Normalize(q.Get("domain")),
+			Alias:  q.Get("alias"),
+		}
+
+		if filter.Action != "" && !audit.Action(filter.Action).Valid() {
+			log.InfoContext(r.Context(), "invalid action", slog.String("action", filter.Action))
+
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, response.Error("invalid action"))
+
+			return
+		}
+
+		if filter.Since, err = timeParam(q.Get("since")); err != nil {
+			log.InfoContext(r.Context(), "invalid since", slog.String("since", q.Get("since")))
+
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, response.Error("invalid since"))
+
+			return
+		}
+
+		if filter.Until, err = timeParam(q.Get("until")); err != nil {
+			log.InfoContext(r.Context(), "invalid until", slog.String("until", q.Get("until")))
+
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, response.Error("invalid until"))
+
+			return
+		}
+
+		entries, err := auditLister.ListAuditEntries(r.Context(), filter, limit, offset)
+		if status, resp, ok := response.Unavailable(err); ok {
+			log.WarnContext(r.Context(), "storage unavailable", slogerr.Error(err))
+
+			render.Status(r, status)
+			render.JSON(w, r, resp)
+
+			return
+		}
+		if err != nil {
+			log.ErrorContext(r.Context(), "failed to list audit entries", slogerr.Error(err))
+
+			render.Status(r, http.StatusInternalServerError)
+			render.JSON(w, r, response.Error("failed to list audit entries"))
+
+			return
+		}
+
+		items := make([]Item, 0, len(entries))
+		for _, e := range entries {
+			items = append(items, Item{
+				ID:         e.ID,
+				CreatedAt:  e.CreatedAt,
+				Actor:      e.Actor,
+				UserID:     e.UserID,
+				APIKeyID:   e.APIKeyID,
+				Action:     e.Action,
+				Domain:     e.Domain,
+				Alias:      e.Alias,
+				Before:     rawJSON(e.Before),
+				After:      rawJSON(e.After),
+				RequestID:  e.RequestID,
+				RemoteAddr: e.RemoteAddr,
+			})
+		}
+
+		render.JSON(w, r, Response{
+			Response: response.OK(),
+			Entries:  items,
+			Limit:    limit,
+			Offset:   offset,
+		})
+	}
+}
+
+func intParam(v string, def int) (int, error) {
+	if v == "" {
+		return def, nil
+	}
+
+	return strconv.Atoi(v)
+}
+
+func timeParam(v string) (time.Time, error) {
+	if v == "" {
+		return time.Time{}, nil
+	}
+
+	return time.Parse(time.RFC3339, v)
+}
+
+// rawJSON embeds a stored JSON state in the response as is.
+func rawJSON(v string) json.RawMessage {
+	if v == "" {
+		return nil
+	}
+
+	return json.RawMessage(v)
+}
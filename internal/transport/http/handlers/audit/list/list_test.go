@@ -0,0 +1,144 @@
+package list_test
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/dkhrunov/url-shortener/internal/storage"
+	"github.com/dkhrunov/url-shortener/internal/transport/http/handlers/audit/list"
+	"github.com/dkhrunov/url-shortener/internal/transport/http/handlers/audit/list/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestListHandler(t *testing.T) {
+	entries := []storage.AuditEntry{
+		{
+			ID:         2,
+			Actor:      "basic:root",
+			Action:     "update",
+			Alias:      "go",
+			Before:     `{"url":"https://go.dev"}`,
+			After:      `{"url":"https://go.dev/doc"}`,
+			RequestID:  "req-2",
+			RemoteAddr: "203.0.113.7",
+		},
+		{ID: 1, Actor: "basic:root", Action: "create", Alias: "go", After: `{"url":"https://go.dev"}`},
+	}
+
+	cases := []struct {
+		name      string
+		query     string
+		filter    storage.AuditFilter
+		limit     int
+		offset    int
+		entries   []storage.AuditEntry
+		respError string
+		mockError error
+		status    int
+	}{
+		{
+			name:    "Success",
+			limit:   50,
+			entries: entries,
+			status:  http.StatusOK,
+		},
+		{
+			name:  "Filters and pagination",
+			query: "?actor=api_key:ci&action=delete&domain=Brand.example&alias=go&since=2026-10-01T00:00:00Z&until=2026-10-02T00:00:00%2B02:00&limit=10&offset=20",
+			filter: storage.AuditFilter{
+				Actor:  "api_key:ci",
+				Action: "delete",
+				Domain: "brand.example",
+				Alias:  "go",
+				Since:  time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC),
+				Until:  time.Date(2026, 10, 1, 22, 0, 0, 0, time.UTC),
+			},
+			limit:   10,
+			offset:  20,
+			entries: []storage.AuditEntry{},
+			status:  http.StatusOK,
+		},
+		{
+			name:      "Invalid action",
+			query:     "?action=purge",
+			respError: "invalid action",
+			status:    http.StatusBadRequest,
+		},
+		{
+			name:      "Invalid since",
+			query:     "?since=yesterday",
+			respError: "invalid since",
+			status:    http.StatusBadRequest,
+		},
+		{
+			name:      "Invalid until",
+			query:     "?until=2026-10-01",
+			respError: "invalid until",
+			status:    http.StatusBadRequest,
+		},
+		{
+			name:      "Invalid limit",
+			query:     "?limit=1001",
+			respError: "invalid limit",
+			status:    http.StatusBadRequest,
+		},
+		{
+			name:      "Failed",
+			limit:     50,
+			respError: "failed to list audit entries",
+			mockError: errors.New("unexpected error"),
+			status:    http.StatusInternalServerError,
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			auditListerMock := mocks.NewAuditLister(t)
+
+			if tc.respError == "" || tc.mockError != nil {
+				auditListerMock.EXPECT().
+					ListAuditEntries(mock.Anything, mock.MatchedBy(func(f storage.AuditFilter) bool {
+						return f.Actor == tc.filter.Actor && f.Action == tc.filter.Action &&
+							f.Domain == tc.filter.Domain && f.Alias == tc.filter.Alias &&
+							f.Since.Equal(tc.filter.Since) && f.Until.Equal(tc.filter.Until)
+					}), tc.limit, tc.offset).
+					Return(tc.entries, tc.mockError).
+					Once()
+			}
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodGet, "/audit"+tc.query, nil)
+
+			handler := list.New(auditListerMock)
+			handler.ServeHTTP(w, r)
+
+			assert.Equal(t, tc.status, w.Code)
+
+			var resp list.Response
+
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+
+			assert.Equal(t, tc.respError, resp.Error)
+
+			require.Len(t, resp.Entries, len(tc.entries))
+			for i, item := range resp.Entries {
+				assert.Equal(t, tc.entries[i].ID, item.ID)
+				assert.Equal(t, tc.entries[i].Action, item.Action)
+				if tc.entries[i].Before != "" {
+					assert.JSONEq(t, tc.entries[i].Before, string(item.Before))
+				}
+				assert.JSONEq(t, tc.entries[i].After, string(item.After))
+			}
+		})
+	}
+}
@@ -0,0 +1,99 @@
+// Code generated by mockery v2.39.1. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+
+	storage "github.com/dkhrunov/url-shortener/internal/storage"
+)
+
+// AuditLister is an autogenerated mock type for the AuditLister type
+type AuditLister struct {
+	mock.Mock
+}
+
+type AuditLister_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *AuditLister) EXPECT() *AuditLister_Expecter {
+	return &AuditLister_Expecter{mock: &_m.Mock}
+}
+
+// ListAuditEntries provides a mock function with given fields: ctx, f, limit, offset
+func (_m *AuditLister) ListAuditEntries(ctx context.Context, f storage.AuditFilter, limit int, offset int) ([]storage.AuditEntry, error) {
+	ret := _m.Called(ctx, f, limit, offset)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListAuditEntries")
+	}
+
+	var r0 []storage.AuditEntry
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, storage.AuditFilter, int, int) ([]storage.AuditEntry, error)); ok {
+		return rf(ctx, f, limit, offset)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, storage.AuditFilter, int, int) []storage.AuditEntry); ok {
+		r0 = rf(ctx, f, limit, offset)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]storage.AuditEntry)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, storage.AuditFilter, int, int) error); ok {
+		r1 = rf(ctx, f, limit, offset)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// AuditLister_ListAuditEntries_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListAuditEntries'
+type AuditLister_ListAuditEntries_Call struct {
+	*mock.Call
+}
+
+// ListAuditEntries is a helper method to define mock.On call
+//   - ctx context.Context
+//   - f storage.AuditFilter
+//   - limit int
+//   - offset int
+func (_e *AuditLister_Expecter) ListAuditEntries(ctx interface{}, f interface{}, limit interface{}, offset interface{}) *AuditLister_ListAuditEntries_Call {
+	return &AuditLister_ListAuditEntries_Call{Call: _e.mock.On("ListAuditEntries", ctx, f, limit, offset)}
+}
+
+func (_c *AuditLister_ListAuditEntries_Call) Run(run func(ctx context.Context, f storage.AuditFilter, limit int, offset int)) *AuditLister_ListAuditEntries_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(storage.AuditFilter), args[2].(int), args[3].(int))
+	})
+	return _c
+}
+
+func (_c *AuditLister_ListAuditEntries_Call) Return(_a0 []storage.AuditEntry, _a1 error) *AuditLister_ListAuditEntries_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *AuditLister_ListAuditEntries_Call) RunAndReturn(run func(context.Context, storage.AuditFilter, int, int) ([]storage.AuditEntry, error)) *AuditLister_ListAuditEntries_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewAuditLister creates a new instance of AuditLister. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewAuditLister(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *AuditLister {
+	mock := &AuditLister{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,56 @@
+package delete
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/dkhrunov/url-shortener/internal/transport/http/common/response"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+)
+
+type Response struct {
+	response.Response
+}
+
+type Unblocker interface {
+	Unblock(client string) bool
+}
+
+func New(unblocker Unblocker) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.blocked.delete.New"
+
+		log := slog.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		// The client is passed in the query: with URLFormat enabled an address in
+		// the path would lose its last octet as a file extension.
+		client := r.URL.Query().Get("client")
+		if client == "" {
+			log.InfoContext(r.Context(), "client is empty")
+
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, response.Error("invalid request"))
+
+			return
+		}
+
+		if !unblocker.Unblock(client) {
+			log.InfoContext(r.Context(), "client not blocked", slog.String("client", client))
+
+			render.Status(r, http.StatusNotFound)
+			render.JSON(w, r, response.Error("not found"))
+
+			return
+		}
+
+		log.InfoContext(r.Context(), "client unblocked", slog.String("client", client))
+
+		render.JSON(w, r, Response{
+			Response: response.OK(),
+		})
+	}
+}
@@ -0,0 +1,72 @@
+package delete_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/dkhrunov/url-shortener/internal/transport/http/handlers/blocked/delete"
+	"github.com/dkhrunov/url-shortener/internal/transport/http/handlers/blocked/delete/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeleteHandler(t *testing.T) {
+	cases := []struct {
+		name      string
+		client    string
+		blocked   bool
+		respError string
+		status    int
+	}{
+		{
+			name:    "Success",
+			client:  "203.0.113.7",
+			blocked: true,
+			status:  http.StatusOK,
+		},
+		{
+			name:      "Empty client",
+			respError: "invalid request",
+			status:    http.StatusBadRequest,
+		},
+		{
+			name:      "Not blocked",
+			client:    "203.0.113.7",
+			respError: "not found",
+			status:    http.StatusNotFound,
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			unblockerMock := mocks.NewUnblocker(t)
+
+			if tc.client != "" {
+				unblockerMock.EXPECT().
+					Unblock(tc.client).
+					Return(tc.blocked).
+					Once()
+			}
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodDelete, "/admin/blocked-clients?client="+tc.client, nil)
+
+			handler := delete.New(unblockerMock)
+			handler.ServeHTTP(w, r)
+
+			assert.Equal(t, tc.status, w.Code)
+
+			var resp delete.Response
+
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+
+			assert.Equal(t, tc.respError, resp.Error)
+		})
+	}
+}
@@ -0,0 +1,78 @@
+// Code generated by mockery v2.39.1. DO NOT EDIT.
+
+package mocks
+
+import mock "github.com/stretchr/testify/mock"
+
+// Unblocker is an autogenerated mock type for the Unblocker type
+type Unblocker struct {
+	mock.Mock
+}
+
+type Unblocker_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *Unblocker) EXPECT() *Unblocker_Expecter {
+	return &Unblocker_Expecter{mock: &_m.Mock}
+}
+
+// Unblock provides a mock function with given fields: client
+func (_m *Unblocker) Unblock(client string) bool {
+	ret := _m.Called(client)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Unblock")
+	}
+
+	var r0 bool
+	if rf, ok := ret.Get(0).(func(string) bool); ok {
+		r0 = rf(client)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	return r0
+}
+
+// Unblocker_Unblock_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Unblock'
+type Unblocker_Unblock_Call struct {
+	*mock.Call
+}
+
+// Unblock is a helper method to define mock.On call
+//   - client string
+func (_e *Unblocker_Expecter) Unblock(client interface{}) *Unblocker_Unblock_Call {
+	return &Unblocker_Unblock_Call{Call: _e.mock.On("Unblock", client)}
+}
+
+func (_c *Unblocker_Unblock_Call) Run(run func(client string)) *Unblocker_Unblock_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string))
+	})
+	return _c
+}
+
+func (_c *Unblocker_Unblock_Call) Return(_a0 bool) *Unblocker_Unblock_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *Unblocker_Unblock_Call) RunAndReturn(run func(string) bool) *Unblocker_Unblock_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewUnblocker creates a new instance of Unblocker. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewUnblocker(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *Unblocker {
+	mock := &Unblocker{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,47 @@
+package list
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/dkhrunov/url-shortener/internal/lib/scanguard"
+	"github.com/dkhrunov/url-shortener/internal/transport/http/common/response"
+	"github.com/go-chi/render"
+)
+
+type Item struct {
+	Client    string    `json:"client"`
+	Misses    int       `json:"misses"`
+	BlockedAt time.Time `json:"blocked_at"`
+	Until     time.Time `json:"until"`
+}
+
+type Response struct {
+	response.Response
+	Clients []Item `json:"clients"`
+}
+
+type BlockLister interface {
+	List() []scanguard.Block
+}
+
+func New(blockLister BlockLister) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		blocks := blockLister.List()
+
+		items := make([]Item, 0, len(blocks))
+		for _, b := range blocks {
+			items = append(items, Item{
+				Client:    b.Client,
+				Misses:    b.Misses,
+				BlockedAt: b.BlockedAt,
+				Until:     b.Until,
+			})
+		}
+
+		render.JSON(w, r, Response{
+			Response: response.OK(),
+			Clients:  items,
+		})
+	}
+}
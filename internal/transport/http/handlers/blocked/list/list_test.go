@@ -0,0 +1,69 @@
+package list_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/dkhrunov/url-shortener/internal/lib/scanguard"
+	"github.com/dkhrunov/url-shortener/internal/transport/http/handlers/blocked/list"
+	"github.com/dkhrunov/url-shortener/internal/transport/http/handlers/blocked/list/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestListHandler(t *testing.T) {
+	now := time.Now()
+
+	cases := []struct {
+		name   string
+		blocks []scanguard.Block
+	}{
+		{
+			name: "Success",
+			blocks: []scanguard.Block{
+				{Client: "203.0.113.7", Misses: 20, BlockedAt: now, Until: now.Add(15 * time.Minute)},
+				{Client: "198.51.100.1", Misses: 21, BlockedAt: now.Add(-time.Minute), Until: now.Add(14 * time.Minute)},
+			},
+		},
+		{
+			name: "Empty",
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			blockListerMock := mocks.NewBlockLister(t)
+
+			blockListerMock.EXPECT().
+				List().
+				Return(tc.blocks).
+				Once()
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodGet, "/admin/blocked-clients", nil)
+
+			handler := list.New(blockListerMock)
+			handler.ServeHTTP(w, r)
+
+			assert.Equal(t, http.StatusOK, w.Code)
+
+			var resp list.Response
+
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+
+			require.NotNil(t, resp.Clients)
+			require.Len(t, resp.Clients, len(tc.blocks))
+			for i, c := range resp.Clients {
+				assert.Equal(t, tc.blocks[i].Client, c.Client)
+				assert.Equal(t, tc.blocks[i].Misses, c.Misses)
+			}
+		})
+	}
+}
@@ -0,0 +1,82 @@
+// Code generated by mockery v2.39.1. DO NOT EDIT.
+
+package mocks
+
+import (
+	scanguard "github.com/dkhrunov/url-shortener/internal/lib/scanguard"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// BlockLister is an autogenerated mock type for the BlockLister type
+type BlockLister struct {
+	mock.Mock
+}
+
+type BlockLister_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *BlockLister) EXPECT() *BlockLister_Expecter {
+	return &BlockLister_Expecter{mock: &_m.Mock}
+}
+
+// List provides a mock function with given fields:
+func (_m *BlockLister) List() []scanguard.Block {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for List")
+	}
+
+	var r0 []scanguard.Block
+	if rf, ok := ret.Get(0).(func() []scanguard.Block); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]scanguard.Block)
+		}
+	}
+
+	return r0
+}
+
+// BlockLister_List_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'List'
+type BlockLister_List_Call struct {
+	*mock.Call
+}
+
+// List is a helper method to define mock.On call
+func (_e *BlockLister_Expecter) List() *BlockLister_List_Call {
+	return &BlockLister_List_Call{Call: _e.mock.On("List")}
+}
+
+func (_c *BlockLister_List_Call) Run(run func()) *BlockLister_List_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *BlockLister_List_Call) Return(_a0 []scanguard.Block) *BlockLister_List_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *BlockLister_List_Call) RunAndReturn(run func() []scanguard.Block) *BlockLister_List_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewBlockLister creates a new instance of BlockLister. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewBlockLister(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *BlockLister {
+	mock := &BlockLister{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
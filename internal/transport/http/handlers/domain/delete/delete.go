@@ -0,0 +1,87 @@
+package delete
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/dkhrunov/url-shortener/internal/lib/logger/slog/slogerr"
+	"github.com/dkhrunov/url-shortener/internal/storage"
+	"github.com/dkhrunov/url-shortener/internal/transport/http/common/response"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+)
+
+type Response struct {
+	response.Response
+}
+
+type DomainDeleter interface {
+	DeleteDomain(ctx context.Context, id int64) error
+}
+
+func New(domainDeleter DomainDeleter) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.domain.delete.New"
+
+		log := slog.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		// Domains are addressed by id: with URLFormat enabled a host in the path
+		// would lose its last label as a file extension.
+		id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+		if err != nil {
+			log.InfoContext(r.Context(), "invalid id", slog.String("id", chi.URLParam(r, "id")))
+
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, response.Error("invalid request"))
+
+			return
+		}
+
+		err = domainDeleter.DeleteDomain(r.Context(), id)
+		if errors.Is(err, storage.ErrDomainNotFound) {
+			log.InfoContext(r.Context(), "domain not found", slog.Int64("id", id))
+
+			render.Status(r, http.StatusNotFound)
+			render.JSON(w, r, response.Error("not found"))
+
+			return
+		}
+		if errors.Is(err, storage.ErrDomainInUse) {
+			log.InfoContext(r.Context(), "domain has links", slog.Int64("id", id))
+
+			render.Status(r, http.StatusConflict)
+			render.JSON(w, r, response.Error("domain has links"))
+
+			return
+		}
+		if status, resp, ok := response.Unavailable(err); ok {
+			log.WarnContext(r.Context(), "storage unavailable", slogerr.Error(err))
+
+			render.Status(r, status)
+			render.JSON(w, r, resp)
+
+			return
+		}
+		if err != nil {
+			log.ErrorContext(r.Context(), "failed to delete domain", slogerr.Error(err))
+
+			render.Status(r, http.StatusInternalServerError)
+			render.JSON(w, r, response.Error("failed to delete domain"))
+
+			return
+		}
+
+		log.InfoContext(r.Context(), "domain deleted", slog.Int64("id", id))
+
+		render.JSON(w, r, Response{
+			Response: response.OK(),
+		})
+	}
+}
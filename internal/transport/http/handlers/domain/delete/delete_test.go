@@ -0,0 +1,105 @@
+package delete_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/dkhrunov/url-shortener/internal/storage"
+	"github.com/dkhrunov/url-shortener/internal/transport/http/handlers/domain/delete"
+	"github.com/dkhrunov/url-shortener/internal/transport/http/handlers/domain/delete/mocks"
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeleteHandler(t *testing.T) {
+	cases := []struct {
+		name      string
+		id        string
+		respError string
+		mockError error
+		status    int
+	}{
+		{
+			name:   "Success",
+			id:     "1",
+			status: http.StatusOK,
+		},
+		{
+			name:      "Invalid id",
+			id:        "brand.example",
+			respError: "invalid request",
+			status:    http.StatusBadRequest,
+		},
+		{
+			name:      "Not found",
+			id:        "1",
+			respError: "not found",
+			mockError: storage.ErrDomainNotFound,
+			status:    http.StatusNotFound,
+		},
+		{
+			name:      "In use",
+			id:        "1",
+			respError: "domain has links",
+			mockError: storage.ErrDomainInUse,
+			status:    http.StatusConflict,
+		},
+		{
+			name:      "Failed",
+			id:        "1",
+			respError: "failed to delete domain",
+			mockError: errors.New("unexpected error"),
+			status:    http.StatusInternalServerError,
+		},
+		{
+			name:      "DeleteDomain timed out",
+			id:        "1",
+			respError: "storage timed out",
+			mockError: fmt.Errorf("storage.sqlite.DeleteDomain: %w", context.DeadlineExceeded),
+			status:    http.StatusGatewayTimeout,
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			domainDeleterMock := mocks.NewDomainDeleter(t)
+
+			if tc.respError == "" || tc.mockError != nil {
+				domainDeleterMock.EXPECT().
+					DeleteDomain(mock.Anything, int64(1)).
+					Return(tc.mockError).
+					Once()
+			}
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodDelete, "/admin/domains/{id}", nil)
+
+			rctx := chi.NewRouteContext()
+			rctx.URLParams.Add("id", tc.id)
+
+			r = r.WithContext(context.WithValue(r.Context(), chi.RouteCtxKey, rctx))
+
+			handler := delete.New(domainDeleterMock)
+			handler.ServeHTTP(w, r)
+
+			assert.Equal(t, tc.status, w.Code)
+
+			var resp delete.Response
+
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+
+			assert.Equal(t, tc.respError, resp.Error)
+		})
+	}
+}
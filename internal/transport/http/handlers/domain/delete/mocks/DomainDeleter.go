@@ -0,0 +1,83 @@
+// Code generated by mockery v2.39.1. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// DomainDeleter is an autogenerated mock type for the DomainDeleter type
+type DomainDeleter struct {
+	mock.Mock
+}
+
+type DomainDeleter_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *DomainDeleter) EXPECT() *DomainDeleter_Expecter {
+	return &DomainDeleter_Expecter{mock: &_m.Mock}
+}
+
+// DeleteDomain provides a mock function with given fields: ctx, id
+func (_m *DomainDeleter) DeleteDomain(ctx context.Context, id int64) error {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteDomain")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64) error); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DomainDeleter_DeleteDomain_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteDomain'
+type DomainDeleter_DeleteDomain_Call struct {
+	*mock.Call
+}
+
+// DeleteDomain is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id int64
+func (_e *DomainDeleter_Expecter) DeleteDomain(ctx interface{}, id interface{}) *DomainDeleter_DeleteDomain_Call {
+	return &DomainDeleter_DeleteDomain_Call{Call: _e.mock.On("DeleteDomain", ctx, id)}
+}
+
+func (_c *DomainDeleter_DeleteDomain_Call) Run(run func(ctx context.Context, id int64)) *DomainDeleter_DeleteDomain_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int64))
+	})
+	return _c
+}
+
+func (_c *DomainDeleter_DeleteDomain_Call) Return(_a0 error) *DomainDeleter_DeleteDomain_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *DomainDeleter_DeleteDomain_Call) RunAndReturn(run func(context.Context, int64) error) *DomainDeleter_DeleteDomain_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewDomainDeleter creates a new instance of DomainDeleter. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewDomainDeleter(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *DomainDeleter {
+	mock := &DomainDeleter{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,72 @@
+package list
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/dkhrunov/url-shortener/internal/lib/logger/slog/slogerr"
+	"github.com/dkhrunov/url-shortener/internal/storage"
+	"github.com/dkhrunov/url-shortener/internal/transport/http/common/response"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+)
+
+type Item struct {
+	ID        int64     `json:"id"`
+	Host      string    `json:"host"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type Response struct {
+	response.Response
+	Domains []Item `json:"domains"`
+}
+
+type DomainLister interface {
+	ListDomains(ctx context.Context) ([]storage.Domain, error)
+}
+
+func New(domainLister DomainLister) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.domain.list.New"
+
+		log := slog.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		domains, err := domainLister.ListDomains(r.Context())
+		if status, resp, ok := response.Unavailable(err); ok {
+			log.WarnContext(r.Context(), "storage unavailable", slogerr.Error(err))
+
+			render.Status(r, status)
+			render.JSON(w, r, resp)
+
+			return
+		}
+		if err != nil {
+			log.ErrorContext(r.Context(), "failed to list domains", slogerr.Error(err))
+
+			render.Status(r, http.StatusInternalServerError)
+			render.JSON(w, r, response.Error("failed to list domains"))
+
+			return
+		}
+
+		items := make([]Item, 0, len(domains))
+		for _, d := range domains {
+			items = append(items, Item{
+				ID:        d.ID,
+				Host:      d.Host,
+				CreatedAt: d.CreatedAt,
+			})
+		}
+
+		render.JSON(w, r, Response{
+			Response: response.OK(),
+			Domains:  items,
+		})
+	}
+}
@@ -0,0 +1,89 @@
+package list_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/dkhrunov/url-shortener/internal/storage"
+	"github.com/dkhrunov/url-shortener/internal/transport/http/handlers/domain/list"
+	"github.com/dkhrunov/url-shortener/internal/transport/http/handlers/domain/list/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestListHandler(t *testing.T) {
+	cases := []struct {
+		name      string
+		domains   []storage.Domain
+		respError string
+		mockError error
+		status    int
+	}{
+		{
+			name: "Success",
+			domains: []storage.Domain{
+				{ID: 1, Host: "brand.example", CreatedAt: time.Now()},
+				{ID: 2, Host: "go.example.org", CreatedAt: time.Now()},
+			},
+			status: http.StatusOK,
+		},
+		{
+			name:    "Empty",
+			domains: []storage.Domain{},
+			status:  http.StatusOK,
+		},
+		{
+			name:      "Failed",
+			respError: "failed to list domains",
+			mockError: errors.New("unexpected error"),
+			status:    http.StatusInternalServerError,
+		},
+		{
+			name:      "ListDomains timed out",
+			respError: "storage timed out",
+			mockError: fmt.Errorf("storage.sqlite.ListDomains: %w", context.DeadlineExceeded),
+			status:    http.StatusGatewayTimeout,
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			domainListerMock := mocks.NewDomainLister(t)
+
+			domainListerMock.EXPECT().
+				ListDomains(mock.Anything).
+				Return(tc.domains, tc.mockError).
+				Once()
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodGet, "/admin/domains", nil)
+
+			handler := list.New(domainListerMock)
+			handler.ServeHTTP(w, r)
+
+			assert.Equal(t, tc.status, w.Code)
+
+			var resp list.Response
+
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+
+			assert.Equal(t, tc.respError, resp.Error)
+
+			require.Len(t, resp.Domains, len(tc.domains))
+			for i, d := range resp.Domains {
+				assert.Equal(t, tc.domains[i].Host, d.Host)
+			}
+		})
+	}
+}
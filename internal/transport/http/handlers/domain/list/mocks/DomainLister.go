@@ -0,0 +1,96 @@
+// Code generated by mockery v2.39.1. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+
+	storage "github.com/dkhrunov/url-shortener/internal/storage"
+)
+
+// DomainLister is an autogenerated mock type for the DomainLister type
+type DomainLister struct {
+	mock.Mock
+}
+
+type DomainLister_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *DomainLister) EXPECT() *DomainLister_Expecter {
+	return &DomainLister_Expecter{mock: &_m.Mock}
+}
+
+// ListDomains provides a mock function with given fields: ctx
+func (_m *DomainLister) ListDomains(ctx context.Context) ([]storage.Domain, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListDomains")
+	}
+
+	var r0 []storage.Domain
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) ([]storage.Domain, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) []storage.Domain); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]storage.Domain)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// DomainLister_ListDomains_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListDomains'
+type DomainLister_ListDomains_Call struct {
+	*mock.Call
+}
+
+// ListDomains is a helper method to define mock.On call
+//   - ctx context.Context
+func (_e *DomainLister_Expecter) ListDomains(ctx interface{}) *DomainLister_ListDomains_Call {
+	return &DomainLister_ListDomains_Call{Call: _e.mock.On("ListDomains", ctx)}
+}
+
+func (_c *DomainLister_ListDomains_Call) Run(run func(ctx context.Context)) *DomainLister_ListDomains_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context))
+	})
+	return _c
+}
+
+func (_c *DomainLister_ListDomains_Call) Return(_a0 []storage.Domain, _a1 error) *DomainLister_ListDomains_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *DomainLister_ListDomains_Call) RunAndReturn(run func(context.Context) ([]storage.Domain, error)) *DomainLister_ListDomains_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewDomainLister creates a new instance of DomainLister. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewDomainLister(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *DomainLister {
+	mock := &DomainLister{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
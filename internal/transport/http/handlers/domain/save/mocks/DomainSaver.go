@@ -0,0 +1,93 @@
+// Code generated by mockery v2.39.1. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// DomainSaver is an autogenerated mock type for the DomainSaver type
+type DomainSaver struct {
+	mock.Mock
+}
+
+type DomainSaver_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *DomainSaver) EXPECT() *DomainSaver_Expecter {
+	return &DomainSaver_Expecter{mock: &_m.Mock}
+}
+
+// SaveDomain provides a mock function with given fields: ctx, host
+func (_m *DomainSaver) SaveDomain(ctx context.Context, host string) (int64, error) {
+	ret := _m.Called(ctx, host)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SaveDomain")
+	}
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (int64, error)); ok {
+		return rf(ctx, host)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) int64); ok {
+		r0 = rf(ctx, host)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, host)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// DomainSaver_SaveDomain_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SaveDomain'
+type DomainSaver_SaveDomain_Call struct {
+	*mock.Call
+}
+
+// SaveDomain is a helper method to define mock.On call
+//   - ctx context.Context
+//   - host string
+func (_e *DomainSaver_Expecter) SaveDomain(ctx interface{}, host interface{}) *DomainSaver_SaveDomain_Call {
+	return &DomainSaver_SaveDomain_Call{Call: _e.mock.On("SaveDomain", ctx, host)}
+}
+
+func (_c *DomainSaver_SaveDomain_Call) Run(run func(ctx context.Context, host string)) *DomainSaver_SaveDomain_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *DomainSaver_SaveDomain_Call) Return(_a0 int64, _a1 error) *DomainSaver_SaveDomain_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *DomainSaver_SaveDomain_Call) RunAndReturn(run func(context.Context, string) (int64, error)) *DomainSaver_SaveDomain_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewDomainSaver creates a new instance of DomainSaver. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewDomainSaver(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *DomainSaver {
+	mock := &DomainSaver{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
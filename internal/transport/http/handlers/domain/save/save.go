@@ -0,0 +1,101 @@
+package save
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/dkhrunov/url-shortener/internal/lib/hostname"
+	"github.com/dkhrunov/url-shortener/internal/lib/logger/slog/slogerr"
+	"github.com/dkhrunov/url-shortener/internal/storage"
+	"github.com/dkhrunov/url-shortener/internal/transport/http/common/response"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+	"github.com/go-playground/validator/v10"
+)
+
+type Request struct {
+	Host string `json:"host" validate:"required,hostname_rfc1123"`
+}
+
+type Response struct {
+	response.Response
+	ID   int64  `json:"id,omitempty"`
+	Host string `json:"host,omitempty"`
+}
+
+type DomainSaver interface {
+	SaveDomain(ctx context.Context, host string) (int64, error)
+}
+
+func New(domainSaver DomainSaver) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.domain.save.New"
+
+		log := slog.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		var req Request
+
+		err := render.DecodeJSON(r.Body, &req)
+		if err != nil {
+			log.ErrorContext(r.Context(), "failed to decode request body", slogerr.Error(err))
+
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, response.Error("failed to decode request"))
+
+			return
+		}
+
+		req.Host = hostname.Normalize(req.Host)
+
+		if err := validator.New().Struct(req); err != nil {
+			validateErr := err.(validator.ValidationErrors)
+
+			log.ErrorContext(r.Context(), "invalid request", slogerr.Error(err))
+
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, response.ValidationError(validateErr))
+
+			return
+		}
+
+		id, err := domainSaver.SaveDomain(r.Context(), req.Host)
+		if errors.Is(err, storage.ErrDomainExist) {
+			log.InfoContext(r.Context(), "domain already exists", slog.String("host", req.Host))
+
+			render.Status(r, http.StatusConflict)
+			render.JSON(w, r, response.Error("domain already exists"))
+
+			return
+		}
+		if status, resp, ok := response.Unavailable(err); ok {
+			log.WarnContext(r.Context(), "storage unavailable", slogerr.Error(err))
+
+			render.Status(r, status)
+			render.JSON(w, r, resp)
+
+			return
+		}
+		if err != nil {
+			log.ErrorContext(r.Context(), "failed to add domain", slogerr.Error(err))
+
+			render.Status(r, http.StatusInternalServerError)
+			render.JSON(w, r, response.Error("failed to add domain"))
+
+			return
+		}
+
+		log.InfoContext(r.Context(), "domain added", slog.Int64("id", id), slog.String("host", req.Host))
+
+		render.Status(r, http.StatusCreated)
+		render.JSON(w, r, Response{
+			Response: response.OK(),
+			ID:       id,
+			Host:     req.Host,
+		})
+	}
+}
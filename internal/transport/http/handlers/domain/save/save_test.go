@@ -0,0 +1,118 @@
+package save_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dkhrunov/url-shortener/internal/storage"
+	"github.com/dkhrunov/url-shortener/internal/transport/http/handlers/domain/save"
+	"github.com/dkhrunov/url-shortener/internal/transport/http/handlers/domain/save/mocks"
+)
+
+func TestSaveHandler(t *testing.T) {
+	cases := []struct {
+		name      string
+		host      string
+		saved     string
+		respError string
+		mockError error
+		status    int
+	}{
+		{
+			name:   "Success",
+			host:   "brand.example",
+			saved:  "brand.example",
+			status: http.StatusCreated,
+		},
+		{
+			name:   "Normalized",
+			host:   "Brand.Example:443",
+			saved:  "brand.example",
+			status: http.StatusCreated,
+		},
+		{
+			name:      "Empty host",
+			host:      "",
+			respError: "field Host is a required field",
+			status:    http.StatusBadRequest,
+		},
+		{
+			name:      "Invalid host",
+			host:      "not a host",
+			respError: "field Host is not valid",
+			status:    http.StatusBadRequest,
+		},
+		{
+			name:      "Domain exist",
+			host:      "brand.example",
+			saved:     "brand.example",
+			respError: "domain already exists",
+			mockError: storage.ErrDomainExist,
+			status:    http.StatusConflict,
+		},
+		{
+			name:      "SaveDomain Error",
+			host:      "brand.example",
+			saved:     "brand.example",
+			respError: "failed to add domain",
+			mockError: errors.New("unexpected error"),
+			status:    http.StatusInternalServerError,
+		},
+		{
+			name:      "SaveDomain timed out",
+			host:      "brand.example",
+			saved:     "brand.example",
+			respError: "storage timed out",
+			mockError: fmt.Errorf("storage.sqlite.SaveDomain: %w", context.DeadlineExceeded),
+			status:    http.StatusGatewayTimeout,
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			domainSaverMock := mocks.NewDomainSaver(t)
+
+			if tc.respError == "" || tc.mockError != nil {
+				domainSaverMock.EXPECT().
+					SaveDomain(mock.Anything, tc.saved).
+					Return(int64(1), tc.mockError).
+					Once()
+			}
+
+			handler := save.New(domainSaverMock)
+
+			input := fmt.Sprintf(`{"host": "%s"}`, tc.host)
+
+			req, err := http.NewRequest(http.MethodPost, "/admin/domains", bytes.NewReader([]byte(input)))
+			require.NoError(t, err)
+
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, req)
+
+			assert.Equal(t, tc.status, rr.Code)
+
+			var resp save.Response
+
+			require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+
+			assert.Equal(t, tc.respError, resp.Error)
+			if tc.respError == "" {
+				assert.Equal(t, tc.saved, resp.Host)
+			}
+		})
+	}
+}
@@ -0,0 +1,38 @@
+package all
+
+import (
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/dkhrunov/url-shortener/internal/lib/clickhub"
+	"github.com/dkhrunov/url-shortener/internal/lib/logger/slog/slogerr"
+	"github.com/dkhrunov/url-shortener/internal/transport/http/common/sse"
+	"github.com/go-chi/chi/v5/middleware"
+)
+
+type ClickSubscriber interface {
+	Subscribe(domain, alias string) *clickhub.Subscription
+}
+
+// New returns the handler streaming the clicks of all links as server-sent events,
+// see sse.Stream.
+func New(clickSubscriber ClickSubscriber, heartbeat time.Duration) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.events.all.New"
+
+		log := slog.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		sub := clickSubscriber.Subscribe("", "")
+		defer sub.Close()
+
+		log.InfoContext(r.Context(), "streaming clicks")
+
+		if err := sse.Stream(w, r, sub, heartbeat); err != nil {
+			log.InfoContext(r.Context(), "click stream ended", slogerr.Error(err))
+		}
+	}
+}
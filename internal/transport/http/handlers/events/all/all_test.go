@@ -0,0 +1,49 @@
+package all_test
+
+import (
+	"bufio"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/dkhrunov/url-shortener/internal/lib/clickhub"
+	"github.com/dkhrunov/url-shortener/internal/transport/http/handlers/events/all"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAllHandler(t *testing.T) {
+	hub := clickhub.New(8)
+
+	server := httptest.NewServer(all.New(hub, time.Minute))
+	defer server.Close()
+
+	resp, err := http.Get(server.URL + "/events")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.Eventually(t, func() bool { return hub.Subscribers() == 1 }, time.Second, time.Millisecond)
+
+	hub.Publish(clickhub.Click{Alias: "go", URL: "https://go.dev"})
+	hub.Publish(clickhub.Click{Domain: "brand.example", Alias: "pkg", URL: "https://pkg.go.dev"})
+
+	var data []string
+	lines := bufio.NewScanner(resp.Body)
+	for lines.Scan() {
+		if line := lines.Text(); len(line) > 5 && line[:5] == "data:" {
+			data = append(data, line)
+		}
+
+		if len(data) == 2 {
+			// Closing the hub on shutdown ends the stream.
+			hub.Close()
+		}
+	}
+	require.NoError(t, lines.Err())
+
+	require.Len(t, data, 2)
+	assert.Contains(t, data[0], `"alias":"go"`)
+	assert.Contains(t, data[1], `"domain":"brand.example"`)
+}
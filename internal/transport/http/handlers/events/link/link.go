@@ -0,0 +1,102 @@
+package link
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/dkhrunov/url-shortener/internal/auth"
+	"github.com/dkhrunov/url-shortener/internal/lib/clickhub"
+	"github.com/dkhrunov/url-shortener/internal/lib/hostname"
+	"github.com/dkhrunov/url-shortener/internal/lib/logger/slog/slogerr"
+	"github.com/dkhrunov/url-shortener/internal/storage"
+	"github.com/dkhrunov/url-shortener/internal/transport/http/common/response"
+	"github.com/dkhrunov/url-shortener/internal/transport/http/common/sse"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+)
+
+type LinkGetter interface {
+	GetLink(ctx context.Context, domain, alias string) (storage.URL, error)
+}
+
+type ClickSubscriber interface {
+	Subscribe(domain, alias string) *clickhub.Subscription
+}
+
+// New returns the handler streaming the clicks of a link as server-sent events, see
+// sse.Stream.
+func New(linkGetter LinkGetter, clickSubscriber ClickSubscriber, heartbeat time.Duration) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.events.link.New"
+
+		log := slog.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		identity, ok := auth.FromContext(r.Context())
+		if !ok {
+			log.ErrorContext(r.Context(), "request is not authenticated")
+
+			render.Status(r, http.StatusUnauthorized)
+			render.JSON(w, r, response.Error("unauthorized"))
+
+			return
+		}
+
+		alias := chi.URLParam(r, "alias")
+		if alias == "" {
+			log.InfoContext(r.Context(), "alias is empty")
+
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, response.Error("invalid request"))
+
+			return
+		}
+
+		domain := hostname.Normalize(r.URL.Query().Get("domain"))
+
+		link, err := linkGetter.GetLink(r.Context(), domain, alias)
+		if err == nil && !identity.CanManage(link.OwnerID) {
+			// Links of other users are reported as missing to not reveal them.
+			err = storage.ErrURLNotFound
+		}
+		if errors.Is(err, storage.ErrURLNotFound) {
+			log.InfoContext(r.Context(), "url not found", "alias", alias)
+
+			render.Status(r, http.StatusNotFound)
+			render.JSON(w, r, response.Error("not found"))
+
+			return
+		}
+		if status, resp, ok := response.Unavailable(err); ok {
+			log.WarnContext(r.Context(), "storage unavailable", slogerr.Error(err))
+
+			render.Status(r, status)
+			render.JSON(w, r, resp)
+
+			return
+		}
+		if err != nil {
+			log.ErrorContext(r.Context(), "failed to get url", slogerr.Error(err))
+
+			render.Status(r, http.StatusInternalServerError)
+			render.JSON(w, r, response.Error("failed to get url"))
+
+			return
+		}
+
+		sub := clickSubscriber.Subscribe(domain, alias)
+		defer sub.Close()
+
+		log.InfoContext(r.Context(), "streaming clicks", slog.String("alias", alias))
+
+		if err := sse.Stream(w, r, sub, heartbeat); err != nil {
+			log.InfoContext(r.Context(), "click stream ended", slogerr.Error(err))
+		}
+	}
+}
@@ -0,0 +1,152 @@
+package link_test
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/dkhrunov/url-shortener/internal/auth"
+	"github.com/dkhrunov/url-shortener/internal/lib/clickhub"
+	"github.com/dkhrunov/url-shortener/internal/storage"
+	"github.com/dkhrunov/url-shortener/internal/transport/http/common/response"
+	"github.com/dkhrunov/url-shortener/internal/transport/http/handlers/events/link"
+	"github.com/dkhrunov/url-shortener/internal/transport/http/handlers/events/link/mocks"
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLinkHandler(t *testing.T) {
+	cases := []struct {
+		name      string
+		alias     string
+		ownerID   int64
+		mockError error
+		respError string
+		status    int
+	}{
+		{
+			name:      "Empty alias",
+			respError: "invalid request",
+			status:    http.StatusBadRequest,
+		},
+		{
+			name:      "Not found",
+			alias:     "go",
+			mockError: storage.ErrURLNotFound,
+			respError: "not found",
+			status:    http.StatusNotFound,
+		},
+		{
+			name:      "Not owned",
+			alias:     "go",
+			ownerID:   7,
+			respError: "not found",
+			status:    http.StatusNotFound,
+		},
+		{
+			name:      "Timeout",
+			alias:     "go",
+			mockError: context.DeadlineExceeded,
+			respError: "storage timed out",
+			status:    http.StatusGatewayTimeout,
+		},
+		{
+			name:      "Failed",
+			alias:     "go",
+			mockError: errors.New("unexpected error"),
+			respError: "failed to get url",
+			status:    http.StatusInternalServerError,
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			linkGetterMock := mocks.NewLinkGetter(t)
+			hub := clickhub.New(1)
+
+			if tc.alias != "" {
+				linkGetterMock.EXPECT().
+					GetLink(mock.Anything, "", tc.alias).
+					Return(storage.URL{Alias: tc.alias, OwnerID: tc.ownerID}, tc.mockError).
+					Once()
+			}
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodGet, "/url/{alias}/events", nil)
+
+			rctx := chi.NewRouteContext()
+			rctx.URLParams.Add("alias", tc.alias)
+
+			ctx := context.WithValue(r.Context(), chi.RouteCtxKey, rctx)
+			r = r.WithContext(auth.WithIdentity(ctx, auth.Identity{UserID: 42, Role: auth.RoleViewer}))
+
+			handler := link.New(linkGetterMock, hub, time.Minute)
+			handler.ServeHTTP(w, r)
+
+			assert.Equal(t, tc.status, w.Code)
+
+			var resp response.Response
+
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+
+			assert.Equal(t, tc.respError, resp.Error)
+			assert.Zero(t, hub.Subscribers())
+		})
+	}
+}
+
+func TestLinkHandler_Stream(t *testing.T) {
+	linkGetterMock := mocks.NewLinkGetter(t)
+	hub := clickhub.New(8)
+
+	linkGetterMock.EXPECT().
+		GetLink(mock.Anything, "brand.example", "go").
+		Return(storage.URL{Domain: "brand.example", Alias: "go", OwnerID: 42}, nil).
+		Once()
+
+	handler := link.New(linkGetterMock, hub, time.Minute)
+
+	router := chi.NewRouter()
+	router.Get("/url/{alias}/events", func(w http.ResponseWriter, r *http.Request) {
+		identity := auth.Identity{UserID: 42, Role: auth.RoleViewer}
+		handler.ServeHTTP(w, r.WithContext(auth.WithIdentity(r.Context(), identity)))
+	})
+
+	server := httptest.NewServer(router)
+	defer server.Close()
+
+	resp, err := http.Get(server.URL + "/url/go/events?domain=Brand.example")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.Eventually(t, func() bool { return hub.Subscribers() == 1 }, time.Second, time.Millisecond)
+
+	// Only clicks of the link in its namespace are streamed.
+	hub.Publish(clickhub.Click{Alias: "go", URL: "https://go.dev"})
+	hub.Publish(clickhub.Click{Domain: "brand.example", Alias: "pkg", URL: "https://pkg.go.dev"})
+	hub.Publish(clickhub.Click{Domain: "brand.example", Alias: "go", URL: "https://go.dev/brand"})
+
+	lines := bufio.NewScanner(resp.Body)
+	require.True(t, lines.Scan())
+	assert.Equal(t, "id: 1", lines.Text())
+	require.True(t, lines.Scan())
+	assert.Equal(t, "event: click", lines.Text())
+	require.True(t, lines.Scan())
+	assert.Contains(t, lines.Text(), `"url":"https://go.dev/brand"`)
+
+	// Closing the response unsubscribes.
+	resp.Body.Close()
+	require.Eventually(t, func() bool { return hub.Subscribers() == 0 }, time.Second, time.Millisecond)
+}
@@ -0,0 +1,96 @@
+// Code generated by mockery v2.39.1. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+
+	storage "github.com/dkhrunov/url-shortener/internal/storage"
+)
+
+// LinkGetter is an autogenerated mock type for the LinkGetter type
+type LinkGetter struct {
+	mock.Mock
+}
+
+type LinkGetter_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *LinkGetter) EXPECT() *LinkGetter_Expecter {
+	return &LinkGetter_Expecter{mock: &_m.Mock}
+}
+
+// GetLink provides a mock function with given fields: ctx, domain, alias
+func (_m *LinkGetter) GetLink(ctx context.Context, domain string, alias string) (storage.URL, error) {
+	ret := _m.Called(ctx, domain, alias)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetLink")
+	}
+
+	var r0 storage.URL
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) (storage.URL, error)); ok {
+		return rf(ctx, domain, alias)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) storage.URL); ok {
+		r0 = rf(ctx, domain, alias)
+	} else {
+		r0 = ret.Get(0).(storage.URL)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, domain, alias)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// LinkGetter_GetLink_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetLink'
+type LinkGetter_GetLink_Call struct {
+	*mock.Call
+}
+
+// GetLink is a helper method to define mock.On call
+//   - ctx context.Context
+//   - domain string
+//   - alias string
+func (_e *LinkGetter_Expecter) GetLink(ctx interface{}, domain interface{}, alias interface{}) *LinkGetter_GetLink_Call {
+	return &LinkGetter_GetLink_Call{Call: _e.mock.On("GetLink", ctx, domain, alias)}
+}
+
+func (_c *LinkGetter_GetLink_Call) Run(run func(ctx context.Context, domain string, alias string)) *LinkGetter_GetLink_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(string))
+	})
+	return _c
+}
+
+func (_c *LinkGetter_GetLink_Call) Return(_a0 storage.URL, _a1 error) *LinkGetter_GetLink_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *LinkGetter_GetLink_Call) RunAndReturn(run func(context.Context, string, string) (storage.URL, error)) *LinkGetter_GetLink_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewLinkGetter creates a new instance of LinkGetter. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewLinkGetter(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *LinkGetter {
+	mock := &LinkGetter{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,16 @@
+package live
+
+import (
+	"net/http"
+
+	"github.com/dkhrunov/url-shortener/internal/transport/http/common/response"
+	"github.com/go-chi/render"
+)
+
+// New reports that the process is up. It checks nothing else on purpose: a failing
+// liveness probe gets the process restarted.
+func New() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		render.JSON(w, r, response.OK())
+	}
+}
@@ -0,0 +1,92 @@
+// Code generated by mockery v2.39.1. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// SchemaChecker is an autogenerated mock type for the SchemaChecker type
+type SchemaChecker struct {
+	mock.Mock
+}
+
+type SchemaChecker_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *SchemaChecker) EXPECT() *SchemaChecker_Expecter {
+	return &SchemaChecker_Expecter{mock: &_m.Mock}
+}
+
+// PendingMigrations provides a mock function with given fields: ctx
+func (_m *SchemaChecker) PendingMigrations(ctx context.Context) (int, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for PendingMigrations")
+	}
+
+	var r0 int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) (int, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) int); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SchemaChecker_PendingMigrations_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'PendingMigrations'
+type SchemaChecker_PendingMigrations_Call struct {
+	*mock.Call
+}
+
+// PendingMigrations is a helper method to define mock.On call
+//   - ctx context.Context
+func (_e *SchemaChecker_Expecter) PendingMigrations(ctx interface{}) *SchemaChecker_PendingMigrations_Call {
+	return &SchemaChecker_PendingMigrations_Call{Call: _e.mock.On("PendingMigrations", ctx)}
+}
+
+func (_c *SchemaChecker_PendingMigrations_Call) Run(run func(ctx context.Context)) *SchemaChecker_PendingMigrations_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context))
+	})
+	return _c
+}
+
+func (_c *SchemaChecker_PendingMigrations_Call) Return(_a0 int, _a1 error) *SchemaChecker_PendingMigrations_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *SchemaChecker_PendingMigrations_Call) RunAndReturn(run func(context.Context) (int, error)) *SchemaChecker_PendingMigrations_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewSchemaChecker creates a new instance of SchemaChecker. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewSchemaChecker(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *SchemaChecker {
+	mock := &SchemaChecker{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,77 @@
+// Code generated by mockery v2.39.1. DO NOT EDIT.
+
+package mocks
+
+import mock "github.com/stretchr/testify/mock"
+
+// ShutdownState is an autogenerated mock type for the ShutdownState type
+type ShutdownState struct {
+	mock.Mock
+}
+
+type ShutdownState_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *ShutdownState) EXPECT() *ShutdownState_Expecter {
+	return &ShutdownState_Expecter{mock: &_m.Mock}
+}
+
+// ShuttingDown provides a mock function with given fields:
+func (_m *ShutdownState) ShuttingDown() bool {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for ShuttingDown")
+	}
+
+	var r0 bool
+	if rf, ok := ret.Get(0).(func() bool); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	return r0
+}
+
+// ShutdownState_ShuttingDown_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ShuttingDown'
+type ShutdownState_ShuttingDown_Call struct {
+	*mock.Call
+}
+
+// ShuttingDown is a helper method to define mock.On call
+func (_e *ShutdownState_Expecter) ShuttingDown() *ShutdownState_ShuttingDown_Call {
+	return &ShutdownState_ShuttingDown_Call{Call: _e.mock.On("ShuttingDown")}
+}
+
+func (_c *ShutdownState_ShuttingDown_Call) Run(run func()) *ShutdownState_ShuttingDown_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *ShutdownState_ShuttingDown_Call) Return(_a0 bool) *ShutdownState_ShuttingDown_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *ShutdownState_ShuttingDown_Call) RunAndReturn(run func() bool) *ShutdownState_ShuttingDown_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewShutdownState creates a new instance of ShutdownState. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewShutdownState(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *ShutdownState {
+	mock := &ShutdownState{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
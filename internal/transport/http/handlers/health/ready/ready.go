@@ -0,0 +1,100 @@
+package ready
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/dkhrunov/url-shortener/internal/lib/logger/slog/slogerr"
+	"github.com/dkhrunov/url-shortener/internal/transport/http/common/response"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+)
+
+const (
+	checkOK   = "ok"
+	checkFail = "failing"
+)
+
+// pingTimeout keeps a locked database from hanging the probe.
+const pingTimeout = 2 * time.Second
+
+type Response struct {
+	response.Response
+	Checks map[string]string `json:"checks"`
+}
+
+type Pinger interface {
+	Ping(ctx context.Context) error
+}
+
+type SchemaChecker interface {
+	// PendingMigrations returns the number of schema migrations not applied yet.
+	PendingMigrations(ctx context.Context) (int, error)
+}
+
+type ShutdownState interface {
+	ShuttingDown() bool
+}
+
+// New reports whether the service can take traffic: the database answers, its schema
+// is migrated and the service is not shutting down. It responds 503 otherwise.
+func New(pinger Pinger, schema SchemaChecker, state ShutdownState) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.health.ready.New"
+
+		log := slog.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		checks := map[string]string{
+			"database":   checkOK,
+			"migrations": checkOK,
+			"shutdown":   checkOK,
+		}
+		ready := true
+
+		ctx, cancel := context.WithTimeout(r.Context(), pingTimeout)
+		defer cancel()
+
+		if err := pinger.Ping(ctx); err != nil {
+			log.ErrorContext(r.Context(), "database is not reachable", slogerr.Error(err))
+
+			checks["database"] = checkFail
+			ready = false
+		}
+
+		pending, err := schema.PendingMigrations(ctx)
+		if err != nil {
+			log.ErrorContext(r.Context(), "failed to check migrations", slogerr.Error(err))
+		} else if pending > 0 {
+			log.WarnContext(r.Context(), "schema migrations are pending", slog.Int("pending", pending))
+		}
+		if err != nil || pending > 0 {
+			checks["migrations"] = checkFail
+			ready = false
+		}
+
+		if state.ShuttingDown() {
+			checks["shutdown"] = checkFail
+			ready = false
+		}
+
+		if !ready {
+			render.Status(r, http.StatusServiceUnavailable)
+			render.JSON(w, r, Response{
+				Response: response.Error("not ready"),
+				Checks:   checks,
+			})
+
+			return
+		}
+
+		render.JSON(w, r, Response{
+			Response: response.OK(),
+			Checks:   checks,
+		})
+	}
+}
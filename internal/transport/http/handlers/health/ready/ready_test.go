@@ -0,0 +1,103 @@
+package ready_test
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/dkhrunov/url-shortener/internal/transport/http/handlers/health/ready"
+	"github.com/dkhrunov/url-shortener/internal/transport/http/handlers/health/ready/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadyHandler(t *testing.T) {
+	cases := []struct {
+		name         string
+		pingError    error
+		pending      int
+		pendingError error
+		shuttingDown bool
+		checks       map[string]string
+		respError    string
+		status       int
+	}{
+		{
+			name:   "Ready",
+			checks: map[string]string{"database": "ok", "migrations": "ok", "shutdown": "ok"},
+			status: http.StatusOK,
+		},
+		{
+			name:      "Database down",
+			pingError: errors.New("database is locked"),
+			checks:    map[string]string{"database": "failing", "migrations": "ok", "shutdown": "ok"},
+			respError: "not ready",
+			status:    http.StatusServiceUnavailable,
+		},
+		{
+			name:      "Migrations pending",
+			pending:   2,
+			checks:    map[string]string{"database": "ok", "migrations": "failing", "shutdown": "ok"},
+			respError: "not ready",
+			status:    http.StatusServiceUnavailable,
+		},
+		{
+			name:         "Migrations unknown",
+			pendingError: errors.New("no such table: schema_migrations"),
+			checks:       map[string]string{"database": "ok", "migrations": "failing", "shutdown": "ok"},
+			respError:    "not ready",
+			status:       http.StatusServiceUnavailable,
+		},
+		{
+			name:         "Shutting down",
+			shuttingDown: true,
+			checks:       map[string]string{"database": "ok", "migrations": "ok", "shutdown": "failing"},
+			respError:    "not ready",
+			status:       http.StatusServiceUnavailable,
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			pingerMock := mocks.NewPinger(t)
+			pingerMock.EXPECT().
+				Ping(mock.Anything).
+				Return(tc.pingError).
+				Once()
+
+			schemaMock := mocks.NewSchemaChecker(t)
+			schemaMock.EXPECT().
+				PendingMigrations(mock.Anything).
+				Return(tc.pending, tc.pendingError).
+				Once()
+
+			stateMock := mocks.NewShutdownState(t)
+			stateMock.EXPECT().
+				ShuttingDown().
+				Return(tc.shuttingDown).
+				Once()
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodGet, "/readyz", nil)
+
+			handler := ready.New(pingerMock, schemaMock, stateMock)
+			handler.ServeHTTP(w, r)
+
+			assert.Equal(t, tc.status, w.Code)
+
+			var resp ready.Response
+
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+
+			assert.Equal(t, tc.respError, resp.Error)
+			assert.Equal(t, tc.checks, resp.Checks)
+		})
+	}
+}
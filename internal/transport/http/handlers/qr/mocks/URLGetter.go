@@ -0,0 +1,96 @@
+// Code generated by mockery v2.39.1. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+
+	storage "github.com/dkhrunov/url-shortener/internal/storage"
+)
+
+// URLGetter is an autogenerated mock type for the URLGetter type
+type URLGetter struct {
+	mock.Mock
+}
+
+type URLGetter_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *URLGetter) EXPECT() *URLGetter_Expecter {
+	return &URLGetter_Expecter{mock: &_m.Mock}
+}
+
+// GetURLByHost provides a mock function with given fields: ctx, host, alias
+func (_m *URLGetter) GetURLByHost(ctx context.Context, host string, alias string) (storage.URL, error) {
+	ret := _m.Called(ctx, host, alias)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetURLByHost")
+	}
+
+	var r0 storage.URL
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) (storage.URL, error)); ok {
+		return rf(ctx, host, alias)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) storage.URL); ok {
+		r0 = rf(ctx, host, alias)
+	} else {
+		r0 = ret.Get(0).(storage.URL)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, host, alias)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// URLGetter_GetURLByHost_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetURLByHost'
+type URLGetter_GetURLByHost_Call struct {
+	*mock.Call
+}
+
+// GetURLByHost is a helper method to define mock.On call
+//   - ctx context.Context
+//   - host string
+//   - alias string
+func (_e *URLGetter_Expecter) GetURLByHost(ctx interface{}, host interface{}, alias interface{}) *URLGetter_GetURLByHost_Call {
+	return &URLGetter_GetURLByHost_Call{Call: _e.mock.On("GetURLByHost", ctx, host, alias)}
+}
+
+func (_c *URLGetter_GetURLByHost_Call) Run(run func(ctx context.Context, host string, alias string)) *URLGetter_GetURLByHost_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(string))
+	})
+	return _c
+}
+
+func (_c *URLGetter_GetURLByHost_Call) Return(_a0 storage.URL, _a1 error) *URLGetter_GetURLByHost_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *URLGetter_GetURLByHost_Call) RunAndReturn(run func(context.Context, string, string) (storage.URL, error)) *URLGetter_GetURLByHost_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewURLGetter creates a new instance of URLGetter. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewURLGetter(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *URLGetter {
+	mock := &URLGetter{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,190 @@
+package qr
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/dkhrunov/url-shortener/internal/lib/hostname"
+	"github.com/dkhrunov/url-shortener/internal/lib/logger/slog/slogerr"
+	"github.com/dkhrunov/url-shortener/internal/lib/qrcode"
+	"github.com/dkhrunov/url-shortener/internal/lib/shorturl"
+	"github.com/dkhrunov/url-shortener/internal/storage"
+	"github.com/dkhrunov/url-shortener/internal/transport/http/common/response"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+)
+
+const (
+	defaultSize   = 256
+	minSize       = 64
+	maxSize       = 2048
+	defaultMargin = 4
+	maxMargin     = 16
+)
+
+var contentTypes = map[qrcode.Format]string{
+	qrcode.FormatPNG: "image/png",
+	qrcode.FormatSVG: "image/svg+xml",
+}
+
+type URLGetter interface {
+	// GetURLByHost resolves alias in the namespace of the domain the request was sent to.
+	GetURLByHost(ctx context.Context, host, alias string) (storage.URL, error)
+}
+
+func New(urlGetter URLGetter, shortURLs *shorturl.Builder) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.qr.New"
+
+		log := slog.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		alias := chi.URLParam(r, "alias")
+		if alias == "" {
+			log.InfoContext(r.Context(), "alias is empty")
+
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, response.Error("invalid request"))
+
+			return
+		}
+
+		format, opts, err := parseQuery(r)
+		if err != nil {
+			log.InfoContext(r.Context(), "invalid query", slogerr.Error(err))
+
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, response.Error(err.Error()))
+
+			return
+		}
+
+		link, err := urlGetter.GetURLByHost(r.Context(), hostname.Normalize(r.Host), alias)
+		if errors.Is(err, storage.ErrURLNotFound) {
+			log.InfoContext(r.Context(), "url not found", "alias", alias)
+
+			render.Status(r, http.StatusNotFound)
+			render.JSON(w, r, response.Error("not found"))
+
+			return
+		}
+		if errors.Is(err, storage.ErrURLGone) {
+			log.InfoContext(r.Context(), "url deleted", "alias", alias)
+
+			render.Status(r, http.StatusGone)
+			render.JSON(w, r, response.Error("gone"))
+
+			return
+		}
+		if status, resp, ok := response.Unavailable(err); ok {
+			log.WarnContext(r.Context(), "storage unavailable", slogerr.Error(err))
+
+			render.Status(r, status)
+			render.JSON(w, r, resp)
+
+			return
+		}
+		if err != nil {
+			log.ErrorContext(r.Context(), "failed to get url", slogerr.Error(err))
+
+			render.Status(r, http.StatusInternalServerError)
+			render.JSON(w, r, response.Error("failed to get url"))
+
+			return
+		}
+
+		// The code encodes the link on the domain it was resolved on.
+		host := r.URL.Query().Get("host")
+
+		shortURL, err := shortURLs.Build(r, link.Domain, host, alias)
+		if err != nil {
+			log.InfoContext(r.Context(), "host is not allowed", slog.String("host", host))
+
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, response.Error("host is not allowed"))
+
+			return
+		}
+
+		img, err := qrcode.Encode(shortURL, format, opts)
+		if errors.Is(err, qrcode.ErrSizeTooSmall) {
+			log.InfoContext(r.Context(), "size is too small", slog.Int("size", opts.Size))
+
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, response.Error("size is too small"))
+
+			return
+		}
+		if err != nil {
+			log.ErrorContext(r.Context(), "failed to encode qr code", slogerr.Error(err))
+
+			render.Status(r, http.StatusInternalServerError)
+			render.JSON(w, r, response.Error("failed to encode qr code"))
+
+			return
+		}
+
+		log.InfoContext(r.Context(), "qr code generated", slog.String("alias", alias), slog.String("format", string(format)))
+
+		w.Header().Set("Content-Type", contentTypes[format])
+		w.Header().Set("Cache-Control", "public, max-age=3600")
+		_, _ = w.Write(img)
+	}
+}
+
+// parseQuery reads the image format and encoding options from the query string.
+// The format can also be set by a file extension, e.g. /{alias}/qr.svg.
+func parseQuery(r *http.Request) (qrcode.Format, qrcode.Options, error) {
+	q := r.URL.Query()
+
+	opts := qrcode.Options{
+		Size:   defaultSize,
+		Level:  qrcode.LevelMedium,
+		Margin: defaultMargin,
+	}
+
+	format := qrcode.Format(q.Get("format"))
+	if format == "" {
+		if ext, ok := r.Context().Value(middleware.URLFormatCtxKey).(string); ok {
+			format = qrcode.Format(ext)
+		}
+	}
+	if format == "" {
+		format = qrcode.FormatPNG
+	}
+	if _, ok := contentTypes[format]; !ok {
+		return format, opts, errors.New("invalid format")
+	}
+
+	if v := q.Get("size"); v != "" {
+		size, err := strconv.Atoi(v)
+		if err != nil || size < minSize || size > maxSize {
+			return format, opts, errors.New("invalid size")
+		}
+		opts.Size = size
+	}
+
+	if v := q.Get("level"); v != "" {
+		level, err := qrcode.ParseLevel(v)
+		if err != nil {
+			return format, opts, errors.New("invalid level")
+		}
+		opts.Level = level
+	}
+
+	if v := q.Get("margin"); v != "" {
+		margin, err := strconv.Atoi(v)
+		if err != nil || margin < 0 || margin > maxMargin {
+			return format, opts, errors.New("invalid margin")
+		}
+		opts.Margin = margin
+	}
+
+	return format, opts, nil
+}
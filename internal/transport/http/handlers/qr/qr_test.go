@@ -0,0 +1,168 @@
+package qr_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"image/png"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/dkhrunov/url-shortener/internal/lib/shorturl"
+	"github.com/dkhrunov/url-shortener/internal/storage"
+	"github.com/dkhrunov/url-shortener/internal/transport/http/common/response"
+	"github.com/dkhrunov/url-shortener/internal/transport/http/handlers/qr"
+	"github.com/dkhrunov/url-shortener/internal/transport/http/handlers/qr/mocks"
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestQRHandler(t *testing.T) {
+	cases := []struct {
+		name        string
+		alias       string
+		query       string
+		respError   string
+		mockError   error
+		skipMock    bool
+		status      int
+		contentType string
+	}{
+		{
+			name:        "Success PNG",
+			alias:       "test_alias",
+			status:      http.StatusOK,
+			contentType: "image/png",
+		},
+		{
+			name:        "Success SVG",
+			alias:       "test_alias",
+			query:       "?format=svg&size=512&level=H&margin=0",
+			status:      http.StatusOK,
+			contentType: "image/svg+xml",
+		},
+		{
+			name:      "Empty alias",
+			alias:     "",
+			respError: "invalid request",
+			skipMock:  true,
+			status:    http.StatusBadRequest,
+		},
+		{
+			name:      "Invalid format",
+			alias:     "test_alias",
+			query:     "?format=gif",
+			respError: "invalid format",
+			skipMock:  true,
+			status:    http.StatusBadRequest,
+		},
+		{
+			name:      "Invalid size",
+			alias:     "test_alias",
+			query:     "?size=10000",
+			respError: "invalid size",
+			skipMock:  true,
+			status:    http.StatusBadRequest,
+		},
+		{
+			name:      "Invalid level",
+			alias:     "test_alias",
+			query:     "?level=X",
+			respError: "invalid level",
+			skipMock:  true,
+			status:    http.StatusBadRequest,
+		},
+		{
+			name:      "Invalid margin",
+			alias:     "test_alias",
+			query:     "?margin=-1",
+			respError: "invalid margin",
+			skipMock:  true,
+			status:    http.StatusBadRequest,
+		},
+		{
+			name:      "Host not allowed",
+			alias:     "test_alias",
+			query:     "?host=evil.com",
+			respError: "host is not allowed",
+			status:    http.StatusBadRequest,
+		},
+		{
+			name:      "Not found",
+			alias:     "test_alias",
+			respError: "not found",
+			mockError: storage.ErrURLNotFound,
+			status:    http.StatusNotFound,
+		},
+		{
+			name:      "Gone",
+			alias:     "test_alias",
+			respError: "gone",
+			mockError: storage.ErrURLGone,
+			status:    http.StatusGone,
+		},
+		{
+			name:      "Failed",
+			alias:     "test_alias",
+			respError: "failed to get url",
+			mockError: errors.New("unexpected error"),
+			status:    http.StatusInternalServerError,
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			urlGetterMock := mocks.NewURLGetter(t)
+
+			if !tc.skipMock {
+				urlGetterMock.EXPECT().
+					GetURLByHost(mock.Anything, "example.com", tc.alias).
+					Return(storage.URL{Alias: tc.alias, URL: "https://google.com"}, tc.mockError).
+					Once()
+			}
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodGet, "/{alias}/qr"+tc.query, nil)
+
+			rctx := chi.NewRouteContext()
+			rctx.URLParams.Add("alias", tc.alias)
+
+			r = r.WithContext(context.WithValue(r.Context(), chi.RouteCtxKey, rctx))
+
+			shortURLs, err := shorturl.New("https://sho.rt", nil)
+			require.NoError(t, err)
+
+			handler := qr.New(urlGetterMock, shortURLs)
+			handler.ServeHTTP(w, r)
+
+			assert.Equal(t, tc.status, w.Code)
+
+			if tc.respError != "" {
+				var resp response.Response
+
+				require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+
+				assert.Equal(t, tc.respError, resp.Error)
+
+				return
+			}
+
+			assert.Equal(t, tc.contentType, w.Header().Get("Content-Type"))
+
+			if tc.contentType == "image/png" {
+				img, err := png.Decode(bytes.NewReader(w.Body.Bytes()))
+				require.NoError(t, err)
+
+				assert.Equal(t, 256, img.Bounds().Dx())
+			}
+		})
+	}
+}
@@ -0,0 +1,82 @@
+// Code generated by mockery v2.39.1. DO NOT EDIT.
+
+package mocks
+
+import (
+	http "net/http"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// BotDetector is an autogenerated mock type for the BotDetector type
+type BotDetector struct {
+	mock.Mock
+}
+
+type BotDetector_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *BotDetector) EXPECT() *BotDetector_Expecter {
+	return &BotDetector_Expecter{mock: &_m.Mock}
+}
+
+// IsBot provides a mock function with given fields: r
+func (_m *BotDetector) IsBot(r *http.Request) bool {
+	ret := _m.Called(r)
+
+	if len(ret) == 0 {
+		panic("no return value specified for IsBot")
+	}
+
+	var r0 bool
+	if rf, ok := ret.Get(0).(func(*http.Request) bool); ok {
+		r0 = rf(r)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	return r0
+}
+
+// BotDetector_IsBot_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'IsBot'
+type BotDetector_IsBot_Call struct {
+	*mock.Call
+}
+
+// IsBot is a helper method to define mock.On call
+//   - r *http.Request
+func (_e *BotDetector_Expecter) IsBot(r interface{}) *BotDetector_IsBot_Call {
+	return &BotDetector_IsBot_Call{Call: _e.mock.On("IsBot", r)}
+}
+
+func (_c *BotDetector_IsBot_Call) Run(run func(r *http.Request)) *BotDetector_IsBot_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(*http.Request))
+	})
+	return _c
+}
+
+func (_c *BotDetector_IsBot_Call) Return(_a0 bool) *BotDetector_IsBot_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *BotDetector_IsBot_Call) RunAndReturn(run func(*http.Request) bool) *BotDetector_IsBot_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewBotDetector creates a new instance of BotDetector. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewBotDetector(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *BotDetector {
+	mock := &BotDetector{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,68 @@
+// Code generated by mockery v2.39.1. DO NOT EDIT.
+
+package mocks
+
+import (
+	clickhub "github.com/dkhrunov/url-shortener/internal/lib/clickhub"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// ClickPublisher is an autogenerated mock type for the ClickPublisher type
+type ClickPublisher struct {
+	mock.Mock
+}
+
+type ClickPublisher_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *ClickPublisher) EXPECT() *ClickPublisher_Expecter {
+	return &ClickPublisher_Expecter{mock: &_m.Mock}
+}
+
+// Publish provides a mock function with given fields: click
+func (_m *ClickPublisher) Publish(click clickhub.Click) {
+	_m.Called(click)
+}
+
+// ClickPublisher_Publish_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Publish'
+type ClickPublisher_Publish_Call struct {
+	*mock.Call
+}
+
+// Publish is a helper method to define mock.On call
+//   - click clickhub.Click
+func (_e *ClickPublisher_Expecter) Publish(click interface{}) *ClickPublisher_Publish_Call {
+	return &ClickPublisher_Publish_Call{Call: _e.mock.On("Publish", click)}
+}
+
+func (_c *ClickPublisher_Publish_Call) Run(run func(click clickhub.Click)) *ClickPublisher_Publish_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(clickhub.Click))
+	})
+	return _c
+}
+
+func (_c *ClickPublisher_Publish_Call) Return() *ClickPublisher_Publish_Call {
+	_c.Call.Return()
+	return _c
+}
+
+func (_c *ClickPublisher_Publish_Call) RunAndReturn(run func(clickhub.Click)) *ClickPublisher_Publish_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewClickPublisher creates a new instance of ClickPublisher. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewClickPublisher(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *ClickPublisher {
+	mock := &ClickPublisher{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
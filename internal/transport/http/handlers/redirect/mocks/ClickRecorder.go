@@ -0,0 +1,71 @@
+// Code generated by mockery v2.39.1. DO NOT EDIT.
+
+package mocks
+
+import (
+	http "net/http"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// ClickRecorder is an autogenerated mock type for the ClickRecorder type
+type ClickRecorder struct {
+	mock.Mock
+}
+
+type ClickRecorder_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *ClickRecorder) EXPECT() *ClickRecorder_Expecter {
+	return &ClickRecorder_Expecter{mock: &_m.Mock}
+}
+
+// Record provides a mock function with given fields: r, linkID, bot
+func (_m *ClickRecorder) Record(r *http.Request, linkID int64, bot bool) {
+	_m.Called(r, linkID, bot)
+}
+
+// ClickRecorder_Record_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Record'
+type ClickRecorder_Record_Call struct {
+	*mock.Call
+}
+
+// Record is a helper method to define mock.On call
+//   - r *http.Request
+//   - linkID int64
+//   - bot bool
+func (_e *ClickRecorder_Expecter) Record(r interface{}, linkID interface{}, bot interface{}) *ClickRecorder_Record_Call {
+	return &ClickRecorder_Record_Call{Call: _e.mock.On("Record", r, linkID, bot)}
+}
+
+func (_c *ClickRecorder_Record_Call) Run(run func(r *http.Request, linkID int64, bot bool)) *ClickRecorder_Record_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(*http.Request), args[1].(int64), args[2].(bool))
+	})
+	return _c
+}
+
+func (_c *ClickRecorder_Record_Call) Return() *ClickRecorder_Record_Call {
+	_c.Call.Return()
+	return _c
+}
+
+func (_c *ClickRecorder_Record_Call) RunAndReturn(run func(*http.Request, int64, bool)) *ClickRecorder_Record_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewClickRecorder creates a new instance of ClickRecorder. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewClickRecorder(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *ClickRecorder {
+	mock := &ClickRecorder{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
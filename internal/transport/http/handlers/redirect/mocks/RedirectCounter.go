@@ -0,0 +1,65 @@
+// Code generated by mockery v2.39.1. DO NOT EDIT.
+
+package mocks
+
+import mock "github.com/stretchr/testify/mock"
+
+// RedirectCounter is an autogenerated mock type for the RedirectCounter type
+type RedirectCounter struct {
+	mock.Mock
+}
+
+type RedirectCounter_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *RedirectCounter) EXPECT() *RedirectCounter_Expecter {
+	return &RedirectCounter_Expecter{mock: &_m.Mock}
+}
+
+// CountRedirect provides a mock function with given fields: hit
+func (_m *RedirectCounter) CountRedirect(hit bool) {
+	_m.Called(hit)
+}
+
+// RedirectCounter_CountRedirect_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CountRedirect'
+type RedirectCounter_CountRedirect_Call struct {
+	*mock.Call
+}
+
+// CountRedirect is a helper method to define mock.On call
+//   - hit bool
+func (_e *RedirectCounter_Expecter) CountRedirect(hit interface{}) *RedirectCounter_CountRedirect_Call {
+	return &RedirectCounter_CountRedirect_Call{Call: _e.mock.On("CountRedirect", hit)}
+}
+
+func (_c *RedirectCounter_CountRedirect_Call) Run(run func(hit bool)) *RedirectCounter_CountRedirect_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(bool))
+	})
+	return _c
+}
+
+func (_c *RedirectCounter_CountRedirect_Call) Return() *RedirectCounter_CountRedirect_Call {
+	_c.Call.Return()
+	return _c
+}
+
+func (_c *RedirectCounter_CountRedirect_Call) RunAndReturn(run func(bool)) *RedirectCounter_CountRedirect_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewRedirectCounter creates a new instance of RedirectCounter. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewRedirectCounter(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *RedirectCounter {
+	mock := &RedirectCounter{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
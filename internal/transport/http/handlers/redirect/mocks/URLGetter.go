@@ -2,7 +2,13 @@
 
 package mocks
 
-import mock "github.com/stretchr/testify/mock"
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+
+	storage "github.com/dkhrunov/url-shortener/internal/storage"
+)
 
 // URLGetter is an autogenerated mock type for the URLGetter type
 type URLGetter struct {
@@ -17,27 +23,27 @@ func (_m *URLGetter) EXPECT() *URLGetter_Expecter {
 	return &URLGetter_Expecter{mock: &_m.Mock}
 }
 
-// GetURL provides a mock function with given fields: alias
-func (_m *URLGetter) GetURL(alias string) (string, error) {
-	ret := _m.Called(alias)
+// GetURLByHost provides a mock function with given fields: ctx, host, alias
+func (_m *URLGetter) GetURLByHost(ctx context.Context, host string, alias string) (storage.URL, error) {
+	ret := _m.Called(ctx, host, alias)
 
 	if len(ret) == 0 {
-		panic("no return value specified for GetURL")
+		panic("no return value specified for GetURLByHost")
 	}
 
-	var r0 string
+	var r0 storage.URL
 	var r1 error
-	if rf, ok := ret.Get(0).(func(string) (string, error)); ok {
-		return rf(alias)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) (storage.URL, error)); ok {
+		return rf(ctx, host, alias)
 	}
-	if rf, ok := ret.Get(0).(func(string) string); ok {
-		r0 = rf(alias)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) storage.URL); ok {
+		r0 = rf(ctx, host, alias)
 	} else {
-		r0 = ret.Get(0).(string)
+		r0 = ret.Get(0).(storage.URL)
 	}
 
-	if rf, ok := ret.Get(1).(func(string) error); ok {
-		r1 = rf(alias)
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, host, alias)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -45,30 +51,32 @@ func (_m *URLGetter) GetURL(alias string) (string, error) {
 	return r0, r1
 }
 
-// URLGetter_GetURL_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetURL'
-type URLGetter_GetURL_Call struct {
+// URLGetter_GetURLByHost_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetURLByHost'
+type URLGetter_GetURLByHost_Call struct {
 	*mock.Call
 }
 
-// GetURL is a helper method to define mock.On call
+// GetURLByHost is a helper method to define mock.On call
+//   - ctx context.Context
+//   - host string
 //   - alias string
-func (_e *URLGetter_Expecter) GetURL(alias interface{}) *URLGetter_GetURL_Call {
-	return &URLGetter_GetURL_Call{Call: _e.mock.On("GetURL", alias)}
+func (_e *URLGetter_Expecter) GetURLByHost(ctx interface{}, host interface{}, alias interface{}) *URLGetter_GetURLByHost_Call {
+	return &URLGetter_GetURLByHost_Call{Call: _e.mock.On("GetURLByHost", ctx, host, alias)}
 }
 
-func (_c *URLGetter_GetURL_Call) Run(run func(alias string)) *URLGetter_GetURL_Call {
+func (_c *URLGetter_GetURLByHost_Call) Run(run func(ctx context.Context, host string, alias string)) *URLGetter_GetURLByHost_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(string))
+		run(args[0].(context.Context), args[1].(string), args[2].(string))
 	})
 	return _c
 }
 
-func (_c *URLGetter_GetURL_Call) Return(_a0 string, _a1 error) *URLGetter_GetURL_Call {
+func (_c *URLGetter_GetURLByHost_Call) Return(_a0 storage.URL, _a1 error) *URLGetter_GetURLByHost_Call {
 	_c.Call.Return(_a0, _a1)
 	return _c
 }
 
-func (_c *URLGetter_GetURL_Call) RunAndReturn(run func(string) (string, error)) *URLGetter_GetURL_Call {
+func (_c *URLGetter_GetURLByHost_Call) RunAndReturn(run func(context.Context, string, string) (storage.URL, error)) *URLGetter_GetURLByHost_Call {
 	_c.Call.Return(run)
 	return _c
 }
@@ -1,11 +1,16 @@
 package redirect
 
 import (
+	"context"
 	"errors"
 	"log/slog"
 	"net/http"
+	"time"
 
+	"github.com/dkhrunov/url-shortener/internal/lib/clickhub"
+	"github.com/dkhrunov/url-shortener/internal/lib/hostname"
 	"github.com/dkhrunov/url-shortener/internal/lib/logger/slog/slogerr"
+	"github.com/dkhrunov/url-shortener/internal/lib/webhook"
 	"github.com/dkhrunov/url-shortener/internal/storage"
 	"github.com/dkhrunov/url-shortener/internal/transport/http/common/response"
 	"github.com/go-chi/chi/v5"
@@ -14,10 +19,31 @@ import (
 )
 
 type URLGetter interface {
-	GetURL(alias string) (string, error)
+	// GetURLByHost resolves alias in the namespace of the domain the request was sent to.
+	GetURLByHost(ctx context.Context, host, alias string) (storage.URL, error)
 }
 
-func New(urlGetter URLGetter) http.HandlerFunc {
+type RedirectCounter interface {
+	CountRedirect(hit bool)
+}
+
+type Notifier interface {
+	Notify(ctx context.Context, e webhook.Event)
+}
+
+type ClickPublisher interface {
+	Publish(click clickhub.Click)
+}
+
+type ClickRecorder interface {
+	Record(r *http.Request, linkID int64, bot bool)
+}
+
+type BotDetector interface {
+	IsBot(r *http.Request) bool
+}
+
+func New(urlGetter URLGetter, redirectCounter RedirectCounter, notifier Notifier, clickPublisher ClickPublisher, clickRecorder ClickRecorder, botDetector BotDetector) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		const op = "handler.redirect.New"
 
@@ -28,7 +54,7 @@ func New(urlGetter URLGetter) http.HandlerFunc {
 
 		alias := chi.URLParam(r, "alias")
 		if alias == "" {
-			log.Info("alias is empty")
+			log.InfoContext(r.Context(), "alias is empty")
 
 			render.Status(r, http.StatusBadRequest)
 			render.JSON(w, r, response.Error("invalid request"))
@@ -36,17 +62,39 @@ func New(urlGetter URLGetter) http.HandlerFunc {
 			return
 		}
 
-		resURL, err := urlGetter.GetURL(alias)
+		host := hostname.Normalize(r.Host)
+
+		link, err := urlGetter.GetURLByHost(r.Context(), host, alias)
 		if errors.Is(err, storage.ErrURLNotFound) {
-			log.Info("url not found", "alias", alias)
+			log.InfoContext(r.Context(), "url not found", "alias", alias)
+
+			redirectCounter.CountRedirect(false)
 
 			render.Status(r, http.StatusNotFound)
 			render.JSON(w, r, response.Error("not found"))
 
 			return
 		}
+		if errors.Is(err, storage.ErrURLGone) {
+			log.InfoContext(r.Context(), "url deleted", "alias", alias)
+
+			redirectCounter.CountRedirect(false)
+
+			render.Status(r, http.StatusGone)
+			render.JSON(w, r, response.Error("gone"))
+
+			return
+		}
+		if status, resp, ok := response.Unavailable(err); ok {
+			log.WarnContext(r.Context(), "storage unavailable", slogerr.Error(err))
+
+			render.Status(r, status)
+			render.JSON(w, r, resp)
+
+			return
+		}
 		if err != nil {
-			log.Error("failed to get url", slogerr.Error(err))
+			log.ErrorContext(r.Context(), "failed to get url", slogerr.Error(err))
 
 			render.Status(r, http.StatusInternalServerError)
 			render.JSON(w, r, response.Error("failed to get url"))
@@ -54,9 +102,31 @@ func New(urlGetter URLGetter) http.HandlerFunc {
 			return
 		}
 
-		log.Info("got url", slog.String("url", resURL))
+		log.InfoContext(r.Context(), "got url", slog.String("url", link.URL))
+
+		redirectCounter.CountRedirect(true)
+
+		bot := botDetector.IsBot(r)
+
+		clickRecorder.Record(r, link.ID, bot)
+
+		clickPublisher.Publish(clickhub.Click{
+			Domain:    link.Domain,
+			Alias:     alias,
+			URL:       link.URL,
+			Host:      host,
+			Referrer:  r.Referer(),
+			UserAgent: r.UserAgent(),
+			Bot:       bot,
+			At:        time.Now().UTC(),
+		})
+
+		notifier.Notify(r.Context(), webhook.Event{
+			Type: webhook.EventLinkClicked,
+			Link: webhook.Link{Domain: link.Domain, Host: host, Alias: alias, URL: link.URL},
+		})
 
 		// redirect to URL
-		http.Redirect(w, r, resURL, http.StatusFound)
+		http.Redirect(w, r, link.URL, http.StatusFound)
 	}
 }
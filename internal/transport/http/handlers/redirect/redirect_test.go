@@ -4,34 +4,59 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"testing"
 
+	"github.com/dkhrunov/url-shortener/internal/lib/clickhub"
+	"github.com/dkhrunov/url-shortener/internal/lib/hostname"
+	"github.com/dkhrunov/url-shortener/internal/lib/webhook"
 	"github.com/dkhrunov/url-shortener/internal/storage"
 	"github.com/dkhrunov/url-shortener/internal/transport/http/common/response"
 	"github.com/dkhrunov/url-shortener/internal/transport/http/handlers/redirect"
 	"github.com/dkhrunov/url-shortener/internal/transport/http/handlers/redirect/mocks"
 	"github.com/go-chi/chi/v5"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 )
 
 func TestRedirectHandler(t *testing.T) {
 	cases := []struct {
 		name      string
+		host      string
+		domain    string
 		alias     string
 		url       string
+		bot       bool
 		respError string
 		mockError error
 		status    int
 	}{
 		{
 			name:   "Success",
+			host:   "sho.rt",
 			alias:  "test_alias",
 			url:    "https://google.com",
 			status: http.StatusFound,
 		},
+		{
+			name:   "Domain",
+			host:   "Brand.example:8080",
+			domain: "brand.example",
+			alias:  "test_alias",
+			url:    "https://google.com",
+			status: http.StatusFound,
+		},
+		{
+			name:   "Bot",
+			host:   "sho.rt",
+			alias:  "test_alias",
+			url:    "https://google.com",
+			bot:    true,
+			status: http.StatusFound,
+		},
 		{
 			name:      "Empty alias",
 			alias:     "",
@@ -45,6 +70,13 @@ func TestRedirectHandler(t *testing.T) {
 			mockError: storage.ErrURLNotFound,
 			status:    http.StatusNotFound,
 		},
+		{
+			name:      "Gone",
+			alias:     "test_alias",
+			respError: "gone",
+			mockError: storage.ErrURLGone,
+			status:    http.StatusGone,
+		},
 		{
 			name:      "Failed",
 			alias:     "test_alias",
@@ -52,6 +84,20 @@ func TestRedirectHandler(t *testing.T) {
 			mockError: errors.New("unexpected error"),
 			status:    http.StatusInternalServerError,
 		},
+		{
+			name:      "Timed out",
+			alias:     "test_alias",
+			respError: "storage timed out",
+			mockError: fmt.Errorf("storage.sqlite.GetURLByHost: %w", context.DeadlineExceeded),
+			status:    http.StatusGatewayTimeout,
+		},
+		{
+			name:      "Canceled",
+			alias:     "test_alias",
+			respError: "request canceled",
+			mockError: fmt.Errorf("storage.sqlite.GetURLByHost: %w", context.Canceled),
+			status:    http.StatusServiceUnavailable,
+		},
 	}
 
 	for _, tc := range cases {
@@ -61,23 +107,56 @@ func TestRedirectHandler(t *testing.T) {
 			t.Parallel()
 
 			urlGetterMock := mocks.NewURLGetter(t)
+			redirectCounterMock := mocks.NewRedirectCounter(t)
+			notifierMock := mocks.NewNotifier(t)
+			clickPublisherMock := mocks.NewClickPublisher(t)
+			clickRecorderMock := mocks.NewClickRecorder(t)
+			botDetectorMock := mocks.NewBotDetector(t)
 
 			if tc.respError == "" || tc.mockError != nil {
 				urlGetterMock.EXPECT().
-					GetURL(tc.alias).
-					Return(tc.url, tc.mockError).
+					GetURLByHost(mock.Anything, hostname.Normalize(tc.host), tc.alias).
+					Return(storage.URL{ID: 42, Domain: tc.domain, Alias: tc.alias, URL: tc.url}, tc.mockError).
+					Once()
+			}
+
+			switch tc.status {
+			case http.StatusFound:
+				redirectCounterMock.EXPECT().CountRedirect(true).Once()
+
+				botDetectorMock.EXPECT().IsBot(mock.Anything).Return(tc.bot).Once()
+
+				clickRecorderMock.EXPECT().Record(mock.Anything, int64(42), tc.bot).Once()
+
+				clickPublisherMock.EXPECT().
+					Publish(mock.MatchedBy(func(c clickhub.Click) bool {
+						return c.Domain == tc.domain && c.Alias == tc.alias && c.URL == tc.url &&
+							c.Host == hostname.Normalize(tc.host) && c.Referrer == "https://news.example/" &&
+							c.Bot == tc.bot && !c.At.IsZero()
+					})).
+					Once()
+
+				notifierMock.EXPECT().
+					Notify(mock.Anything, webhook.Event{
+						Type: webhook.EventLinkClicked,
+						Link: webhook.Link{Domain: tc.domain, Host: hostname.Normalize(tc.host), Alias: tc.alias, URL: tc.url},
+					}).
 					Once()
+			case http.StatusNotFound, http.StatusGone:
+				redirectCounterMock.EXPECT().CountRedirect(false).Once()
 			}
 
 			w := httptest.NewRecorder()
 			r := httptest.NewRequest(http.MethodDelete, "/{alias}", nil)
+			r.Host = tc.host
+			r.Header.Set("Referer", "https://news.example/")
 
 			rctx := chi.NewRouteContext()
 			rctx.URLParams.Add("alias", tc.alias)
 
 			r = r.WithContext(context.WithValue(r.Context(), chi.RouteCtxKey, rctx))
 
-			handler := redirect.New(urlGetterMock)
+			handler := redirect.New(urlGetterMock, redirectCounterMock, notifierMock, clickPublisherMock, clickRecorderMock, botDetectorMock)
 			handler.ServeHTTP(w, r)
 
 			if tc.respError != "" {
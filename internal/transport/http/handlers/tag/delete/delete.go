@@ -0,0 +1,127 @@
+package delete
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+
+	"github.com/dkhrunov/url-shortener/internal/auth"
+	"github.com/dkhrunov/url-shortener/internal/lib/audit"
+	"github.com/dkhrunov/url-shortener/internal/lib/logger/slog/slogerr"
+	"github.com/dkhrunov/url-shortener/internal/lib/tags"
+	"github.com/dkhrunov/url-shortener/internal/lib/webhook"
+	"github.com/dkhrunov/url-shortener/internal/storage"
+	"github.com/dkhrunov/url-shortener/internal/transport/http/common/response"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+)
+
+type Response struct {
+	response.Response
+	Deleted int64 `json:"deleted"`
+}
+
+type URLDeleter interface {
+	DeleteURLsByTag(ctx context.Context, tag string, ownerID int64) ([]storage.URL, error)
+}
+
+type LinkCounter interface {
+	CountLinksDeleted(n int64)
+}
+
+type Auditor interface {
+	RecordAll(r *http.Request, events []audit.Event)
+}
+
+type Notifier interface {
+	Notify(ctx context.Context, e webhook.Event)
+}
+
+// New returns the handler soft deleting all links with a tag. Users only delete their
+// own links. Every link deleted is audited and notified to webhooks.
+func New(urlDeleter URLDeleter, linkCounter LinkCounter, auditor Auditor, notifier Notifier) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.tag.delete.New"
+
+		log := slog.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		identity, ok := auth.FromContext(r.Context())
+		if !ok {
+			log.ErrorContext(r.Context(), "request is not authenticated")
+
+			render.Status(r, http.StatusUnauthorized)
+			render.JSON(w, r, response.Error("unauthorized"))
+
+			return
+		}
+
+		tag := tags.Normalize(chi.URLParam(r, "tag"))
+		if !tags.Valid(tag) {
+			log.InfoContext(r.Context(), "invalid tag", slog.String("tag", tag))
+
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, response.Error("invalid tag"))
+
+			return
+		}
+
+		links, err := urlDeleter.DeleteURLsByTag(r.Context(), tag, identity.OwnerFilter())
+		if status, resp, ok := response.Unavailable(err); ok {
+			log.WarnContext(r.Context(), "storage unavailable", slogerr.Error(err))
+
+			render.Status(r, status)
+			render.JSON(w, r, resp)
+
+			return
+		}
+		if err != nil {
+			log.ErrorContext(r.Context(), "failed to delete urls", slogerr.Error(err))
+
+			render.Status(r, http.StatusInternalServerError)
+			render.JSON(w, r, response.Error("failed to delete urls"))
+
+			return
+		}
+		if len(links) == 0 {
+			log.InfoContext(r.Context(), "no urls with tag", slog.String("tag", tag))
+
+			render.Status(r, http.StatusNotFound)
+			render.JSON(w, r, response.Error("not found"))
+
+			return
+		}
+
+		deleted := int64(len(links))
+
+		log.InfoContext(r.Context(), "urls deleted", slog.String("tag", tag), slog.Int64("deleted", deleted))
+
+		linkCounter.CountLinksDeleted(deleted)
+
+		events := make([]audit.Event, 0, len(links))
+		for _, link := range links {
+			events = append(events, audit.Event{
+				Action: audit.ActionDelete,
+				Domain: link.Domain,
+				Alias:  link.Alias,
+				Before: audit.LinkOf(link),
+			})
+		}
+		auditor.RecordAll(r, events)
+
+		for _, link := range links {
+			notifier.Notify(r.Context(), webhook.Event{
+				Type: webhook.EventLinkDeleted,
+				Link: webhook.Link{Domain: link.Domain, Alias: link.Alias, URL: link.URL, Tags: link.Tags},
+			})
+		}
+
+		render.JSON(w, r, Response{
+			Response: response.OK(),
+			Deleted:  deleted,
+		})
+	}
+}
@@ -0,0 +1,152 @@
+package delete_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/dkhrunov/url-shortener/internal/auth"
+	"github.com/dkhrunov/url-shortener/internal/lib/audit"
+	"github.com/dkhrunov/url-shortener/internal/lib/webhook"
+	"github.com/dkhrunov/url-shortener/internal/storage"
+	"github.com/dkhrunov/url-shortener/internal/transport/http/handlers/tag/delete"
+	"github.com/dkhrunov/url-shortener/internal/transport/http/handlers/tag/delete/mocks"
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeleteHandler(t *testing.T) {
+	links := []storage.URL{
+		{ID: 1, Alias: "go", URL: "https://go.dev", OwnerID: 42, Tags: []string{"spring-sale"}},
+		{ID: 2, Domain: "sho.rt", Alias: "pkg", URL: "https://pkg.go.dev", OwnerID: 42, Tags: []string{"docs", "spring-sale"}},
+	}
+
+	cases := []struct {
+		name      string
+		tag       string
+		wantTag   string
+		role      auth.Role
+		ownerID   int64
+		links     []storage.URL
+		respError string
+		mockError error
+		status    int
+	}{
+		{
+			name:    "Success",
+			tag:     "Spring-Sale",
+			wantTag: "spring-sale",
+			ownerID: 42,
+			links:   links,
+			status:  http.StatusOK,
+		},
+		{
+			name:    "Admin deletes all",
+			tag:     "spring-sale",
+			wantTag: "spring-sale",
+			role:    auth.RoleAdmin,
+			links:   links[1:],
+			status:  http.StatusOK,
+		},
+		{
+			name:      "No links",
+			tag:       "spring-sale",
+			wantTag:   "spring-sale",
+			ownerID:   42,
+			respError: "not found",
+			status:    http.StatusNotFound,
+		},
+		{
+			name:      "Invalid tag",
+			tag:       "spring sale",
+			respError: "invalid tag",
+			status:    http.StatusBadRequest,
+		},
+		{
+			name:      "Failed",
+			tag:       "spring-sale",
+			wantTag:   "spring-sale",
+			ownerID:   42,
+			respError: "failed to delete urls",
+			mockError: errors.New("unexpected error"),
+			status:    http.StatusInternalServerError,
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			urlDeleterMock := mocks.NewURLDeleter(t)
+			linkCounterMock := mocks.NewLinkCounter(t)
+			auditorMock := mocks.NewAuditor(t)
+			notifierMock := mocks.NewNotifier(t)
+
+			if tc.wantTag != "" {
+				urlDeleterMock.EXPECT().
+					DeleteURLsByTag(mock.Anything, tc.wantTag, tc.ownerID).
+					Return(tc.links, tc.mockError).
+					Once()
+			}
+
+			if tc.status == http.StatusOK {
+				linkCounterMock.EXPECT().CountLinksDeleted(int64(len(tc.links))).Once()
+
+				var events []audit.Event
+				for _, link := range tc.links {
+					events = append(events, audit.Event{
+						Action: audit.ActionDelete,
+						Domain: link.Domain,
+						Alias:  link.Alias,
+						Before: audit.Link{URL: link.URL, Tags: link.Tags},
+					})
+				}
+				auditorMock.EXPECT().RecordAll(mock.Anything, events).Once()
+
+				for _, link := range tc.links {
+					notifierMock.EXPECT().
+						Notify(mock.Anything, webhook.Event{
+							Type: webhook.EventLinkDeleted,
+							Link: webhook.Link{Domain: link.Domain, Alias: link.Alias, URL: link.URL, Tags: link.Tags},
+						}).
+						Once()
+				}
+			}
+
+			role := tc.role
+			if role == "" {
+				role = auth.RoleEditor
+			}
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodPost, "/url/tags/{tag}/delete", nil)
+
+			rctx := chi.NewRouteContext()
+			rctx.URLParams.Add("tag", tc.tag)
+
+			ctx := context.WithValue(r.Context(), chi.RouteCtxKey, rctx)
+			r = r.WithContext(auth.WithIdentity(ctx, auth.Identity{UserID: 42, Role: role}))
+
+			handler := delete.New(urlDeleterMock, linkCounterMock, auditorMock, notifierMock)
+			handler.ServeHTTP(w, r)
+
+			assert.Equal(t, tc.status, w.Code)
+
+			var resp delete.Response
+
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+
+			assert.Equal(t, tc.respError, resp.Error)
+			if tc.status == http.StatusOK {
+				assert.Equal(t, int64(len(tc.links)), resp.Deleted)
+			}
+		})
+	}
+}
@@ -0,0 +1,65 @@
+// Code generated by mockery v2.39.1. DO NOT EDIT.
+
+package mocks
+
+import mock "github.com/stretchr/testify/mock"
+
+// LinkCounter is an autogenerated mock type for the LinkCounter type
+type LinkCounter struct {
+	mock.Mock
+}
+
+type LinkCounter_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *LinkCounter) EXPECT() *LinkCounter_Expecter {
+	return &LinkCounter_Expecter{mock: &_m.Mock}
+}
+
+// CountLinksDeleted provides a mock function with given fields: n
+func (_m *LinkCounter) CountLinksDeleted(n int64) {
+	_m.Called(n)
+}
+
+// LinkCounter_CountLinksDeleted_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CountLinksDeleted'
+type LinkCounter_CountLinksDeleted_Call struct {
+	*mock.Call
+}
+
+// CountLinksDeleted is a helper method to define mock.On call
+//   - n int64
+func (_e *LinkCounter_Expecter) CountLinksDeleted(n interface{}) *LinkCounter_CountLinksDeleted_Call {
+	return &LinkCounter_CountLinksDeleted_Call{Call: _e.mock.On("CountLinksDeleted", n)}
+}
+
+func (_c *LinkCounter_CountLinksDeleted_Call) Run(run func(n int64)) *LinkCounter_CountLinksDeleted_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(int64))
+	})
+	return _c
+}
+
+func (_c *LinkCounter_CountLinksDeleted_Call) Return() *LinkCounter_CountLinksDeleted_Call {
+	_c.Call.Return()
+	return _c
+}
+
+func (_c *LinkCounter_CountLinksDeleted_Call) RunAndReturn(run func(int64)) *LinkCounter_CountLinksDeleted_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewLinkCounter creates a new instance of LinkCounter. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewLinkCounter(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *LinkCounter {
+	mock := &LinkCounter{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,98 @@
+// Code generated by mockery v2.39.1. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+
+	storage "github.com/dkhrunov/url-shortener/internal/storage"
+)
+
+// URLDeleter is an autogenerated mock type for the URLDeleter type
+type URLDeleter struct {
+	mock.Mock
+}
+
+type URLDeleter_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *URLDeleter) EXPECT() *URLDeleter_Expecter {
+	return &URLDeleter_Expecter{mock: &_m.Mock}
+}
+
+// DeleteURLsByTag provides a mock function with given fields: ctx, tag, ownerID
+func (_m *URLDeleter) DeleteURLsByTag(ctx context.Context, tag string, ownerID int64) ([]storage.URL, error) {
+	ret := _m.Called(ctx, tag, ownerID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteURLsByTag")
+	}
+
+	var r0 []storage.URL
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, int64) ([]storage.URL, error)); ok {
+		return rf(ctx, tag, ownerID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, int64) []storage.URL); ok {
+		r0 = rf(ctx, tag, ownerID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]storage.URL)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, int64) error); ok {
+		r1 = rf(ctx, tag, ownerID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// URLDeleter_DeleteURLsByTag_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteURLsByTag'
+type URLDeleter_DeleteURLsByTag_Call struct {
+	*mock.Call
+}
+
+// DeleteURLsByTag is a helper method to define mock.On call
+//   - ctx context.Context
+//   - tag string
+//   - ownerID int64
+func (_e *URLDeleter_Expecter) DeleteURLsByTag(ctx interface{}, tag interface{}, ownerID interface{}) *URLDeleter_DeleteURLsByTag_Call {
+	return &URLDeleter_DeleteURLsByTag_Call{Call: _e.mock.On("DeleteURLsByTag", ctx, tag, ownerID)}
+}
+
+func (_c *URLDeleter_DeleteURLsByTag_Call) Run(run func(ctx context.Context, tag string, ownerID int64)) *URLDeleter_DeleteURLsByTag_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(int64))
+	})
+	return _c
+}
+
+func (_c *URLDeleter_DeleteURLsByTag_Call) Return(_a0 []storage.URL, _a1 error) *URLDeleter_DeleteURLsByTag_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *URLDeleter_DeleteURLsByTag_Call) RunAndReturn(run func(context.Context, string, int64) ([]storage.URL, error)) *URLDeleter_DeleteURLsByTag_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewURLDeleter creates a new instance of URLDeleter. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewURLDeleter(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *URLDeleter {
+	mock := &URLDeleter{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,145 @@
+package expire
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/dkhrunov/url-shortener/internal/auth"
+	"github.com/dkhrunov/url-shortener/internal/lib/audit"
+	"github.com/dkhrunov/url-shortener/internal/lib/logger/slog/slogerr"
+	"github.com/dkhrunov/url-shortener/internal/lib/tags"
+	"github.com/dkhrunov/url-shortener/internal/storage"
+	"github.com/dkhrunov/url-shortener/internal/transport/http/common/response"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+)
+
+// Request sets when the links expire; without a body or expires_at they expire now.
+type Request struct {
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+}
+
+type Response struct {
+	response.Response
+	Expired   int64     `json:"expired"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+type URLExpirer interface {
+	ExpireURLsByTag(ctx context.Context, tag string, ownerID int64, at time.Time) ([]storage.URL, error)
+}
+
+type Auditor interface {
+	RecordAll(r *http.Request, events []audit.Event)
+}
+
+// New returns the handler expiring all links with a tag, so they stop resolving.
+// Users only expire their own links.
+func New(urlExpirer URLExpirer, auditor Auditor) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.tag.expire.New"
+
+		log := slog.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		identity, ok := auth.FromContext(r.Context())
+		if !ok {
+			log.ErrorContext(r.Context(), "request is not authenticated")
+
+			render.Status(r, http.StatusUnauthorized)
+			render.JSON(w, r, response.Error("unauthorized"))
+
+			return
+		}
+
+		tag := tags.Normalize(chi.URLParam(r, "tag"))
+		if !tags.Valid(tag) {
+			log.InfoContext(r.Context(), "invalid tag", slog.String("tag", tag))
+
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, response.Error("invalid tag"))
+
+			return
+		}
+
+		var req Request
+
+		err := render.DecodeJSON(r.Body, &req)
+		if err != nil && !errors.Is(err, io.EOF) {
+			log.ErrorContext(r.Context(), "failed to decode request body", slogerr.Error(err))
+
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, response.Error("failed to decode request"))
+
+			return
+		}
+
+		// Expiry is kept with a precision of seconds.
+		at := time.Now().UTC().Truncate(time.Second)
+		if req.ExpiresAt != nil {
+			at = req.ExpiresAt.UTC().Truncate(time.Second)
+		}
+
+		links, err := urlExpirer.ExpireURLsByTag(r.Context(), tag, identity.OwnerFilter(), at)
+		if status, resp, ok := response.Unavailable(err); ok {
+			log.WarnContext(r.Context(), "storage unavailable", slogerr.Error(err))
+
+			render.Status(r, status)
+			render.JSON(w, r, resp)
+
+			return
+		}
+		if err != nil {
+			log.ErrorContext(r.Context(), "failed to expire urls", slogerr.Error(err))
+
+			render.Status(r, http.StatusInternalServerError)
+			render.JSON(w, r, response.Error("failed to expire urls"))
+
+			return
+		}
+		if len(links) == 0 {
+			log.InfoContext(r.Context(), "no urls with tag", slog.String("tag", tag))
+
+			render.Status(r, http.StatusNotFound)
+			render.JSON(w, r, response.Error("not found"))
+
+			return
+		}
+
+		expired := int64(len(links))
+
+		log.InfoContext(r.Context(), "urls expired",
+			slog.String("tag", tag),
+			slog.Int64("expired", expired),
+			slog.Time("expires_at", at),
+		)
+
+		events := make([]audit.Event, 0, len(links))
+		for _, link := range links {
+			after := audit.LinkOf(link)
+			after.ExpiresAt = &at
+
+			events = append(events, audit.Event{
+				Action: audit.ActionUpdate,
+				Domain: link.Domain,
+				Alias:  link.Alias,
+				Before: audit.LinkOf(link),
+				After:  after,
+			})
+		}
+		auditor.RecordAll(r, events)
+
+		render.JSON(w, r, Response{
+			Response:  response.OK(),
+			Expired:   expired,
+			ExpiresAt: at,
+		})
+	}
+}
@@ -0,0 +1,158 @@
+package expire_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dkhrunov/url-shortener/internal/auth"
+	"github.com/dkhrunov/url-shortener/internal/lib/audit"
+	"github.com/dkhrunov/url-shortener/internal/storage"
+	"github.com/dkhrunov/url-shortener/internal/transport/http/handlers/tag/expire"
+	"github.com/dkhrunov/url-shortener/internal/transport/http/handlers/tag/expire/mocks"
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExpireHandler(t *testing.T) {
+	at := time.Date(2026, 11, 1, 12, 0, 0, 0, time.UTC)
+	later := at.Add(24 * time.Hour)
+
+	links := []storage.URL{
+		{ID: 1, Alias: "go", URL: "https://go.dev", Tags: []string{"spring-sale"}},
+		{ID: 2, Domain: "sho.rt", Alias: "pkg", URL: "https://pkg.go.dev", ExpiresAt: &later, Tags: []string{"spring-sale"}},
+	}
+
+	cases := []struct {
+		name      string
+		tag       string
+		body      string
+		at        time.Time
+		links     []storage.URL
+		respError string
+		mockError error
+		status    int
+	}{
+		{
+			name:   "At given time",
+			tag:    "spring-sale",
+			body:   `{"expires_at": "2026-11-01T14:00:00.5+02:00"}`,
+			at:     at,
+			links:  links,
+			status: http.StatusOK,
+		},
+		{
+			name:   "Now without body",
+			tag:    "spring-sale",
+			links:  links[:1],
+			status: http.StatusOK,
+		},
+		{
+			name:      "No links",
+			tag:       "spring-sale",
+			at:        at,
+			body:      `{"expires_at": "2026-11-01T12:00:00Z"}`,
+			respError: "not found",
+			status:    http.StatusNotFound,
+		},
+		{
+			name:      "Invalid tag",
+			tag:       "spring/sale",
+			respError: "invalid tag",
+			status:    http.StatusBadRequest,
+		},
+		{
+			name:      "Invalid time",
+			tag:       "spring-sale",
+			body:      `{"expires_at": "tomorrow"}`,
+			respError: "failed to decode request",
+			status:    http.StatusBadRequest,
+		},
+		{
+			name:      "Failed",
+			tag:       "spring-sale",
+			respError: "failed to expire urls",
+			mockError: errors.New("unexpected error"),
+			status:    http.StatusInternalServerError,
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			urlExpirerMock := mocks.NewURLExpirer(t)
+			auditorMock := mocks.NewAuditor(t)
+
+			if tc.status != http.StatusBadRequest {
+				var when any = tc.at
+				if tc.at.IsZero() {
+					when = mock.MatchedBy(func(at time.Time) bool {
+						return time.Since(at) < time.Minute
+					})
+				}
+
+				urlExpirerMock.EXPECT().
+					ExpireURLsByTag(mock.Anything, tc.tag, int64(42), when).
+					Return(tc.links, tc.mockError).
+					Once()
+			}
+
+			if tc.status == http.StatusOK {
+				auditorMock.EXPECT().
+					RecordAll(mock.Anything, mock.MatchedBy(func(events []audit.Event) bool {
+						if len(events) != len(tc.links) {
+							return false
+						}
+
+						for i, e := range events {
+							before, after := e.Before.(audit.Link), e.After.(audit.Link)
+
+							if e.Action != audit.ActionUpdate || e.Domain != tc.links[i].Domain || e.Alias != tc.links[i].Alias ||
+								before.ExpiresAt != tc.links[i].ExpiresAt || after.ExpiresAt == nil || after.URL != tc.links[i].URL {
+								return false
+							}
+							if !tc.at.IsZero() && !after.ExpiresAt.Equal(tc.at) {
+								return false
+							}
+						}
+
+						return true
+					})).
+					Once()
+			}
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodPost, "/url/tags/{tag}/expire", strings.NewReader(tc.body))
+
+			rctx := chi.NewRouteContext()
+			rctx.URLParams.Add("tag", tc.tag)
+
+			ctx := context.WithValue(r.Context(), chi.RouteCtxKey, rctx)
+			r = r.WithContext(auth.WithIdentity(ctx, auth.Identity{UserID: 42, Role: auth.RoleEditor}))
+
+			handler := expire.New(urlExpirerMock, auditorMock)
+			handler.ServeHTTP(w, r)
+
+			assert.Equal(t, tc.status, w.Code)
+
+			var resp expire.Response
+
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+
+			assert.Equal(t, tc.respError, resp.Error)
+			if tc.status == http.StatusOK {
+				assert.Equal(t, int64(len(tc.links)), resp.Expired)
+			}
+		})
+	}
+}
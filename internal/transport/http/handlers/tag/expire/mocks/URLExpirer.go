@@ -0,0 +1,101 @@
+// Code generated by mockery v2.39.1. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+
+	storage "github.com/dkhrunov/url-shortener/internal/storage"
+
+	time "time"
+)
+
+// URLExpirer is an autogenerated mock type for the URLExpirer type
+type URLExpirer struct {
+	mock.Mock
+}
+
+type URLExpirer_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *URLExpirer) EXPECT() *URLExpirer_Expecter {
+	return &URLExpirer_Expecter{mock: &_m.Mock}
+}
+
+// ExpireURLsByTag provides a mock function with given fields: ctx, tag, ownerID, at
+func (_m *URLExpirer) ExpireURLsByTag(ctx context.Context, tag string, ownerID int64, at time.Time) ([]storage.URL, error) {
+	ret := _m.Called(ctx, tag, ownerID, at)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ExpireURLsByTag")
+	}
+
+	var r0 []storage.URL
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, int64, time.Time) ([]storage.URL, error)); ok {
+		return rf(ctx, tag, ownerID, at)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, int64, time.Time) []storage.URL); ok {
+		r0 = rf(ctx, tag, ownerID, at)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]storage.URL)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, int64, time.Time) error); ok {
+		r1 = rf(ctx, tag, ownerID, at)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// URLExpirer_ExpireURLsByTag_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ExpireURLsByTag'
+type URLExpirer_ExpireURLsByTag_Call struct {
+	*mock.Call
+}
+
+// ExpireURLsByTag is a helper method to define mock.On call
+//   - ctx context.Context
+//   - tag string
+//   - ownerID int64
+//   - at time.Time
+func (_e *URLExpirer_Expecter) ExpireURLsByTag(ctx interface{}, tag interface{}, ownerID interface{}, at interface{}) *URLExpirer_ExpireURLsByTag_Call {
+	return &URLExpirer_ExpireURLsByTag_Call{Call: _e.mock.On("ExpireURLsByTag", ctx, tag, ownerID, at)}
+}
+
+func (_c *URLExpirer_ExpireURLsByTag_Call) Run(run func(ctx context.Context, tag string, ownerID int64, at time.Time)) *URLExpirer_ExpireURLsByTag_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(int64), args[3].(time.Time))
+	})
+	return _c
+}
+
+func (_c *URLExpirer_ExpireURLsByTag_Call) Return(_a0 []storage.URL, _a1 error) *URLExpirer_ExpireURLsByTag_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *URLExpirer_ExpireURLsByTag_Call) RunAndReturn(run func(context.Context, string, int64, time.Time) ([]storage.URL, error)) *URLExpirer_ExpireURLsByTag_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewURLExpirer creates a new instance of URLExpirer. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewURLExpirer(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *URLExpirer {
+	mock := &URLExpirer{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,72 @@
+// Code generated by mockery v2.39.1. DO NOT EDIT.
+
+package mocks
+
+import (
+	http "net/http"
+
+	audit "github.com/dkhrunov/url-shortener/internal/lib/audit"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// Auditor is an autogenerated mock type for the Auditor type
+type Auditor struct {
+	mock.Mock
+}
+
+type Auditor_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *Auditor) EXPECT() *Auditor_Expecter {
+	return &Auditor_Expecter{mock: &_m.Mock}
+}
+
+// RecordAll provides a mock function with given fields: r, events
+func (_m *Auditor) RecordAll(r *http.Request, events []audit.Event) {
+	_m.Called(r, events)
+}
+
+// Auditor_RecordAll_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RecordAll'
+type Auditor_RecordAll_Call struct {
+	*mock.Call
+}
+
+// RecordAll is a helper method to define mock.On call
+//   - r *http.Request
+//   - events []audit.Event
+func (_e *Auditor_Expecter) RecordAll(r interface{}, events interface{}) *Auditor_RecordAll_Call {
+	return &Auditor_RecordAll_Call{Call: _e.mock.On("RecordAll", r, events)}
+}
+
+func (_c *Auditor_RecordAll_Call) Run(run func(r *http.Request, events []audit.Event)) *Auditor_RecordAll_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(*http.Request), args[1].([]audit.Event))
+	})
+	return _c
+}
+
+func (_c *Auditor_RecordAll_Call) Return() *Auditor_RecordAll_Call {
+	_c.Call.Return()
+	return _c
+}
+
+func (_c *Auditor_RecordAll_Call) RunAndReturn(run func(*http.Request, []audit.Event)) *Auditor_RecordAll_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewAuditor creates a new instance of Auditor. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewAuditor(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *Auditor {
+	mock := &Auditor{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
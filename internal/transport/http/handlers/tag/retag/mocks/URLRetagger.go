@@ -0,0 +1,99 @@
+// Code generated by mockery v2.39.1. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+
+	storage "github.com/dkhrunov/url-shortener/internal/storage"
+)
+
+// URLRetagger is an autogenerated mock type for the URLRetagger type
+type URLRetagger struct {
+	mock.Mock
+}
+
+type URLRetagger_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *URLRetagger) EXPECT() *URLRetagger_Expecter {
+	return &URLRetagger_Expecter{mock: &_m.Mock}
+}
+
+// RetagURLs provides a mock function with given fields: ctx, tag, newTag, ownerID
+func (_m *URLRetagger) RetagURLs(ctx context.Context, tag string, newTag string, ownerID int64) ([]storage.URL, error) {
+	ret := _m.Called(ctx, tag, newTag, ownerID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RetagURLs")
+	}
+
+	var r0 []storage.URL
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, int64) ([]storage.URL, error)); ok {
+		return rf(ctx, tag, newTag, ownerID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, int64) []storage.URL); ok {
+		r0 = rf(ctx, tag, newTag, ownerID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]storage.URL)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, int64) error); ok {
+		r1 = rf(ctx, tag, newTag, ownerID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// URLRetagger_RetagURLs_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RetagURLs'
+type URLRetagger_RetagURLs_Call struct {
+	*mock.Call
+}
+
+// RetagURLs is a helper method to define mock.On call
+//   - ctx context.Context
+//   - tag string
+//   - newTag string
+//   - ownerID int64
+func (_e *URLRetagger_Expecter) RetagURLs(ctx interface{}, tag interface{}, newTag interface{}, ownerID interface{}) *URLRetagger_RetagURLs_Call {
+	return &URLRetagger_RetagURLs_Call{Call: _e.mock.On("RetagURLs", ctx, tag, newTag, ownerID)}
+}
+
+func (_c *URLRetagger_RetagURLs_Call) Run(run func(ctx context.Context, tag string, newTag string, ownerID int64)) *URLRetagger_RetagURLs_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(string), args[3].(int64))
+	})
+	return _c
+}
+
+func (_c *URLRetagger_RetagURLs_Call) Return(_a0 []storage.URL, _a1 error) *URLRetagger_RetagURLs_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *URLRetagger_RetagURLs_Call) RunAndReturn(run func(context.Context, string, string, int64) ([]storage.URL, error)) *URLRetagger_RetagURLs_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewURLRetagger creates a new instance of URLRetagger. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewURLRetagger(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *URLRetagger {
+	mock := &URLRetagger{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,164 @@
+package retag
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+
+	"github.com/dkhrunov/url-shortener/internal/auth"
+	"github.com/dkhrunov/url-shortener/internal/lib/audit"
+	"github.com/dkhrunov/url-shortener/internal/lib/logger/slog/slogerr"
+	"github.com/dkhrunov/url-shortener/internal/lib/tags"
+	"github.com/dkhrunov/url-shortener/internal/storage"
+	"github.com/dkhrunov/url-shortener/internal/transport/http/common/response"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+	"github.com/go-playground/validator/v10"
+)
+
+type Request struct {
+	// Tag replaces the tag of the path on the links.
+	Tag string `json:"tag" validate:"required"`
+}
+
+type Response struct {
+	response.Response
+	Retagged int64 `json:"retagged"`
+}
+
+type URLRetagger interface {
+	RetagURLs(ctx context.Context, tag, newTag string, ownerID int64) ([]storage.URL, error)
+}
+
+type Auditor interface {
+	RecordAll(r *http.Request, events []audit.Event)
+}
+
+// New returns the handler replacing a tag with another on all links with it. Users
+// only retag their own links.
+func New(urlRetagger URLRetagger, auditor Auditor) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.tag.retag.New"
+
+		log := slog.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		identity, ok := auth.FromContext(r.Context())
+		if !ok {
+			log.ErrorContext(r.Context(), "request is not authenticated")
+
+			render.Status(r, http.StatusUnauthorized)
+			render.JSON(w, r, response.Error("unauthorized"))
+
+			return
+		}
+
+		tag := tags.Normalize(chi.URLParam(r, "tag"))
+		if !tags.Valid(tag) {
+			log.InfoContext(r.Context(), "invalid tag", slog.String("tag", tag))
+
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, response.Error("invalid tag"))
+
+			return
+		}
+
+		var req Request
+
+		err := render.DecodeJSON(r.Body, &req)
+		if err != nil {
+			log.ErrorContext(r.Context(), "failed to decode request body", slogerr.Error(err))
+
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, response.Error("failed to decode request"))
+
+			return
+		}
+
+		if err := validator.New().Struct(req); err != nil {
+			validateErr := err.(validator.ValidationErrors)
+
+			log.ErrorContext(r.Context(), "invalid request", slogerr.Error(err))
+
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, response.ValidationError(validateErr))
+
+			return
+		}
+
+		newTag := tags.Normalize(req.Tag)
+		if !tags.Valid(newTag) {
+			log.InfoContext(r.Context(), "invalid new tag", slog.String("tag", newTag))
+
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, response.Error(tags.ErrInvalid.Error()))
+
+			return
+		}
+		if newTag == tag {
+			log.InfoContext(r.Context(), "tag is unchanged", slog.String("tag", tag))
+
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, response.Error("tag is unchanged"))
+
+			return
+		}
+
+		links, err := urlRetagger.RetagURLs(r.Context(), tag, newTag, identity.OwnerFilter())
+		if status, resp, ok := response.Unavailable(err); ok {
+			log.WarnContext(r.Context(), "storage unavailable", slogerr.Error(err))
+
+			render.Status(r, status)
+			render.JSON(w, r, resp)
+
+			return
+		}
+		if err != nil {
+			log.ErrorContext(r.Context(), "failed to retag urls", slogerr.Error(err))
+
+			render.Status(r, http.StatusInternalServerError)
+			render.JSON(w, r, response.Error("failed to retag urls"))
+
+			return
+		}
+		if len(links) == 0 {
+			log.InfoContext(r.Context(), "no urls with tag", slog.String("tag", tag))
+
+			render.Status(r, http.StatusNotFound)
+			render.JSON(w, r, response.Error("not found"))
+
+			return
+		}
+
+		retagged := int64(len(links))
+
+		log.InfoContext(r.Context(), "urls retagged",
+			slog.String("tag", tag),
+			slog.String("new_tag", newTag),
+			slog.Int64("retagged", retagged),
+		)
+
+		events := make([]audit.Event, 0, len(links))
+		for _, link := range links {
+			after := audit.LinkOf(link)
+			after.Tags = tags.Replace(link.Tags, tag, newTag)
+
+			events = append(events, audit.Event{
+				Action: audit.ActionUpdate,
+				Domain: link.Domain,
+				Alias:  link.Alias,
+				Before: audit.LinkOf(link),
+				After:  after,
+			})
+		}
+		auditor.RecordAll(r, events)
+
+		render.JSON(w, r, Response{
+			Response: response.OK(),
+			Retagged: retagged,
+		})
+	}
+}
@@ -0,0 +1,168 @@
+package retag_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/dkhrunov/url-shortener/internal/auth"
+	"github.com/dkhrunov/url-shortener/internal/lib/audit"
+	"github.com/dkhrunov/url-shortener/internal/lib/tags"
+	"github.com/dkhrunov/url-shortener/internal/storage"
+	"github.com/dkhrunov/url-shortener/internal/transport/http/handlers/tag/retag"
+	"github.com/dkhrunov/url-shortener/internal/transport/http/handlers/tag/retag/mocks"
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRetagHandler(t *testing.T) {
+	links := []storage.URL{
+		{ID: 1, Alias: "go", URL: "https://go.dev", Tags: []string{"docs", "spring-sale"}},
+		{ID: 2, Domain: "sho.rt", Alias: "pkg", URL: "https://pkg.go.dev", Tags: []string{"spring-2026", "spring-sale"}},
+	}
+
+	cases := []struct {
+		name      string
+		tag       string
+		body      string
+		newTag    string
+		role      auth.Role
+		ownerID   int64
+		links     []storage.URL
+		respError string
+		mockError error
+		status    int
+	}{
+		{
+			name:    "Success",
+			tag:     "spring-sale",
+			body:    `{"tag": "Spring-2026"}`,
+			newTag:  "spring-2026",
+			ownerID: 42,
+			links:   links[:1],
+			status:  http.StatusOK,
+		},
+		{
+			name:   "Admin retags all",
+			tag:    "spring-sale",
+			body:   `{"tag": "spring-2026"}`,
+			newTag: "spring-2026",
+			role:   auth.RoleAdmin,
+			links:  links,
+			status: http.StatusOK,
+		},
+		{
+			name:      "No links",
+			tag:       "spring-sale",
+			body:      `{"tag": "spring-2026"}`,
+			newTag:    "spring-2026",
+			ownerID:   42,
+			respError: "not found",
+			status:    http.StatusNotFound,
+		},
+		{
+			name:      "Missing tag",
+			tag:       "spring-sale",
+			body:      `{}`,
+			respError: "field Tag is a required field",
+			status:    http.StatusBadRequest,
+		},
+		{
+			name:      "Invalid new tag",
+			tag:       "spring-sale",
+			body:      `{"tag": "spring 2026"}`,
+			respError: tags.ErrInvalid.Error(),
+			status:    http.StatusBadRequest,
+		},
+		{
+			name:      "Unchanged",
+			tag:       "spring-sale",
+			body:      `{"tag": "Spring-Sale"}`,
+			respError: "tag is unchanged",
+			status:    http.StatusBadRequest,
+		},
+		{
+			name:      "Failed",
+			tag:       "spring-sale",
+			body:      `{"tag": "spring-2026"}`,
+			newTag:    "spring-2026",
+			ownerID:   42,
+			respError: "failed to retag urls",
+			mockError: errors.New("unexpected error"),
+			status:    http.StatusInternalServerError,
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			urlRetaggerMock := mocks.NewURLRetagger(t)
+			auditorMock := mocks.NewAuditor(t)
+
+			if tc.newTag != "" {
+				urlRetaggerMock.EXPECT().
+					RetagURLs(mock.Anything, tc.tag, tc.newTag, tc.ownerID).
+					Return(tc.links, tc.mockError).
+					Once()
+			}
+
+			role := tc.role
+			if role == "" {
+				role = auth.RoleEditor
+			}
+
+			if tc.status == http.StatusOK {
+				events := []audit.Event{{
+					Action: audit.ActionUpdate,
+					Alias:  "go",
+					Before: audit.Link{URL: "https://go.dev", Tags: []string{"docs", "spring-sale"}},
+					After:  audit.Link{URL: "https://go.dev", Tags: []string{"docs", "spring-2026"}},
+				}}
+				if len(tc.links) > 1 {
+					// Links with both tags keep one.
+					events = append(events, audit.Event{
+						Action: audit.ActionUpdate,
+						Domain: "sho.rt",
+						Alias:  "pkg",
+						Before: audit.Link{URL: "https://pkg.go.dev", Tags: []string{"spring-2026", "spring-sale"}},
+						After:  audit.Link{URL: "https://pkg.go.dev", Tags: []string{"spring-2026"}},
+					})
+				}
+
+				auditorMock.EXPECT().RecordAll(mock.Anything, events).Once()
+			}
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodPost, "/url/tags/{tag}/retag", strings.NewReader(tc.body))
+
+			rctx := chi.NewRouteContext()
+			rctx.URLParams.Add("tag", tc.tag)
+
+			ctx := context.WithValue(r.Context(), chi.RouteCtxKey, rctx)
+			r = r.WithContext(auth.WithIdentity(ctx, auth.Identity{UserID: 42, Role: role}))
+
+			handler := retag.New(urlRetaggerMock, auditorMock)
+			handler.ServeHTTP(w, r)
+
+			assert.Equal(t, tc.status, w.Code)
+
+			var resp retag.Response
+
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+
+			assert.Equal(t, tc.respError, resp.Error)
+			if tc.status == http.StatusOK {
+				assert.Equal(t, int64(len(tc.links)), resp.Retagged)
+			}
+		})
+	}
+}
@@ -0,0 +1,97 @@
+// Code generated by mockery v2.39.1. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+
+	storage "github.com/dkhrunov/url-shortener/internal/storage"
+)
+
+// TagStatsGetter is an autogenerated mock type for the TagStatsGetter type
+type TagStatsGetter struct {
+	mock.Mock
+}
+
+type TagStatsGetter_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *TagStatsGetter) EXPECT() *TagStatsGetter_Expecter {
+	return &TagStatsGetter_Expecter{mock: &_m.Mock}
+}
+
+// TagStats provides a mock function with given fields: ctx, ownerID
+func (_m *TagStatsGetter) TagStats(ctx context.Context, ownerID int64) ([]storage.TagStats, error) {
+	ret := _m.Called(ctx, ownerID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for TagStats")
+	}
+
+	var r0 []storage.TagStats
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64) ([]storage.TagStats, error)); ok {
+		return rf(ctx, ownerID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64) []storage.TagStats); ok {
+		r0 = rf(ctx, ownerID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]storage.TagStats)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = rf(ctx, ownerID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// TagStatsGetter_TagStats_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'TagStats'
+type TagStatsGetter_TagStats_Call struct {
+	*mock.Call
+}
+
+// TagStats is a helper method to define mock.On call
+//   - ctx context.Context
+//   - ownerID int64
+func (_e *TagStatsGetter_Expecter) TagStats(ctx interface{}, ownerID interface{}) *TagStatsGetter_TagStats_Call {
+	return &TagStatsGetter_TagStats_Call{Call: _e.mock.On("TagStats", ctx, ownerID)}
+}
+
+func (_c *TagStatsGetter_TagStats_Call) Run(run func(ctx context.Context, ownerID int64)) *TagStatsGetter_TagStats_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int64))
+	})
+	return _c
+}
+
+func (_c *TagStatsGetter_TagStats_Call) Return(_a0 []storage.TagStats, _a1 error) *TagStatsGetter_TagStats_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *TagStatsGetter_TagStats_Call) RunAndReturn(run func(context.Context, int64) ([]storage.TagStats, error)) *TagStatsGetter_TagStats_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewTagStatsGetter creates a new instance of TagStatsGetter. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewTagStatsGetter(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *TagStatsGetter {
+	mock := &TagStatsGetter{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,86 @@
+package stats
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+
+	"github.com/dkhrunov/url-shortener/internal/auth"
+	"github.com/dkhrunov/url-shortener/internal/lib/logger/slog/slogerr"
+	"github.com/dkhrunov/url-shortener/internal/storage"
+	"github.com/dkhrunov/url-shortener/internal/transport/http/common/response"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+)
+
+type Item struct {
+	Tag     string `json:"tag"`
+	Links   int    `json:"links"`
+	Expired int    `json:"expired"`
+	Deleted int    `json:"deleted"`
+}
+
+type Response struct {
+	response.Response
+	Tags []Item `json:"tags"`
+}
+
+type TagStatsGetter interface {
+	TagStats(ctx context.Context, ownerID int64) ([]storage.TagStats, error)
+}
+
+// New returns the handler summing up the links of each tag. Users only see their
+// own links counted.
+func New(statsGetter TagStatsGetter) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.tag.stats.New"
+
+		log := slog.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		identity, ok := auth.FromContext(r.Context())
+		if !ok {
+			log.ErrorContext(r.Context(), "request is not authenticated")
+
+			render.Status(r, http.StatusUnauthorized)
+			render.JSON(w, r, response.Error("unauthorized"))
+
+			return
+		}
+
+		stats, err := statsGetter.TagStats(r.Context(), identity.OwnerFilter())
+		if status, resp, ok := response.Unavailable(err); ok {
+			log.WarnContext(r.Context(), "storage unavailable", slogerr.Error(err))
+
+			render.Status(r, status)
+			render.JSON(w, r, resp)
+
+			return
+		}
+		if err != nil {
+			log.ErrorContext(r.Context(), "failed to get tag stats", slogerr.Error(err))
+
+			render.Status(r, http.StatusInternalServerError)
+			render.JSON(w, r, response.Error("failed to get tag stats"))
+
+			return
+		}
+
+		items := make([]Item, 0, len(stats))
+		for _, s := range stats {
+			items = append(items, Item{
+				Tag:     s.Name,
+				Links:   s.Links,
+				Expired: s.Expired,
+				Deleted: s.Deleted,
+			})
+		}
+
+		render.JSON(w, r, Response{
+			Response: response.OK(),
+			Tags:     items,
+		})
+	}
+}
@@ -0,0 +1,111 @@
+package stats_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/dkhrunov/url-shortener/internal/auth"
+	"github.com/dkhrunov/url-shortener/internal/storage"
+	"github.com/dkhrunov/url-shortener/internal/transport/http/handlers/tag/stats"
+	"github.com/dkhrunov/url-shortener/internal/transport/http/handlers/tag/stats/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStatsHandler(t *testing.T) {
+	tagStats := []storage.TagStats{
+		{Name: "spring-sale", Links: 3, Expired: 1, Deleted: 2},
+		{Name: "team-a", Links: 1},
+	}
+
+	cases := []struct {
+		name      string
+		role      auth.Role
+		ownerID   int64
+		stats     []storage.TagStats
+		want      []stats.Item
+		respError string
+		mockError error
+		status    int
+	}{
+		{
+			name:    "Success",
+			ownerID: 42,
+			stats:   tagStats,
+			want: []stats.Item{
+				{Tag: "spring-sale", Links: 3, Expired: 1, Deleted: 2},
+				{Tag: "team-a", Links: 1},
+			},
+			status: http.StatusOK,
+		},
+		{
+			name:   "Admin sees all",
+			role:   auth.RoleAdmin,
+			stats:  tagStats[:1],
+			want:   []stats.Item{{Tag: "spring-sale", Links: 3, Expired: 1, Deleted: 2}},
+			status: http.StatusOK,
+		},
+		{
+			name:    "No tags",
+			ownerID: 42,
+			stats:   []storage.TagStats{},
+			want:    []stats.Item{},
+			status:  http.StatusOK,
+		},
+		{
+			name:      "Failed",
+			ownerID:   42,
+			respError: "failed to get tag stats",
+			mockError: errors.New("unexpected error"),
+			status:    http.StatusInternalServerError,
+		},
+		{
+			name:      "Timed out",
+			ownerID:   42,
+			respError: "storage timed out",
+			mockError: fmt.Errorf("storage.sqlite.TagStats: %w", context.DeadlineExceeded),
+			status:    http.StatusGatewayTimeout,
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			statsGetterMock := mocks.NewTagStatsGetter(t)
+			statsGetterMock.EXPECT().
+				TagStats(mock.Anything, tc.ownerID).
+				Return(tc.stats, tc.mockError).
+				Once()
+
+			role := tc.role
+			if role == "" {
+				role = auth.RoleViewer
+			}
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodGet, "/url/tags", nil)
+			r = r.WithContext(auth.WithIdentity(r.Context(), auth.Identity{UserID: 42, Role: role}))
+
+			handler := stats.New(statsGetterMock)
+			handler.ServeHTTP(w, r)
+
+			assert.Equal(t, tc.status, w.Code)
+
+			var resp stats.Response
+
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+
+			assert.Equal(t, tc.respError, resp.Error)
+			assert.Equal(t, tc.want, resp.Tags)
+		})
+	}
+}
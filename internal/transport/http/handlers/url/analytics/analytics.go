@@ -0,0 +1,309 @@
+package analytics
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/dkhrunov/url-shortener/internal/auth"
+	analyticslib "github.com/dkhrunov/url-shortener/internal/lib/analytics"
+	"github.com/dkhrunov/url-shortener/internal/lib/hostname"
+	"github.com/dkhrunov/url-shortener/internal/lib/logger/slog/slogerr"
+	"github.com/dkhrunov/url-shortener/internal/storage"
+	"github.com/dkhrunov/url-shortener/internal/transport/http/common/response"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+)
+
+const (
+	defaultLimit = 10
+	maxLimit     = 100
+	// defaultRange is the range reported when from is not given.
+	defaultRange = 30 * 24 * time.Hour
+	// maxBuckets bounds the length of the series, e.g. about three months of hours.
+	maxBuckets = 2500
+)
+
+// Count is the number of clicks with a value, empty for direct visits among referrers
+// and for clients that couldn't be told among the others.
+type Count struct {
+	Value  string `json:"value"`
+	Clicks int64  `json:"clicks"`
+}
+
+type Bucket struct {
+	Start  time.Time `json:"start"`
+	Clicks int64     `json:"clicks"`
+}
+
+type Response struct {
+	response.Response
+	Alias       string    `json:"alias,omitempty"`
+	Domain      string    `json:"domain,omitempty"`
+	Interval    string    `json:"interval,omitempty"`
+	From        time.Time `json:"from"`
+	To          time.Time `json:"to"`
+	IncludeBots bool      `json:"include_bots"`
+	Total       int64     `json:"total"`
+	// Bots is the number of clicks of bots, counted in the rest only with include_bots.
+	Bots      int64    `json:"bots"`
+	Referrers []Count  `json:"referrers"`
+	Browsers  []Count  `json:"browsers"`
+	OS        []Count  `json:"os"`
+	Devices   []Count  `json:"devices"`
+	Countries []Count  `json:"countries"`
+	Series    []Bucket `json:"series"`
+}
+
+type ClickAnalyzer interface {
+	GetLink(ctx context.Context, domain, alias string) (storage.URL, error)
+	ClickAnalytics(ctx context.Context, q storage.AnalyticsQuery) (storage.Analytics, error)
+}
+
+// New returns the handler reporting the clicks of a link in the range [from, to): the
+// top referrers, browsers, OS, devices and countries, and a series of the clicks per
+// hour, day or week. from and to are RFC 3339 times or dates, in UTC, and default to
+// the last 30 days. from is rounded down to the interval and to up to the hour. Clicks
+// of bots are left out unless include_bots is set.
+func New(clickAnalyzer ClickAnalyzer) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.url.analytics.New"
+
+		log := slog.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		identity, ok := auth.FromContext(r.Context())
+		if !ok {
+			log.ErrorContext(r.Context(), "request is not authenticated")
+
+			render.Status(r, http.StatusUnauthorized)
+			render.JSON(w, r, response.Error("unauthorized"))
+
+			return
+		}
+
+		alias := chi.URLParam(r, "alias")
+		if alias == "" {
+			log.InfoContext(r.Context(), "alias is empty")
+
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, response.Error("invalid request"))
+
+			return
+		}
+
+		q := r.URL.Query()
+		domain := hostname.Normalize(q.Get("domain"))
+
+		interval := analyticslib.Interval(q.Get("interval"))
+		if interval == "" {
+			interval = analyticslib.IntervalDay
+		}
+		if !interval.Valid() {
+			log.InfoContext(r.Context(), "invalid interval", slog.String("interval", string(interval)))
+
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, response.Error("invalid interval"))
+
+			return
+		}
+
+		limit, err := intParam(q.Get("limit"), defaultLimit)
+		if err != nil || limit < 1 || limit > maxLimit {
+			log.InfoContext(r.Context(), "invalid limit", slog.String("limit", q.Get("limit")))
+
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, response.Error("invalid limit"))
+
+			return
+		}
+
+		includeBots, err := boolParam(q.Get("include_bots"))
+		if err != nil {
+			log.InfoContext(r.Context(), "invalid include_bots", slog.String("include_bots", q.Get("include_bots")))
+
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, response.Error("invalid include_bots"))
+
+			return
+		}
+
+		to, err := timeParam(q.Get("to"), time.Now())
+		if err != nil {
+			log.InfoContext(r.Context(), "invalid to", slog.String("to", q.Get("to")))
+
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, response.Error("invalid to"))
+
+			return
+		}
+
+		from, err := timeParam(q.Get("from"), to.Add(-defaultRange))
+		if err != nil {
+			log.InfoContext(r.Context(), "invalid from", slog.String("from", q.Get("from")))
+
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, response.Error("invalid from"))
+
+			return
+		}
+
+		from = interval.Truncate(from)
+		if hour := to.Truncate(time.Hour); !hour.Equal(to) {
+			to = hour.Add(time.Hour)
+		}
+
+		if !from.Before(to) {
+			log.InfoContext(r.Context(), "invalid range", slog.Time("from", from), slog.Time("to", to))
+
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, response.Error("invalid range"))
+
+			return
+		}
+
+		if buckets(interval, from, to) > maxBuckets {
+			log.InfoContext(r.Context(), "range too large", slog.Time("from", from), slog.Time("to", to))
+
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, response.Error("range too large"))
+
+			return
+		}
+
+		link, err := clickAnalyzer.GetLink(r.Context(), domain, alias)
+		if err == nil && !identity.CanManage(link.OwnerID) {
+			// Links of other users are reported as missing to not reveal them.
+			err = storage.ErrURLNotFound
+		}
+		if errors.Is(err, storage.ErrURLNotFound) {
+			log.InfoContext(r.Context(), "url not found", "alias", alias)
+
+			render.Status(r, http.StatusNotFound)
+			render.JSON(w, r, response.Error("not found"))
+
+			return
+		}
+		if status, resp, ok := response.Unavailable(err); ok {
+			log.WarnContext(r.Context(), "storage unavailable", slogerr.Error(err))
+
+			render.Status(r, status)
+			render.JSON(w, r, resp)
+
+			return
+		}
+		if err != nil {
+			log.ErrorContext(r.Context(), "failed to get url", slogerr.Error(err))
+
+			render.Status(r, http.StatusInternalServerError)
+			render.JSON(w, r, response.Error("failed to get url"))
+
+			return
+		}
+
+		a, err := clickAnalyzer.ClickAnalytics(r.Context(), storage.AnalyticsQuery{
+			LinkID:      link.ID,
+			From:        from,
+			To:          to,
+			Interval:    string(interval),
+			Limit:       limit,
+			IncludeBots: includeBots,
+		})
+		if status, resp, ok := response.Unavailable(err); ok {
+			log.WarnContext(r.Context(), "storage unavailable", slogerr.Error(err))
+
+			render.Status(r, status)
+			render.JSON(w, r, resp)
+
+			return
+		}
+		if err != nil {
+			log.ErrorContext(r.Context(), "failed to get click analytics", slogerr.Error(err))
+
+			render.Status(r, http.StatusInternalServerError)
+			render.JSON(w, r, response.Error("failed to get click analytics"))
+
+			return
+		}
+
+		series := analyticslib.Fill(a.Series, interval, from, to)
+		points := make([]Bucket, 0, len(series))
+		for _, b := range series {
+			points = append(points, Bucket{Start: b.Start, Clicks: b.Clicks})
+		}
+
+		render.JSON(w, r, Response{
+			Response:    response.OK(),
+			Alias:       alias,
+			Domain:      domain,
+			Interval:    string(interval),
+			From:        from,
+			To:          to,
+			IncludeBots: includeBots,
+			Total:       a.Total,
+			Bots:        a.Bots,
+			Referrers:   counts(a.Referrers),
+			Browsers:    counts(a.Browsers),
+			OS:          counts(a.OS),
+			Devices:     counts(a.Devices),
+			Countries:   counts(a.Countries),
+			Series:      points,
+		})
+	}
+}
+
+func counts(cs []storage.ClickCount) []Count {
+	out := make([]Count, 0, len(cs))
+	for _, c := range cs {
+		out = append(out, Count{Value: c.Value, Clicks: c.Clicks})
+	}
+
+	return out
+}
+
+// buckets returns the number of buckets of the interval in [from, to).
+func buckets(interval analyticslib.Interval, from, to time.Time) int {
+	n := 0
+	for start := from; start.Before(to) && n <= maxBuckets; start = interval.Next(start) {
+		n++
+	}
+
+	return n
+}
+
+func intParam(v string, def int) (int, error) {
+	if v == "" {
+		return def, nil
+	}
+
+	return strconv.Atoi(v)
+}
+
+func boolParam(v string) (bool, error) {
+	if v == "" {
+		return false, nil
+	}
+
+	return strconv.ParseBool(v)
+}
+
+// timeParam parses an RFC 3339 time or a date, returning def when v is empty.
+func timeParam(v string, def time.Time) (time.Time, error) {
+	if v == "" {
+		return def.UTC(), nil
+	}
+
+	if t, err := time.Parse(time.DateOnly, v); err == nil {
+		return t, nil
+	}
+
+	t, err := time.Parse(time.RFC3339, v)
+
+	return t.UTC(), err
+}
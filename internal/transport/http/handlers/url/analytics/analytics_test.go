@@ -0,0 +1,290 @@
+package analytics_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/dkhrunov/url-shortener/internal/auth"
+	"github.com/dkhrunov/url-shortener/internal/storage"
+	"github.com/dkhrunov/url-shortener/internal/transport/http/handlers/url/analytics"
+	"github.com/dkhrunov/url-shortener/internal/transport/http/handlers/url/analytics/mocks"
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAnalyticsHandler(t *testing.T) {
+	day := func(d, h int) time.Time {
+		return time.Date(2024, 3, d, h, 0, 0, 0, time.UTC)
+	}
+
+	report := storage.Analytics{
+		Total:     3,
+		Bots:      2,
+		Referrers: []storage.ClickCount{{Value: "news.example", Clicks: 2}, {Value: "", Clicks: 1}},
+		Browsers:  []storage.ClickCount{{Value: "Chrome", Clicks: 3}},
+		OS:        []storage.ClickCount{{Value: "Windows", Clicks: 3}},
+		Devices:   []storage.ClickCount{{Value: "desktop", Clicks: 3}},
+		Countries: []storage.ClickCount{{Value: "US", Clicks: 3}},
+	}
+
+	cases := []struct {
+		name      string
+		alias     string
+		query     string
+		ownerID   int64
+		role      auth.Role
+		want      *storage.AnalyticsQuery
+		stored    []storage.ClickBucket
+		series    []analytics.Bucket
+		respError string
+		getError  error
+		mockError error
+		status    int
+	}{
+		{
+			name:    "Success",
+			alias:   "test_alias",
+			query:   "from=2024-03-04&to=2024-03-07",
+			ownerID: 42,
+			want:    &storage.AnalyticsQuery{From: day(4, 0), To: day(7, 0), Interval: "day", Limit: 10},
+			stored:  []storage.ClickBucket{{Start: day(5, 0), Clicks: 3}},
+			series: []analytics.Bucket{
+				{Start: day(4, 0), Clicks: 0},
+				{Start: day(5, 0), Clicks: 3},
+				{Start: day(6, 0), Clicks: 0},
+			},
+			status: http.StatusOK,
+		},
+		{
+			name:    "Weeks with bots",
+			alias:   "test_alias",
+			query:   "interval=week&from=2024-03-06T10:00:00Z&to=2024-03-06T10:30:00%2B01:00&limit=5&include_bots=true",
+			ownerID: 42,
+			want:    &storage.AnalyticsQuery{From: day(4, 0), To: day(6, 10), Interval: "week", Limit: 5, IncludeBots: true},
+			stored:  []storage.ClickBucket{{Start: day(4, 0), Clicks: 3}},
+			series:  []analytics.Bucket{{Start: day(4, 0), Clicks: 3}},
+			status:  http.StatusOK,
+		},
+		{
+			name:    "Admin sees foreign link",
+			alias:   "test_alias",
+			query:   "interval=hour&from=2024-03-05T00:00:00Z&to=2024-03-05T02:00:00Z",
+			ownerID: 7,
+			role:    auth.RoleAdmin,
+			want:    &storage.AnalyticsQuery{From: day(5, 0), To: day(5, 2), Interval: "hour", Limit: 10},
+			stored:  []storage.ClickBucket{{Start: day(5, 0), Clicks: 3}},
+			series:  []analytics.Bucket{{Start: day(5, 0), Clicks: 3}, {Start: day(5, 1), Clicks: 0}},
+			status:  http.StatusOK,
+		},
+		{
+			name:      "Foreign link",
+			alias:     "test_alias",
+			ownerID:   7,
+			respError: "not found",
+			status:    http.StatusNotFound,
+		},
+		{
+			name:      "Empty alias",
+			alias:     "",
+			respError: "invalid request",
+			status:    http.StatusBadRequest,
+		},
+		{
+			name:      "Invalid interval",
+			alias:     "test_alias",
+			query:     "interval=month",
+			respError: "invalid interval",
+			status:    http.StatusBadRequest,
+		},
+		{
+			name:      "Invalid limit",
+			alias:     "test_alias",
+			query:     "limit=500",
+			respError: "invalid limit",
+			status:    http.StatusBadRequest,
+		},
+		{
+			name:      "Invalid include_bots",
+			alias:     "test_alias",
+			query:     "include_bots=maybe",
+			respError: "invalid include_bots",
+			status:    http.StatusBadRequest,
+		},
+		{
+			name:      "Invalid from",
+			alias:     "test_alias",
+			query:     "from=yesterday",
+			respError: "invalid from",
+			status:    http.StatusBadRequest,
+		},
+		{
+			name:      "Invalid to",
+			alias:     "test_alias",
+			query:     "to=tomorrow",
+			respError: "invalid to",
+			status:    http.StatusBadRequest,
+		},
+		{
+			name:      "Invalid range",
+			alias:     "test_alias",
+			query:     "from=2024-03-07&to=2024-03-04",
+			respError: "invalid range",
+			status:    http.StatusBadRequest,
+		},
+		{
+			name:      "Range too large",
+			alias:     "test_alias",
+			query:     "interval=hour&from=2023-01-01&to=2024-01-01",
+			respError: "range too large",
+			status:    http.StatusBadRequest,
+		},
+		{
+			name:      "Not found",
+			alias:     "test_alias",
+			respError: "not found",
+			getError:  storage.ErrURLNotFound,
+			status:    http.StatusNotFound,
+		},
+		{
+			name:      "Failed",
+			alias:     "test_alias",
+			ownerID:   42,
+			respError: "failed to get click analytics",
+			mockError: errors.New("unexpected error"),
+			status:    http.StatusInternalServerError,
+		},
+		{
+			name:      "Timed out",
+			alias:     "test_alias",
+			ownerID:   42,
+			respError: "storage timed out",
+			mockError: fmt.Errorf("storage.sqlite.ClickAnalytics: %w", context.DeadlineExceeded),
+			status:    http.StatusGatewayTimeout,
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			clickAnalyzerMock := mocks.NewClickAnalyzer(t)
+
+			if tc.status != http.StatusBadRequest {
+				clickAnalyzerMock.EXPECT().
+					GetLink(mock.Anything, "", tc.alias).
+					Return(storage.URL{ID: 9, Alias: tc.alias, OwnerID: tc.ownerID}, tc.getError).
+					Once()
+			}
+
+			if tc.want != nil || tc.mockError != nil {
+				report := report
+				report.Series = tc.stored
+
+				clickAnalyzerMock.EXPECT().
+					ClickAnalytics(mock.Anything, mock.MatchedBy(func(q storage.AnalyticsQuery) bool {
+						if tc.want == nil {
+							return q.LinkID == 9
+						}
+
+						return q.LinkID == 9 && q.From.Equal(tc.want.From) && q.To.Equal(tc.want.To) &&
+							q.Interval == tc.want.Interval && q.Limit == tc.want.Limit && q.IncludeBots == tc.want.IncludeBots
+					})).
+					Return(report, tc.mockError).
+					Once()
+			}
+
+			role := tc.role
+			if role == "" {
+				role = auth.RoleEditor
+			}
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodGet, "/url/{alias}/analytics?"+tc.query, nil)
+
+			rctx := chi.NewRouteContext()
+			rctx.URLParams.Add("alias", tc.alias)
+
+			ctx := context.WithValue(r.Context(), chi.RouteCtxKey, rctx)
+			r = r.WithContext(auth.WithIdentity(ctx, auth.Identity{UserID: 42, Role: role}))
+
+			handler := analytics.New(clickAnalyzerMock)
+			handler.ServeHTTP(w, r)
+
+			assert.Equal(t, tc.status, w.Code)
+
+			var resp analytics.Response
+
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+
+			assert.Equal(t, tc.respError, resp.Error)
+
+			if tc.status == http.StatusOK {
+				assert.Equal(t, tc.want.Interval, resp.Interval)
+				assert.Equal(t, tc.want.IncludeBots, resp.IncludeBots)
+				assert.Equal(t, int64(3), resp.Total)
+				assert.Equal(t, int64(2), resp.Bots)
+				assert.Equal(t, []analytics.Count{{Value: "news.example", Clicks: 2}, {Value: "", Clicks: 1}}, resp.Referrers)
+				assert.Equal(t, []analytics.Count{{Value: "US", Clicks: 3}}, resp.Countries)
+				assert.Equal(t, tc.series, resp.Series)
+			}
+		})
+	}
+}
+
+func TestAnalyticsHandlerDefaultRange(t *testing.T) {
+	clickAnalyzerMock := mocks.NewClickAnalyzer(t)
+
+	clickAnalyzerMock.EXPECT().
+		GetLink(mock.Anything, "brand.example", "test_alias").
+		Return(storage.URL{ID: 9}, nil).
+		Once()
+	clickAnalyzerMock.EXPECT().
+		ClickAnalytics(mock.Anything, mock.Anything).
+		Return(storage.Analytics{}, nil).
+		Once()
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodGet, "/url/{alias}/analytics?domain=Brand.Example", nil)
+
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("alias", "test_alias")
+
+	ctx := context.WithValue(r.Context(), chi.RouteCtxKey, rctx)
+	r = r.WithContext(auth.WithIdentity(ctx, auth.Identity{Role: auth.RoleAdmin}))
+
+	analytics.New(clickAnalyzerMock).ServeHTTP(w, r)
+
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var resp analytics.Response
+
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+
+	// The last 30 days up to the end of the current hour, per day.
+	assert.Equal(t, "day", resp.Interval)
+	assert.WithinDuration(t, time.Now().Add(time.Hour), resp.To, time.Hour)
+	assert.Len(t, resp.Series, 31)
+	assert.Empty(t, resp.Referrers)
+	assert.NotNil(t, resp.Referrers)
+}
+
+func TestAnalyticsHandlerUnauthenticated(t *testing.T) {
+	handler := analytics.New(mocks.NewClickAnalyzer(t))
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodGet, "/url/test_alias/analytics", nil)
+
+	handler.ServeHTTP(w, r)
+
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+}
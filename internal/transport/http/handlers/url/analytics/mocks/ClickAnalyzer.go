@@ -0,0 +1,152 @@
+// Code generated by mockery v2.39.1. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	storage "github.com/dkhrunov/url-shortener/internal/storage"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// ClickAnalyzer is an autogenerated mock type for the ClickAnalyzer type
+type ClickAnalyzer struct {
+	mock.Mock
+}
+
+type ClickAnalyzer_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *ClickAnalyzer) EXPECT() *ClickAnalyzer_Expecter {
+	return &ClickAnalyzer_Expecter{mock: &_m.Mock}
+}
+
+// ClickAnalytics provides a mock function with given fields: ctx, q
+func (_m *ClickAnalyzer) ClickAnalytics(ctx context.Context, q storage.AnalyticsQuery) (storage.Analytics, error) {
+	ret := _m.Called(ctx, q)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ClickAnalytics")
+	}
+
+	var r0 storage.Analytics
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, storage.AnalyticsQuery) (storage.Analytics, error)); ok {
+		return rf(ctx, q)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, storage.AnalyticsQuery) storage.Analytics); ok {
+		r0 = rf(ctx, q)
+	} else {
+		r0 = ret.Get(0).(storage.Analytics)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, storage.AnalyticsQuery) error); ok {
+		r1 = rf(ctx, q)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ClickAnalyzer_ClickAnalytics_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ClickAnalytics'
+type ClickAnalyzer_ClickAnalytics_Call struct {
+	*mock.Call
+}
+
+// ClickAnalytics is a helper method to define mock.On call
+//   - ctx context.Context
+//   - q storage.AnalyticsQuery
+func (_e *ClickAnalyzer_Expecter) ClickAnalytics(ctx interface{}, q interface{}) *ClickAnalyzer_ClickAnalytics_Call {
+	return &ClickAnalyzer_ClickAnalytics_Call{Call: _e.mock.On("ClickAnalytics", ctx, q)}
+}
+
+func (_c *ClickAnalyzer_ClickAnalytics_Call) Run(run func(ctx context.Context, q storage.AnalyticsQuery)) *ClickAnalyzer_ClickAnalytics_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(storage.AnalyticsQuery))
+	})
+	return _c
+}
+
+func (_c *ClickAnalyzer_ClickAnalytics_Call) Return(_a0 storage.Analytics, _a1 error) *ClickAnalyzer_ClickAnalytics_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *ClickAnalyzer_ClickAnalytics_Call) RunAndReturn(run func(context.Context, storage.AnalyticsQuery) (storage.Analytics, error)) *ClickAnalyzer_ClickAnalytics_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetLink provides a mock function with given fields: ctx, domain, alias
+func (_m *ClickAnalyzer) GetLink(ctx context.Context, domain string, alias string) (storage.URL, error) {
+	ret := _m.Called(ctx, domain, alias)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetLink")
+	}
+
+	var r0 storage.URL
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) (storage.URL, error)); ok {
+		return rf(ctx, domain, alias)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) storage.URL); ok {
+		r0 = rf(ctx, domain, alias)
+	} else {
+		r0 = ret.Get(0).(storage.URL)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, domain, alias)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ClickAnalyzer_GetLink_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetLink'
+type ClickAnalyzer_GetLink_Call struct {
+	*mock.Call
+}
+
+// GetLink is a helper method to define mock.On call
+//   - ctx context.Context
+//   - domain string
+//   - alias string
+func (_e *ClickAnalyzer_Expecter) GetLink(ctx interface{}, domain interface{}, alias interface{}) *ClickAnalyzer_GetLink_Call {
+	return &ClickAnalyzer_GetLink_Call{Call: _e.mock.On("GetLink", ctx, domain, alias)}
+}
+
+func (_c *ClickAnalyzer_GetLink_Call) Run(run func(ctx context.Context, domain string, alias string)) *ClickAnalyzer_GetLink_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(string))
+	})
+	return _c
+}
+
+func (_c *ClickAnalyzer_GetLink_Call) Return(_a0 storage.URL, _a1 error) *ClickAnalyzer_GetLink_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *ClickAnalyzer_GetLink_Call) RunAndReturn(run func(context.Context, string, string) (storage.URL, error)) *ClickAnalyzer_GetLink_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewClickAnalyzer creates a new instance of ClickAnalyzer. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewClickAnalyzer(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *ClickAnalyzer {
+	mock := &ClickAnalyzer{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -1,11 +1,16 @@
 package delete
 
 import (
+	"context"
 	"errors"
 	"log/slog"
 	"net/http"
 
+	"github.com/dkhrunov/url-shortener/internal/auth"
+	"github.com/dkhrunov/url-shortener/internal/lib/audit"
+	"github.com/dkhrunov/url-shortener/internal/lib/hostname"
 	"github.com/dkhrunov/url-shortener/internal/lib/logger/slog/slogerr"
+	"github.com/dkhrunov/url-shortener/internal/lib/webhook"
 	"github.com/dkhrunov/url-shortener/internal/storage"
 	"github.com/dkhrunov/url-shortener/internal/transport/http/common/response"
 	"github.com/go-chi/chi/v5"
@@ -18,10 +23,23 @@ type Response struct {
 }
 
 type URLDeleter interface {
-	DeleteURL(alias string) error
+	GetLink(ctx context.Context, domain, alias string) (storage.URL, error)
+	DeleteURL(ctx context.Context, domain, alias string) error
 }
 
-func New(urlDeleter URLDeleter) http.HandlerFunc {
+type LinkCounter interface {
+	CountLinkDeleted()
+}
+
+type Auditor interface {
+	Record(r *http.Request, e audit.Event)
+}
+
+type Notifier interface {
+	Notify(ctx context.Context, e webhook.Event)
+}
+
+func New(urlDeleter URLDeleter, linkCounter LinkCounter, auditor Auditor, notifier Notifier) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		const op = "handlers.url.delete.New"
 
@@ -30,9 +48,19 @@ func New(urlDeleter URLDeleter) http.HandlerFunc {
 			slog.String("request_id", middleware.GetReqID(r.Context())),
 		)
 
+		identity, ok := auth.FromContext(r.Context())
+		if !ok {
+			log.ErrorContext(r.Context(), "request is not authenticated")
+
+			render.Status(r, http.StatusUnauthorized)
+			render.JSON(w, r, response.Error("unauthorized"))
+
+			return
+		}
+
 		alias := chi.URLParam(r, "alias")
 		if alias == "" {
-			log.Info("alias is empty")
+			log.InfoContext(r.Context(), "alias is empty")
 
 			render.Status(r, http.StatusBadRequest)
 			render.JSON(w, r, response.Error("invalid request"))
@@ -40,17 +68,34 @@ func New(urlDeleter URLDeleter) http.HandlerFunc {
 			return
 		}
 
-		err := urlDeleter.DeleteURL(alias)
+		domain := hostname.Normalize(r.URL.Query().Get("domain"))
+
+		link, err := urlDeleter.GetLink(r.Context(), domain, alias)
+		if err == nil && !identity.CanManage(link.OwnerID) {
+			// Links of other users are reported as missing to not reveal them.
+			err = storage.ErrURLNotFound
+		}
+		if err == nil {
+			err = urlDeleter.DeleteURL(r.Context(), domain, alias)
+		}
 		if errors.Is(err, storage.ErrURLNotFound) {
-			log.Info("url not found", "alias", alias)
+			log.InfoContext(r.Context(), "url not found", "alias", alias)
 
 			render.Status(r, http.StatusNotFound)
 			render.JSON(w, r, response.Error("not found"))
 
 			return
 		}
+		if status, resp, ok := response.Unavailable(err); ok {
+			log.WarnContext(r.Context(), "storage unavailable", slogerr.Error(err))
+
+			render.Status(r, status)
+			render.JSON(w, r, resp)
+
+			return
+		}
 		if err != nil {
-			log.Error("failed to delete url", slogerr.Error(err))
+			log.ErrorContext(r.Context(), "failed to delete url", slogerr.Error(err))
 
 			render.Status(r, http.StatusInternalServerError)
 			render.JSON(w, r, response.Error("failed to delete url"))
@@ -58,7 +103,21 @@ func New(urlDeleter URLDeleter) http.HandlerFunc {
 			return
 		}
 
-		log.Info("url deleted")
+		log.InfoContext(r.Context(), "url deleted", slog.String("alias", alias))
+
+		linkCounter.CountLinkDeleted()
+
+		auditor.Record(r, audit.Event{
+			Action: audit.ActionDelete,
+			Domain: domain,
+			Alias:  alias,
+			Before: audit.LinkOf(link),
+		})
+
+		notifier.Notify(r.Context(), webhook.Event{
+			Type: webhook.EventLinkDeleted,
+			Link: webhook.Link{Domain: domain, Alias: alias, URL: link.URL, Tags: link.Tags},
+		})
 
 		render.JSON(w, r, Response{
 			Response: response.OK(),
@@ -4,30 +4,61 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"testing"
 
+	"github.com/dkhrunov/url-shortener/internal/auth"
+	"github.com/dkhrunov/url-shortener/internal/lib/audit"
+	"github.com/dkhrunov/url-shortener/internal/lib/webhook"
 	"github.com/dkhrunov/url-shortener/internal/storage"
 	"github.com/dkhrunov/url-shortener/internal/transport/http/handlers/url/delete"
 	"github.com/dkhrunov/url-shortener/internal/transport/http/handlers/url/delete/mocks"
 	"github.com/go-chi/chi/v5"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 )
 
 func TestDeleteHandler(t *testing.T) {
 	cases := []struct {
-		name      string
-		alias     string
-		respError string
-		mockError error
-		status    int
+		name        string
+		alias       string
+		domain      string
+		role        auth.Role
+		ownerID     int64
+		respError   string
+		getError    error
+		deleteError error
+		status      int
 	}{
 		{
-			name:   "Success",
-			alias:  "test_alias",
-			status: http.StatusOK,
+			name:    "Success",
+			alias:   "test_alias",
+			ownerID: 42,
+			status:  http.StatusOK,
+		},
+		{
+			name:    "Domain",
+			alias:   "test_alias",
+			domain:  "brand.example",
+			ownerID: 42,
+			status:  http.StatusOK,
+		},
+		{
+			name:    "Admin deletes foreign link",
+			alias:   "test_alias",
+			role:    auth.RoleAdmin,
+			ownerID: 7,
+			status:  http.StatusOK,
+		},
+		{
+			name:      "Foreign link",
+			alias:     "test_alias",
+			ownerID:   7,
+			respError: "not found",
+			status:    http.StatusNotFound,
 		},
 		{
 			name:      "Empty alias",
@@ -39,15 +70,24 @@ func TestDeleteHandler(t *testing.T) {
 			name:      "Not found",
 			alias:     "test_alias",
 			respError: "not found",
-			mockError: storage.ErrURLNotFound,
+			getError:  storage.ErrURLNotFound,
 			status:    http.StatusNotFound,
 		},
 		{
-			name:      "Failed",
-			alias:     "test_alias",
-			respError: "failed to delete url",
-			mockError: errors.New("unexpected error"),
-			status:    http.StatusInternalServerError,
+			name:        "Failed",
+			alias:       "test_alias",
+			ownerID:     42,
+			respError:   "failed to delete url",
+			deleteError: errors.New("unexpected error"),
+			status:      http.StatusInternalServerError,
+		},
+		{
+			name:        "Timed out",
+			alias:       "test_alias",
+			ownerID:     42,
+			respError:   "storage timed out",
+			deleteError: fmt.Errorf("storage.sqlite.DeleteURL: %w", context.DeadlineExceeded),
+			status:      http.StatusGatewayTimeout,
 		},
 	}
 
@@ -58,23 +98,60 @@ func TestDeleteHandler(t *testing.T) {
 			t.Parallel()
 
 			urlDeleterMock := mocks.NewURLDeleter(t)
+			linkCounterMock := mocks.NewLinkCounter(t)
+			auditorMock := mocks.NewAuditor(t)
+			notifierMock := mocks.NewNotifier(t)
 
-			if tc.respError == "" || tc.mockError != nil {
+			role := tc.role
+			if role == "" {
+				role = auth.RoleEditor
+			}
+			identity := auth.Identity{UserID: 42, Role: role}
+
+			if tc.alias != "" {
 				urlDeleterMock.EXPECT().
-					DeleteURL(tc.alias).
-					Return(tc.mockError).
+					GetLink(mock.Anything, tc.domain, tc.alias).
+					Return(storage.URL{Domain: tc.domain, Alias: tc.alias, OwnerID: tc.ownerID}, tc.getError).
+					Once()
+			}
+
+			if tc.getError == nil && identity.CanManage(tc.ownerID) {
+				urlDeleterMock.EXPECT().
+					DeleteURL(mock.Anything, tc.domain, tc.alias).
+					Return(tc.deleteError).
+					Once()
+			}
+
+			if tc.status == http.StatusOK {
+				linkCounterMock.EXPECT().CountLinkDeleted().Once()
+
+				auditorMock.EXPECT().
+					Record(mock.Anything, audit.Event{
+						Action: audit.ActionDelete,
+						Domain: tc.domain,
+						Alias:  tc.alias,
+						Before: audit.Link{},
+					}).
+					Once()
+
+				notifierMock.EXPECT().
+					Notify(mock.Anything, webhook.Event{
+						Type: webhook.EventLinkDeleted,
+						Link: webhook.Link{Domain: tc.domain, Alias: tc.alias},
+					}).
 					Once()
 			}
 
 			w := httptest.NewRecorder()
-			r := httptest.NewRequest(http.MethodDelete, "/url/{alias}", nil)
+			r := httptest.NewRequest(http.MethodDelete, "/url/{alias}?domain="+tc.domain, nil)
 
 			rctx := chi.NewRouteContext()
 			rctx.URLParams.Add("alias", tc.alias)
 
-			r = r.WithContext(context.WithValue(r.Context(), chi.RouteCtxKey, rctx))
+			ctx := context.WithValue(r.Context(), chi.RouteCtxKey, rctx)
+			r = r.WithContext(auth.WithIdentity(ctx, identity))
 
-			handler := delete.New(urlDeleterMock)
+			handler := delete.New(urlDeleterMock, linkCounterMock, auditorMock, notifierMock)
 			handler.ServeHTTP(w, r)
 
 			assert.Equal(t, tc.status, w.Code)
@@ -89,3 +166,14 @@ func TestDeleteHandler(t *testing.T) {
 		})
 	}
 }
+
+func TestDeleteHandlerUnauthenticated(t *testing.T) {
+	handler := delete.New(mocks.NewURLDeleter(t), mocks.NewLinkCounter(t), mocks.NewAuditor(t), mocks.NewNotifier(t))
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodDelete, "/url/test_alias", nil)
+
+	handler.ServeHTTP(w, r)
+
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+}
@@ -0,0 +1,72 @@
+// Code generated by mockery v2.39.1. DO NOT EDIT.
+
+package mocks
+
+import (
+	audit "github.com/dkhrunov/url-shortener/internal/lib/audit"
+
+	http "net/http"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// Auditor is an autogenerated mock type for the Auditor type
+type Auditor struct {
+	mock.Mock
+}
+
+type Auditor_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *Auditor) EXPECT() *Auditor_Expecter {
+	return &Auditor_Expecter{mock: &_m.Mock}
+}
+
+// Record provides a mock function with given fields: r, e
+func (_m *Auditor) Record(r *http.Request, e audit.Event) {
+	_m.Called(r, e)
+}
+
+// Auditor_Record_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Record'
+type Auditor_Record_Call struct {
+	*mock.Call
+}
+
+// Record is a helper method to define mock.On call
+//   - r *http.Request
+//   - e audit.Event
+func (_e *Auditor_Expecter) Record(r interface{}, e interface{}) *Auditor_Record_Call {
+	return &Auditor_Record_Call{Call: _e.mock.On("Record", r, e)}
+}
+
+func (_c *Auditor_Record_Call) Run(run func(r *http.Request, e audit.Event)) *Auditor_Record_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(*http.Request), args[1].(audit.Event))
+	})
+	return _c
+}
+
+func (_c *Auditor_Record_Call) Return() *Auditor_Record_Call {
+	_c.Call.Return()
+	return _c
+}
+
+func (_c *Auditor_Record_Call) RunAndReturn(run func(*http.Request, audit.Event)) *Auditor_Record_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewAuditor creates a new instance of Auditor. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewAuditor(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *Auditor {
+	mock := &Auditor{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
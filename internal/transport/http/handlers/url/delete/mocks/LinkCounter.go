@@ -0,0 +1,64 @@
+// Code generated by mockery v2.39.1. DO NOT EDIT.
+
+package mocks
+
+import mock "github.com/stretchr/testify/mock"
+
+// LinkCounter is an autogenerated mock type for the LinkCounter type
+type LinkCounter struct {
+	mock.Mock
+}
+
+type LinkCounter_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *LinkCounter) EXPECT() *LinkCounter_Expecter {
+	return &LinkCounter_Expecter{mock: &_m.Mock}
+}
+
+// CountLinkDeleted provides a mock function with given fields:
+func (_m *LinkCounter) CountLinkDeleted() {
+	_m.Called()
+}
+
+// LinkCounter_CountLinkDeleted_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CountLinkDeleted'
+type LinkCounter_CountLinkDeleted_Call struct {
+	*mock.Call
+}
+
+// CountLinkDeleted is a helper method to define mock.On call
+func (_e *LinkCounter_Expecter) CountLinkDeleted() *LinkCounter_CountLinkDeleted_Call {
+	return &LinkCounter_CountLinkDeleted_Call{Call: _e.mock.On("CountLinkDeleted")}
+}
+
+func (_c *LinkCounter_CountLinkDeleted_Call) Run(run func()) *LinkCounter_CountLinkDeleted_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *LinkCounter_CountLinkDeleted_Call) Return() *LinkCounter_CountLinkDeleted_Call {
+	_c.Call.Return()
+	return _c
+}
+
+func (_c *LinkCounter_CountLinkDeleted_Call) RunAndReturn(run func()) *LinkCounter_CountLinkDeleted_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewLinkCounter creates a new instance of LinkCounter. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewLinkCounter(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *LinkCounter {
+	mock := &LinkCounter{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
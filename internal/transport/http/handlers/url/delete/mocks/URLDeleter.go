@@ -2,7 +2,13 @@
 
 package mocks
 
-import mock "github.com/stretchr/testify/mock"
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+
+	storage "github.com/dkhrunov/url-shortener/internal/storage"
+)
 
 // URLDeleter is an autogenerated mock type for the URLDeleter type
 type URLDeleter struct {
@@ -17,17 +23,17 @@ func (_m *URLDeleter) EXPECT() *URLDeleter_Expecter {
 	return &URLDeleter_Expecter{mock: &_m.Mock}
 }
 
-// DeleteURL provides a mock function with given fields: alias
-func (_m *URLDeleter) DeleteURL(alias string) error {
-	ret := _m.Called(alias)
+// DeleteURL provides a mock function with given fields: ctx, domain, alias
+func (_m *URLDeleter) DeleteURL(ctx context.Context, domain string, alias string) error {
+	ret := _m.Called(ctx, domain, alias)
 
 	if len(ret) == 0 {
 		panic("no return value specified for DeleteURL")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(string) error); ok {
-		r0 = rf(alias)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) error); ok {
+		r0 = rf(ctx, domain, alias)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -41,14 +47,16 @@ type URLDeleter_DeleteURL_Call struct {
 }
 
 // DeleteURL is a helper method to define mock.On call
+//   - ctx context.Context
+//   - domain string
 //   - alias string
-func (_e *URLDeleter_Expecter) DeleteURL(alias interface{}) *URLDeleter_DeleteURL_Call {
-	return &URLDeleter_DeleteURL_Call{Call: _e.mock.On("DeleteURL", alias)}
+func (_e *URLDeleter_Expecter) DeleteURL(ctx interface{}, domain interface{}, alias interface{}) *URLDeleter_DeleteURL_Call {
+	return &URLDeleter_DeleteURL_Call{Call: _e.mock.On("DeleteURL", ctx, domain, alias)}
 }
 
-func (_c *URLDeleter_DeleteURL_Call) Run(run func(alias string)) *URLDeleter_DeleteURL_Call {
+func (_c *URLDeleter_DeleteURL_Call) Run(run func(ctx context.Context, domain string, alias string)) *URLDeleter_DeleteURL_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(string))
+		run(args[0].(context.Context), args[1].(string), args[2].(string))
 	})
 	return _c
 }
@@ -58,7 +66,65 @@ func (_c *URLDeleter_DeleteURL_Call) Return(_a0 error) *URLDeleter_DeleteURL_Cal
 	return _c
 }
 
-func (_c *URLDeleter_DeleteURL_Call) RunAndReturn(run func(string) error) *URLDeleter_DeleteURL_Call {
+func (_c *URLDeleter_DeleteURL_Call) RunAndReturn(run func(context.Context, string, string) error) *URLDeleter_DeleteURL_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetLink provides a mock function with given fields: ctx, domain, alias
+func (_m *URLDeleter) GetLink(ctx context.Context, domain string, alias string) (storage.URL, error) {
+	ret := _m.Called(ctx, domain, alias)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetLink")
+	}
+
+	var r0 storage.URL
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) (storage.URL, error)); ok {
+		return rf(ctx, domain, alias)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) storage.URL); ok {
+		r0 = rf(ctx, domain, alias)
+	} else {
+		r0 = ret.Get(0).(storage.URL)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, domain, alias)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// URLDeleter_GetLink_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetLink'
+type URLDeleter_GetLink_Call struct {
+	*mock.Call
+}
+
+// GetLink is a helper method to define mock.On call
+//   - ctx context.Context
+//   - domain string
+//   - alias string
+func (_e *URLDeleter_Expecter) GetLink(ctx interface{}, domain interface{}, alias interface{}) *URLDeleter_GetLink_Call {
+	return &URLDeleter_GetLink_Call{Call: _e.mock.On("GetLink", ctx, domain, alias)}
+}
+
+func (_c *URLDeleter_GetLink_Call) Run(run func(ctx context.Context, domain string, alias string)) *URLDeleter_GetLink_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(string))
+	})
+	return _c
+}
+
+func (_c *URLDeleter_GetLink_Call) Return(_a0 storage.URL, _a1 error) *URLDeleter_GetLink_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *URLDeleter_GetLink_Call) RunAndReturn(run func(context.Context, string, string) (storage.URL, error)) *URLDeleter_GetLink_Call {
 	_c.Call.Return(run)
 	return _c
 }
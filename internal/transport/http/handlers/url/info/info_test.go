@@ -0,0 +1,167 @@
+package info_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/dkhrunov/url-shortener/internal/auth"
+	"github.com/dkhrunov/url-shortener/internal/lib/shorturl"
+	"github.com/dkhrunov/url-shortener/internal/storage"
+	"github.com/dkhrunov/url-shortener/internal/transport/http/handlers/url/info"
+	"github.com/dkhrunov/url-shortener/internal/transport/http/handlers/url/info/mocks"
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInfoHandler(t *testing.T) {
+	cases := []struct {
+		name      string
+		alias     string
+		domain    string
+		host      string
+		url       string
+		ownerID   int64
+		role      auth.Role
+		shortURL  string
+		respError string
+		mockError error
+		status    int
+	}{
+		{
+			name:     "Success",
+			alias:    "test_alias",
+			url:      "https://google.com",
+			ownerID:  42,
+			shortURL: "https://sho.rt/test_alias",
+			status:   http.StatusOK,
+		},
+		{
+			name:     "Allowed host",
+			alias:    "test_alias",
+			host:     "go.example.org",
+			url:      "https://google.com",
+			ownerID:  42,
+			shortURL: "https://go.example.org/test_alias",
+			status:   http.StatusOK,
+		},
+		{
+			name:     "Domain",
+			alias:    "test_alias",
+			domain:   "brand.example",
+			url:      "https://google.com",
+			ownerID:  42,
+			shortURL: "https://brand.example/test_alias",
+			status:   http.StatusOK,
+		},
+		{
+			name:      "Foreign link",
+			alias:     "test_alias",
+			ownerID:   7,
+			respError: "not found",
+			status:    http.StatusNotFound,
+		},
+		{
+			name:     "Admin sees foreign link",
+			alias:    "test_alias",
+			url:      "https://google.com",
+			ownerID:  7,
+			role:     auth.RoleAdmin,
+			shortURL: "https://sho.rt/test_alias",
+			status:   http.StatusOK,
+		},
+		{
+			name:      "Host not allowed",
+			alias:     "test_alias",
+			host:      "evil.com",
+			respError: "host is not allowed",
+			status:    http.StatusBadRequest,
+		},
+		{
+			name:      "Empty alias",
+			alias:     "",
+			respError: "invalid request",
+			status:    http.StatusBadRequest,
+		},
+		{
+			name:      "Not found",
+			alias:     "test_alias",
+			respError: "not found",
+			mockError: storage.ErrURLNotFound,
+			status:    http.StatusNotFound,
+		},
+		{
+			name:      "Failed",
+			alias:     "test_alias",
+			respError: "failed to get url",
+			mockError: errors.New("unexpected error"),
+			status:    http.StatusInternalServerError,
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			linkGetterMock := mocks.NewLinkGetter(t)
+
+			if tc.respError == "" || tc.mockError != nil || tc.ownerID != 0 {
+				linkGetterMock.EXPECT().
+					GetLink(mock.Anything, tc.domain, tc.alias).
+					Return(storage.URL{Domain: tc.domain, Alias: tc.alias, URL: tc.url, OwnerID: tc.ownerID}, tc.mockError).
+					Once()
+			}
+
+			role := tc.role
+			if role == "" {
+				role = auth.RoleEditor
+			}
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodGet, "/url/{alias}?host="+tc.host+"&domain="+tc.domain, nil)
+
+			rctx := chi.NewRouteContext()
+			rctx.URLParams.Add("alias", tc.alias)
+
+			ctx := context.WithValue(r.Context(), chi.RouteCtxKey, rctx)
+			r = r.WithContext(auth.WithIdentity(ctx, auth.Identity{UserID: 42, Role: role}))
+
+			shortURLs, err := shorturl.New("https://sho.rt", []string{"go.example.org"})
+			require.NoError(t, err)
+
+			handler := info.New(linkGetterMock, shortURLs)
+			handler.ServeHTTP(w, r)
+
+			assert.Equal(t, tc.status, w.Code)
+
+			var resp info.Response
+
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+
+			assert.Equal(t, tc.respError, resp.Error)
+			assert.Equal(t, tc.url, resp.URL)
+			assert.Equal(t, tc.shortURL, resp.ShortURL)
+		})
+	}
+}
+
+func TestInfoHandlerUnauthenticated(t *testing.T) {
+	shortURLs, err := shorturl.New("https://sho.rt", nil)
+	require.NoError(t, err)
+
+	handler := info.New(mocks.NewLinkGetter(t), shortURLs)
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodGet, "/url/test_alias", nil)
+
+	handler.ServeHTTP(w, r)
+
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+}
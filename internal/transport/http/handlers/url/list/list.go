@@ -0,0 +1,159 @@
+package list
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/dkhrunov/url-shortener/internal/auth"
+	"github.com/dkhrunov/url-shortener/internal/lib/hostname"
+	"github.com/dkhrunov/url-shortener/internal/lib/logger/slog/slogerr"
+	"github.com/dkhrunov/url-shortener/internal/lib/shorturl"
+	"github.com/dkhrunov/url-shortener/internal/lib/tags"
+	"github.com/dkhrunov/url-shortener/internal/storage"
+	"github.com/dkhrunov/url-shortener/internal/transport/http/common/response"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+)
+
+const (
+	defaultLimit = 50
+	maxLimit     = 1000
+)
+
+type Item struct {
+	Alias     string     `json:"alias"`
+	URL       string     `json:"url"`
+	ShortURL  string     `json:"short_url"`
+	Tags      []string   `json:"tags"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+}
+
+type Response struct {
+	response.Response
+	Domain string `json:"domain,omitempty"`
+	Tag    string `json:"tag,omitempty"`
+	URLs   []Item `json:"urls"`
+	Limit  int    `json:"limit"`
+	Offset int    `json:"offset"`
+}
+
+type URLLister interface {
+	ListURLs(ctx context.Context, domain string, ownerID int64, tag string, limit, offset int) ([]storage.URL, error)
+}
+
+func New(urlLister URLLister, shortURLs *shorturl.Builder) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.url.list.New"
+
+		log := slog.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		identity, ok := auth.FromContext(r.Context())
+		if !ok {
+			log.ErrorContext(r.Context(), "request is not authenticated")
+
+			render.Status(r, http.StatusUnauthorized)
+			render.JSON(w, r, response.Error("unauthorized"))
+
+			return
+		}
+
+		q := r.URL.Query()
+
+		limit, err := intParam(q.Get("limit"), defaultLimit)
+		if err != nil || limit < 1 || limit > maxLimit {
+			log.InfoContext(r.Context(), "invalid limit", slog.String("limit", q.Get("limit")))
+
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, response.Error("invalid limit"))
+
+			return
+		}
+
+		offset, err := intParam(q.Get("offset"), 0)
+		if err != nil || offset < 0 {
+			log.InfoContext(r.Context(), "invalid offset", slog.String("offset", q.Get("offset")))
+
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, response.Error("invalid offset"))
+
+			return
+		}
+
+		tag := tags.Normalize(q.Get("tag"))
+		if tag != "" && !tags.Valid(tag) {
+			log.InfoContext(r.Context(), "invalid tag", slog.String("tag", q.Get("tag")))
+
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, response.Error("invalid tag"))
+
+			return
+		}
+
+		domain := hostname.Normalize(q.Get("domain"))
+		host := q.Get("host")
+
+		// Check the host up front so an invalid one is reported even for an empty page.
+		if _, err := shortURLs.Build(r, domain, host, ""); err != nil {
+			log.InfoContext(r.Context(), "host is not allowed", slog.String("host", host))
+
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, response.Error("host is not allowed"))
+
+			return
+		}
+
+		urls, err := urlLister.ListURLs(r.Context(), domain, identity.OwnerFilter(), tag, limit, offset)
+		if status, resp, ok := response.Unavailable(err); ok {
+			log.WarnContext(r.Context(), "storage unavailable", slogerr.Error(err))
+
+			render.Status(r, status)
+			render.JSON(w, r, resp)
+
+			return
+		}
+		if err != nil {
+			log.ErrorContext(r.Context(), "failed to list urls", slogerr.Error(err))
+
+			render.Status(r, http.StatusInternalServerError)
+			render.JSON(w, r, response.Error("failed to list urls"))
+
+			return
+		}
+
+		items := make([]Item, 0, len(urls))
+		for _, u := range urls {
+			shortURL, _ := shortURLs.Build(r, u.Domain, host, u.Alias)
+
+			items = append(items, Item{
+				Alias:     u.Alias,
+				URL:       u.URL,
+				ShortURL:  shortURL,
+				Tags:      u.Tags,
+				ExpiresAt: u.ExpiresAt,
+			})
+		}
+
+		render.JSON(w, r, Response{
+			Response: response.OK(),
+			Domain:   domain,
+			Tag:      tag,
+			URLs:     items,
+			Limit:    limit,
+			Offset:   offset,
+		})
+	}
+}
+
+func intParam(v string, def int) (int, error) {
+	if v == "" {
+		return def, nil
+	}
+
+	return strconv.Atoi(v)
+}
@@ -0,0 +1,172 @@
+package list_test
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/dkhrunov/url-shortener/internal/auth"
+	"github.com/dkhrunov/url-shortener/internal/lib/shorturl"
+	"github.com/dkhrunov/url-shortener/internal/storage"
+	"github.com/dkhrunov/url-shortener/internal/transport/http/handlers/url/list"
+	"github.com/dkhrunov/url-shortener/internal/transport/http/handlers/url/list/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestListHandler(t *testing.T) {
+	urls := []storage.URL{
+		{ID: 1, Alias: "first", URL: "https://google.com"},
+		{ID: 2, Alias: "second", URL: "https://go.dev"},
+	}
+
+	cases := []struct {
+		name      string
+		query     string
+		domain    string
+		tag       string
+		role      auth.Role
+		ownerID   int64
+		limit     int
+		offset    int
+		urls      []storage.URL
+		shortURLs []string
+		respError string
+		mockError error
+		status    int
+	}{
+		{
+			name:      "Success",
+			ownerID:   42,
+			limit:     50,
+			urls:      urls,
+			shortURLs: []string{"https://sho.rt/first", "https://sho.rt/second"},
+			status:    http.StatusOK,
+		},
+		{
+			name:      "Admin lists all",
+			role:      auth.RoleAdmin,
+			limit:     50,
+			urls:      urls,
+			shortURLs: []string{"https://sho.rt/first", "https://sho.rt/second"},
+			status:    http.StatusOK,
+		},
+		{
+			name:      "Pagination and host",
+			ownerID:   42,
+			query:     "?limit=2&offset=4&host=go.example.org",
+			limit:     2,
+			offset:    4,
+			urls:      urls,
+			shortURLs: []string{"https://go.example.org/first", "https://go.example.org/second"},
+			status:    http.StatusOK,
+		},
+		{
+			name:    "Domain",
+			query:   "?domain=brand.example",
+			domain:  "brand.example",
+			ownerID: 42,
+			limit:   50,
+			urls: []storage.URL{
+				{ID: 3, Domain: "brand.example", Alias: "first", URL: "https://google.com"},
+			},
+			shortURLs: []string{"https://brand.example/first"},
+			status:    http.StatusOK,
+		},
+		{
+			name:    "Tag",
+			query:   "?tag=Spring-Sale",
+			tag:     "spring-sale",
+			ownerID: 42,
+			limit:   50,
+			urls: []storage.URL{
+				{ID: 4, Alias: "first", URL: "https://google.com", Tags: []string{"spring-sale", "team-a"}},
+			},
+			shortURLs: []string{"https://sho.rt/first"},
+			status:    http.StatusOK,
+		},
+		{
+			name:      "Invalid tag",
+			query:     "?tag=spring%20sale",
+			respError: "invalid tag",
+			status:    http.StatusBadRequest,
+		},
+		{
+			name:      "Invalid limit",
+			query:     "?limit=0",
+			respError: "invalid limit",
+			status:    http.StatusBadRequest,
+		},
+		{
+			name:      "Invalid offset",
+			query:     "?offset=abc",
+			respError: "invalid offset",
+			status:    http.StatusBadRequest,
+		},
+		{
+			name:      "Host not allowed",
+			query:     "?host=evil.com",
+			respError: "host is not allowed",
+			status:    http.StatusBadRequest,
+		},
+		{
+			name:      "Failed",
+			ownerID:   42,
+			limit:     50,
+			respError: "failed to list urls",
+			mockError: errors.New("unexpected error"),
+			status:    http.StatusInternalServerError,
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			urlListerMock := mocks.NewURLLister(t)
+
+			if tc.respError == "" || tc.mockError != nil {
+				urlListerMock.EXPECT().
+					ListURLs(mock.Anything, tc.domain, tc.ownerID, tc.tag, tc.limit, tc.offset).
+					Return(tc.urls, tc.mockError).
+					Once()
+			}
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodGet, "/url"+tc.query, nil)
+
+			role := tc.role
+			if role == "" {
+				role = auth.RoleEditor
+			}
+
+			r = r.WithContext(auth.WithIdentity(r.Context(), auth.Identity{UserID: 42, Role: role}))
+
+			shortURLs, err := shorturl.New("https://sho.rt", []string{"go.example.org"})
+			require.NoError(t, err)
+
+			handler := list.New(urlListerMock, shortURLs)
+			handler.ServeHTTP(w, r)
+
+			assert.Equal(t, tc.status, w.Code)
+
+			var resp list.Response
+
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+
+			assert.Equal(t, tc.respError, resp.Error)
+
+			require.Len(t, resp.URLs, len(tc.shortURLs))
+			for i, item := range resp.URLs {
+				assert.Equal(t, tc.urls[i].Alias, item.Alias)
+				assert.Equal(t, tc.shortURLs[i], item.ShortURL)
+				assert.Equal(t, tc.urls[i].Tags, item.Tags)
+			}
+		})
+	}
+}
@@ -0,0 +1,101 @@
+// Code generated by mockery v2.39.1. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+
+	storage "github.com/dkhrunov/url-shortener/internal/storage"
+)
+
+// URLLister is an autogenerated mock type for the URLLister type
+type URLLister struct {
+	mock.Mock
+}
+
+type URLLister_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *URLLister) EXPECT() *URLLister_Expecter {
+	return &URLLister_Expecter{mock: &_m.Mock}
+}
+
+// ListURLs provides a mock function with given fields: ctx, domain, ownerID, tag, limit, offset
+func (_m *URLLister) ListURLs(ctx context.Context, domain string, ownerID int64, tag string, limit int, offset int) ([]storage.URL, error) {
+	ret := _m.Called(ctx, domain, ownerID, tag, limit, offset)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListURLs")
+	}
+
+	var r0 []storage.URL
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, int64, string, int, int) ([]storage.URL, error)); ok {
+		return rf(ctx, domain, ownerID, tag, limit, offset)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, int64, string, int, int) []storage.URL); ok {
+		r0 = rf(ctx, domain, ownerID, tag, limit, offset)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]storage.URL)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, int64, string, int, int) error); ok {
+		r1 = rf(ctx, domain, ownerID, tag, limit, offset)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// URLLister_ListURLs_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListURLs'
+type URLLister_ListURLs_Call struct {
+	*mock.Call
+}
+
+// ListURLs is a helper method to define mock.On call
+//   - ctx context.Context
+//   - domain string
+//   - ownerID int64
+//   - tag string
+//   - limit int
+//   - offset int
+func (_e *URLLister_Expecter) ListURLs(ctx interface{}, domain interface{}, ownerID interface{}, tag interface{}, limit interface{}, offset interface{}) *URLLister_ListURLs_Call {
+	return &URLLister_ListURLs_Call{Call: _e.mock.On("ListURLs", ctx, domain, ownerID, tag, limit, offset)}
+}
+
+func (_c *URLLister_ListURLs_Call) Run(run func(ctx context.Context, domain string, ownerID int64, tag string, limit int, offset int)) *URLLister_ListURLs_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(int64), args[3].(string), args[4].(int), args[5].(int))
+	})
+	return _c
+}
+
+func (_c *URLLister_ListURLs_Call) Return(_a0 []storage.URL, _a1 error) *URLLister_ListURLs_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *URLLister_ListURLs_Call) RunAndReturn(run func(context.Context, string, int64, string, int, int) ([]storage.URL, error)) *URLLister_ListURLs_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewURLLister creates a new instance of URLLister. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewURLLister(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *URLLister {
+	mock := &URLLister{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,203 @@
+// Code generated by mockery v2.39.1. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+
+	storage "github.com/dkhrunov/url-shortener/internal/storage"
+)
+
+// URLRestorer is an autogenerated mock type for the URLRestorer type
+type URLRestorer struct {
+	mock.Mock
+}
+
+type URLRestorer_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *URLRestorer) EXPECT() *URLRestorer_Expecter {
+	return &URLRestorer_Expecter{mock: &_m.Mock}
+}
+
+// GetDeletedURL provides a mock function with given fields: ctx, domain, alias
+func (_m *URLRestorer) GetDeletedURL(ctx context.Context, domain string, alias string) (storage.URL, error) {
+	ret := _m.Called(ctx, domain, alias)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetDeletedURL")
+	}
+
+	var r0 storage.URL
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) (storage.URL, error)); ok {
+		return rf(ctx, domain, alias)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) storage.URL); ok {
+		r0 = rf(ctx, domain, alias)
+	} else {
+		r0 = ret.Get(0).(storage.URL)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, domain, alias)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// URLRestorer_GetDeletedURL_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetDeletedURL'
+type URLRestorer_GetDeletedURL_Call struct {
+	*mock.Call
+}
+
+// GetDeletedURL is a helper method to define mock.On call
+//   - ctx context.Context
+//   - domain string
+//   - alias string
+func (_e *URLRestorer_Expecter) GetDeletedURL(ctx interface{}, domain interface{}, alias interface{}) *URLRestorer_GetDeletedURL_Call {
+	return &URLRestorer_GetDeletedURL_Call{Call: _e.mock.On("GetDeletedURL", ctx, domain, alias)}
+}
+
+func (_c *URLRestorer_GetDeletedURL_Call) Run(run func(ctx context.Context, domain string, alias string)) *URLRestorer_GetDeletedURL_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(string))
+	})
+	return _c
+}
+
+func (_c *URLRestorer_GetDeletedURL_Call) Return(_a0 storage.URL, _a1 error) *URLRestorer_GetDeletedURL_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *URLRestorer_GetDeletedURL_Call) RunAndReturn(run func(context.Context, string, string) (storage.URL, error)) *URLRestorer_GetDeletedURL_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// LinkUsage provides a mock function with given fields: ctx, userID, apiKeyID
+func (_m *URLRestorer) LinkUsage(ctx context.Context, userID int64, apiKeyID int64) (storage.LinkUsage, error) {
+	ret := _m.Called(ctx, userID, apiKeyID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for LinkUsage")
+	}
+
+	var r0 storage.LinkUsage
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int64) (storage.LinkUsage, error)); ok {
+		return rf(ctx, userID, apiKeyID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int64) storage.LinkUsage); ok {
+		r0 = rf(ctx, userID, apiKeyID)
+	} else {
+		r0 = ret.Get(0).(storage.LinkUsage)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64, int64) error); ok {
+		r1 = rf(ctx, userID, apiKeyID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// URLRestorer_LinkUsage_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'LinkUsage'
+type URLRestorer_LinkUsage_Call struct {
+	*mock.Call
+}
+
+// LinkUsage is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID int64
+//   - apiKeyID int64
+func (_e *URLRestorer_Expecter) LinkUsage(ctx interface{}, userID interface{}, apiKeyID interface{}) *URLRestorer_LinkUsage_Call {
+	return &URLRestorer_LinkUsage_Call{Call: _e.mock.On("LinkUsage", ctx, userID, apiKeyID)}
+}
+
+func (_c *URLRestorer_LinkUsage_Call) Run(run func(ctx context.Context, userID int64, apiKeyID int64)) *URLRestorer_LinkUsage_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int64), args[2].(int64))
+	})
+	return _c
+}
+
+func (_c *URLRestorer_LinkUsage_Call) Return(_a0 storage.LinkUsage, _a1 error) *URLRestorer_LinkUsage_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *URLRestorer_LinkUsage_Call) RunAndReturn(run func(context.Context, int64, int64) (storage.LinkUsage, error)) *URLRestorer_LinkUsage_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// RestoreURL provides a mock function with given fields: ctx, domain, alias, limits
+func (_m *URLRestorer) RestoreURL(ctx context.Context, domain string, alias string, limits storage.LinkLimits) error {
+	ret := _m.Called(ctx, domain, alias, limits)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RestoreURL")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, storage.LinkLimits) error); ok {
+		r0 = rf(ctx, domain, alias, limits)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// URLRestorer_RestoreURL_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RestoreURL'
+type URLRestorer_RestoreURL_Call struct {
+	*mock.Call
+}
+
+// RestoreURL is a helper method to define mock.On call
+//   - ctx context.Context
+//   - domain string
+//   - alias string
+//   - limits storage.LinkLimits
+func (_e *URLRestorer_Expecter) RestoreURL(ctx interface{}, domain interface{}, alias interface{}, limits interface{}) *URLRestorer_RestoreURL_Call {
+	return &URLRestorer_RestoreURL_Call{Call: _e.mock.On("RestoreURL", ctx, domain, alias, limits)}
+}
+
+func (_c *URLRestorer_RestoreURL_Call) Run(run func(ctx context.Context, domain string, alias string, limits storage.LinkLimits)) *URLRestorer_RestoreURL_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(string), args[3].(storage.LinkLimits))
+	})
+	return _c
+}
+
+func (_c *URLRestorer_RestoreURL_Call) Return(_a0 error) *URLRestorer_RestoreURL_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *URLRestorer_RestoreURL_Call) RunAndReturn(run func(context.Context, string, string, storage.LinkLimits) error) *URLRestorer_RestoreURL_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewURLRestorer creates a new instance of URLRestorer. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewURLRestorer(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *URLRestorer {
+	mock := &URLRestorer{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,133 @@
+package restore
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/dkhrunov/url-shortener/internal/auth"
+	"github.com/dkhrunov/url-shortener/internal/lib/audit"
+	"github.com/dkhrunov/url-shortener/internal/lib/hostname"
+	"github.com/dkhrunov/url-shortener/internal/lib/logger/slog/slogerr"
+	"github.com/dkhrunov/url-shortener/internal/lib/quota"
+	"github.com/dkhrunov/url-shortener/internal/storage"
+	"github.com/dkhrunov/url-shortener/internal/transport/http/common/response"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+)
+
+type Response struct {
+	response.Response
+}
+
+type URLRestorer interface {
+	GetDeletedURL(ctx context.Context, domain, alias string) (storage.URL, error)
+	RestoreURL(ctx context.Context, domain, alias string, limits storage.LinkLimits) error
+	LinkUsage(ctx context.Context, userID, apiKeyID int64) (storage.LinkUsage, error)
+}
+
+type Auditor interface {
+	Record(r *http.Request, e audit.Event)
+}
+
+// New restores a deleted link that was not purged yet. The restored link is active
+// again, so it's refused when that goes over the active links limit of its creator.
+func New(urlRestorer URLRestorer, limits quota.Limits, auditor Auditor) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.url.restore.New"
+
+		log := slog.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		identity, ok := auth.FromContext(r.Context())
+		if !ok {
+			log.ErrorContext(r.Context(), "request is not authenticated")
+
+			render.Status(r, http.StatusUnauthorized)
+			render.JSON(w, r, response.Error("unauthorized"))
+
+			return
+		}
+
+		alias := chi.URLParam(r, "alias")
+		if alias == "" {
+			log.InfoContext(r.Context(), "alias is empty")
+
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, response.Error("invalid request"))
+
+			return
+		}
+
+		domain := hostname.Normalize(r.URL.Query().Get("domain"))
+
+		link, err := urlRestorer.GetDeletedURL(r.Context(), domain, alias)
+		if err == nil && !identity.CanManage(link.OwnerID) {
+			// Links of other users are reported as missing to not reveal them.
+			err = storage.ErrURLNotFound
+		}
+		if err == nil {
+			err = urlRestorer.RestoreURL(r.Context(), domain, alias, storage.LinkLimits(limits))
+		}
+		if errors.Is(err, storage.ErrURLNotFound) {
+			log.InfoContext(r.Context(), "deleted url not found", "alias", alias)
+
+			render.Status(r, http.StatusNotFound)
+			render.JSON(w, r, response.Error("not found"))
+
+			return
+		}
+		if errors.Is(err, storage.ErrQuotaExceeded) {
+			log.InfoContext(r.Context(), "quota exceeded", slogerr.Error(err))
+
+			// The headers describe the usage of the owner the link counts towards.
+			if link.OwnerID != 0 {
+				usage, err := urlRestorer.LinkUsage(r.Context(), link.OwnerID, 0)
+				if err != nil {
+					log.WarnContext(r.Context(), "failed to get quota usage", slogerr.Error(err))
+				} else {
+					limits.SetHeaders(w.Header(), quota.Usage(usage), time.Now())
+				}
+			}
+
+			render.Status(r, http.StatusTooManyRequests)
+			render.JSON(w, r, response.Error(quota.ErrActiveExceeded.Error()))
+
+			return
+		}
+		if status, resp, ok := response.Unavailable(err); ok {
+			log.WarnContext(r.Context(), "storage unavailable", slogerr.Error(err))
+
+			render.Status(r, status)
+			render.JSON(w, r, resp)
+
+			return
+		}
+		if err != nil {
+			log.ErrorContext(r.Context(), "failed to restore url", slogerr.Error(err))
+
+			render.Status(r, http.StatusInternalServerError)
+			render.JSON(w, r, response.Error("failed to restore url"))
+
+			return
+		}
+
+		log.InfoContext(r.Context(), "url restored", slog.String("alias", alias))
+
+		auditor.Record(r, audit.Event{
+			Action: audit.ActionRestore,
+			Domain: domain,
+			Alias:  alias,
+			After:  audit.LinkOf(link),
+		})
+
+		render.JSON(w, r, Response{
+			Response: response.OK(),
+		})
+	}
+}
@@ -0,0 +1,170 @@
+package restore_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/dkhrunov/url-shortener/internal/auth"
+	"github.com/dkhrunov/url-shortener/internal/lib/audit"
+	"github.com/dkhrunov/url-shortener/internal/lib/quota"
+	"github.com/dkhrunov/url-shortener/internal/storage"
+	"github.com/dkhrunov/url-shortener/internal/transport/http/handlers/url/restore"
+	"github.com/dkhrunov/url-shortener/internal/transport/http/handlers/url/restore/mocks"
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRestoreHandler(t *testing.T) {
+	limits := quota.Limits{ActiveLinks: 3}
+
+	cases := []struct {
+		name         string
+		alias        string
+		domain       string
+		role         auth.Role
+		ownerID      int64
+		respError    string
+		getError     error
+		restoreError error
+		status       int
+	}{
+		{
+			name:    "Success",
+			alias:   "test_alias",
+			ownerID: 42,
+			status:  http.StatusOK,
+		},
+		{
+			name:    "Domain",
+			alias:   "test_alias",
+			domain:  "brand.example",
+			ownerID: 42,
+			status:  http.StatusOK,
+		},
+		{
+			name:    "Admin restores foreign link",
+			alias:   "test_alias",
+			role:    auth.RoleAdmin,
+			ownerID: 7,
+			status:  http.StatusOK,
+		},
+		{
+			name:      "Foreign link",
+			alias:     "test_alias",
+			ownerID:   7,
+			respError: "not found",
+			status:    http.StatusNotFound,
+		},
+		{
+			name:      "Empty alias",
+			alias:     "",
+			respError: "invalid request",
+			status:    http.StatusBadRequest,
+		},
+		{
+			name:      "Not deleted or purged",
+			alias:     "test_alias",
+			respError: "not found",
+			getError:  storage.ErrURLNotFound,
+			status:    http.StatusNotFound,
+		},
+		{
+			name:         "Active quota exceeded",
+			alias:        "test_alias",
+			ownerID:      42,
+			respError:    "active link quota exceeded",
+			restoreError: storage.ErrActiveQuotaExceeded,
+			status:       http.StatusTooManyRequests,
+		},
+		{
+			name:         "Failed",
+			alias:        "test_alias",
+			ownerID:      42,
+			respError:    "failed to restore url",
+			restoreError: errors.New("unexpected error"),
+			status:       http.StatusInternalServerError,
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			urlRestorerMock := mocks.NewURLRestorer(t)
+			auditorMock := mocks.NewAuditor(t)
+
+			role := tc.role
+			if role == "" {
+				role = auth.RoleEditor
+			}
+			identity := auth.Identity{UserID: 42, Role: role}
+
+			deletedAt := time.Now()
+
+			if tc.alias != "" {
+				urlRestorerMock.EXPECT().
+					GetDeletedURL(mock.Anything, tc.domain, tc.alias).
+					Return(storage.URL{Domain: tc.domain, Alias: tc.alias, OwnerID: tc.ownerID, DeletedAt: &deletedAt}, tc.getError).
+					Once()
+			}
+
+			if tc.getError == nil && identity.CanManage(tc.ownerID) {
+				urlRestorerMock.EXPECT().
+					RestoreURL(mock.Anything, tc.domain, tc.alias, storage.LinkLimits(limits)).
+					Return(tc.restoreError).
+					Once()
+			}
+
+			if tc.status == http.StatusTooManyRequests {
+				urlRestorerMock.EXPECT().
+					LinkUsage(mock.Anything, tc.ownerID, int64(0)).
+					Return(storage.LinkUsage{ActiveLinks: 3}, nil).
+					Once()
+			}
+
+			if tc.status == http.StatusOK {
+				auditorMock.EXPECT().
+					Record(mock.Anything, audit.Event{
+						Action: audit.ActionRestore,
+						Domain: tc.domain,
+						Alias:  tc.alias,
+						After:  audit.Link{},
+					}).
+					Once()
+			}
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodPost, "/url/{alias}/restore?domain="+tc.domain, nil)
+
+			rctx := chi.NewRouteContext()
+			rctx.URLParams.Add("alias", tc.alias)
+
+			ctx := context.WithValue(r.Context(), chi.RouteCtxKey, rctx)
+			r = r.WithContext(auth.WithIdentity(ctx, identity))
+
+			handler := restore.New(urlRestorerMock, limits, auditorMock)
+			handler.ServeHTTP(w, r)
+
+			assert.Equal(t, tc.status, w.Code)
+
+			if tc.status == http.StatusTooManyRequests {
+				assert.Equal(t, "0", w.Header().Get(quota.HeaderActiveRemaining))
+			}
+
+			var resp restore.Response
+
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+
+			assert.Equal(t, tc.respError, resp.Error)
+		})
+	}
+}
@@ -0,0 +1,64 @@
+// Code generated by mockery v2.39.1. DO NOT EDIT.
+
+package mocks
+
+import mock "github.com/stretchr/testify/mock"
+
+// LinkCounter is an autogenerated mock type for the LinkCounter type
+type LinkCounter struct {
+	mock.Mock
+}
+
+type LinkCounter_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *LinkCounter) EXPECT() *LinkCounter_Expecter {
+	return &LinkCounter_Expecter{mock: &_m.Mock}
+}
+
+// CountLinkCreated provides a mock function with given fields:
+func (_m *LinkCounter) CountLinkCreated() {
+	_m.Called()
+}
+
+// LinkCounter_CountLinkCreated_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CountLinkCreated'
+type LinkCounter_CountLinkCreated_Call struct {
+	*mock.Call
+}
+
+// CountLinkCreated is a helper method to define mock.On call
+func (_e *LinkCounter_Expecter) CountLinkCreated() *LinkCounter_CountLinkCreated_Call {
+	return &LinkCounter_CountLinkCreated_Call{Call: _e.mock.On("CountLinkCreated")}
+}
+
+func (_c *LinkCounter_CountLinkCreated_Call) Run(run func()) *LinkCounter_CountLinkCreated_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *LinkCounter_CountLinkCreated_Call) Return() *LinkCounter_CountLinkCreated_Call {
+	_c.Call.Return()
+	return _c
+}
+
+func (_c *LinkCounter_CountLinkCreated_Call) RunAndReturn(run func()) *LinkCounter_CountLinkCreated_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewLinkCounter creates a new instance of LinkCounter. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewLinkCounter(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *LinkCounter {
+	mock := &LinkCounter{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
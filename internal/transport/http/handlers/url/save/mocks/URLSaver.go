@@ -2,7 +2,13 @@
 
 package mocks
 
-import mock "github.com/stretchr/testify/mock"
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+
+	storage "github.com/dkhrunov/url-shortener/internal/storage"
+)
 
 // URLSaver is an autogenerated mock type for the URLSaver type
 type URLSaver struct {
@@ -17,9 +23,67 @@ func (_m *URLSaver) EXPECT() *URLSaver_Expecter {
 	return &URLSaver_Expecter{mock: &_m.Mock}
 }
 
-// SaveURL provides a mock function with given fields: urlToSave, alias
-func (_m *URLSaver) SaveURL(urlToSave string, alias string) (int64, error) {
-	ret := _m.Called(urlToSave, alias)
+// LinkUsage provides a mock function with given fields: ctx, userID, apiKeyID
+func (_m *URLSaver) LinkUsage(ctx context.Context, userID int64, apiKeyID int64) (storage.LinkUsage, error) {
+	ret := _m.Called(ctx, userID, apiKeyID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for LinkUsage")
+	}
+
+	var r0 storage.LinkUsage
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int64) (storage.LinkUsage, error)); ok {
+		return rf(ctx, userID, apiKeyID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int64) storage.LinkUsage); ok {
+		r0 = rf(ctx, userID, apiKeyID)
+	} else {
+		r0 = ret.Get(0).(storage.LinkUsage)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64, int64) error); ok {
+		r1 = rf(ctx, userID, apiKeyID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// URLSaver_LinkUsage_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'LinkUsage'
+type URLSaver_LinkUsage_Call struct {
+	*mock.Call
+}
+
+// LinkUsage is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID int64
+//   - apiKeyID int64
+func (_e *URLSaver_Expecter) LinkUsage(ctx interface{}, userID interface{}, apiKeyID interface{}) *URLSaver_LinkUsage_Call {
+	return &URLSaver_LinkUsage_Call{Call: _e.mock.On("LinkUsage", ctx, userID, apiKeyID)}
+}
+
+func (_c *URLSaver_LinkUsage_Call) Run(run func(ctx context.Context, userID int64, apiKeyID int64)) *URLSaver_LinkUsage_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int64), args[2].(int64))
+	})
+	return _c
+}
+
+func (_c *URLSaver_LinkUsage_Call) Return(_a0 storage.LinkUsage, _a1 error) *URLSaver_LinkUsage_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *URLSaver_LinkUsage_Call) RunAndReturn(run func(context.Context, int64, int64) (storage.LinkUsage, error)) *URLSaver_LinkUsage_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// SaveURL provides a mock function with given fields: ctx, domain, urlToSave, alias, ownerID, apiKeyID, tags, limits
+func (_m *URLSaver) SaveURL(ctx context.Context, domain string, urlToSave string, alias string, ownerID int64, apiKeyID int64, tags []string, limits storage.LinkLimits) (int64, error) {
+	ret := _m.Called(ctx, domain, urlToSave, alias, ownerID, apiKeyID, tags, limits)
 
 	if len(ret) == 0 {
 		panic("no return value specified for SaveURL")
@@ -27,17 +91,17 @@ func (_m *URLSaver) SaveURL(urlToSave string, alias string) (int64, error) {
 
 	var r0 int64
 	var r1 error
-	if rf, ok := ret.Get(0).(func(string, string) (int64, error)); ok {
-		return rf(urlToSave, alias)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string, int64, int64, []string, storage.LinkLimits) (int64, error)); ok {
+		return rf(ctx, domain, urlToSave, alias, ownerID, apiKeyID, tags, limits)
 	}
-	if rf, ok := ret.Get(0).(func(string, string) int64); ok {
-		r0 = rf(urlToSave, alias)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string, int64, int64, []string, storage.LinkLimits) int64); ok {
+		r0 = rf(ctx, domain, urlToSave, alias, ownerID, apiKeyID, tags, limits)
 	} else {
 		r0 = ret.Get(0).(int64)
 	}
 
-	if rf, ok := ret.Get(1).(func(string, string) error); ok {
-		r1 = rf(urlToSave, alias)
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, string, int64, int64, []string, storage.LinkLimits) error); ok {
+		r1 = rf(ctx, domain, urlToSave, alias, ownerID, apiKeyID, tags, limits)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -51,15 +115,21 @@ type URLSaver_SaveURL_Call struct {
 }
 
 // SaveURL is a helper method to define mock.On call
+//   - ctx context.Context
+//   - domain string
 //   - urlToSave string
 //   - alias string
-func (_e *URLSaver_Expecter) SaveURL(urlToSave interface{}, alias interface{}) *URLSaver_SaveURL_Call {
-	return &URLSaver_SaveURL_Call{Call: _e.mock.On("SaveURL", urlToSave, alias)}
+//   - ownerID int64
+//   - apiKeyID int64
+//   - tags []string
+//   - limits storage.LinkLimits
+func (_e *URLSaver_Expecter) SaveURL(ctx interface{}, domain interface{}, urlToSave interface{}, alias interface{}, ownerID interface{}, apiKeyID interface{}, tags interface{}, limits interface{}) *URLSaver_SaveURL_Call {
+	return &URLSaver_SaveURL_Call{Call: _e.mock.On("SaveURL", ctx, domain, urlToSave, alias, ownerID, apiKeyID, tags, limits)}
 }
 
-func (_c *URLSaver_SaveURL_Call) Run(run func(urlToSave string, alias string)) *URLSaver_SaveURL_Call {
+func (_c *URLSaver_SaveURL_Call) Run(run func(ctx context.Context, domain string, urlToSave string, alias string, ownerID int64, apiKeyID int64, tags []string, limits storage.LinkLimits)) *URLSaver_SaveURL_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(string), args[1].(string))
+		run(args[0].(context.Context), args[1].(string), args[2].(string), args[3].(string), args[4].(int64), args[5].(int64), args[6].([]string), args[7].(storage.LinkLimits))
 	})
 	return _c
 }
@@ -69,7 +139,7 @@ func (_c *URLSaver_SaveURL_Call) Return(_a0 int64, _a1 error) *URLSaver_SaveURL_
 	return _c
 }
 
-func (_c *URLSaver_SaveURL_Call) RunAndReturn(run func(string, string) (int64, error)) *URLSaver_SaveURL_Call {
+func (_c *URLSaver_SaveURL_Call) RunAndReturn(run func(context.Context, string, string, string, int64, int64, []string, storage.LinkLimits) (int64, error)) *URLSaver_SaveURL_Call {
 	_c.Call.Return(run)
 	return _c
 }
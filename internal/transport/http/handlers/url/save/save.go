@@ -1,12 +1,24 @@
 package save
 
 import (
+	"context"
 	"errors"
 	"log/slog"
+	"math"
 	"net/http"
+	"slices"
+	"strconv"
+	"time"
 
+	"github.com/dkhrunov/url-shortener/internal/auth"
+	"github.com/dkhrunov/url-shortener/internal/lib/audit"
+	"github.com/dkhrunov/url-shortener/internal/lib/hostname"
 	"github.com/dkhrunov/url-shortener/internal/lib/logger/slog/slogerr"
+	"github.com/dkhrunov/url-shortener/internal/lib/quota"
 	"github.com/dkhrunov/url-shortener/internal/lib/random"
+	"github.com/dkhrunov/url-shortener/internal/lib/shorturl"
+	"github.com/dkhrunov/url-shortener/internal/lib/tags"
+	"github.com/dkhrunov/url-shortener/internal/lib/webhook"
 	"github.com/dkhrunov/url-shortener/internal/lib/zero"
 	"github.com/dkhrunov/url-shortener/internal/storage"
 	"github.com/dkhrunov/url-shortener/internal/transport/http/common/response"
@@ -16,23 +28,48 @@ import (
 )
 
 type Request struct {
-	URL   string `json:"url" validate:"required,url"`
-	Alias string `json:"alias,omitempty"`
+	URL    string `json:"url" validate:"required,url"`
+	Alias  string `json:"alias,omitempty"`
+	Host   string `json:"host,omitempty"`
+	Domain string `json:"domain,omitempty"`
+	// Tags group the link with others, see tags.NormalizeList.
+	Tags []string `json:"tags,omitempty"`
 }
 
 type Response struct {
 	response.Response
-	Alias string `json:"alias,omitempty"`
+	Alias    string   `json:"alias,omitempty"`
+	Domain   string   `json:"domain,omitempty"`
+	ShortURL string   `json:"short_url,omitempty"`
+	Tags     []string `json:"tags,omitempty"`
 }
 
 // TODO: move to config
 const aliasLength = 6
 
+// reservedAliases are the top-level paths routed to something other than a redirect.
+var reservedAliases = []string{"admin", "audit", "events", "healthz", "metrics", "readyz", "url"}
+
 type URLSaver interface {
-	SaveURL(urlToSave, alias string) (int64, error)
+	SaveURL(ctx context.Context, domain, urlToSave, alias string, ownerID, apiKeyID int64, tags []string, limits storage.LinkLimits) (int64, error)
+	LinkUsage(ctx context.Context, userID, apiKeyID int64) (storage.LinkUsage, error)
+}
+
+type LinkCounter interface {
+	CountLinkCreated()
+}
+
+type Auditor interface {
+	Record(r *http.Request, e audit.Event)
 }
 
-func New(urlSaver URLSaver) http.HandlerFunc {
+type Notifier interface {
+	Notify(ctx context.Context, e webhook.Event)
+}
+
+// New returns the handler creating links. Requests of users and API keys are
+// subject to limits; the built-in admin is not.
+func New(urlSaver URLSaver, shortURLs *shorturl.Builder, limits quota.Limits, linkCounter LinkCounter, auditor Auditor, notifier Notifier) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		const op = "handlers.url.save.New"
 
@@ -41,12 +78,22 @@ func New(urlSaver URLSaver) http.HandlerFunc {
 			slog.String("request_id", middleware.GetReqID(r.Context())),
 		)
 
+		identity, ok := auth.FromContext(r.Context())
+		if !ok {
+			log.ErrorContext(r.Context(), "request is not authenticated")
+
+			render.Status(r, http.StatusUnauthorized)
+			render.JSON(w, r, response.Error("unauthorized"))
+
+			return
+		}
+
 		// Decode request
 		var req Request
 
 		err := render.DecodeJSON(r.Body, &req)
 		if err != nil {
-			log.Error("failed to decode request body", slogerr.Error(err))
+			log.ErrorContext(r.Context(), "failed to decode request body", slogerr.Error(err))
 
 			render.Status(r, http.StatusBadRequest)
 			render.JSON(w, r, response.Error("failed to decode request"))
@@ -54,13 +101,13 @@ func New(urlSaver URLSaver) http.HandlerFunc {
 			return
 		}
 
-		log.Info("request body decoded", slog.Any("request", req))
+		log.InfoContext(r.Context(), "request body decoded", slog.Any("request", req))
 
 		// Validation
 		if err := validator.New().Struct(req); err != nil {
 			validateErr := err.(validator.ValidationErrors)
 
-			log.Error("invalid request", slogerr.Error(err))
+			log.ErrorContext(r.Context(), "invalid request", slogerr.Error(err))
 
 			render.Status(r, http.StatusBadRequest)
 			render.JSON(w, r, response.ValidationError(validateErr))
@@ -68,22 +115,104 @@ func New(urlSaver URLSaver) http.HandlerFunc {
 			return
 		}
 
+		if slices.Contains(reservedAliases, req.Alias) {
+			log.InfoContext(r.Context(), "alias is reserved", slog.String("alias", req.Alias))
+
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, response.Error("alias is reserved"))
+
+			return
+		}
+
+		linkTags, err := tags.NormalizeList(req.Tags)
+		if err != nil {
+			log.InfoContext(r.Context(), "invalid tags", slogerr.Error(err))
+
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, response.Error(err.Error()))
+
+			return
+		}
+
 		alias := req.Alias
 		if alias == zero.Zero[string]() {
 			alias = random.RandomString(aliasLength)
 		}
 
-		id, err := urlSaver.SaveURL(req.URL, alias)
+		domain := hostname.Normalize(req.Domain)
+
+		shortURL, err := shortURLs.Build(r, domain, req.Host, alias)
+		if err != nil {
+			log.InfoContext(r.Context(), "host is not allowed", slog.String("host", req.Host))
+
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, response.Error("host is not allowed"))
+
+			return
+		}
+
+		// Quota, enforced by the storage within the transaction saving the link.
+		limited := limits.Enabled() && (identity.UserID != 0 || identity.KeyID != 0)
+
+		var linkLimits storage.LinkLimits
+		if limited {
+			linkLimits = storage.LinkLimits(limits)
+		}
+
+		id, err := urlSaver.SaveURL(r.Context(), domain, req.URL, alias, identity.UserID, identity.KeyID, linkTags, linkLimits)
+		if errors.Is(err, storage.ErrDomainNotFound) {
+			log.InfoContext(r.Context(), "domain not found", slog.String("domain", domain))
+
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, response.Error("domain not found"))
+
+			return
+		}
+		if errors.Is(err, storage.ErrURLGone) {
+			log.InfoContext(r.Context(), "alias is tombstoned", slog.String("alias", alias))
+
+			render.Status(r, http.StatusConflict)
+			render.JSON(w, r, response.Error("alias was deleted and can't be reused"))
+
+			return
+		}
 		if errors.Is(err, storage.ErrURLExist) {
-			log.Info("url already exists", slog.String("url", req.URL))
+			log.InfoContext(r.Context(), "url already exists", slog.String("url", req.URL))
 
 			render.Status(r, http.StatusConflict)
 			render.JSON(w, r, response.Error("url already exists"))
 
 			return
 		}
+		if errors.Is(err, storage.ErrQuotaExceeded) {
+			log.InfoContext(r.Context(), "quota exceeded", slogerr.Error(err))
+
+			now := time.Now()
+			setQuotaHeaders(r.Context(), log, w.Header(), urlSaver, limits, identity, now)
+
+			exceeded := quota.ErrActiveExceeded
+			if errors.Is(err, storage.ErrDailyQuotaExceeded) {
+				exceeded = quota.ErrDailyExceeded
+
+				retryAfter := int(math.Ceil(quota.NextReset(now).Sub(now).Seconds()))
+				w.Header().Set("Retry-After", strconv.Itoa(retryAfter))
+			}
+
+			render.Status(r, http.StatusTooManyRequests)
+			render.JSON(w, r, response.Error(exceeded.Error()))
+
+			return
+		}
+		if status, resp, ok := response.Unavailable(err); ok {
+			log.WarnContext(r.Context(), "storage unavailable", slogerr.Error(err))
+
+			render.Status(r, status)
+			render.JSON(w, r, resp)
+
+			return
+		}
 		if err != nil {
-			log.Error("failed to add url", slogerr.Error(err))
+			log.ErrorContext(r.Context(), "failed to add url", slogerr.Error(err))
 
 			render.Status(r, http.StatusInternalServerError)
 			render.JSON(w, r, response.Error("failed to add url"))
@@ -91,11 +220,45 @@ func New(urlSaver URLSaver) http.HandlerFunc {
 			return
 		}
 
-		log.Info("url added", slog.Int64("id", id))
+		log.InfoContext(r.Context(), "url added", slog.Int64("id", id))
+
+		linkCounter.CountLinkCreated()
+
+		auditor.Record(r, audit.Event{
+			Action: audit.ActionCreate,
+			Domain: domain,
+			Alias:  alias,
+			After:  audit.Link{URL: req.URL, Tags: linkTags},
+		})
+
+		notifier.Notify(r.Context(), webhook.Event{
+			Type: webhook.EventLinkCreated,
+			Link: webhook.Link{Domain: domain, Alias: alias, URL: req.URL, Tags: linkTags},
+		})
+
+		if limited {
+			setQuotaHeaders(r.Context(), log, w.Header(), urlSaver, limits, identity, time.Now())
+		}
 
 		render.JSON(w, r, Response{
 			Response: response.OK(),
 			Alias:    alias,
+			Domain:   domain,
+			ShortURL: shortURL,
+			Tags:     linkTags,
 		})
 	}
 }
+
+// setQuotaHeaders describes the quota usage of the identity in the response headers.
+// They're left out when the usage can't be read, the link being saved or refused already.
+func setQuotaHeaders(ctx context.Context, log *slog.Logger, h http.Header, urlSaver URLSaver, limits quota.Limits, identity auth.Identity, now time.Time) {
+	usage, err := urlSaver.LinkUsage(ctx, identity.UserID, identity.KeyID)
+	if err != nil {
+		log.WarnContext(ctx, "failed to get quota usage", slogerr.Error(err))
+
+		return
+	}
+
+	limits.SetHeaders(h, quota.Usage(usage), now)
+}
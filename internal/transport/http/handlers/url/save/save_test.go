@@ -2,17 +2,25 @@ package save_test
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 
+	"github.com/dkhrunov/url-shortener/internal/auth"
+	"github.com/dkhrunov/url-shortener/internal/lib/audit"
+	"github.com/dkhrunov/url-shortener/internal/lib/quota"
+	"github.com/dkhrunov/url-shortener/internal/lib/shorturl"
+	"github.com/dkhrunov/url-shortener/internal/lib/tags"
+	"github.com/dkhrunov/url-shortener/internal/lib/webhook"
 	"github.com/dkhrunov/url-shortener/internal/storage"
 	"github.com/dkhrunov/url-shortener/internal/transport/http/handlers/url/save"
 	"github.com/dkhrunov/url-shortener/internal/transport/http/handlers/url/save/mocks"
@@ -23,15 +31,79 @@ func TestSaveHandler(t *testing.T) {
 		name      string
 		alias     string
 		url       string
+		host      string
+		domain    string
+		tags      string
+		wantTags  []string
+		shortURL  string
 		respError string
 		mockError error
 		status    int
 	}{
 		{
-			name:   "Success",
-			alias:  "test_alias",
-			url:    "https://google.com",
-			status: http.StatusOK,
+			name:     "Success",
+			alias:    "test_alias",
+			url:      "https://google.com",
+			shortURL: "https://sho.rt/test_alias",
+			status:   http.StatusOK,
+		},
+		{
+			name:     "Allowed host",
+			alias:    "test_alias",
+			url:      "https://google.com",
+			host:     "go.example.org",
+			shortURL: "https://go.example.org/test_alias",
+			status:   http.StatusOK,
+		},
+		{
+			name:     "Domain",
+			alias:    "test_alias",
+			url:      "https://google.com",
+			domain:   "Brand.example",
+			shortURL: "https://brand.example/test_alias",
+			status:   http.StatusOK,
+		},
+		{
+			name:     "Tags",
+			alias:    "test_alias",
+			url:      "https://google.com",
+			tags:     `["Team-A", "spring_sale", "team-a"]`,
+			wantTags: []string{"spring_sale", "team-a"},
+			shortURL: "https://sho.rt/test_alias",
+			status:   http.StatusOK,
+		},
+		{
+			name:      "Invalid tag",
+			alias:     "test_alias",
+			url:       "https://google.com",
+			tags:      `["spring sale"]`,
+			respError: tags.ErrInvalid.Error(),
+			status:    http.StatusBadRequest,
+		},
+		{
+			name:      "Too many tags",
+			alias:     "test_alias",
+			url:       "https://google.com",
+			tags:      `["a", "b", "c", "d", "e", "f", "g", "h", "i", "j", "k"]`,
+			respError: tags.ErrTooMany.Error(),
+			status:    http.StatusBadRequest,
+		},
+		{
+			name:      "Domain not found",
+			alias:     "test_alias",
+			url:       "https://google.com",
+			domain:    "unknown.example",
+			respError: "domain not found",
+			mockError: storage.ErrDomainNotFound,
+			status:    http.StatusBadRequest,
+		},
+		{
+			name:      "Host not allowed",
+			alias:     "test_alias",
+			url:       "https://google.com",
+			host:      "evil.com",
+			respError: "host is not allowed",
+			status:    http.StatusBadRequest,
 		},
 		{
 			name:   "Empty alias",
@@ -53,6 +125,13 @@ func TestSaveHandler(t *testing.T) {
 			respError: "field URL is not a valid URL",
 			status:    http.StatusBadRequest,
 		},
+		{
+			name:      "Reserved alias",
+			alias:     "healthz",
+			url:       "https://google.com",
+			respError: "alias is reserved",
+			status:    http.StatusBadRequest,
+		},
 		{
 			name:      "Alias exist",
 			alias:     "test_alias",
@@ -61,6 +140,14 @@ func TestSaveHandler(t *testing.T) {
 			mockError: storage.ErrURLExist,
 			status:    http.StatusConflict,
 		},
+		{
+			name:      "Alias tombstoned",
+			alias:     "test_alias",
+			url:       "https://google.com",
+			respError: "alias was deleted and can't be reused",
+			mockError: storage.ErrURLGone,
+			status:    http.StatusConflict,
+		},
 		{
 			name:      "SaveURL Error",
 			alias:     "test_alias",
@@ -69,6 +156,14 @@ func TestSaveHandler(t *testing.T) {
 			mockError: errors.New("unexpected error"),
 			status:    http.StatusInternalServerError,
 		},
+		{
+			name:      "SaveURL timed out",
+			alias:     "test_alias",
+			url:       "https://google.com",
+			respError: "storage timed out",
+			mockError: fmt.Errorf("storage.sqlite.SaveURL: %w", context.DeadlineExceeded),
+			status:    http.StatusGatewayTimeout,
+		},
 	}
 
 	for _, tc := range cases {
@@ -78,21 +173,60 @@ func TestSaveHandler(t *testing.T) {
 			t.Parallel()
 
 			urlSaverMock := mocks.NewURLSaver(t)
+			linkCounterMock := mocks.NewLinkCounter(t)
+			auditorMock := mocks.NewAuditor(t)
+			notifierMock := mocks.NewNotifier(t)
+
+			wantTags := tc.wantTags
+			if wantTags == nil {
+				wantTags = []string{}
+			}
 
 			if tc.respError == "" || tc.mockError != nil {
 				urlSaverMock.EXPECT().
-					SaveURL(tc.url, mock.AnythingOfType("string")).
+					SaveURL(mock.Anything, strings.ToLower(tc.domain), tc.url, mock.AnythingOfType("string"), int64(42), int64(0), wantTags, storage.LinkLimits{}).
 					Return(int64(1), tc.mockError).
 					Once()
 			}
 
-			handler := save.New(urlSaverMock)
+			if tc.status == http.StatusOK {
+				linkCounterMock.EXPECT().CountLinkCreated().Once()
+
+				auditorMock.EXPECT().
+					Record(mock.Anything, mock.MatchedBy(func(e audit.Event) bool {
+						return e.Action == audit.ActionCreate && e.Before == nil &&
+							e.After.(audit.Link).URL == tc.url
+					})).
+					Once()
+
+				notifierMock.EXPECT().
+					Notify(mock.Anything, mock.MatchedBy(func(e webhook.Event) bool {
+						return e.Type == webhook.EventLinkCreated && e.Link.URL == tc.url &&
+							e.Link.Domain == strings.ToLower(tc.domain)
+					})).
+					Once()
+			}
+
+			shortURLs, err := shorturl.New("https://sho.rt", []string{"go.example.org"})
+			require.NoError(t, err)
+
+			handler := save.New(urlSaverMock, shortURLs, quota.Limits{}, linkCounterMock, auditorMock, notifierMock)
+
+			reqTags := tc.tags
+			if reqTags == "" {
+				reqTags = "null"
+			}
 
-			input := fmt.Sprintf(`{"url": "%s", "alias": "%s"}`, tc.url, tc.alias)
+			input := fmt.Sprintf(
+				`{"url": "%s", "alias": "%s", "host": "%s", "domain": "%s", "tags": %s}`,
+				tc.url, tc.alias, tc.host, tc.domain, reqTags,
+			)
 
 			req, err := http.NewRequest(http.MethodPost, "/save", bytes.NewReader([]byte(input)))
 			require.NoError(t, err)
 
+			req = req.WithContext(auth.WithIdentity(req.Context(), auth.Identity{UserID: 42, Role: auth.RoleEditor}))
+
 			rr := httptest.NewRecorder()
 			handler.ServeHTTP(rr, req)
 
@@ -106,7 +240,159 @@ func TestSaveHandler(t *testing.T) {
 
 			assert.Equal(t, tc.respError, resp.Error)
 
+			if tc.shortURL != "" {
+				assert.Equal(t, tc.shortURL, resp.ShortURL)
+				assert.Equal(t, tc.wantTags, resp.Tags)
+			}
+
 			// TODO: add more checks
 		})
 	}
 }
+
+func TestSaveHandlerUnauthenticated(t *testing.T) {
+	shortURLs, err := shorturl.New("https://sho.rt", nil)
+	require.NoError(t, err)
+
+	handler := save.New(mocks.NewURLSaver(t), shortURLs, quota.Limits{}, mocks.NewLinkCounter(t), mocks.NewAuditor(t), mocks.NewNotifier(t))
+
+	req := httptest.NewRequest(http.MethodPost, "/save", bytes.NewReader([]byte(`{"url": "https://google.com"}`)))
+
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusUnauthorized, rr.Code)
+}
+
+func TestSaveHandlerQuota(t *testing.T) {
+	cases := []struct {
+		name       string
+		identity   auth.Identity
+		limits     quota.Limits
+		linkLimits storage.LinkLimits
+		saveError  error
+		usage      storage.LinkUsage
+		usageError error
+		respError  string
+		status     int
+		headers    map[string]string
+		retryAfter bool
+	}{
+		{
+			name:       "User under quota",
+			identity:   auth.Identity{UserID: 42, Role: auth.RoleEditor},
+			limits:     quota.Limits{LinksPerDay: 10, ActiveLinks: 100},
+			linkLimits: storage.LinkLimits{LinksPerDay: 10, ActiveLinks: 100},
+			usage:      storage.LinkUsage{LinksToday: 4, ActiveLinks: 51},
+			status:     http.StatusOK,
+			headers: map[string]string{
+				quota.HeaderDailyLimit:      "10",
+				quota.HeaderDailyRemaining:  "6",
+				quota.HeaderActiveLimit:     "100",
+				quota.HeaderActiveRemaining: "49",
+			},
+		},
+		{
+			name:       "Daily quota exceeded",
+			identity:   auth.Identity{UserID: 42, Role: auth.RoleEditor},
+			limits:     quota.Limits{LinksPerDay: 10},
+			linkLimits: storage.LinkLimits{LinksPerDay: 10},
+			saveError:  fmt.Errorf("storage.sqlite.SaveURL: %w", storage.ErrDailyQuotaExceeded),
+			usage:      storage.LinkUsage{LinksToday: 10},
+			respError:  "daily link quota exceeded",
+			status:     http.StatusTooManyRequests,
+			headers: map[string]string{
+				quota.HeaderDailyLimit:     "10",
+				quota.HeaderDailyRemaining: "0",
+			},
+			retryAfter: true,
+		},
+		{
+			name:       "Active quota exceeded for key",
+			identity:   auth.Identity{KeyID: 7, Role: auth.RoleAdmin},
+			limits:     quota.Limits{ActiveLinks: 100},
+			linkLimits: storage.LinkLimits{ActiveLinks: 100},
+			saveError:  fmt.Errorf("storage.sqlite.SaveURL: %w", storage.ErrActiveQuotaExceeded),
+			usage:      storage.LinkUsage{ActiveLinks: 100},
+			respError:  "active link quota exceeded",
+			status:     http.StatusTooManyRequests,
+			headers: map[string]string{
+				quota.HeaderActiveRemaining: "0",
+			},
+		},
+		{
+			name:     "Built-in admin is not limited",
+			identity: auth.Identity{Role: auth.RoleAdmin},
+			limits:   quota.Limits{LinksPerDay: 1},
+			status:   http.StatusOK,
+		},
+		{
+			name:       "Usage error",
+			identity:   auth.Identity{UserID: 42, Role: auth.RoleEditor},
+			limits:     quota.Limits{LinksPerDay: 10},
+			linkLimits: storage.LinkLimits{LinksPerDay: 10},
+			usageError: errors.New("unexpected error"),
+			status:     http.StatusOK,
+			headers: map[string]string{
+				quota.HeaderDailyLimit: "",
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			urlSaverMock := mocks.NewURLSaver(t)
+			linkCounterMock := mocks.NewLinkCounter(t)
+			auditorMock := mocks.NewAuditor(t)
+			notifierMock := mocks.NewNotifier(t)
+
+			urlSaverMock.EXPECT().
+				SaveURL(mock.Anything, "", "https://google.com", "test_alias", tc.identity.UserID, tc.identity.KeyID, []string{}, tc.linkLimits).
+				Return(int64(1), tc.saveError).
+				Once()
+
+			if tc.identity.UserID != 0 || tc.identity.KeyID != 0 {
+				urlSaverMock.EXPECT().
+					LinkUsage(mock.Anything, tc.identity.UserID, tc.identity.KeyID).
+					Return(tc.usage, tc.usageError).
+					Once()
+			}
+
+			if tc.saveError == nil {
+				linkCounterMock.EXPECT().CountLinkCreated().Once()
+				auditorMock.EXPECT().Record(mock.Anything, mock.Anything).Once()
+				notifierMock.EXPECT().Notify(mock.Anything, mock.Anything).Once()
+			}
+
+			shortURLs, err := shorturl.New("https://sho.rt", nil)
+			require.NoError(t, err)
+
+			handler := save.New(urlSaverMock, shortURLs, tc.limits, linkCounterMock, auditorMock, notifierMock)
+
+			input := `{"url": "https://google.com", "alias": "test_alias"}`
+
+			req := httptest.NewRequest(http.MethodPost, "/save", bytes.NewReader([]byte(input)))
+			req = req.WithContext(auth.WithIdentity(req.Context(), tc.identity))
+
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, req)
+
+			assert.Equal(t, tc.status, rr.Code)
+
+			var resp save.Response
+
+			require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+
+			assert.Equal(t, tc.respError, resp.Error)
+
+			for k, v := range tc.headers {
+				assert.Equal(t, v, rr.Header().Get(k), k)
+			}
+			assert.Equal(t, tc.retryAfter, rr.Header().Get("Retry-After") != "")
+		})
+	}
+}
@@ -0,0 +1,144 @@
+// Code generated by mockery v2.39.1. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	storage "github.com/dkhrunov/url-shortener/internal/storage"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// URLUpdater is an autogenerated mock type for the URLUpdater type
+type URLUpdater struct {
+	mock.Mock
+}
+
+type URLUpdater_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *URLUpdater) EXPECT() *URLUpdater_Expecter {
+	return &URLUpdater_Expecter{mock: &_m.Mock}
+}
+
+// GetLink provides a mock function with given fields: ctx, domain, alias
+func (_m *URLUpdater) GetLink(ctx context.Context, domain string, alias string) (storage.URL, error) {
+	ret := _m.Called(ctx, domain, alias)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetLink")
+	}
+
+	var r0 storage.URL
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) (storage.URL, error)); ok {
+		return rf(ctx, domain, alias)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) storage.URL); ok {
+		r0 = rf(ctx, domain, alias)
+	} else {
+		r0 = ret.Get(0).(storage.URL)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, domain, alias)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// URLUpdater_GetLink_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetLink'
+type URLUpdater_GetLink_Call struct {
+	*mock.Call
+}
+
+// GetLink is a helper method to define mock.On call
+//   - ctx context.Context
+//   - domain string
+//   - alias string
+func (_e *URLUpdater_Expecter) GetLink(ctx interface{}, domain interface{}, alias interface{}) *URLUpdater_GetLink_Call {
+	return &URLUpdater_GetLink_Call{Call: _e.mock.On("GetLink", ctx, domain, alias)}
+}
+
+func (_c *URLUpdater_GetLink_Call) Run(run func(ctx context.Context, domain string, alias string)) *URLUpdater_GetLink_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(string))
+	})
+	return _c
+}
+
+func (_c *URLUpdater_GetLink_Call) Return(_a0 storage.URL, _a1 error) *URLUpdater_GetLink_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *URLUpdater_GetLink_Call) RunAndReturn(run func(context.Context, string, string) (storage.URL, error)) *URLUpdater_GetLink_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// UpdateURL provides a mock function with given fields: ctx, domain, alias, upd
+func (_m *URLUpdater) UpdateURL(ctx context.Context, domain string, alias string, upd storage.LinkUpdate) error {
+	ret := _m.Called(ctx, domain, alias, upd)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateURL")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, storage.LinkUpdate) error); ok {
+		r0 = rf(ctx, domain, alias, upd)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// URLUpdater_UpdateURL_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UpdateURL'
+type URLUpdater_UpdateURL_Call struct {
+	*mock.Call
+}
+
+// UpdateURL is a helper method to define mock.On call
+//   - ctx context.Context
+//   - domain string
+//   - alias string
+//   - upd storage.LinkUpdate
+func (_e *URLUpdater_Expecter) UpdateURL(ctx interface{}, domain interface{}, alias interface{}, upd interface{}) *URLUpdater_UpdateURL_Call {
+	return &URLUpdater_UpdateURL_Call{Call: _e.mock.On("UpdateURL", ctx, domain, alias, upd)}
+}
+
+func (_c *URLUpdater_UpdateURL_Call) Run(run func(ctx context.Context, domain string, alias string, upd storage.LinkUpdate)) *URLUpdater_UpdateURL_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(string), args[3].(storage.LinkUpdate))
+	})
+	return _c
+}
+
+func (_c *URLUpdater_UpdateURL_Call) Return(_a0 error) *URLUpdater_UpdateURL_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *URLUpdater_UpdateURL_Call) RunAndReturn(run func(context.Context, string, string, storage.LinkUpdate) error) *URLUpdater_UpdateURL_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewURLUpdater creates a new instance of URLUpdater. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewURLUpdater(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *URLUpdater {
+	mock := &URLUpdater{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,166 @@
+package update
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/dkhrunov/url-shortener/internal/auth"
+	"github.com/dkhrunov/url-shortener/internal/lib/audit"
+	"github.com/dkhrunov/url-shortener/internal/lib/hostname"
+	"github.com/dkhrunov/url-shortener/internal/lib/logger/slog/slogerr"
+	"github.com/dkhrunov/url-shortener/internal/lib/tags"
+	"github.com/dkhrunov/url-shortener/internal/storage"
+	"github.com/dkhrunov/url-shortener/internal/transport/http/common/response"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+	"github.com/go-playground/validator/v10"
+)
+
+// Request changes the target, the tags or both. Tags replace the tags of the link when
+// present; an empty list removes them.
+type Request struct {
+	URL  string    `json:"url,omitempty" validate:"required_without=Tags,omitempty,url"`
+	Tags *[]string `json:"tags,omitempty"`
+}
+
+type Response struct {
+	response.Response
+}
+
+type URLUpdater interface {
+	GetLink(ctx context.Context, domain, alias string) (storage.URL, error)
+	UpdateURL(ctx context.Context, domain, alias string, upd storage.LinkUpdate) error
+}
+
+type Auditor interface {
+	Record(r *http.Request, e audit.Event)
+}
+
+func New(urlUpdater URLUpdater, auditor Auditor) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.url.update.New"
+
+		log := slog.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		identity, ok := auth.FromContext(r.Context())
+		if !ok {
+			log.ErrorContext(r.Context(), "request is not authenticated")
+
+			render.Status(r, http.StatusUnauthorized)
+			render.JSON(w, r, response.Error("unauthorized"))
+
+			return
+		}
+
+		alias := chi.URLParam(r, "alias")
+		if alias == "" {
+			log.InfoContext(r.Context(), "alias is empty")
+
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, response.Error("invalid request"))
+
+			return
+		}
+
+		var req Request
+
+		err := render.DecodeJSON(r.Body, &req)
+		if err != nil {
+			log.ErrorContext(r.Context(), "failed to decode request body", slogerr.Error(err))
+
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, response.Error("failed to decode request"))
+
+			return
+		}
+
+		if err := validator.New().Struct(req); err != nil {
+			validateErr := err.(validator.ValidationErrors)
+
+			log.ErrorContext(r.Context(), "invalid request", slogerr.Error(err))
+
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, response.ValidationError(validateErr))
+
+			return
+		}
+
+		upd := storage.LinkUpdate{URL: req.URL}
+		if req.Tags != nil {
+			linkTags, err := tags.NormalizeList(*req.Tags)
+			if err != nil {
+				log.InfoContext(r.Context(), "invalid tags", slogerr.Error(err))
+
+				render.Status(r, http.StatusBadRequest)
+				render.JSON(w, r, response.Error(err.Error()))
+
+				return
+			}
+
+			upd.Tags = &linkTags
+		}
+
+		domain := hostname.Normalize(r.URL.Query().Get("domain"))
+
+		link, err := urlUpdater.GetLink(r.Context(), domain, alias)
+		if err == nil && !identity.CanManage(link.OwnerID) {
+			// Links of other users are reported as missing to not reveal them.
+			err = storage.ErrURLNotFound
+		}
+		if err == nil {
+			err = urlUpdater.UpdateURL(r.Context(), domain, alias, upd)
+		}
+		if errors.Is(err, storage.ErrURLNotFound) {
+			log.InfoContext(r.Context(), "url not found", "alias", alias)
+
+			render.Status(r, http.StatusNotFound)
+			render.JSON(w, r, response.Error("not found"))
+
+			return
+		}
+		if status, resp, ok := response.Unavailable(err); ok {
+			log.WarnContext(r.Context(), "storage unavailable", slogerr.Error(err))
+
+			render.Status(r, status)
+			render.JSON(w, r, resp)
+
+			return
+		}
+		if err != nil {
+			log.ErrorContext(r.Context(), "failed to update url", slogerr.Error(err))
+
+			render.Status(r, http.StatusInternalServerError)
+			render.JSON(w, r, response.Error("failed to update url"))
+
+			return
+		}
+
+		log.InfoContext(r.Context(), "url updated", slog.String("alias", alias))
+
+		after := link
+		if upd.URL != "" {
+			after.URL = upd.URL
+		}
+		if upd.Tags != nil {
+			after.Tags = *upd.Tags
+		}
+
+		auditor.Record(r, audit.Event{
+			Action: audit.ActionUpdate,
+			Domain: domain,
+			Alias:  alias,
+			Before: audit.LinkOf(link),
+			After:  audit.LinkOf(after),
+		})
+
+		render.JSON(w, r, Response{
+			Response: response.OK(),
+		})
+	}
+}
@@ -0,0 +1,213 @@
+package update_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/dkhrunov/url-shortener/internal/auth"
+	"github.com/dkhrunov/url-shortener/internal/lib/audit"
+	"github.com/dkhrunov/url-shortener/internal/lib/tags"
+	"github.com/dkhrunov/url-shortener/internal/storage"
+	"github.com/dkhrunov/url-shortener/internal/transport/http/handlers/url/update"
+	"github.com/dkhrunov/url-shortener/internal/transport/http/handlers/url/update/mocks"
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUpdateHandler(t *testing.T) {
+	cases := []struct {
+		name        string
+		alias       string
+		domain      string
+		url         string
+		tags        string
+		update      storage.LinkUpdate
+		role        auth.Role
+		ownerID     int64
+		respError   string
+		getError    error
+		updateError error
+		status      int
+	}{
+		{
+			name:    "Success",
+			alias:   "test_alias",
+			url:     "https://go.dev",
+			ownerID: 42,
+			status:  http.StatusOK,
+		},
+		{
+			name:    "Domain",
+			alias:   "test_alias",
+			domain:  "brand.example",
+			url:     "https://go.dev",
+			ownerID: 42,
+			status:  http.StatusOK,
+		},
+		{
+			name:    "Admin updates foreign link",
+			alias:   "test_alias",
+			url:     "https://go.dev",
+			role:    auth.RoleAdmin,
+			ownerID: 7,
+			status:  http.StatusOK,
+		},
+		{
+			name:      "Foreign link",
+			alias:     "test_alias",
+			url:       "https://go.dev",
+			ownerID:   7,
+			respError: "not found",
+			status:    http.StatusNotFound,
+		},
+		{
+			name:    "Tags",
+			alias:   "test_alias",
+			tags:    `["Team-A", "promo"]`,
+			update:  storage.LinkUpdate{Tags: &[]string{"promo", "team-a"}},
+			ownerID: 42,
+			status:  http.StatusOK,
+		},
+		{
+			name:    "URL and no tags",
+			alias:   "test_alias",
+			url:     "https://go.dev",
+			tags:    `[]`,
+			update:  storage.LinkUpdate{URL: "https://go.dev", Tags: &[]string{}},
+			ownerID: 42,
+			status:  http.StatusOK,
+		},
+		{
+			name:      "Invalid tag",
+			alias:     "test_alias",
+			tags:      `["a b"]`,
+			respError: tags.ErrInvalid.Error(),
+			status:    http.StatusBadRequest,
+		},
+		{
+			name:      "Nothing to update",
+			alias:     "test_alias",
+			respError: "field URL is a required field",
+			status:    http.StatusBadRequest,
+		},
+		{
+			name:      "Invalid URL",
+			alias:     "test_alias",
+			url:       "some invalid URL",
+			respError: "field URL is not a valid URL",
+			status:    http.StatusBadRequest,
+		},
+		{
+			name:      "Empty alias",
+			alias:     "",
+			url:       "https://go.dev",
+			respError: "invalid request",
+			status:    http.StatusBadRequest,
+		},
+		{
+			name:      "Not found",
+			alias:     "test_alias",
+			url:       "https://go.dev",
+			respError: "not found",
+			getError:  storage.ErrURLNotFound,
+			status:    http.StatusNotFound,
+		},
+		{
+			name:        "Failed",
+			alias:       "test_alias",
+			url:         "https://go.dev",
+			ownerID:     42,
+			respError:   "failed to update url",
+			updateError: errors.New("unexpected error"),
+			status:      http.StatusInternalServerError,
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			urlUpdaterMock := mocks.NewURLUpdater(t)
+			auditorMock := mocks.NewAuditor(t)
+
+			role := tc.role
+			if role == "" {
+				role = auth.RoleEditor
+			}
+			identity := auth.Identity{UserID: 42, Role: role}
+
+			if tc.ownerID != 0 || tc.getError != nil {
+				urlUpdaterMock.EXPECT().
+					GetLink(mock.Anything, tc.domain, tc.alias).
+					Return(storage.URL{Domain: tc.domain, Alias: tc.alias, OwnerID: tc.ownerID}, tc.getError).
+					Once()
+			}
+
+			upd := tc.update
+			if upd.URL == "" && upd.Tags == nil {
+				upd.URL = tc.url
+			}
+
+			if tc.getError == nil && tc.ownerID != 0 && identity.CanManage(tc.ownerID) {
+				urlUpdaterMock.EXPECT().
+					UpdateURL(mock.Anything, tc.domain, tc.alias, upd).
+					Return(tc.updateError).
+					Once()
+			}
+
+			reqTags := tc.tags
+			if reqTags == "" {
+				reqTags = "null"
+			}
+
+			input := fmt.Sprintf(`{"url": "%s", "tags": %s}`, tc.url, reqTags)
+
+			if tc.status == http.StatusOK {
+				after := audit.Link{URL: upd.URL}
+				if upd.Tags != nil {
+					after.Tags = *upd.Tags
+				}
+
+				auditorMock.EXPECT().
+					Record(mock.Anything, audit.Event{
+						Action: audit.ActionUpdate,
+						Domain: tc.domain,
+						Alias:  tc.alias,
+						Before: audit.Link{},
+						After:  after,
+					}).
+					Once()
+			}
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodPatch, "/url/{alias}?domain="+tc.domain, bytes.NewReader([]byte(input)))
+
+			rctx := chi.NewRouteContext()
+			rctx.URLParams.Add("alias", tc.alias)
+
+			ctx := context.WithValue(r.Context(), chi.RouteCtxKey, rctx)
+			r = r.WithContext(auth.WithIdentity(ctx, identity))
+
+			handler := update.New(urlUpdaterMock, auditorMock)
+			handler.ServeHTTP(w, r)
+
+			assert.Equal(t, tc.status, w.Code)
+
+			var resp update.Response
+
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+
+			assert.Equal(t, tc.respError, resp.Error)
+		})
+	}
+}
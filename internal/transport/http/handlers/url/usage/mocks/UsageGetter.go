@@ -0,0 +1,95 @@
+// Code generated by mockery v2.39.1. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	storage "github.com/dkhrunov/url-shortener/internal/storage"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// UsageGetter is an autogenerated mock type for the UsageGetter type
+type UsageGetter struct {
+	mock.Mock
+}
+
+type UsageGetter_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *UsageGetter) EXPECT() *UsageGetter_Expecter {
+	return &UsageGetter_Expecter{mock: &_m.Mock}
+}
+
+// LinkUsage provides a mock function with given fields: ctx, userID, apiKeyID
+func (_m *UsageGetter) LinkUsage(ctx context.Context, userID int64, apiKeyID int64) (storage.LinkUsage, error) {
+	ret := _m.Called(ctx, userID, apiKeyID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for LinkUsage")
+	}
+
+	var r0 storage.LinkUsage
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int64) (storage.LinkUsage, error)); ok {
+		return rf(ctx, userID, apiKeyID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int64) storage.LinkUsage); ok {
+		r0 = rf(ctx, userID, apiKeyID)
+	} else {
+		r0 = ret.Get(0).(storage.LinkUsage)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64, int64) error); ok {
+		r1 = rf(ctx, userID, apiKeyID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UsageGetter_LinkUsage_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'LinkUsage'
+type UsageGetter_LinkUsage_Call struct {
+	*mock.Call
+}
+
+// LinkUsage is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID int64
+//   - apiKeyID int64
+func (_e *UsageGetter_Expecter) LinkUsage(ctx interface{}, userID interface{}, apiKeyID interface{}) *UsageGetter_LinkUsage_Call {
+	return &UsageGetter_LinkUsage_Call{Call: _e.mock.On("LinkUsage", ctx, userID, apiKeyID)}
+}
+
+func (_c *UsageGetter_LinkUsage_Call) Run(run func(ctx context.Context, userID int64, apiKeyID int64)) *UsageGetter_LinkUsage_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int64), args[2].(int64))
+	})
+	return _c
+}
+
+func (_c *UsageGetter_LinkUsage_Call) Return(_a0 storage.LinkUsage, _a1 error) *UsageGetter_LinkUsage_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *UsageGetter_LinkUsage_Call) RunAndReturn(run func(context.Context, int64, int64) (storage.LinkUsage, error)) *UsageGetter_LinkUsage_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewUsageGetter creates a new instance of UsageGetter. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewUsageGetter(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *UsageGetter {
+	mock := &UsageGetter{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
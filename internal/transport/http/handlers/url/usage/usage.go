@@ -0,0 +1,104 @@
+package usage
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/dkhrunov/url-shortener/internal/auth"
+	"github.com/dkhrunov/url-shortener/internal/lib/logger/slog/slogerr"
+	"github.com/dkhrunov/url-shortener/internal/lib/quota"
+	"github.com/dkhrunov/url-shortener/internal/storage"
+	"github.com/dkhrunov/url-shortener/internal/transport/http/common/response"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+)
+
+// Limit describes one quota. A zero Limit means unlimited, in which case Remaining is -1.
+type Limit struct {
+	Limit     int        `json:"limit"`
+	Used      int        `json:"used"`
+	Remaining int        `json:"remaining"`
+	ResetAt   *time.Time `json:"reset_at,omitempty"`
+}
+
+type Response struct {
+	response.Response
+	Daily  *Limit `json:"daily,omitempty"`
+	Active *Limit `json:"active,omitempty"`
+}
+
+type UsageGetter interface {
+	LinkUsage(ctx context.Context, userID, apiKeyID int64) (storage.LinkUsage, error)
+}
+
+func New(usageGetter UsageGetter, limits quota.Limits) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.url.usage.New"
+
+		log := slog.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		identity, ok := auth.FromContext(r.Context())
+		if !ok {
+			log.ErrorContext(r.Context(), "request is not authenticated")
+
+			render.Status(r, http.StatusUnauthorized)
+			render.JSON(w, r, response.Error("unauthorized"))
+
+			return
+		}
+
+		// The built-in admin is not limited.
+		var usage quota.Usage
+
+		limits := limits
+		if identity.UserID == 0 && identity.KeyID == 0 {
+			limits = quota.Limits{}
+		} else {
+			linkUsage, err := usageGetter.LinkUsage(r.Context(), identity.UserID, identity.KeyID)
+			if status, resp, ok := response.Unavailable(err); ok {
+				log.WarnContext(r.Context(), "storage unavailable", slogerr.Error(err))
+
+				render.Status(r, status)
+				render.JSON(w, r, resp)
+
+				return
+			}
+			if err != nil {
+				log.ErrorContext(r.Context(), "failed to get quota usage", slogerr.Error(err))
+
+				render.Status(r, http.StatusInternalServerError)
+				render.JSON(w, r, response.Error("failed to get usage"))
+
+				return
+			}
+
+			usage = quota.Usage(linkUsage)
+		}
+
+		now := time.Now()
+		resetAt := quota.NextReset(now)
+		daily, active := limits.Remaining(usage)
+
+		limits.SetHeaders(w.Header(), usage, now)
+
+		render.JSON(w, r, Response{
+			Response: response.OK(),
+			Daily: &Limit{
+				Limit:     limits.LinksPerDay,
+				Used:      usage.LinksToday,
+				Remaining: daily,
+				ResetAt:   &resetAt,
+			},
+			Active: &Limit{
+				Limit:     limits.ActiveLinks,
+				Used:      usage.ActiveLinks,
+				Remaining: active,
+			},
+		})
+	}
+}
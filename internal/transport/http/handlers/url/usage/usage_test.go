@@ -0,0 +1,109 @@
+package usage_test
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/dkhrunov/url-shortener/internal/auth"
+	"github.com/dkhrunov/url-shortener/internal/lib/quota"
+	"github.com/dkhrunov/url-shortener/internal/storage"
+	"github.com/dkhrunov/url-shortener/internal/transport/http/handlers/url/usage"
+	"github.com/dkhrunov/url-shortener/internal/transport/http/handlers/url/usage/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUsageHandler(t *testing.T) {
+	cases := []struct {
+		name      string
+		identity  auth.Identity
+		limits    quota.Limits
+		usage     storage.LinkUsage
+		mockError error
+		daily     usage.Limit
+		active    usage.Limit
+		respError string
+		status    int
+	}{
+		{
+			name:     "User",
+			identity: auth.Identity{UserID: 42, Role: auth.RoleEditor},
+			limits:   quota.Limits{LinksPerDay: 10, ActiveLinks: 100},
+			usage:    storage.LinkUsage{LinksToday: 4, ActiveLinks: 20},
+			daily:    usage.Limit{Limit: 10, Used: 4, Remaining: 6},
+			active:   usage.Limit{Limit: 100, Used: 20, Remaining: 80},
+			status:   http.StatusOK,
+		},
+		{
+			name:     "Key without limits",
+			identity: auth.Identity{KeyID: 7, Role: auth.RoleAdmin},
+			usage:    storage.LinkUsage{LinksToday: 4, ActiveLinks: 20},
+			daily:    usage.Limit{Used: 4, Remaining: -1},
+			active:   usage.Limit{Used: 20, Remaining: -1},
+			status:   http.StatusOK,
+		},
+		{
+			name:     "Built-in admin",
+			identity: auth.Identity{Role: auth.RoleAdmin},
+			limits:   quota.Limits{LinksPerDay: 10},
+			daily:    usage.Limit{Remaining: -1},
+			active:   usage.Limit{Remaining: -1},
+			status:   http.StatusOK,
+		},
+		{
+			name:      "Failed",
+			identity:  auth.Identity{UserID: 42, Role: auth.RoleEditor},
+			mockError: errors.New("unexpected error"),
+			respError: "failed to get usage",
+			status:    http.StatusInternalServerError,
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			usageGetterMock := mocks.NewUsageGetter(t)
+
+			if tc.identity.UserID != 0 || tc.identity.KeyID != 0 {
+				usageGetterMock.EXPECT().
+					LinkUsage(mock.Anything, tc.identity.UserID, tc.identity.KeyID).
+					Return(tc.usage, tc.mockError).
+					Once()
+			}
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodGet, "/url/usage", nil)
+			r = r.WithContext(auth.WithIdentity(r.Context(), tc.identity))
+
+			handler := usage.New(usageGetterMock, tc.limits)
+			handler.ServeHTTP(w, r)
+
+			assert.Equal(t, tc.status, w.Code)
+
+			var resp usage.Response
+
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+
+			assert.Equal(t, tc.respError, resp.Error)
+
+			if tc.respError != "" {
+				return
+			}
+
+			require.NotNil(t, resp.Daily)
+			require.NotNil(t, resp.Active)
+			assert.NotNil(t, resp.Daily.ResetAt)
+
+			resp.Daily.ResetAt = nil
+			assert.Equal(t, tc.daily, *resp.Daily)
+			assert.Equal(t, tc.active, *resp.Active)
+		})
+	}
+}
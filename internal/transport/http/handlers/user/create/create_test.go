@@ -0,0 +1,141 @@
+package create_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dkhrunov/url-shortener/internal/lib/password"
+	"github.com/dkhrunov/url-shortener/internal/storage"
+	"github.com/dkhrunov/url-shortener/internal/transport/http/handlers/user/create"
+	"github.com/dkhrunov/url-shortener/internal/transport/http/handlers/user/create/mocks"
+)
+
+func TestCreateHandler(t *testing.T) {
+	cases := []struct {
+		name      string
+		username  string
+		password  string
+		role      string
+		respError string
+		mockError error
+		status    int
+	}{
+		{
+			name:     "Success",
+			username: "alice",
+			password: "secret-password",
+			role:     "editor",
+			status:   http.StatusCreated,
+		},
+		{
+			name:     "Viewer",
+			username: "carol",
+			password: "secret-password",
+			role:     "viewer",
+			status:   http.StatusCreated,
+		},
+		{
+			name:     "Admin",
+			username: "root",
+			password: "secret-password",
+			role:     "admin",
+			status:   http.StatusCreated,
+		},
+		{
+			name:      "Short password",
+			username:  "alice",
+			password:  "short",
+			role:      "editor",
+			respError: "field Password is not valid",
+			status:    http.StatusBadRequest,
+		},
+		{
+			name:      "Unknown role",
+			username:  "alice",
+			password:  "secret-password",
+			role:      "owner",
+			respError: "field Role is not valid",
+			status:    http.StatusBadRequest,
+		},
+		{
+			name:      "User exists",
+			username:  "alice",
+			password:  "secret-password",
+			role:      "editor",
+			respError: "user already exists",
+			mockError: storage.ErrUserExist,
+			status:    http.StatusConflict,
+		},
+		{
+			name:      "Failed",
+			username:  "alice",
+			password:  "secret-password",
+			role:      "editor",
+			respError: "failed to create user",
+			mockError: errors.New("unexpected error"),
+			status:    http.StatusInternalServerError,
+		},
+		{
+			name:      "SaveUser timed out",
+			username:  "alice",
+			password:  "secret-password",
+			role:      "editor",
+			respError: "storage timed out",
+			mockError: fmt.Errorf("storage.sqlite.SaveUser: %w", context.DeadlineExceeded),
+			status:    http.StatusGatewayTimeout,
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			userSaverMock := mocks.NewUserSaver(t)
+
+			var savedHash string
+
+			if tc.respError == "" || tc.mockError != nil {
+				userSaverMock.EXPECT().
+					SaveUser(mock.Anything, tc.username, mock.AnythingOfType("string"), tc.role).
+					Run(func(_ context.Context, _, passwordHash, _ string) { savedHash = passwordHash }).
+					Return(int64(1), tc.mockError).
+					Once()
+			}
+
+			handler := create.New(userSaverMock)
+
+			input := fmt.Sprintf(`{"username": "%s", "password": "%s", "role": "%s"}`, tc.username, tc.password, tc.role)
+
+			req, err := http.NewRequest(http.MethodPost, "/admin/users", bytes.NewReader([]byte(input)))
+			require.NoError(t, err)
+
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, req)
+
+			assert.Equal(t, tc.status, rr.Code)
+
+			var resp create.Response
+
+			require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+
+			assert.Equal(t, tc.respError, resp.Error)
+
+			if tc.respError == "" {
+				assert.Equal(t, tc.username, resp.Username)
+				assert.True(t, password.Check(savedHash, tc.password))
+			}
+		})
+	}
+}
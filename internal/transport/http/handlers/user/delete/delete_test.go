@@ -0,0 +1,98 @@
+package delete_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/dkhrunov/url-shortener/internal/storage"
+	"github.com/dkhrunov/url-shortener/internal/transport/http/handlers/user/delete"
+	"github.com/dkhrunov/url-shortener/internal/transport/http/handlers/user/delete/mocks"
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeleteHandler(t *testing.T) {
+	cases := []struct {
+		name      string
+		id        string
+		respError string
+		mockError error
+		status    int
+	}{
+		{
+			name:   "Success",
+			id:     "1",
+			status: http.StatusOK,
+		},
+		{
+			name:      "Invalid id",
+			id:        "abc",
+			respError: "invalid request",
+			status:    http.StatusBadRequest,
+		},
+		{
+			name:      "Not found",
+			id:        "1",
+			respError: "not found",
+			mockError: storage.ErrUserNotFound,
+			status:    http.StatusNotFound,
+		},
+		{
+			name:      "Failed",
+			id:        "1",
+			respError: "failed to delete user",
+			mockError: errors.New("unexpected error"),
+			status:    http.StatusInternalServerError,
+		},
+		{
+			name:      "DeleteUser timed out",
+			id:        "1",
+			respError: "storage timed out",
+			mockError: fmt.Errorf("storage.sqlite.DeleteUser: %w", context.DeadlineExceeded),
+			status:    http.StatusGatewayTimeout,
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			userDeleterMock := mocks.NewUserDeleter(t)
+
+			if tc.respError == "" || tc.mockError != nil {
+				userDeleterMock.EXPECT().
+					DeleteUser(mock.Anything, int64(1)).
+					Return(tc.mockError).
+					Once()
+			}
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodDelete, "/admin/users/{id}", nil)
+
+			rctx := chi.NewRouteContext()
+			rctx.URLParams.Add("id", tc.id)
+
+			r = r.WithContext(context.WithValue(r.Context(), chi.RouteCtxKey, rctx))
+
+			handler := delete.New(userDeleterMock)
+			handler.ServeHTTP(w, r)
+
+			assert.Equal(t, tc.status, w.Code)
+
+			var resp delete.Response
+
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+
+			assert.Equal(t, tc.respError, resp.Error)
+		})
+	}
+}
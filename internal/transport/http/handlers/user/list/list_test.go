@@ -0,0 +1,88 @@
+package list_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/dkhrunov/url-shortener/internal/storage"
+	"github.com/dkhrunov/url-shortener/internal/transport/http/handlers/user/list"
+	"github.com/dkhrunov/url-shortener/internal/transport/http/handlers/user/list/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestListHandler(t *testing.T) {
+	now := time.Now().UTC().Truncate(time.Second)
+
+	cases := []struct {
+		name      string
+		users     []storage.User
+		respError string
+		mockError error
+		status    int
+	}{
+		{
+			name: "Success",
+			users: []storage.User{
+				{ID: 1, Username: "alice", PasswordHash: "secret", Role: "admin", CreatedAt: now},
+				{ID: 2, Username: "bob", PasswordHash: "secret", Role: "editor", CreatedAt: now},
+			},
+			status: http.StatusOK,
+		},
+		{
+			name:      "Failed",
+			respError: "failed to list users",
+			mockError: errors.New("unexpected error"),
+			status:    http.StatusInternalServerError,
+		},
+		{
+			name:      "ListUsers timed out",
+			respError: "storage timed out",
+			mockError: fmt.Errorf("storage.sqlite.ListUsers: %w", context.DeadlineExceeded),
+			status:    http.StatusGatewayTimeout,
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			userListerMock := mocks.NewUserLister(t)
+
+			userListerMock.EXPECT().
+				ListUsers(mock.Anything).
+				Return(tc.users, tc.mockError).
+				Once()
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodGet, "/admin/users", nil)
+
+			handler := list.New(userListerMock)
+			handler.ServeHTTP(w, r)
+
+			assert.Equal(t, tc.status, w.Code)
+			assert.NotContains(t, w.Body.String(), "secret")
+
+			var resp list.Response
+
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+
+			assert.Equal(t, tc.respError, resp.Error)
+
+			require.Len(t, resp.Users, len(tc.users))
+			for i, u := range resp.Users {
+				assert.Equal(t, tc.users[i].Username, u.Username)
+				assert.Equal(t, tc.users[i].Role, u.Role)
+			}
+		})
+	}
+}
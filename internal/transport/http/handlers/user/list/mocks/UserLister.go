@@ -0,0 +1,96 @@
+// Code generated by mockery v2.39.1. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+
+	storage "github.com/dkhrunov/url-shortener/internal/storage"
+)
+
+// UserLister is an autogenerated mock type for the UserLister type
+type UserLister struct {
+	mock.Mock
+}
+
+type UserLister_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *UserLister) EXPECT() *UserLister_Expecter {
+	return &UserLister_Expecter{mock: &_m.Mock}
+}
+
+// ListUsers provides a mock function with given fields: ctx
+func (_m *UserLister) ListUsers(ctx context.Context) ([]storage.User, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListUsers")
+	}
+
+	var r0 []storage.User
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) ([]storage.User, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) []storage.User); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]storage.User)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UserLister_ListUsers_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListUsers'
+type UserLister_ListUsers_Call struct {
+	*mock.Call
+}
+
+// ListUsers is a helper method to define mock.On call
+//   - ctx context.Context
+func (_e *UserLister_Expecter) ListUsers(ctx interface{}) *UserLister_ListUsers_Call {
+	return &UserLister_ListUsers_Call{Call: _e.mock.On("ListUsers", ctx)}
+}
+
+func (_c *UserLister_ListUsers_Call) Run(run func(ctx context.Context)) *UserLister_ListUsers_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context))
+	})
+	return _c
+}
+
+func (_c *UserLister_ListUsers_Call) Return(_a0 []storage.User, _a1 error) *UserLister_ListUsers_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *UserLister_ListUsers_Call) RunAndReturn(run func(context.Context) ([]storage.User, error)) *UserLister_ListUsers_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewUserLister creates a new instance of UserLister. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewUserLister(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *UserLister {
+	mock := &UserLister{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
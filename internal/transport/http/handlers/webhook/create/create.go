@@ -0,0 +1,122 @@
+package create
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"slices"
+
+	"github.com/dkhrunov/url-shortener/internal/lib/logger/slog/slogerr"
+	"github.com/dkhrunov/url-shortener/internal/lib/webhook"
+	"github.com/dkhrunov/url-shortener/internal/transport/http/common/response"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+	"github.com/go-playground/validator/v10"
+)
+
+type Request struct {
+	URL string `json:"url" validate:"required,http_url"`
+	// Events are the event types the webhook is notified of, e.g. link.created.
+	Events []string `json:"events" validate:"required,min=1"`
+}
+
+type Response struct {
+	response.Response
+	ID     int64    `json:"id,omitempty"`
+	URL    string   `json:"url,omitempty"`
+	Events []string `json:"events,omitempty"`
+	// Secret signs the payloads, see webhook.Sign. It is returned only once, on
+	// creation.
+	Secret string `json:"secret,omitempty"`
+}
+
+type WebhookSaver interface {
+	SaveWebhook(ctx context.Context, url, secret string, events []string) (int64, error)
+}
+
+func New(webhookSaver WebhookSaver) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.webhook.create.New"
+
+		log := slog.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		var req Request
+
+		err := render.DecodeJSON(r.Body, &req)
+		if err != nil {
+			log.ErrorContext(r.Context(), "failed to decode request body", slogerr.Error(err))
+
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, response.Error("failed to decode request"))
+
+			return
+		}
+
+		if err := validator.New().Struct(req); err != nil {
+			validateErr := err.(validator.ValidationErrors)
+
+			log.ErrorContext(r.Context(), "invalid request", slogerr.Error(err))
+
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, response.ValidationError(validateErr))
+
+			return
+		}
+
+		for _, e := range req.Events {
+			if !webhook.EventType(e).Valid() {
+				log.InfoContext(r.Context(), "invalid event type", slog.String("event", e))
+
+				render.Status(r, http.StatusBadRequest)
+				render.JSON(w, r, response.Error("invalid event type"))
+
+				return
+			}
+		}
+
+		slices.Sort(req.Events)
+		req.Events = slices.Compact(req.Events)
+
+		secret, err := webhook.GenerateSecret()
+		if err != nil {
+			log.ErrorContext(r.Context(), "failed to generate webhook secret", slogerr.Error(err))
+
+			render.Status(r, http.StatusInternalServerError)
+			render.JSON(w, r, response.Error("failed to create webhook"))
+
+			return
+		}
+
+		id, err := webhookSaver.SaveWebhook(r.Context(), req.URL, secret, req.Events)
+		if status, resp, ok := response.Unavailable(err); ok {
+			log.WarnContext(r.Context(), "storage unavailable", slogerr.Error(err))
+
+			render.Status(r, status)
+			render.JSON(w, r, resp)
+
+			return
+		}
+		if err != nil {
+			log.ErrorContext(r.Context(), "failed to save webhook", slogerr.Error(err))
+
+			render.Status(r, http.StatusInternalServerError)
+			render.JSON(w, r, response.Error("failed to create webhook"))
+
+			return
+		}
+
+		log.InfoContext(r.Context(), "webhook created", slog.Int64("id", id), slog.String("url", req.URL))
+
+		render.Status(r, http.StatusCreated)
+		render.JSON(w, r, Response{
+			Response: response.OK(),
+			ID:       id,
+			URL:      req.URL,
+			Events:   req.Events,
+			Secret:   secret,
+		})
+	}
+}
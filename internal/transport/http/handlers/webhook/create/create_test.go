@@ -0,0 +1,112 @@
+package create_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/dkhrunov/url-shortener/internal/transport/http/handlers/webhook/create"
+	"github.com/dkhrunov/url-shortener/internal/transport/http/handlers/webhook/create/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateHandler(t *testing.T) {
+	cases := []struct {
+		name       string
+		input      string
+		wantEvents []string
+		respError  string
+		mockError  error
+		status     int
+	}{
+		{
+			name:       "Success",
+			input:      `{"url": "https://hooks.example/links", "events": ["link.deleted", "link.created", "link.deleted"]}`,
+			wantEvents: []string{"link.created", "link.deleted"},
+			status:     http.StatusCreated,
+		},
+		{
+			name:      "Invalid URL",
+			input:     `{"url": "ftp://hooks.example", "events": ["link.created"]}`,
+			respError: "field URL is not a valid URL",
+			status:    http.StatusBadRequest,
+		},
+		{
+			name:      "No events",
+			input:     `{"url": "https://hooks.example/links", "events": []}`,
+			respError: "field Events is not valid",
+			status:    http.StatusBadRequest,
+		},
+		{
+			name:      "Unknown event",
+			input:     `{"url": "https://hooks.example/links", "events": ["link.renamed"]}`,
+			respError: "invalid event type",
+			status:    http.StatusBadRequest,
+		},
+		{
+			name:       "SaveWebhook Error",
+			input:      `{"url": "https://hooks.example/links", "events": ["link.clicked"]}`,
+			wantEvents: []string{"link.clicked"},
+			respError:  "failed to create webhook",
+			mockError:  errors.New("unexpected error"),
+			status:     http.StatusInternalServerError,
+		},
+		{
+			name:       "SaveWebhook timed out",
+			input:      `{"url": "https://hooks.example/links", "events": ["link.clicked"]}`,
+			wantEvents: []string{"link.clicked"},
+			respError:  "storage timed out",
+			mockError:  fmt.Errorf("storage.sqlite.SaveWebhook: %w", context.DeadlineExceeded),
+			status:     http.StatusGatewayTimeout,
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			webhookSaverMock := mocks.NewWebhookSaver(t)
+
+			var savedSecret string
+			if tc.wantEvents != nil {
+				webhookSaverMock.EXPECT().
+					SaveWebhook(mock.Anything, "https://hooks.example/links", mock.AnythingOfType("string"), tc.wantEvents).
+					Run(func(_ context.Context, _, secret string, _ []string) { savedSecret = secret }).
+					Return(int64(1), tc.mockError).
+					Once()
+			}
+
+			handler := create.New(webhookSaverMock)
+
+			req, err := http.NewRequest(http.MethodPost, "/admin/webhooks", bytes.NewReader([]byte(tc.input)))
+			require.NoError(t, err)
+
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, req)
+
+			assert.Equal(t, tc.status, rr.Code)
+
+			var resp create.Response
+
+			require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+
+			assert.Equal(t, tc.respError, resp.Error)
+
+			if tc.respError == "" {
+				assert.Equal(t, savedSecret, resp.Secret)
+				assert.Equal(t, tc.wantEvents, resp.Events)
+			} else {
+				assert.Empty(t, resp.Secret)
+			}
+		})
+	}
+}
@@ -0,0 +1,95 @@
+// Code generated by mockery v2.39.1. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// WebhookSaver is an autogenerated mock type for the WebhookSaver type
+type WebhookSaver struct {
+	mock.Mock
+}
+
+type WebhookSaver_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *WebhookSaver) EXPECT() *WebhookSaver_Expecter {
+	return &WebhookSaver_Expecter{mock: &_m.Mock}
+}
+
+// SaveWebhook provides a mock function with given fields: ctx, url, secret, events
+func (_m *WebhookSaver) SaveWebhook(ctx context.Context, url string, secret string, events []string) (int64, error) {
+	ret := _m.Called(ctx, url, secret, events)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SaveWebhook")
+	}
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, []string) (int64, error)); ok {
+		return rf(ctx, url, secret, events)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, []string) int64); ok {
+		r0 = rf(ctx, url, secret, events)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, []string) error); ok {
+		r1 = rf(ctx, url, secret, events)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// WebhookSaver_SaveWebhook_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SaveWebhook'
+type WebhookSaver_SaveWebhook_Call struct {
+	*mock.Call
+}
+
+// SaveWebhook is a helper method to define mock.On call
+//   - ctx context.Context
+//   - url string
+//   - secret string
+//   - events []string
+func (_e *WebhookSaver_Expecter) SaveWebhook(ctx interface{}, url interface{}, secret interface{}, events interface{}) *WebhookSaver_SaveWebhook_Call {
+	return &WebhookSaver_SaveWebhook_Call{Call: _e.mock.On("SaveWebhook", ctx, url, secret, events)}
+}
+
+func (_c *WebhookSaver_SaveWebhook_Call) Run(run func(ctx context.Context, url string, secret string, events []string)) *WebhookSaver_SaveWebhook_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(string), args[3].([]string))
+	})
+	return _c
+}
+
+func (_c *WebhookSaver_SaveWebhook_Call) Return(_a0 int64, _a1 error) *WebhookSaver_SaveWebhook_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *WebhookSaver_SaveWebhook_Call) RunAndReturn(run func(context.Context, string, string, []string) (int64, error)) *WebhookSaver_SaveWebhook_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewWebhookSaver creates a new instance of WebhookSaver. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewWebhookSaver(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *WebhookSaver {
+	mock := &WebhookSaver{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
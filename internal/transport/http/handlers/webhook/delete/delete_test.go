@@ -0,0 +1,98 @@
+package delete_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/dkhrunov/url-shortener/internal/storage"
+	"github.com/dkhrunov/url-shortener/internal/transport/http/handlers/webhook/delete"
+	"github.com/dkhrunov/url-shortener/internal/transport/http/handlers/webhook/delete/mocks"
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeleteHandler(t *testing.T) {
+	cases := []struct {
+		name      string
+		id        string
+		respError string
+		mockError error
+		status    int
+	}{
+		{
+			name:   "Success",
+			id:     "1",
+			status: http.StatusOK,
+		},
+		{
+			name:      "Invalid id",
+			id:        "hooks",
+			respError: "invalid request",
+			status:    http.StatusBadRequest,
+		},
+		{
+			name:      "Not found",
+			id:        "1",
+			respError: "not found",
+			mockError: storage.ErrWebhookNotFound,
+			status:    http.StatusNotFound,
+		},
+		{
+			name:      "Failed",
+			id:        "1",
+			respError: "failed to delete webhook",
+			mockError: errors.New("unexpected error"),
+			status:    http.StatusInternalServerError,
+		},
+		{
+			name:      "DeleteWebhook timed out",
+			id:        "1",
+			respError: "storage timed out",
+			mockError: fmt.Errorf("storage.sqlite.DeleteWebhook: %w", context.DeadlineExceeded),
+			status:    http.StatusGatewayTimeout,
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			webhookDeleterMock := mocks.NewWebhookDeleter(t)
+
+			if tc.respError == "" || tc.mockError != nil {
+				webhookDeleterMock.EXPECT().
+					DeleteWebhook(mock.Anything, int64(1)).
+					Return(tc.mockError).
+					Once()
+			}
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodDelete, "/admin/webhooks/{id}", nil)
+
+			rctx := chi.NewRouteContext()
+			rctx.URLParams.Add("id", tc.id)
+
+			r = r.WithContext(context.WithValue(r.Context(), chi.RouteCtxKey, rctx))
+
+			handler := delete.New(webhookDeleterMock)
+			handler.ServeHTTP(w, r)
+
+			assert.Equal(t, tc.status, w.Code)
+
+			var resp delete.Response
+
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+
+			assert.Equal(t, tc.respError, resp.Error)
+		})
+	}
+}
@@ -0,0 +1,83 @@
+// Code generated by mockery v2.39.1. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// WebhookDeleter is an autogenerated mock type for the WebhookDeleter type
+type WebhookDeleter struct {
+	mock.Mock
+}
+
+type WebhookDeleter_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *WebhookDeleter) EXPECT() *WebhookDeleter_Expecter {
+	return &WebhookDeleter_Expecter{mock: &_m.Mock}
+}
+
+// DeleteWebhook provides a mock function with given fields: ctx, id
+func (_m *WebhookDeleter) DeleteWebhook(ctx context.Context, id int64) error {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteWebhook")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64) error); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// WebhookDeleter_DeleteWebhook_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteWebhook'
+type WebhookDeleter_DeleteWebhook_Call struct {
+	*mock.Call
+}
+
+// DeleteWebhook is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id int64
+func (_e *WebhookDeleter_Expecter) DeleteWebhook(ctx interface{}, id interface{}) *WebhookDeleter_DeleteWebhook_Call {
+	return &WebhookDeleter_DeleteWebhook_Call{Call: _e.mock.On("DeleteWebhook", ctx, id)}
+}
+
+func (_c *WebhookDeleter_DeleteWebhook_Call) Run(run func(ctx context.Context, id int64)) *WebhookDeleter_DeleteWebhook_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int64))
+	})
+	return _c
+}
+
+func (_c *WebhookDeleter_DeleteWebhook_Call) Return(_a0 error) *WebhookDeleter_DeleteWebhook_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *WebhookDeleter_DeleteWebhook_Call) RunAndReturn(run func(context.Context, int64) error) *WebhookDeleter_DeleteWebhook_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewWebhookDeleter creates a new instance of WebhookDeleter. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewWebhookDeleter(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *WebhookDeleter {
+	mock := &WebhookDeleter{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
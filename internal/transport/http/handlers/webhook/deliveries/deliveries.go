@@ -0,0 +1,151 @@
+package deliveries
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/dkhrunov/url-shortener/internal/lib/logger/slog/slogerr"
+	"github.com/dkhrunov/url-shortener/internal/storage"
+	"github.com/dkhrunov/url-shortener/internal/transport/http/common/response"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+)
+
+const (
+	defaultLimit = 50
+	maxLimit     = 1000
+)
+
+type Item struct {
+	ID        int64  `json:"id"`
+	MessageID int64  `json:"message_id"`
+	Event     string `json:"event"`
+	Attempt   int    `json:"attempt"`
+	// StatusCode is zero when the webhook didn't answer.
+	StatusCode int `json:"status_code,omitempty"`
+	// Error is empty for successful deliveries.
+	Error      string    `json:"error,omitempty"`
+	DurationMS int64     `json:"duration_ms"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+type Response struct {
+	response.Response
+	Deliveries []Item `json:"deliveries"`
+	Limit      int    `json:"limit"`
+	Offset     int    `json:"offset"`
+}
+
+type DeliveryLister interface {
+	GetWebhook(ctx context.Context, id int64) (storage.Webhook, error)
+	ListWebhookDeliveries(ctx context.Context, webhookID int64, limit, offset int) ([]storage.WebhookDelivery, error)
+}
+
+// New returns the handler listing the delivery attempts of a webhook, newest first.
+func New(deliveryLister DeliveryLister) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.webhook.deliveries.New"
+
+		log := slog.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+		if err != nil {
+			log.InfoContext(r.Context(), "invalid id", slog.String("id", chi.URLParam(r, "id")))
+
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, response.Error("invalid request"))
+
+			return
+		}
+
+		q := r.URL.Query()
+
+		limit, err := intParam(q.Get("limit"), defaultLimit)
+		if err != nil || limit < 1 || limit > maxLimit {
+			log.InfoContext(r.Context(), "invalid limit", slog.String("limit", q.Get("limit")))
+
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, response.Error("invalid limit"))
+
+			return
+		}
+
+		offset, err := intParam(q.Get("offset"), 0)
+		if err != nil || offset < 0 {
+			log.InfoContext(r.Context(), "invalid offset", slog.String("offset", q.Get("offset")))
+
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, response.Error("invalid offset"))
+
+			return
+		}
+
+		_, err = deliveryLister.GetWebhook(r.Context(), id)
+		if errors.Is(err, storage.ErrWebhookNotFound) {
+			log.InfoContext(r.Context(), "webhook not found", slog.Int64("id", id))
+
+			render.Status(r, http.StatusNotFound)
+			render.JSON(w, r, response.Error("not found"))
+
+			return
+		}
+
+		var deliveries []storage.WebhookDelivery
+		if err == nil {
+			deliveries, err = deliveryLister.ListWebhookDeliveries(r.Context(), id, limit, offset)
+		}
+		if status, resp, ok := response.Unavailable(err); ok {
+			log.WarnContext(r.Context(), "storage unavailable", slogerr.Error(err))
+
+			render.Status(r, status)
+			render.JSON(w, r, resp)
+
+			return
+		}
+		if err != nil {
+			log.ErrorContext(r.Context(), "failed to list webhook deliveries", slogerr.Error(err))
+
+			render.Status(r, http.StatusInternalServerError)
+			render.JSON(w, r, response.Error("failed to list webhook deliveries"))
+
+			return
+		}
+
+		items := make([]Item, 0, len(deliveries))
+		for _, d := range deliveries {
+			items = append(items, Item{
+				ID:         d.ID,
+				MessageID:  d.MessageID,
+				Event:      d.Event,
+				Attempt:    d.Attempt,
+				StatusCode: d.StatusCode,
+				Error:      d.Error,
+				DurationMS: d.Duration.Milliseconds(),
+				CreatedAt:  d.CreatedAt,
+			})
+		}
+
+		render.JSON(w, r, Response{
+			Response:   response.OK(),
+			Deliveries: items,
+			Limit:      limit,
+			Offset:     offset,
+		})
+	}
+}
+
+func intParam(v string, def int) (int, error) {
+	if v == "" {
+		return def, nil
+	}
+
+	return strconv.Atoi(v)
+}
@@ -0,0 +1,140 @@
+package deliveries_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/dkhrunov/url-shortener/internal/storage"
+	"github.com/dkhrunov/url-shortener/internal/transport/http/handlers/webhook/deliveries"
+	"github.com/dkhrunov/url-shortener/internal/transport/http/handlers/webhook/deliveries/mocks"
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeliveriesHandler(t *testing.T) {
+	cases := []struct {
+		name       string
+		id         string
+		query      string
+		limit      int
+		offset     int
+		deliveries []storage.WebhookDelivery
+		getError   error
+		listError  error
+		respError  string
+		status     int
+	}{
+		{
+			name:  "Success",
+			id:    "1",
+			limit: 50,
+			deliveries: []storage.WebhookDelivery{
+				{ID: 2, WebhookID: 1, MessageID: 7, Event: "link.created", Attempt: 2, StatusCode: 200, Duration: 80 * time.Millisecond},
+				{ID: 1, WebhookID: 1, MessageID: 7, Event: "link.created", Attempt: 1, Error: "connection refused"},
+			},
+			status: http.StatusOK,
+		},
+		{
+			name:       "Paged",
+			id:         "1",
+			query:      "?limit=10&offset=20",
+			limit:      10,
+			offset:     20,
+			deliveries: []storage.WebhookDelivery{},
+			status:     http.StatusOK,
+		},
+		{
+			name:      "Invalid id",
+			id:        "hooks",
+			respError: "invalid request",
+			status:    http.StatusBadRequest,
+		},
+		{
+			name:      "Invalid limit",
+			id:        "1",
+			query:     "?limit=0",
+			respError: "invalid limit",
+			status:    http.StatusBadRequest,
+		},
+		{
+			name:      "Invalid offset",
+			id:        "1",
+			query:     "?offset=-1",
+			respError: "invalid offset",
+			status:    http.StatusBadRequest,
+		},
+		{
+			name:      "Not found",
+			id:        "1",
+			limit:     50,
+			getError:  storage.ErrWebhookNotFound,
+			respError: "not found",
+			status:    http.StatusNotFound,
+		},
+		{
+			name:      "Failed",
+			id:        "1",
+			limit:     50,
+			listError: errors.New("unexpected error"),
+			respError: "failed to list webhook deliveries",
+			status:    http.StatusInternalServerError,
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			deliveryListerMock := mocks.NewDeliveryLister(t)
+
+			if tc.limit != 0 {
+				deliveryListerMock.EXPECT().
+					GetWebhook(mock.Anything, int64(1)).
+					Return(storage.Webhook{ID: 1}, tc.getError).
+					Once()
+			}
+
+			if tc.limit != 0 && tc.getError == nil {
+				deliveryListerMock.EXPECT().
+					ListWebhookDeliveries(mock.Anything, int64(1), tc.limit, tc.offset).
+					Return(tc.deliveries, tc.listError).
+					Once()
+			}
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodGet, "/admin/webhooks/{id}/deliveries"+tc.query, nil)
+
+			rctx := chi.NewRouteContext()
+			rctx.URLParams.Add("id", tc.id)
+
+			r = r.WithContext(context.WithValue(r.Context(), chi.RouteCtxKey, rctx))
+
+			handler := deliveries.New(deliveryListerMock)
+			handler.ServeHTTP(w, r)
+
+			assert.Equal(t, tc.status, w.Code)
+
+			var resp deliveries.Response
+
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+
+			assert.Equal(t, tc.respError, resp.Error)
+
+			require.Len(t, resp.Deliveries, len(tc.deliveries))
+			for i, d := range resp.Deliveries {
+				assert.Equal(t, tc.deliveries[i].Attempt, d.Attempt)
+				assert.Equal(t, tc.deliveries[i].Error, d.Error)
+				assert.Equal(t, tc.deliveries[i].Duration.Milliseconds(), d.DurationMS)
+			}
+		})
+	}
+}
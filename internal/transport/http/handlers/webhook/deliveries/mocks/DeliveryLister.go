@@ -0,0 +1,156 @@
+// Code generated by mockery v2.39.1. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+
+	storage "github.com/dkhrunov/url-shortener/internal/storage"
+)
+
+// DeliveryLister is an autogenerated mock type for the DeliveryLister type
+type DeliveryLister struct {
+	mock.Mock
+}
+
+type DeliveryLister_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *DeliveryLister) EXPECT() *DeliveryLister_Expecter {
+	return &DeliveryLister_Expecter{mock: &_m.Mock}
+}
+
+// GetWebhook provides a mock function with given fields: ctx, id
+func (_m *DeliveryLister) GetWebhook(ctx context.Context, id int64) (storage.Webhook, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetWebhook")
+	}
+
+	var r0 storage.Webhook
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64) (storage.Webhook, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64) storage.Webhook); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Get(0).(storage.Webhook)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// DeliveryLister_GetWebhook_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetWebhook'
+type DeliveryLister_GetWebhook_Call struct {
+	*mock.Call
+}
+
+// GetWebhook is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id int64
+func (_e *DeliveryLister_Expecter) GetWebhook(ctx interface{}, id interface{}) *DeliveryLister_GetWebhook_Call {
+	return &DeliveryLister_GetWebhook_Call{Call: _e.mock.On("GetWebhook", ctx, id)}
+}
+
+func (_c *DeliveryLister_GetWebhook_Call) Run(run func(ctx context.Context, id int64)) *DeliveryLister_GetWebhook_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int64))
+	})
+	return _c
+}
+
+func (_c *DeliveryLister_GetWebhook_Call) Return(_a0 storage.Webhook, _a1 error) *DeliveryLister_GetWebhook_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *DeliveryLister_GetWebhook_Call) RunAndReturn(run func(context.Context, int64) (storage.Webhook, error)) *DeliveryLister_GetWebhook_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ListWebhookDeliveries provides a mock function with given fields: ctx, webhookID, limit, offset
+func (_m *DeliveryLister) ListWebhookDeliveries(ctx context.Context, webhookID int64, limit int, offset int) ([]storage.WebhookDelivery, error) {
+	ret := _m.Called(ctx, webhookID, limit, offset)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListWebhookDeliveries")
+	}
+
+	var r0 []storage.WebhookDelivery
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int, int) ([]storage.WebhookDelivery, error)); ok {
+		return rf(ctx, webhookID, limit, offset)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int, int) []storage.WebhookDelivery); ok {
+		r0 = rf(ctx, webhookID, limit, offset)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]storage.WebhookDelivery)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64, int, int) error); ok {
+		r1 = rf(ctx, webhookID, limit, offset)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// DeliveryLister_ListWebhookDeliveries_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListWebhookDeliveries'
+type DeliveryLister_ListWebhookDeliveries_Call struct {
+	*mock.Call
+}
+
+// ListWebhookDeliveries is a helper method to define mock.On call
+//   - ctx context.Context
+//   - webhookID int64
+//   - limit int
+//   - offset int
+func (_e *DeliveryLister_Expecter) ListWebhookDeliveries(ctx interface{}, webhookID interface{}, limit interface{}, offset interface{}) *DeliveryLister_ListWebhookDeliveries_Call {
+	return &DeliveryLister_ListWebhookDeliveries_Call{Call: _e.mock.On("ListWebhookDeliveries", ctx, webhookID, limit, offset)}
+}
+
+func (_c *DeliveryLister_ListWebhookDeliveries_Call) Run(run func(ctx context.Context, webhookID int64, limit int, offset int)) *DeliveryLister_ListWebhookDeliveries_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int64), args[2].(int), args[3].(int))
+	})
+	return _c
+}
+
+func (_c *DeliveryLister_ListWebhookDeliveries_Call) Return(_a0 []storage.WebhookDelivery, _a1 error) *DeliveryLister_ListWebhookDeliveries_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *DeliveryLister_ListWebhookDeliveries_Call) RunAndReturn(run func(context.Context, int64, int, int) ([]storage.WebhookDelivery, error)) *DeliveryLister_ListWebhookDeliveries_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewDeliveryLister creates a new instance of DeliveryLister. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewDeliveryLister(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *DeliveryLister {
+	mock := &DeliveryLister{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,93 @@
+package list_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/dkhrunov/url-shortener/internal/storage"
+	"github.com/dkhrunov/url-shortener/internal/transport/http/handlers/webhook/list"
+	"github.com/dkhrunov/url-shortener/internal/transport/http/handlers/webhook/list/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestListHandler(t *testing.T) {
+	cases := []struct {
+		name      string
+		webhooks  []storage.Webhook
+		respError string
+		mockError error
+		status    int
+	}{
+		{
+			name: "Success",
+			webhooks: []storage.Webhook{
+				{ID: 1, URL: "https://hooks.example/a", Secret: "whsec_a", Events: []string{"link.created"}, CreatedAt: time.Now()},
+				{ID: 2, URL: "https://hooks.example/b", Secret: "whsec_b", Events: []string{"link.clicked"}, CreatedAt: time.Now()},
+			},
+			status: http.StatusOK,
+		},
+		{
+			name:     "Empty",
+			webhooks: []storage.Webhook{},
+			status:   http.StatusOK,
+		},
+		{
+			name:      "Failed",
+			respError: "failed to list webhooks",
+			mockError: errors.New("unexpected error"),
+			status:    http.StatusInternalServerError,
+		},
+		{
+			name:      "ListWebhooks timed out",
+			respError: "storage timed out",
+			mockError: fmt.Errorf("storage.sqlite.ListWebhooks: %w", context.DeadlineExceeded),
+			status:    http.StatusGatewayTimeout,
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			webhookListerMock := mocks.NewWebhookLister(t)
+
+			webhookListerMock.EXPECT().
+				ListWebhooks(mock.Anything).
+				Return(tc.webhooks, tc.mockError).
+				Once()
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodGet, "/admin/webhooks", nil)
+
+			handler := list.New(webhookListerMock)
+			handler.ServeHTTP(w, r)
+
+			assert.Equal(t, tc.status, w.Code)
+
+			// Secrets are never listed.
+			assert.NotContains(t, w.Body.String(), "whsec_")
+
+			var resp list.Response
+
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+
+			assert.Equal(t, tc.respError, resp.Error)
+
+			require.Len(t, resp.Webhooks, len(tc.webhooks))
+			for i, wh := range resp.Webhooks {
+				assert.Equal(t, tc.webhooks[i].URL, wh.URL)
+				assert.Equal(t, tc.webhooks[i].Events, wh.Events)
+			}
+		})
+	}
+}
@@ -0,0 +1,96 @@
+// Code generated by mockery v2.39.1. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+
+	storage "github.com/dkhrunov/url-shortener/internal/storage"
+)
+
+// WebhookLister is an autogenerated mock type for the WebhookLister type
+type WebhookLister struct {
+	mock.Mock
+}
+
+type WebhookLister_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *WebhookLister) EXPECT() *WebhookLister_Expecter {
+	return &WebhookLister_Expecter{mock: &_m.Mock}
+}
+
+// ListWebhooks provides a mock function with given fields: ctx
+func (_m *WebhookLister) ListWebhooks(ctx context.Context) ([]storage.Webhook, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListWebhooks")
+	}
+
+	var r0 []storage.Webhook
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) ([]storage.Webhook, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) []storage.Webhook); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]storage.Webhook)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// WebhookLister_ListWebhooks_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListWebhooks'
+type WebhookLister_ListWebhooks_Call struct {
+	*mock.Call
+}
+
+// ListWebhooks is a helper method to define mock.On call
+//   - ctx context.Context
+func (_e *WebhookLister_Expecter) ListWebhooks(ctx interface{}) *WebhookLister_ListWebhooks_Call {
+	return &WebhookLister_ListWebhooks_Call{Call: _e.mock.On("ListWebhooks", ctx)}
+}
+
+func (_c *WebhookLister_ListWebhooks_Call) Run(run func(ctx context.Context)) *WebhookLister_ListWebhooks_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context))
+	})
+	return _c
+}
+
+func (_c *WebhookLister_ListWebhooks_Call) Return(_a0 []storage.Webhook, _a1 error) *WebhookLister_ListWebhooks_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *WebhookLister_ListWebhooks_Call) RunAndReturn(run func(context.Context) ([]storage.Webhook, error)) *WebhookLister_ListWebhooks_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewWebhookLister creates a new instance of WebhookLister. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewWebhookLister(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *WebhookLister {
+	mock := &WebhookLister{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,235 @@
+package middleware
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/dkhrunov/url-shortener/internal/auth"
+	"github.com/dkhrunov/url-shortener/internal/lib/apikey"
+	"github.com/dkhrunov/url-shortener/internal/lib/logger/slog/slogerr"
+	"github.com/dkhrunov/url-shortener/internal/lib/password"
+	"github.com/dkhrunov/url-shortener/internal/storage"
+	"github.com/dkhrunov/url-shortener/internal/transport/http/common/response"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+)
+
+const apiKeyHeader = "X-API-Key"
+
+var errInvalidCredentials = errors.New("invalid credentials")
+
+type Authenticator interface {
+	UseAPIKey(ctx context.Context, keyHash string) (storage.APIKey, error)
+	GetUser(ctx context.Context, id int64) (storage.User, error)
+	GetUserByUsername(ctx context.Context, username string) (storage.User, error)
+}
+
+// Auth authenticates requests with an API key sent either as a bearer token or in
+// the X-API-Key header. When basic is true, BasicAuth is accepted as a fallback, both
+// for user accounts and for the built-in admins. The authenticated auth.Identity is
+// stored in the request context. Requests already authenticated by an earlier
+// middleware, e.g. JWT, are passed through.
+//
+// API keys that are not tied to a user act with the admin role.
+func Auth(users Authenticator, admins map[string]string, basic bool) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		fn := func(w http.ResponseWriter, r *http.Request) {
+			const op = "middleware.Auth"
+
+			log := slog.With(
+				slog.String("op", op),
+				slog.String("request_id", middleware.GetReqID(r.Context())),
+			)
+
+			if _, ok := auth.FromContext(r.Context()); ok {
+				next.ServeHTTP(w, r)
+
+				return
+			}
+
+			if key := requestAPIKey(r); key != "" {
+				id, err := apiKeyIdentity(r.Context(), users, key)
+				if errors.Is(err, storage.ErrAPIKeyNotFound) || errors.Is(err, storage.ErrUserNotFound) {
+					log.InfoContext(r.Context(), "invalid api key")
+
+					unauthorized(w, r, basic, "invalid api key")
+
+					return
+				}
+				if status, resp, ok := response.Unavailable(err); ok {
+					log.WarnContext(r.Context(), "storage unavailable", slogerr.Error(err))
+
+					render.Status(r, status)
+					render.JSON(w, r, resp)
+
+					return
+				}
+				if err != nil {
+					log.ErrorContext(r.Context(), "failed to check api key", slogerr.Error(err))
+
+					render.Status(r, http.StatusInternalServerError)
+					render.JSON(w, r, response.Error("failed to authenticate"))
+
+					return
+				}
+
+				next.ServeHTTP(w, r.WithContext(auth.WithIdentity(r.Context(), id)))
+
+				return
+			}
+
+			if user, pass, ok := r.BasicAuth(); ok && basic {
+				id, err := basicIdentity(r.Context(), users, admins, user, pass)
+				if errors.Is(err, errInvalidCredentials) {
+					log.InfoContext(r.Context(), "invalid basic auth credentials", slog.String("user", user))
+
+					unauthorized(w, r, basic, "invalid credentials")
+
+					return
+				}
+				if status, resp, ok := response.Unavailable(err); ok {
+					log.WarnContext(r.Context(), "storage unavailable", slogerr.Error(err))
+
+					render.Status(r, status)
+					render.JSON(w, r, resp)
+
+					return
+				}
+				if err != nil {
+					log.ErrorContext(r.Context(), "failed to check credentials", slogerr.Error(err))
+
+					render.Status(r, http.StatusInternalServerError)
+					render.JSON(w, r, response.Error("failed to authenticate"))
+
+					return
+				}
+
+				next.ServeHTTP(w, r.WithContext(auth.WithIdentity(r.Context(), id)))
+
+				return
+			}
+
+			unauthorized(w, r, basic, "unauthorized")
+		}
+
+		return http.HandlerFunc(fn)
+	}
+}
+
+// Require rejects requests whose identity doesn't have the permission with 403. It must be used after Auth.
+func Require(perm auth.Permission) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		fn := func(w http.ResponseWriter, r *http.Request) {
+			const op = "middleware.Require"
+
+			id, ok := auth.FromContext(r.Context())
+			if !ok || !id.Can(perm) {
+				slog.InfoContext(r.Context(), "permission denied",
+					slog.String("op", op),
+					slog.String("request_id", middleware.GetReqID(r.Context())),
+					slog.String("permission", string(perm)),
+					slog.String("role", string(id.Role)),
+				)
+
+				render.Status(r, http.StatusForbidden)
+				render.JSON(w, r, response.Error("forbidden"))
+
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		}
+
+		return http.HandlerFunc(fn)
+	}
+}
+
+func apiKeyIdentity(ctx context.Context, users Authenticator, key string) (auth.Identity, error) {
+	k, err := users.UseAPIKey(ctx, apikey.Hash(key))
+	if err != nil {
+		return auth.Identity{}, err
+	}
+
+	id := auth.Identity{
+		Method: auth.MethodAPIKey,
+		Name:   k.Name,
+		KeyID:  k.ID,
+		Role:   auth.RoleAdmin,
+	}
+
+	if k.UserID != 0 {
+		u, err := users.GetUser(ctx, k.UserID)
+		if err != nil {
+			return auth.Identity{}, err
+		}
+
+		id.UserID = u.ID
+		id.Role = auth.Role(u.Role)
+	}
+
+	return id, nil
+}
+
+func basicIdentity(ctx context.Context, users Authenticator, admins map[string]string, user, pass string) (auth.Identity, error) {
+	if expected, ok := admins[user]; ok {
+		if subtle.ConstantTimeCompare([]byte(expected), []byte(pass)) != 1 {
+			return auth.Identity{}, errInvalidCredentials
+		}
+
+		return auth.Identity{
+			Method: auth.MethodBasic,
+			Name:   user,
+			Role:   auth.RoleAdmin,
+		}, nil
+	}
+
+	u, err := users.GetUserByUsername(ctx, user)
+	if errors.Is(err, storage.ErrUserNotFound) {
+		return auth.Identity{}, errInvalidCredentials
+	}
+	if err != nil {
+		return auth.Identity{}, err
+	}
+
+	if !password.Check(u.PasswordHash, pass) {
+		return auth.Identity{}, errInvalidCredentials
+	}
+
+	return auth.Identity{
+		Method: auth.MethodBasic,
+		Name:   u.Username,
+		UserID: u.ID,
+		Role:   auth.Role(u.Role),
+	}, nil
+}
+
+func requestAPIKey(r *http.Request) string {
+	if key := r.Header.Get(apiKeyHeader); key != "" {
+		return key
+	}
+
+	return bearerToken(r)
+}
+
+func bearerToken(r *http.Request) string {
+	scheme, token, ok := strings.Cut(r.Header.Get("Authorization"), " ")
+	if ok && strings.EqualFold(scheme, "Bearer") {
+		return strings.TrimSpace(token)
+	}
+
+	return ""
+}
+
+func unauthorized(w http.ResponseWriter, r *http.Request, basic bool, msg string) {
+	w.Header().Add("WWW-Authenticate", `Bearer realm="url-shortener"`)
+	if basic {
+		w.Header().Add("WWW-Authenticate", `Basic realm="url-shortener"`)
+	}
+
+	render.Status(r, http.StatusUnauthorized)
+	render.JSON(w, r, response.Error(msg))
+}
@@ -0,0 +1,310 @@
+package middleware_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/dkhrunov/url-shortener/internal/auth"
+	"github.com/dkhrunov/url-shortener/internal/lib/apikey"
+	"github.com/dkhrunov/url-shortener/internal/lib/password"
+	"github.com/dkhrunov/url-shortener/internal/storage"
+	"github.com/dkhrunov/url-shortener/internal/transport/http/common/response"
+	"github.com/dkhrunov/url-shortener/internal/transport/http/middleware"
+	"github.com/dkhrunov/url-shortener/internal/transport/http/middleware/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuth(t *testing.T) {
+	const key = "usk_secret"
+
+	hash, err := password.Hash("alice-password")
+	require.NoError(t, err)
+
+	alice := storage.User{ID: 3, Username: "alice", PasswordHash: hash, Role: "editor"}
+	admins := map[string]string{"myuser": "mypass"}
+
+	cases := []struct {
+		name      string
+		headers   map[string]string
+		basicUser string
+		basicPass string
+		basic     bool
+		setup     func(m *mocks.Authenticator)
+		identity  auth.Identity
+		respError string
+		status    int
+	}{
+		{
+			name:    "Bearer token",
+			headers: map[string]string{"Authorization": "Bearer " + key},
+			setup: func(m *mocks.Authenticator) {
+				m.EXPECT().UseAPIKey(mock.Anything, apikey.Hash(key)).Return(storage.APIKey{ID: 7, Name: "ci"}, nil).Once()
+			},
+			identity: auth.Identity{Method: auth.MethodAPIKey, Name: "ci", KeyID: 7, Role: auth.RoleAdmin},
+			status:   http.StatusOK,
+		},
+		{
+			name:    "X-API-Key header",
+			headers: map[string]string{"X-API-Key": key},
+			setup: func(m *mocks.Authenticator) {
+				m.EXPECT().UseAPIKey(mock.Anything, apikey.Hash(key)).Return(storage.APIKey{ID: 7, Name: "ci"}, nil).Once()
+			},
+			identity: auth.Identity{Method: auth.MethodAPIKey, Name: "ci", KeyID: 7, Role: auth.RoleAdmin},
+			status:   http.StatusOK,
+		},
+		{
+			name:    "User key",
+			headers: map[string]string{"X-API-Key": key},
+			setup: func(m *mocks.Authenticator) {
+				m.EXPECT().UseAPIKey(mock.Anything, apikey.Hash(key)).Return(storage.APIKey{ID: 7, Name: "ci", UserID: 3}, nil).Once()
+				m.EXPECT().GetUser(mock.Anything, int64(3)).Return(alice, nil).Once()
+			},
+			identity: auth.Identity{Method: auth.MethodAPIKey, Name: "ci", KeyID: 7, UserID: 3, Role: auth.RoleEditor},
+			status:   http.StatusOK,
+		},
+		{
+			name:    "Key of deleted user",
+			headers: map[string]string{"X-API-Key": key},
+			setup: func(m *mocks.Authenticator) {
+				m.EXPECT().UseAPIKey(mock.Anything, apikey.Hash(key)).Return(storage.APIKey{ID: 7, Name: "ci", UserID: 3}, nil).Once()
+				m.EXPECT().GetUser(mock.Anything, int64(3)).Return(storage.User{}, storage.ErrUserNotFound).Once()
+			},
+			respError: "invalid api key",
+			status:    http.StatusUnauthorized,
+		},
+		{
+			name:    "Unknown or revoked key",
+			headers: map[string]string{"X-API-Key": key},
+			setup: func(m *mocks.Authenticator) {
+				m.EXPECT().UseAPIKey(mock.Anything, apikey.Hash(key)).Return(storage.APIKey{}, storage.ErrAPIKeyNotFound).Once()
+			},
+			respError: "invalid api key",
+			status:    http.StatusUnauthorized,
+		},
+		{
+			name:    "Storage error",
+			headers: map[string]string{"X-API-Key": key},
+			setup: func(m *mocks.Authenticator) {
+				m.EXPECT().UseAPIKey(mock.Anything, apikey.Hash(key)).Return(storage.APIKey{}, errors.New("unexpected error")).Once()
+			},
+			respError: "failed to authenticate",
+			status:    http.StatusInternalServerError,
+		},
+		{
+			name:    "Storage timeout",
+			headers: map[string]string{"X-API-Key": key},
+			setup: func(m *mocks.Authenticator) {
+				m.EXPECT().UseAPIKey(mock.Anything, apikey.Hash(key)).Return(storage.APIKey{}, context.DeadlineExceeded).Once()
+			},
+			respError: "storage timed out",
+			status:    http.StatusGatewayTimeout,
+		},
+		{
+			name:      "Basic built-in admin",
+			basicUser: "myuser",
+			basicPass: "mypass",
+			basic:     true,
+			identity:  auth.Identity{Method: auth.MethodBasic, Name: "myuser", Role: auth.RoleAdmin},
+			status:    http.StatusOK,
+		},
+		{
+			name:      "Basic built-in admin wrong password",
+			basicUser: "myuser",
+			basicPass: "wrong",
+			basic:     true,
+			respError: "invalid credentials",
+			status:    http.StatusUnauthorized,
+		},
+		{
+			name:      "Basic user account",
+			basicUser: "alice",
+			basicPass: "alice-password",
+			basic:     true,
+			setup: func(m *mocks.Authenticator) {
+				m.EXPECT().GetUserByUsername(mock.Anything, "alice").Return(alice, nil).Once()
+			},
+			identity: auth.Identity{Method: auth.MethodBasic, Name: "alice", UserID: 3, Role: auth.RoleEditor},
+			status:   http.StatusOK,
+		},
+		{
+			name:      "Basic user account wrong password",
+			basicUser: "alice",
+			basicPass: "wrong",
+			basic:     true,
+			setup: func(m *mocks.Authenticator) {
+				m.EXPECT().GetUserByUsername(mock.Anything, "alice").Return(alice, nil).Once()
+			},
+			respError: "invalid credentials",
+			status:    http.StatusUnauthorized,
+		},
+		{
+			name:      "Basic unknown user",
+			basicUser: "bob",
+			basicPass: "bob-password",
+			basic:     true,
+			setup: func(m *mocks.Authenticator) {
+				m.EXPECT().GetUserByUsername(mock.Anything, "bob").Return(storage.User{}, storage.ErrUserNotFound).Once()
+			},
+			respError: "invalid credentials",
+			status:    http.StatusUnauthorized,
+		},
+		{
+			name:      "Basic storage timeout",
+			basicUser: "alice",
+			basicPass: "alice-password",
+			basic:     true,
+			setup: func(m *mocks.Authenticator) {
+				m.EXPECT().GetUserByUsername(mock.Anything, "alice").Return(storage.User{}, context.DeadlineExceeded).Once()
+			},
+			respError: "storage timed out",
+			status:    http.StatusGatewayTimeout,
+		},
+		{
+			name:      "Basic fallback disabled",
+			basicUser: "myuser",
+			basicPass: "mypass",
+			respError: "unauthorized",
+			status:    http.StatusUnauthorized,
+		},
+		{
+			name:      "No credentials",
+			respError: "unauthorized",
+			status:    http.StatusUnauthorized,
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			authenticatorMock := mocks.NewAuthenticator(t)
+
+			if tc.setup != nil {
+				tc.setup(authenticatorMock)
+			}
+
+			var got auth.Identity
+			next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				got, _ = auth.FromContext(r.Context())
+			})
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodGet, "/url", nil)
+			for k, v := range tc.headers {
+				r.Header.Set(k, v)
+			}
+			if tc.basicUser != "" {
+				r.SetBasicAuth(tc.basicUser, tc.basicPass)
+			}
+
+			middleware.Auth(authenticatorMock, admins, tc.basic)(next).ServeHTTP(w, r)
+
+			assert.Equal(t, tc.status, w.Code)
+			assert.Equal(t, tc.identity, got)
+
+			if tc.respError != "" {
+				var resp response.Response
+
+				require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+
+				assert.Equal(t, tc.respError, resp.Error)
+			}
+			if tc.status == http.StatusUnauthorized {
+				assert.NotEmpty(t, w.Header().Values("WWW-Authenticate"))
+			}
+		})
+	}
+}
+
+func TestAuthAlreadyAuthenticated(t *testing.T) {
+	id := auth.Identity{Method: auth.MethodJWT, Name: "alice", UserID: 3, Role: auth.RoleEditor}
+
+	var got auth.Identity
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got, _ = auth.FromContext(r.Context())
+	})
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodGet, "/url", nil)
+	r = r.WithContext(auth.WithIdentity(r.Context(), id))
+
+	middleware.Auth(mocks.NewAuthenticator(t), nil, false)(next).ServeHTTP(w, r)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, id, got)
+}
+
+func TestRequire(t *testing.T) {
+	cases := []struct {
+		name     string
+		identity *auth.Identity
+		perm     auth.Permission
+		status   int
+	}{
+		{
+			name:     "Admin manages users",
+			identity: &auth.Identity{Role: auth.RoleAdmin},
+			perm:     auth.PermUserManage,
+			status:   http.StatusOK,
+		},
+		{
+			name:     "Editor creates links",
+			identity: &auth.Identity{UserID: 3, Role: auth.RoleEditor},
+			perm:     auth.PermLinkCreate,
+			status:   http.StatusOK,
+		},
+		{
+			name:     "Editor manages users",
+			identity: &auth.Identity{UserID: 3, Role: auth.RoleEditor},
+			perm:     auth.PermUserManage,
+			status:   http.StatusForbidden,
+		},
+		{
+			name:     "Viewer deletes links",
+			identity: &auth.Identity{UserID: 3, Role: auth.RoleViewer},
+			perm:     auth.PermLinkDelete,
+			status:   http.StatusForbidden,
+		},
+		{
+			name:   "No identity",
+			perm:   auth.PermLinkRead,
+			status: http.StatusForbidden,
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodGet, "/admin/users", nil)
+			if tc.identity != nil {
+				r = r.WithContext(auth.WithIdentity(r.Context(), *tc.identity))
+			}
+
+			middleware.Require(tc.perm)(next).ServeHTTP(w, r)
+
+			assert.Equal(t, tc.status, w.Code)
+
+			if tc.status == http.StatusForbidden {
+				var resp response.Response
+
+				require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+
+				assert.Equal(t, "forbidden", resp.Error)
+			}
+		})
+	}
+}
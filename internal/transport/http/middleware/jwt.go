@@ -0,0 +1,155 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/dkhrunov/url-shortener/internal/auth"
+	"github.com/dkhrunov/url-shortener/internal/lib/jwtauth"
+	"github.com/dkhrunov/url-shortener/internal/lib/logger/slog/slogerr"
+	"github.com/dkhrunov/url-shortener/internal/storage"
+	"github.com/dkhrunov/url-shortener/internal/transport/http/common/response"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+)
+
+type TokenVerifier interface {
+	Verify(token string) (jwtauth.Claims, error)
+}
+
+type TokenUsers interface {
+	GetUserByUsername(ctx context.Context, username string) (storage.User, error)
+	SaveUser(ctx context.Context, username, passwordHash, role string) (int64, error)
+}
+
+var tokenErrors = []struct {
+	err error
+	msg string
+}{
+	{jwtauth.ErrExpired, "token expired"},
+	{jwtauth.ErrNotValidYet, "token not valid yet"},
+	{jwtauth.ErrInvalidSignature, "invalid token signature"},
+	{jwtauth.ErrUnknownKey, "unknown token signing key"},
+	{jwtauth.ErrInvalidClaims, "invalid token claims"},
+}
+
+// JWT authenticates requests carrying a JWT as a bearer token. The subject is the
+// username of the account the request acts as; an account is created on first use.
+// The role claim, when present, overrides the role of the account. Other bearer
+// tokens, such as API keys, and requests without one are passed on untouched.
+func JWT(verifier TokenVerifier, users TokenUsers) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		fn := func(w http.ResponseWriter, r *http.Request) {
+			const op = "middleware.JWT"
+
+			log := slog.With(
+				slog.String("op", op),
+				slog.String("request_id", middleware.GetReqID(r.Context())),
+			)
+
+			token := bearerToken(r)
+			if strings.Count(token, ".") != 2 {
+				next.ServeHTTP(w, r)
+
+				return
+			}
+
+			claims, err := verifier.Verify(token)
+			if err != nil {
+				log.InfoContext(r.Context(), "invalid token", slogerr.Error(err))
+
+				invalidToken(w, r, tokenErrorMessage(err))
+
+				return
+			}
+
+			role := auth.Role(claims.Role)
+			if role != "" && !role.Valid() {
+				log.InfoContext(r.Context(), "unknown role in token", slog.String("role", claims.Role))
+
+				invalidToken(w, r, "unknown role")
+
+				return
+			}
+
+			user, err := tokenUser(r.Context(), users, claims.Subject, role)
+			if status, resp, ok := response.Unavailable(err); ok {
+				log.WarnContext(r.Context(), "storage unavailable", slogerr.Error(err))
+
+				render.Status(r, status)
+				render.JSON(w, r, resp)
+
+				return
+			}
+			if err != nil {
+				log.ErrorContext(r.Context(), "failed to get token user", slogerr.Error(err))
+
+				render.Status(r, http.StatusInternalServerError)
+				render.JSON(w, r, response.Error("failed to authenticate"))
+
+				return
+			}
+
+			if role == "" {
+				role = auth.Role(user.Role)
+			}
+
+			id := auth.Identity{
+				Method: auth.MethodJWT,
+				Name:   claims.Subject,
+				UserID: user.ID,
+				Role:   role,
+			}
+
+			next.ServeHTTP(w, r.WithContext(auth.WithIdentity(r.Context(), id)))
+		}
+
+		return http.HandlerFunc(fn)
+	}
+}
+
+// tokenUser returns the account of the token subject, creating it with the role
+// from the token, or the viewer role, if it doesn't exist yet. Such accounts have
+// no password and can't use BasicAuth.
+func tokenUser(ctx context.Context, users TokenUsers, username string, role auth.Role) (storage.User, error) {
+	user, err := users.GetUserByUsername(ctx, username)
+	if !errors.Is(err, storage.ErrUserNotFound) {
+		return user, err
+	}
+
+	if role == "" {
+		role = auth.RoleViewer
+	}
+
+	id, err := users.SaveUser(ctx, username, "", string(role))
+	if errors.Is(err, storage.ErrUserExist) {
+		// Created by a concurrent request.
+		return users.GetUserByUsername(ctx, username)
+	}
+	if err != nil {
+		return storage.User{}, err
+	}
+
+	return storage.User{ID: id, Username: username, Role: string(role)}, nil
+}
+
+func tokenErrorMessage(err error) string {
+	for _, e := range tokenErrors {
+		if errors.Is(err, e.err) {
+			return e.msg
+		}
+	}
+
+	return "invalid token"
+}
+
+func invalidToken(w http.ResponseWriter, r *http.Request, msg string) {
+	w.Header().Set("WWW-Authenticate", fmt.Sprintf(`Bearer realm="url-shortener", error="invalid_token", error_description=%q`, msg))
+
+	render.Status(r, http.StatusUnauthorized)
+	render.JSON(w, r, response.Error(msg))
+}
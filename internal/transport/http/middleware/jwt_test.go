@@ -0,0 +1,197 @@
+package middleware_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/dkhrunov/url-shortener/internal/auth"
+	"github.com/dkhrunov/url-shortener/internal/lib/jwtauth"
+	"github.com/dkhrunov/url-shortener/internal/storage"
+	"github.com/dkhrunov/url-shortener/internal/transport/http/common/response"
+	"github.com/dkhrunov/url-shortener/internal/transport/http/middleware"
+	"github.com/dkhrunov/url-shortener/internal/transport/http/middleware/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJWT(t *testing.T) {
+	const token = "header.payload.signature"
+
+	alice := storage.User{ID: 3, Username: "alice", Role: "viewer"}
+
+	cases := []struct {
+		name      string
+		header    string
+		setup     func(v *mocks.TokenVerifier, u *mocks.TokenUsers)
+		identity  auth.Identity
+		next      bool
+		respError string
+		status    int
+	}{
+		{
+			name:   "Role from token",
+			header: "Bearer " + token,
+			setup: func(v *mocks.TokenVerifier, u *mocks.TokenUsers) {
+				v.EXPECT().Verify(token).Return(jwtauth.Claims{Subject: "alice", Role: "editor"}, nil).Once()
+				u.EXPECT().GetUserByUsername(mock.Anything, "alice").Return(alice, nil).Once()
+			},
+			identity: auth.Identity{Method: auth.MethodJWT, Name: "alice", UserID: 3, Role: auth.RoleEditor},
+			next:     true,
+			status:   http.StatusOK,
+		},
+		{
+			name:   "Role from account",
+			header: "Bearer " + token,
+			setup: func(v *mocks.TokenVerifier, u *mocks.TokenUsers) {
+				v.EXPECT().Verify(token).Return(jwtauth.Claims{Subject: "alice"}, nil).Once()
+				u.EXPECT().GetUserByUsername(mock.Anything, "alice").Return(alice, nil).Once()
+			},
+			identity: auth.Identity{Method: auth.MethodJWT, Name: "alice", UserID: 3, Role: auth.RoleViewer},
+			next:     true,
+			status:   http.StatusOK,
+		},
+		{
+			name:   "Account created on first use",
+			header: "Bearer " + token,
+			setup: func(v *mocks.TokenVerifier, u *mocks.TokenUsers) {
+				v.EXPECT().Verify(token).Return(jwtauth.Claims{Subject: "bob", Role: "admin"}, nil).Once()
+				u.EXPECT().GetUserByUsername(mock.Anything, "bob").Return(storage.User{}, storage.ErrUserNotFound).Once()
+				u.EXPECT().SaveUser(mock.Anything, "bob", "", "admin").Return(int64(4), nil).Once()
+			},
+			identity: auth.Identity{Method: auth.MethodJWT, Name: "bob", UserID: 4, Role: auth.RoleAdmin},
+			next:     true,
+			status:   http.StatusOK,
+		},
+		{
+			name:   "Account created as viewer",
+			header: "Bearer " + token,
+			setup: func(v *mocks.TokenVerifier, u *mocks.TokenUsers) {
+				v.EXPECT().Verify(token).Return(jwtauth.Claims{Subject: "bob"}, nil).Once()
+				u.EXPECT().GetUserByUsername(mock.Anything, "bob").Return(storage.User{}, storage.ErrUserNotFound).Once()
+				u.EXPECT().SaveUser(mock.Anything, "bob", "", "viewer").Return(int64(4), nil).Once()
+			},
+			identity: auth.Identity{Method: auth.MethodJWT, Name: "bob", UserID: 4, Role: auth.RoleViewer},
+			next:     true,
+			status:   http.StatusOK,
+		},
+		{
+			name:   "Expired",
+			header: "Bearer " + token,
+			setup: func(v *mocks.TokenVerifier, u *mocks.TokenUsers) {
+				v.EXPECT().Verify(token).Return(jwtauth.Claims{}, jwtauth.ErrExpired).Once()
+			},
+			respError: "token expired",
+			status:    http.StatusUnauthorized,
+		},
+		{
+			name:   "Invalid signature",
+			header: "Bearer " + token,
+			setup: func(v *mocks.TokenVerifier, u *mocks.TokenUsers) {
+				v.EXPECT().Verify(token).Return(jwtauth.Claims{}, jwtauth.ErrInvalidSignature).Once()
+			},
+			respError: "invalid token signature",
+			status:    http.StatusUnauthorized,
+		},
+		{
+			name:   "Malformed",
+			header: "Bearer " + token,
+			setup: func(v *mocks.TokenVerifier, u *mocks.TokenUsers) {
+				v.EXPECT().Verify(token).Return(jwtauth.Claims{}, jwtauth.ErrMalformed).Once()
+			},
+			respError: "invalid token",
+			status:    http.StatusUnauthorized,
+		},
+		{
+			name:   "Unknown role",
+			header: "Bearer " + token,
+			setup: func(v *mocks.TokenVerifier, u *mocks.TokenUsers) {
+				v.EXPECT().Verify(token).Return(jwtauth.Claims{Subject: "alice", Role: "owner"}, nil).Once()
+			},
+			respError: "unknown role",
+			status:    http.StatusUnauthorized,
+		},
+		{
+			name:   "Storage error",
+			header: "Bearer " + token,
+			setup: func(v *mocks.TokenVerifier, u *mocks.TokenUsers) {
+				v.EXPECT().Verify(token).Return(jwtauth.Claims{Subject: "alice"}, nil).Once()
+				u.EXPECT().GetUserByUsername(mock.Anything, "alice").Return(storage.User{}, errors.New("unexpected error")).Once()
+			},
+			respError: "failed to authenticate",
+			status:    http.StatusInternalServerError,
+		},
+		{
+			name:   "Storage timeout",
+			header: "Bearer " + token,
+			setup: func(v *mocks.TokenVerifier, u *mocks.TokenUsers) {
+				v.EXPECT().Verify(token).Return(jwtauth.Claims{Subject: "alice"}, nil).Once()
+				u.EXPECT().GetUserByUsername(mock.Anything, "alice").Return(storage.User{}, context.DeadlineExceeded).Once()
+			},
+			respError: "storage timed out",
+			status:    http.StatusGatewayTimeout,
+		},
+		{
+			name:   "API key is passed on",
+			header: "Bearer usk_secret",
+			next:   true,
+			status: http.StatusOK,
+		},
+		{
+			name:   "No token is passed on",
+			next:   true,
+			status: http.StatusOK,
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			verifierMock := mocks.NewTokenVerifier(t)
+			usersMock := mocks.NewTokenUsers(t)
+
+			if tc.setup != nil {
+				tc.setup(verifierMock, usersMock)
+			}
+
+			var (
+				called bool
+				got    auth.Identity
+			)
+			next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				called = true
+				got, _ = auth.FromContext(r.Context())
+			})
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodGet, "/url", nil)
+			if tc.header != "" {
+				r.Header.Set("Authorization", tc.header)
+			}
+
+			middleware.JWT(verifierMock, usersMock)(next).ServeHTTP(w, r)
+
+			assert.Equal(t, tc.status, w.Code)
+			assert.Equal(t, tc.next, called)
+			assert.Equal(t, tc.identity, got)
+
+			if tc.respError != "" {
+				var resp response.Response
+
+				require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+
+				assert.Equal(t, tc.respError, resp.Error)
+			}
+			if tc.status == http.StatusUnauthorized {
+				assert.Contains(t, w.Header().Get("WWW-Authenticate"), `error="invalid_token"`)
+			}
+		})
+	}
+}
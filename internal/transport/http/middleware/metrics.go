@@ -0,0 +1,55 @@
+package middleware
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+)
+
+type RequestObserver interface {
+	ObserveRequest(route, method string, status int, d time.Duration)
+}
+
+// Metrics observes the count and latency of requests by route pattern rather than
+// path, so aliases don't end up as label values. Requests matching no route are
+// observed under "unmatched", and methods outside the standard ones under "other".
+func Metrics(observer RequestObserver) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		fn := func(w http.ResponseWriter, r *http.Request) {
+			ww := middleware.NewWrapResponseWriter(w, r.ProtoMajor)
+
+			start := time.Now()
+
+			next.ServeHTTP(ww, r)
+
+			route := "unmatched"
+			if rctx := chi.RouteContext(r.Context()); rctx != nil && rctx.RoutePattern() != "" {
+				route = rctx.RoutePattern()
+			}
+
+			status := ww.Status()
+			if status == 0 {
+				// Nothing was written, net/http responds with 200.
+				status = http.StatusOK
+			}
+
+			observer.ObserveRequest(route, metricMethod(r.Method), status, time.Since(start))
+		}
+
+		return http.HandlerFunc(fn)
+	}
+}
+
+// metricMethod returns the method as a label value. Clients can send any method, so
+// only the standard ones are kept to bound the number of series.
+func metricMethod(method string) string {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodPost, http.MethodPut, http.MethodPatch,
+		http.MethodDelete, http.MethodConnect, http.MethodOptions, http.MethodTrace:
+		return method
+	default:
+		return "other"
+	}
+}
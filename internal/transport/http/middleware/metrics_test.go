@@ -0,0 +1,87 @@
+package middleware_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/dkhrunov/url-shortener/internal/transport/http/middleware"
+	"github.com/dkhrunov/url-shortener/internal/transport/http/middleware/mocks"
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestMetrics(t *testing.T) {
+	cases := []struct {
+		name    string
+		method  string
+		path    string
+		route   string
+		observe string
+		status  int
+	}{
+		{
+			name:   "Route pattern",
+			path:   "/test_alias",
+			route:  "/{alias}",
+			status: http.StatusFound,
+		},
+		{
+			name:   "Nested route pattern",
+			path:   "/url/test_alias",
+			route:  "/url/{alias}",
+			status: http.StatusOK,
+		},
+		{
+			name:   "Unmatched",
+			path:   "/test_alias/unknown",
+			route:  "unmatched",
+			status: http.StatusNotFound,
+		},
+		{
+			name:    "Unknown method",
+			method:  "BREW",
+			path:    "/test_alias",
+			route:   "unmatched",
+			observe: "other",
+			status:  http.StatusMethodNotAllowed,
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			method := tc.method
+			if method == "" {
+				method = http.MethodGet
+			}
+			observe := tc.observe
+			if observe == "" {
+				observe = method
+			}
+
+			observerMock := mocks.NewRequestObserver(t)
+			observerMock.EXPECT().
+				ObserveRequest(tc.route, observe, tc.status, mock.Anything).
+				Once()
+
+			r := chi.NewRouter()
+			r.Use(middleware.Metrics(observerMock))
+			r.Get("/{alias}", func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusFound)
+			})
+			r.Route("/url", func(r chi.Router) {
+				r.Get("/{alias}", func(w http.ResponseWriter, r *http.Request) {})
+			})
+
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, httptest.NewRequest(method, tc.path, nil))
+
+			assert.Equal(t, tc.status, w.Code)
+		})
+	}
+}
@@ -0,0 +1,209 @@
+// Code generated by mockery v2.39.1. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+
+	storage "github.com/dkhrunov/url-shortener/internal/storage"
+)
+
+// Authenticator is an autogenerated mock type for the Authenticator type
+type Authenticator struct {
+	mock.Mock
+}
+
+type Authenticator_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *Authenticator) EXPECT() *Authenticator_Expecter {
+	return &Authenticator_Expecter{mock: &_m.Mock}
+}
+
+// GetUser provides a mock function with given fields: ctx, id
+func (_m *Authenticator) GetUser(ctx context.Context, id int64) (storage.User, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetUser")
+	}
+
+	var r0 storage.User
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64) (storage.User, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64) storage.User); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Get(0).(storage.User)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Authenticator_GetUser_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetUser'
+type Authenticator_GetUser_Call struct {
+	*mock.Call
+}
+
+// GetUser is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id int64
+func (_e *Authenticator_Expecter) GetUser(ctx interface{}, id interface{}) *Authenticator_GetUser_Call {
+	return &Authenticator_GetUser_Call{Call: _e.mock.On("GetUser", ctx, id)}
+}
+
+func (_c *Authenticator_GetUser_Call) Run(run func(ctx context.Context, id int64)) *Authenticator_GetUser_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int64))
+	})
+	return _c
+}
+
+func (_c *Authenticator_GetUser_Call) Return(_a0 storage.User, _a1 error) *Authenticator_GetUser_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Authenticator_GetUser_Call) RunAndReturn(run func(context.Context, int64) (storage.User, error)) *Authenticator_GetUser_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetUserByUsername provides a mock function with given fields: ctx, username
+func (_m *Authenticator) GetUserByUsername(ctx context.Context, username string) (storage.User, error) {
+	ret := _m.Called(ctx, username)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetUserByUsername")
+	}
+
+	var r0 storage.User
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (storage.User, error)); ok {
+		return rf(ctx, username)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) storage.User); ok {
+		r0 = rf(ctx, username)
+	} else {
+		r0 = ret.Get(0).(storage.User)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, username)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Authenticator_GetUserByUsername_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetUserByUsername'
+type Authenticator_GetUserByUsername_Call struct {
+	*mock.Call
+}
+
+// GetUserByUsername is a helper method to define mock.On call
+//   - ctx context.Context
+//   - username string
+func (_e *Authenticator_Expecter) GetUserByUsername(ctx interface{}, username interface{}) *Authenticator_GetUserByUsername_Call {
+	return &Authenticator_GetUserByUsername_Call{Call: _e.mock.On("GetUserByUsername", ctx, username)}
+}
+
+func (_c *Authenticator_GetUserByUsername_Call) Run(run func(ctx context.Context, username string)) *Authenticator_GetUserByUsername_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *Authenticator_GetUserByUsername_Call) Return(_a0 storage.User, _a1 error) *Authenticator_GetUserByUsername_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Authenticator_GetUserByUsername_Call) RunAndReturn(run func(context.Context, string) (storage.User, error)) *Authenticator_GetUserByUsername_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// UseAPIKey provides a mock function with given fields: ctx, keyHash
+func (_m *Authenticator) UseAPIKey(ctx context.Context, keyHash string) (storage.APIKey, error) {
+	ret := _m.Called(ctx, keyHash)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UseAPIKey")
+	}
+
+	var r0 storage.APIKey
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (storage.APIKey, error)); ok {
+		return rf(ctx, keyHash)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) storage.APIKey); ok {
+		r0 = rf(ctx, keyHash)
+	} else {
+		r0 = ret.Get(0).(storage.APIKey)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, keyHash)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Authenticator_UseAPIKey_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UseAPIKey'
+type Authenticator_UseAPIKey_Call struct {
+	*mock.Call
+}
+
+// UseAPIKey is a helper method to define mock.On call
+//   - ctx context.Context
+//   - keyHash string
+func (_e *Authenticator_Expecter) UseAPIKey(ctx interface{}, keyHash interface{}) *Authenticator_UseAPIKey_Call {
+	return &Authenticator_UseAPIKey_Call{Call: _e.mock.On("UseAPIKey", ctx, keyHash)}
+}
+
+func (_c *Authenticator_UseAPIKey_Call) Run(run func(ctx context.Context, keyHash string)) *Authenticator_UseAPIKey_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *Authenticator_UseAPIKey_Call) Return(_a0 storage.APIKey, _a1 error) *Authenticator_UseAPIKey_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Authenticator_UseAPIKey_Call) RunAndReturn(run func(context.Context, string) (storage.APIKey, error)) *Authenticator_UseAPIKey_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewAuthenticator creates a new instance of Authenticator. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewAuthenticator(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *Authenticator {
+	mock := &Authenticator{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,82 @@
+// Code generated by mockery v2.39.1. DO NOT EDIT.
+
+package mocks
+
+import (
+	http "net/http"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// ClientIPResolver is an autogenerated mock type for the ClientIPResolver type
+type ClientIPResolver struct {
+	mock.Mock
+}
+
+type ClientIPResolver_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *ClientIPResolver) EXPECT() *ClientIPResolver_Expecter {
+	return &ClientIPResolver_Expecter{mock: &_m.Mock}
+}
+
+// IP provides a mock function with given fields: r
+func (_m *ClientIPResolver) IP(r *http.Request) string {
+	ret := _m.Called(r)
+
+	if len(ret) == 0 {
+		panic("no return value specified for IP")
+	}
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func(*http.Request) string); ok {
+		r0 = rf(r)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	return r0
+}
+
+// ClientIPResolver_IP_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'IP'
+type ClientIPResolver_IP_Call struct {
+	*mock.Call
+}
+
+// IP is a helper method to define mock.On call
+//   - r *http.Request
+func (_e *ClientIPResolver_Expecter) IP(r interface{}) *ClientIPResolver_IP_Call {
+	return &ClientIPResolver_IP_Call{Call: _e.mock.On("IP", r)}
+}
+
+func (_c *ClientIPResolver_IP_Call) Run(run func(r *http.Request)) *ClientIPResolver_IP_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(*http.Request))
+	})
+	return _c
+}
+
+func (_c *ClientIPResolver_IP_Call) Return(_a0 string) *ClientIPResolver_IP_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *ClientIPResolver_IP_Call) RunAndReturn(run func(*http.Request) string) *ClientIPResolver_IP_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewClientIPResolver creates a new instance of ClientIPResolver. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewClientIPResolver(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *ClientIPResolver {
+	mock := &ClientIPResolver{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,92 @@
+// Code generated by mockery v2.39.1. DO NOT EDIT.
+
+package mocks
+
+import (
+	ratelimit "github.com/dkhrunov/url-shortener/internal/lib/ratelimit"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// RateLimiter is an autogenerated mock type for the RateLimiter type
+type RateLimiter struct {
+	mock.Mock
+}
+
+type RateLimiter_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *RateLimiter) EXPECT() *RateLimiter_Expecter {
+	return &RateLimiter_Expecter{mock: &_m.Mock}
+}
+
+// Allow provides a mock function with given fields: key, rate
+func (_m *RateLimiter) Allow(key string, rate ratelimit.Rate) (ratelimit.Result, error) {
+	ret := _m.Called(key, rate)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Allow")
+	}
+
+	var r0 ratelimit.Result
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string, ratelimit.Rate) (ratelimit.Result, error)); ok {
+		return rf(key, rate)
+	}
+	if rf, ok := ret.Get(0).(func(string, ratelimit.Rate) ratelimit.Result); ok {
+		r0 = rf(key, rate)
+	} else {
+		r0 = ret.Get(0).(ratelimit.Result)
+	}
+
+	if rf, ok := ret.Get(1).(func(string, ratelimit.Rate) error); ok {
+		r1 = rf(key, rate)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RateLimiter_Allow_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Allow'
+type RateLimiter_Allow_Call struct {
+	*mock.Call
+}
+
+// Allow is a helper method to define mock.On call
+//   - key string
+//   - rate ratelimit.Rate
+func (_e *RateLimiter_Expecter) Allow(key interface{}, rate interface{}) *RateLimiter_Allow_Call {
+	return &RateLimiter_Allow_Call{Call: _e.mock.On("Allow", key, rate)}
+}
+
+func (_c *RateLimiter_Allow_Call) Run(run func(key string, rate ratelimit.Rate)) *RateLimiter_Allow_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string), args[1].(ratelimit.Rate))
+	})
+	return _c
+}
+
+func (_c *RateLimiter_Allow_Call) Return(_a0 ratelimit.Result, _a1 error) *RateLimiter_Allow_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *RateLimiter_Allow_Call) RunAndReturn(run func(string, ratelimit.Rate) (ratelimit.Result, error)) *RateLimiter_Allow_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewRateLimiter creates a new instance of RateLimiter. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewRateLimiter(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *RateLimiter {
+	mock := &RateLimiter{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
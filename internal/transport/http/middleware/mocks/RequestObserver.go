@@ -0,0 +1,72 @@
+// Code generated by mockery v2.39.1. DO NOT EDIT.
+
+package mocks
+
+import (
+	time "time"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// RequestObserver is an autogenerated mock type for the RequestObserver type
+type RequestObserver struct {
+	mock.Mock
+}
+
+type RequestObserver_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *RequestObserver) EXPECT() *RequestObserver_Expecter {
+	return &RequestObserver_Expecter{mock: &_m.Mock}
+}
+
+// ObserveRequest provides a mock function with given fields: route, method, status, d
+func (_m *RequestObserver) ObserveRequest(route string, method string, status int, d time.Duration) {
+	_m.Called(route, method, status, d)
+}
+
+// RequestObserver_ObserveRequest_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ObserveRequest'
+type RequestObserver_ObserveRequest_Call struct {
+	*mock.Call
+}
+
+// ObserveRequest is a helper method to define mock.On call
+//   - route string
+//   - method string
+//   - status int
+//   - d time.Duration
+func (_e *RequestObserver_Expecter) ObserveRequest(route interface{}, method interface{}, status interface{}, d interface{}) *RequestObserver_ObserveRequest_Call {
+	return &RequestObserver_ObserveRequest_Call{Call: _e.mock.On("ObserveRequest", route, method, status, d)}
+}
+
+func (_c *RequestObserver_ObserveRequest_Call) Run(run func(route string, method string, status int, d time.Duration)) *RequestObserver_ObserveRequest_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string), args[1].(string), args[2].(int), args[3].(time.Duration))
+	})
+	return _c
+}
+
+func (_c *RequestObserver_ObserveRequest_Call) Return() *RequestObserver_ObserveRequest_Call {
+	_c.Call.Return()
+	return _c
+}
+
+func (_c *RequestObserver_ObserveRequest_Call) RunAndReturn(run func(string, string, int, time.Duration)) *RequestObserver_ObserveRequest_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewRequestObserver creates a new instance of RequestObserver. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewRequestObserver(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *RequestObserver {
+	mock := &RequestObserver{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
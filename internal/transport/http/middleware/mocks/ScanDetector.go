@@ -0,0 +1,147 @@
+// Code generated by mockery v2.39.1. DO NOT EDIT.
+
+package mocks
+
+import (
+	scanguard "github.com/dkhrunov/url-shortener/internal/lib/scanguard"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// ScanDetector is an autogenerated mock type for the ScanDetector type
+type ScanDetector struct {
+	mock.Mock
+}
+
+type ScanDetector_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *ScanDetector) EXPECT() *ScanDetector_Expecter {
+	return &ScanDetector_Expecter{mock: &_m.Mock}
+}
+
+// Blocked provides a mock function with given fields: client
+func (_m *ScanDetector) Blocked(client string) (scanguard.Block, bool) {
+	ret := _m.Called(client)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Blocked")
+	}
+
+	var r0 scanguard.Block
+	var r1 bool
+	if rf, ok := ret.Get(0).(func(string) (scanguard.Block, bool)); ok {
+		return rf(client)
+	}
+	if rf, ok := ret.Get(0).(func(string) scanguard.Block); ok {
+		r0 = rf(client)
+	} else {
+		r0 = ret.Get(0).(scanguard.Block)
+	}
+
+	if rf, ok := ret.Get(1).(func(string) bool); ok {
+		r1 = rf(client)
+	} else {
+		r1 = ret.Get(1).(bool)
+	}
+
+	return r0, r1
+}
+
+// ScanDetector_Blocked_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Blocked'
+type ScanDetector_Blocked_Call struct {
+	*mock.Call
+}
+
+// Blocked is a helper method to define mock.On call
+//   - client string
+func (_e *ScanDetector_Expecter) Blocked(client interface{}) *ScanDetector_Blocked_Call {
+	return &ScanDetector_Blocked_Call{Call: _e.mock.On("Blocked", client)}
+}
+
+func (_c *ScanDetector_Blocked_Call) Run(run func(client string)) *ScanDetector_Blocked_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string))
+	})
+	return _c
+}
+
+func (_c *ScanDetector_Blocked_Call) Return(_a0 scanguard.Block, _a1 bool) *ScanDetector_Blocked_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *ScanDetector_Blocked_Call) RunAndReturn(run func(string) (scanguard.Block, bool)) *ScanDetector_Blocked_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Miss provides a mock function with given fields: client
+func (_m *ScanDetector) Miss(client string) (scanguard.Block, bool) {
+	ret := _m.Called(client)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Miss")
+	}
+
+	var r0 scanguard.Block
+	var r1 bool
+	if rf, ok := ret.Get(0).(func(string) (scanguard.Block, bool)); ok {
+		return rf(client)
+	}
+	if rf, ok := ret.Get(0).(func(string) scanguard.Block); ok {
+		r0 = rf(client)
+	} else {
+		r0 = ret.Get(0).(scanguard.Block)
+	}
+
+	if rf, ok := ret.Get(1).(func(string) bool); ok {
+		r1 = rf(client)
+	} else {
+		r1 = ret.Get(1).(bool)
+	}
+
+	return r0, r1
+}
+
+// ScanDetector_Miss_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Miss'
+type ScanDetector_Miss_Call struct {
+	*mock.Call
+}
+
+// Miss is a helper method to define mock.On call
+//   - client string
+func (_e *ScanDetector_Expecter) Miss(client interface{}) *ScanDetector_Miss_Call {
+	return &ScanDetector_Miss_Call{Call: _e.mock.On("Miss", client)}
+}
+
+func (_c *ScanDetector_Miss_Call) Run(run func(client string)) *ScanDetector_Miss_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string))
+	})
+	return _c
+}
+
+func (_c *ScanDetector_Miss_Call) Return(_a0 scanguard.Block, _a1 bool) *ScanDetector_Miss_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *ScanDetector_Miss_Call) RunAndReturn(run func(string) (scanguard.Block, bool)) *ScanDetector_Miss_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewScanDetector creates a new instance of ScanDetector. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewScanDetector(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *ScanDetector {
+	mock := &ScanDetector{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,154 @@
+// Code generated by mockery v2.39.1. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+
+	storage "github.com/dkhrunov/url-shortener/internal/storage"
+)
+
+// TokenUsers is an autogenerated mock type for the TokenUsers type
+type TokenUsers struct {
+	mock.Mock
+}
+
+type TokenUsers_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *TokenUsers) EXPECT() *TokenUsers_Expecter {
+	return &TokenUsers_Expecter{mock: &_m.Mock}
+}
+
+// GetUserByUsername provides a mock function with given fields: ctx, username
+func (_m *TokenUsers) GetUserByUsername(ctx context.Context, username string) (storage.User, error) {
+	ret := _m.Called(ctx, username)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetUserByUsername")
+	}
+
+	var r0 storage.User
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (storage.User, error)); ok {
+		return rf(ctx, username)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) storage.User); ok {
+		r0 = rf(ctx, username)
+	} else {
+		r0 = ret.Get(0).(storage.User)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, username)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// TokenUsers_GetUserByUsername_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetUserByUsername'
+type TokenUsers_GetUserByUsername_Call struct {
+	*mock.Call
+}
+
+// GetUserByUsername is a helper method to define mock.On call
+//   - ctx context.Context
+//   - username string
+func (_e *TokenUsers_Expecter) GetUserByUsername(ctx interface{}, username interface{}) *TokenUsers_GetUserByUsername_Call {
+	return &TokenUsers_GetUserByUsername_Call{Call: _e.mock.On("GetUserByUsername", ctx, username)}
+}
+
+func (_c *TokenUsers_GetUserByUsername_Call) Run(run func(ctx context.Context, username string)) *TokenUsers_GetUserByUsername_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *TokenUsers_GetUserByUsername_Call) Return(_a0 storage.User, _a1 error) *TokenUsers_GetUserByUsername_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *TokenUsers_GetUserByUsername_Call) RunAndReturn(run func(context.Context, string) (storage.User, error)) *TokenUsers_GetUserByUsername_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// SaveUser provides a mock function with given fields: ctx, username, passwordHash, role
+func (_m *TokenUsers) SaveUser(ctx context.Context, username string, passwordHash string, role string) (int64, error) {
+	ret := _m.Called(ctx, username, passwordHash, role)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SaveUser")
+	}
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string) (int64, error)); ok {
+		return rf(ctx, username, passwordHash, role)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string) int64); ok {
+		r0 = rf(ctx, username, passwordHash, role)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, string) error); ok {
+		r1 = rf(ctx, username, passwordHash, role)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// TokenUsers_SaveUser_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SaveUser'
+type TokenUsers_SaveUser_Call struct {
+	*mock.Call
+}
+
+// SaveUser is a helper method to define mock.On call
+//   - ctx context.Context
+//   - username string
+//   - passwordHash string
+//   - role string
+func (_e *TokenUsers_Expecter) SaveUser(ctx interface{}, username interface{}, passwordHash interface{}, role interface{}) *TokenUsers_SaveUser_Call {
+	return &TokenUsers_SaveUser_Call{Call: _e.mock.On("SaveUser", ctx, username, passwordHash, role)}
+}
+
+func (_c *TokenUsers_SaveUser_Call) Run(run func(ctx context.Context, username string, passwordHash string, role string)) *TokenUsers_SaveUser_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(string), args[3].(string))
+	})
+	return _c
+}
+
+func (_c *TokenUsers_SaveUser_Call) Return(_a0 int64, _a1 error) *TokenUsers_SaveUser_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *TokenUsers_SaveUser_Call) RunAndReturn(run func(context.Context, string, string, string) (int64, error)) *TokenUsers_SaveUser_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewTokenUsers creates a new instance of TokenUsers. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewTokenUsers(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *TokenUsers {
+	mock := &TokenUsers{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
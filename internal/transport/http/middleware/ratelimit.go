@@ -0,0 +1,100 @@
+package middleware
+
+import (
+	"log/slog"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/dkhrunov/url-shortener/internal/auth"
+	"github.com/dkhrunov/url-shortener/internal/lib/logger/slog/slogerr"
+	"github.com/dkhrunov/url-shortener/internal/lib/ratelimit"
+	"github.com/dkhrunov/url-shortener/internal/transport/http/common/response"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+)
+
+type RateLimiter interface {
+	Allow(key string, rate ratelimit.Rate) (ratelimit.Result, error)
+}
+
+type ClientIPResolver interface {
+	IP(r *http.Request) string
+}
+
+// RateKeyFunc returns the key requests are counted by.
+type RateKeyFunc func(r *http.Request) string
+
+// ByClientIP counts requests per client address.
+func ByClientIP(ips ClientIPResolver) RateKeyFunc {
+	return func(r *http.Request) string {
+		return "ip:" + ips.IP(r)
+	}
+}
+
+// ByAPIKey counts requests authenticated with an API key per key, and other requests
+// per client address. It must be used after Auth.
+func ByAPIKey(ips ClientIPResolver) RateKeyFunc {
+	return func(r *http.Request) string {
+		if id, ok := auth.FromContext(r.Context()); ok && id.KeyID != 0 {
+			return "key:" + strconv.FormatInt(id.KeyID, 10)
+		}
+
+		return "ip:" + ips.IP(r)
+	}
+}
+
+// RateLimit rejects requests over the rate with 429 and a Retry-After header. Requests
+// are let through when the limiter fails, so an outage of a shared store doesn't take
+// the service down with it.
+func RateLimit(limiter RateLimiter, rate ratelimit.Rate, key RateKeyFunc) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		fn := func(w http.ResponseWriter, r *http.Request) {
+			const op = "middleware.RateLimit"
+
+			k := key(r)
+
+			res, err := limiter.Allow(k, rate)
+			if err != nil {
+				slog.ErrorContext(r.Context(), "failed to check rate limit",
+					slog.String("op", op),
+					slog.String("request_id", middleware.GetReqID(r.Context())),
+					slogerr.Error(err),
+				)
+
+				next.ServeHTTP(w, r)
+
+				return
+			}
+
+			w.Header().Set("X-RateLimit-Limit", strconv.Itoa(res.Limit))
+			w.Header().Set("X-RateLimit-Remaining", strconv.Itoa(res.Remaining))
+			w.Header().Set("X-RateLimit-Reset", strconv.Itoa(seconds(res.ResetAfter)))
+
+			if !res.Allowed {
+				slog.InfoContext(r.Context(), "rate limit exceeded",
+					slog.String("op", op),
+					slog.String("request_id", middleware.GetReqID(r.Context())),
+					slog.String("key", k),
+				)
+
+				w.Header().Set("Retry-After", strconv.Itoa(seconds(res.RetryAfter)))
+
+				render.Status(r, http.StatusTooManyRequests)
+				render.JSON(w, r, response.Error("too many requests"))
+
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		}
+
+		return http.HandlerFunc(fn)
+	}
+}
+
+// seconds rounds d up to whole seconds, as expected by Retry-After.
+func seconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
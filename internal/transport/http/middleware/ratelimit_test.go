@@ -0,0 +1,125 @@
+package middleware_test
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/dkhrunov/url-shortener/internal/auth"
+	"github.com/dkhrunov/url-shortener/internal/lib/ratelimit"
+	"github.com/dkhrunov/url-shortener/internal/transport/http/common/response"
+	"github.com/dkhrunov/url-shortener/internal/transport/http/middleware"
+	"github.com/dkhrunov/url-shortener/internal/transport/http/middleware/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRateLimit(t *testing.T) {
+	rate := ratelimit.Rate{Requests: 60, Period: time.Minute}
+
+	cases := []struct {
+		name      string
+		identity  *auth.Identity
+		key       string
+		result    ratelimit.Result
+		mockError error
+		next      bool
+		status    int
+		headers   map[string]string
+	}{
+		{
+			name:   "Allowed",
+			key:    "ip:203.0.113.7",
+			result: ratelimit.Result{Allowed: true, Limit: 60, Remaining: 59, ResetAfter: 1500 * time.Millisecond},
+			next:   true,
+			status: http.StatusOK,
+			headers: map[string]string{
+				"X-RateLimit-Limit":     "60",
+				"X-RateLimit-Remaining": "59",
+				"X-RateLimit-Reset":     "2",
+			},
+		},
+		{
+			name:   "Limited",
+			key:    "ip:203.0.113.7",
+			result: ratelimit.Result{Limit: 60, RetryAfter: 300 * time.Millisecond, ResetAfter: time.Minute},
+			status: http.StatusTooManyRequests,
+			headers: map[string]string{
+				"X-RateLimit-Remaining": "0",
+				"Retry-After":           "1",
+			},
+		},
+		{
+			name:     "By API key",
+			identity: &auth.Identity{Method: auth.MethodAPIKey, KeyID: 7},
+			key:      "key:7",
+			result:   ratelimit.Result{Allowed: true, Limit: 60, Remaining: 10},
+			next:     true,
+			status:   http.StatusOK,
+		},
+		{
+			name:     "Basic user by address",
+			identity: &auth.Identity{Method: auth.MethodBasic, UserID: 3},
+			key:      "ip:203.0.113.7",
+			result:   ratelimit.Result{Allowed: true, Limit: 60, Remaining: 10},
+			next:     true,
+			status:   http.StatusOK,
+		},
+		{
+			name:      "Limiter failure lets requests through",
+			key:       "ip:203.0.113.7",
+			mockError: errors.New("unexpected error"),
+			next:      true,
+			status:    http.StatusOK,
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			limiterMock := mocks.NewRateLimiter(t)
+			limiterMock.EXPECT().
+				Allow(tc.key, rate).
+				Return(tc.result, tc.mockError).
+				Once()
+
+			ipsMock := mocks.NewClientIPResolver(t)
+			ipsMock.EXPECT().IP(mock.Anything).Return("203.0.113.7").Maybe()
+
+			var called bool
+			next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				called = true
+			})
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodGet, "/alias", nil)
+			if tc.identity != nil {
+				r = r.WithContext(auth.WithIdentity(r.Context(), *tc.identity))
+			}
+
+			middleware.RateLimit(limiterMock, rate, middleware.ByAPIKey(ipsMock))(next).ServeHTTP(w, r)
+
+			assert.Equal(t, tc.status, w.Code)
+			assert.Equal(t, tc.next, called)
+
+			for k, v := range tc.headers {
+				assert.Equal(t, v, w.Header().Get(k), k)
+			}
+
+			if tc.status == http.StatusTooManyRequests {
+				var resp response.Response
+
+				require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+
+				assert.Equal(t, "too many requests", resp.Error)
+			}
+		})
+	}
+}
@@ -0,0 +1,63 @@
+package middleware
+
+import (
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/dkhrunov/url-shortener/internal/lib/scanguard"
+	"github.com/dkhrunov/url-shortener/internal/transport/http/common/response"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+)
+
+type ScanDetector interface {
+	Blocked(client string) (scanguard.Block, bool)
+	Miss(client string) (scanguard.Block, bool)
+}
+
+// ScanGuard counts not found responses per client address and rejects clients the
+// detector blocked, which are likely enumerating aliases, with 429 and a Retry-After
+// header until the block ends.
+func ScanGuard(detector ScanDetector, ips ClientIPResolver) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		fn := func(w http.ResponseWriter, r *http.Request) {
+			const op = "middleware.ScanGuard"
+
+			log := slog.With(
+				slog.String("op", op),
+				slog.String("request_id", middleware.GetReqID(r.Context())),
+			)
+
+			client := ips.IP(r)
+
+			if b, ok := detector.Blocked(client); ok {
+				w.Header().Set("Retry-After", strconv.Itoa(seconds(time.Until(b.Until))))
+
+				render.Status(r, http.StatusTooManyRequests)
+				render.JSON(w, r, response.Error("client temporarily blocked"))
+
+				return
+			}
+
+			ww := middleware.NewWrapResponseWriter(w, r.ProtoMajor)
+
+			next.ServeHTTP(ww, r)
+
+			if ww.Status() != http.StatusNotFound {
+				return
+			}
+
+			if b, blocked := detector.Miss(client); blocked {
+				log.WarnContext(r.Context(), "client blocked for alias enumeration",
+					slog.String("client", client),
+					slog.Int("misses", b.Misses),
+					slog.Time("until", b.Until),
+				)
+			}
+		}
+
+		return http.HandlerFunc(fn)
+	}
+}
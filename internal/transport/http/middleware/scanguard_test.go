@@ -0,0 +1,106 @@
+package middleware_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/dkhrunov/url-shortener/internal/lib/scanguard"
+	"github.com/dkhrunov/url-shortener/internal/transport/http/common/response"
+	"github.com/dkhrunov/url-shortener/internal/transport/http/middleware"
+	"github.com/dkhrunov/url-shortener/internal/transport/http/middleware/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScanGuard(t *testing.T) {
+	const client = "203.0.113.7"
+
+	cases := []struct {
+		name   string
+		setup  func(d *mocks.ScanDetector)
+		status int
+		next   bool
+	}{
+		{
+			name: "Found",
+			setup: func(d *mocks.ScanDetector) {
+				d.EXPECT().Blocked(client).Return(scanguard.Block{}, false).Once()
+			},
+			status: http.StatusFound,
+			next:   true,
+		},
+		{
+			name: "Not found is recorded",
+			setup: func(d *mocks.ScanDetector) {
+				d.EXPECT().Blocked(client).Return(scanguard.Block{}, false).Once()
+				d.EXPECT().Miss(client).Return(scanguard.Block{}, false).Once()
+			},
+			status: http.StatusNotFound,
+			next:   true,
+		},
+		{
+			name: "Not found blocks",
+			setup: func(d *mocks.ScanDetector) {
+				d.EXPECT().Blocked(client).Return(scanguard.Block{}, false).Once()
+				d.EXPECT().Miss(client).Return(scanguard.Block{Client: client, Misses: 20}, true).Once()
+			},
+			status: http.StatusNotFound,
+			next:   true,
+		},
+		{
+			name: "Blocked",
+			setup: func(d *mocks.ScanDetector) {
+				d.EXPECT().Blocked(client).Return(scanguard.Block{Client: client, Until: time.Now().Add(time.Minute)}, true).Once()
+			},
+			status: http.StatusTooManyRequests,
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			detectorMock := mocks.NewScanDetector(t)
+			tc.setup(detectorMock)
+
+			ipsMock := mocks.NewClientIPResolver(t)
+			ipsMock.EXPECT().IP(mock.Anything).Return(client).Once()
+
+			var called bool
+			next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				called = true
+
+				if tc.status == http.StatusNotFound {
+					w.WriteHeader(http.StatusNotFound)
+					return
+				}
+
+				w.WriteHeader(http.StatusFound)
+			})
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodGet, "/alias", nil)
+
+			middleware.ScanGuard(detectorMock, ipsMock)(next).ServeHTTP(w, r)
+
+			assert.Equal(t, tc.status, w.Code)
+			assert.Equal(t, tc.next, called)
+
+			if tc.status == http.StatusTooManyRequests {
+				assert.Equal(t, "60", w.Header().Get("Retry-After"))
+
+				var resp response.Response
+
+				require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+
+				assert.Equal(t, "client temporarily blocked", resp.Error)
+			}
+		})
+	}
+}